@@ -4,8 +4,9 @@ import (
 	"gorm.io/cli/gorm/genconfig"
 )
 
-// Exclude I2 and S2
+// Exclude I2, S2 and the Secret field of S1
 var _ = genconfig.Config{
 	ExcludeInterfaces: []any{I2[any](nil)},
 	ExcludeStructs:    []any{S2{}},
+	ExcludeFields:     []any{"S1.Secret"},
 }
@@ -0,0 +1,13 @@
+//go:build gorm
+
+package pattern
+
+import "gorm.io/cli/gorm/genconfig"
+
+// Include only interfaces whose names start with "Query"
+//
+// The config lives in gorm.gen.go behind the gorm build tag: the generator reads it,
+// regular builds don't compile it, so genconfig isn't linked into binaries.
+var _ = genconfig.Config{
+	IncludeInterfaces: []any{"Query*"},
+}
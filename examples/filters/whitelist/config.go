@@ -4,8 +4,9 @@ import (
 	"gorm.io/cli/gorm/genconfig"
 )
 
-// Only generate I1 and S1
+// Only generate I1 and S1, without the Secret field of S1
 var _ = genconfig.Config{
 	IncludeInterfaces: []any{"I1"},
 	IncludeStructs:    []any{"S1"},
+	IncludeFields:     []any{"S1.ID", "S1.Name"},
 }
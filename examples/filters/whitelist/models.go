@@ -1,8 +1,9 @@
 package whitelist
 
 type S1 struct {
-	ID   int
-	Name string
+	ID     int
+	Name   string
+	Secret string
 }
 
 type S2 struct {
@@ -0,0 +1,13 @@
+//go:build gorm
+
+package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+// Generate CRUD repositories and fixture factories of the models, e.g. NewUserRepository(db)
+// and NewUserFixture()
+var _ = genconfig.Config{
+	Scope:     "package",
+	CRUD:      true,
+	Factories: true,
+}
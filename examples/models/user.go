@@ -28,11 +28,19 @@ type User struct {
 	Team      []User     `gorm:"foreignkey:ManagerID"`
 	Languages []Language `gorm:"many2many:UserSpeak"`
 	Friends   []*User    `gorm:"many2many:user_friends"`
-	Role      string
+	Role      Role
 	IsAdult   bool   `gorm:"column:is_adult"`
 	Profile   string `gen:"json"`
 }
 
+// Role is the role of a user
+type Role string
+
+const (
+	RoleActive  Role = "active"
+	RolePending Role = "pending"
+)
+
 type Account struct {
 	gorm.Model
 	UserID       sql.NullInt64
@@ -43,9 +51,11 @@ type Account struct {
 
 type Pet struct {
 	gorm.Model
-	UserID *uint
-	Name   string
-	Toy    Toy `gorm:"polymorphic:Owner;"`
+	UserID     *uint
+	Name       string
+	Toy        Toy               `gorm:"polymorphic:Owner;"`
+	Tags       []string          `gorm:"serializer:json"`
+	Attributes map[string]string `gorm:"serializer:json"`
 }
 
 type Toy struct {
@@ -53,11 +63,14 @@ type Toy struct {
 	Name      string
 	OwnerID   uint
 	OwnerType string
+	CreatedBy string `gorm:"<-:create"`
+	Code      string `gorm:"->"`
 }
 
 type Company struct {
-	ID   int
-	Name string
+	ID      int
+	Name    string `gorm:"size:64;uniqueIndex"`
+	Version int    `gen:"version"`
 }
 
 type Language struct {
@@ -65,6 +78,15 @@ type Language struct {
 	Name string
 }
 
+// AdultUser lists the adult users, it's a database view
+//
+//gorm:view SELECT id, name, age FROM users WHERE is_adult = true AND deleted_at IS NULL
+type AdultUser struct {
+	ID   uint
+	Name string
+	Age  int
+}
+
 type CreditCard struct {
 	*gorm.Model
 	Number string
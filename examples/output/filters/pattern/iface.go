@@ -10,6 +10,7 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// QueryUser should match pattern "Query*"
 func QueryUser[T any](db *gorm.DB, opts ...clause.Expression) _QueryUserInterface[T] {
 	return _QueryUserImpl[T]{
 		Interface: gorm.G[T](db, opts...),
@@ -37,6 +38,7 @@ func (e _QueryUserImpl[T]) ByID(ctx context.Context, id int) (T, error) {
 	return result, err
 }
 
+// QueryOrder should match pattern "Query*"
 func QueryOrder[T any](db *gorm.DB, opts ...clause.Expression) _QueryOrderInterface[T] {
 	return _QueryOrderImpl[T]{
 		Interface: gorm.G[T](db, opts...),
@@ -4,16 +4,151 @@ package twolevel
 
 import (
 	"gorm.io/cli/gorm/field"
+	"gorm.io/gorm/clause"
 )
 
-var S1 = struct {
+var S1 = _S1{
+	ID: field.Number[int]{}.WithColumn("id"),
+}
+
+type _S1 struct {
 	ID field.Number[int]
+}
+
+// S1As returns the S1 field helpers qualified with the table alias, e.g. for self joins
+func S1As(alias string) _S1Alias {
+	return _S1Alias{
+		_S1: _S1{
+			ID: field.Number[int]{}.WithColumn("id").WithTable(alias),
+		},
+		Alias: alias,
+	}
+}
+
+// _S1Alias holds the S1 field helpers of an aliased table
+type _S1Alias struct {
+	_S1
+	// Alias is the table alias, e.g. for clause.JoinTarget.As
+	Alias string
+}
+
+// Table returns the aliased clause.Table of S1
+func (a _S1Alias) Table() clause.Table {
+	return clause.Table{Name: "s1", Alias: a.Alias}
+}
+
+// S1Columns holds the column names of S1
+var S1Columns = struct {
+	ID string
 }{
+	ID: "id",
+}
+
+// AllFields returns all column fields of S1, e.g. Select(S1.AllFields()...)
+func (s _S1) AllFields() []field.Selectable {
+	return []field.Selectable{
+		s.ID,
+	}
+}
+
+// AllColumns returns all columns of S1
+func (s _S1) AllColumns() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// PrimaryKey returns the primary key columns of S1
+func (s _S1) PrimaryKey() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// ByID returns the condition matching the primary key of S1, e.g. Where(S1.ByID(id))
+func (s _S1) ByID(id int) clause.Expression {
+	return s.ID.Eq(id)
+}
+
+// TableName returns the table name of S1
+func (_S1) TableName() string {
+	return "s1"
+}
+
+// Table returns the clause.Table of S1
+func (_S1) Table() clause.Table {
+	return clause.Table{Name: "s1"}
+}
+
+var S2 = _S2{
 	ID: field.Number[int]{}.WithColumn("id"),
 }
 
-var S2 = struct {
+type _S2 struct {
 	ID field.Number[int]
+}
+
+// S2As returns the S2 field helpers qualified with the table alias, e.g. for self joins
+func S2As(alias string) _S2Alias {
+	return _S2Alias{
+		_S2: _S2{
+			ID: field.Number[int]{}.WithColumn("id").WithTable(alias),
+		},
+		Alias: alias,
+	}
+}
+
+// _S2Alias holds the S2 field helpers of an aliased table
+type _S2Alias struct {
+	_S2
+	// Alias is the table alias, e.g. for clause.JoinTarget.As
+	Alias string
+}
+
+// Table returns the aliased clause.Table of S2
+func (a _S2Alias) Table() clause.Table {
+	return clause.Table{Name: "s2", Alias: a.Alias}
+}
+
+// S2Columns holds the column names of S2
+var S2Columns = struct {
+	ID string
 }{
-	ID: field.Number[int]{}.WithColumn("id"),
+	ID: "id",
+}
+
+// AllFields returns all column fields of S2, e.g. Select(S2.AllFields()...)
+func (s _S2) AllFields() []field.Selectable {
+	return []field.Selectable{
+		s.ID,
+	}
+}
+
+// AllColumns returns all columns of S2
+func (s _S2) AllColumns() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// PrimaryKey returns the primary key columns of S2
+func (s _S2) PrimaryKey() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// ByID returns the condition matching the primary key of S2, e.g. Where(S2.ByID(id))
+func (s _S2) ByID(id int) clause.Expression {
+	return s.ID.Eq(id)
+}
+
+// TableName returns the table name of S2
+func (_S2) TableName() string {
+	return "s2"
+}
+
+// Table returns the clause.Table of S2
+func (_S2) Table() clause.Table {
+	return clause.Table{Name: "s2"}
 }
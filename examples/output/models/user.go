@@ -3,63 +3,321 @@
 package models
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
 
-	"gorm.io/cli/gorm/examples"
 	"gorm.io/cli/gorm/examples/models"
 	"gorm.io/cli/gorm/field"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-var User = struct {
+// User has one `Account` (has one), many `Pets` (has many) and `Toys` (has many - polymorphic)
+// He works in a Company (belongs to), he has a Manager (belongs to - single-table), and also managed a Team (has many - single-table)
+// He speaks many languages (many to many) and has many friends (many to many - single-table)
+// His pet also has one Toy (has one - polymorphic)
+var User = _User{
+	ID:        field.Number[uint]{}.WithColumn("id"),
+	CreatedAt: field.Time{}.WithColumn("created_at"),
+	UpdatedAt: field.Time{}.WithColumn("updated_at"),
+	DeletedAt: field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at"),
+	Name:      field.String{}.WithColumn("name"),
+	Age:       field.Number[int]{}.WithColumn("age"),
+	Birthday:  field.Null[time.Time]{}.WithColumn("birthday"),
+	Score:     field.Field[sql.NullInt64]{}.WithColumn("score"),
+	LastLogin: field.Null[time.Time]{}.WithColumn("last_login"),
+	Account:   field.Struct[models.Account]{}.WithName("Account").WithMetadata(field.AssociationMetadata{Kind: field.HasOne, ForeignKey: "user_id", References: "id"}),
+	Pets:      field.Slice[models.Pet]{}.WithName("Pets").WithMetadata(field.AssociationMetadata{Kind: field.HasMany, ForeignKey: "user_id", References: "id"}),
+	Toys:      field.Slice[models.Toy]{}.WithName("Toys").WithMetadata(field.AssociationMetadata{Kind: field.HasMany, ForeignKey: "owner_id", References: "id", PolymorphicType: "owner_type", PolymorphicID: "owner_id", PolymorphicValue: "users"}),
+	CompanyID: field.Null[int]{}.WithColumn("company_id"),
+	Company:   field.Struct[models.Company]{}.WithName("Company").WithMetadata(field.AssociationMetadata{Kind: field.BelongsTo, ForeignKey: "company_id", References: "id"}),
+	ManagerID: field.Null[uint]{}.WithColumn("manager_id"),
+	Manager:   field.Struct[models.User]{}.WithName("Manager").WithMetadata(field.AssociationMetadata{Kind: field.BelongsTo, ForeignKey: "manager_id", References: "id"}),
+	Team:      field.Slice[models.User]{}.WithName("Team").WithMetadata(field.AssociationMetadata{Kind: field.HasMany, ForeignKey: "manager_id", References: "id"}),
+	Languages: field.Slice[models.Language]{}.WithName("Languages").WithMetadata(field.AssociationMetadata{Kind: field.Many2Many, ForeignKey: "id", References: "code", JoinTable: "user_speaks", JoinForeignKey: "user_id", JoinReferences: "language_code"}),
+	Friends:   field.Slice[models.User]{}.WithName("Friends").WithMetadata(field.AssociationMetadata{Kind: field.Many2Many, ForeignKey: "id", References: "id", JoinTable: "user_friends", JoinForeignKey: "user_id", JoinReferences: "friend_id"}),
+	Role:      field.Enum[models.Role]{}.WithColumn("role").WithValues(models.RoleActive, models.RolePending),
+	IsAdult:   field.Bool{}.WithColumn("is_adult"),
+	Profile:   field.JSON{}.WithColumn("profile"),
+}
+
+type _User struct {
 	ID        field.Number[uint]
 	CreatedAt field.Time
 	UpdatedAt field.Time
 	DeletedAt field.Field[gorm.DeletedAt]
 	Name      field.String
 	Age       field.Number[int]
-	Birthday  field.Time
+	Birthday  field.Null[time.Time]
 	Score     field.Field[sql.NullInt64]
-	LastLogin field.Time
+	LastLogin field.Null[time.Time]
 	Account   field.Struct[models.Account]
 	Pets      field.Slice[models.Pet]
 	Toys      field.Slice[models.Toy]
-	CompanyID field.Number[int]
+	CompanyID field.Null[int]
 	Company   field.Struct[models.Company]
-	ManagerID field.Number[uint]
+	ManagerID field.Null[uint]
 	Manager   field.Struct[models.User]
 	Team      field.Slice[models.User]
 	Languages field.Slice[models.Language]
 	Friends   field.Slice[models.User]
-	Role      field.String
+	Role      field.Enum[models.Role]
 	IsAdult   field.Bool
-	Profile   examples.JSON
+	Profile   field.JSON
+}
+
+// UserAs returns the User field helpers qualified with the table alias, e.g. for self joins
+func UserAs(alias string) _UserAlias {
+	return _UserAlias{
+		_User: _User{
+			ID:        field.Number[uint]{}.WithColumn("id").WithTable(alias),
+			CreatedAt: field.Time{}.WithColumn("created_at").WithTable(alias),
+			UpdatedAt: field.Time{}.WithColumn("updated_at").WithTable(alias),
+			DeletedAt: field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at").WithTable(alias),
+			Name:      field.String{}.WithColumn("name").WithTable(alias),
+			Age:       field.Number[int]{}.WithColumn("age").WithTable(alias),
+			Birthday:  field.Null[time.Time]{}.WithColumn("birthday").WithTable(alias),
+			Score:     field.Field[sql.NullInt64]{}.WithColumn("score").WithTable(alias),
+			LastLogin: field.Null[time.Time]{}.WithColumn("last_login").WithTable(alias),
+			Account:   field.Struct[models.Account]{}.WithName("Account").WithMetadata(field.AssociationMetadata{Kind: field.HasOne, ForeignKey: "user_id", References: "id"}),
+			Pets:      field.Slice[models.Pet]{}.WithName("Pets").WithMetadata(field.AssociationMetadata{Kind: field.HasMany, ForeignKey: "user_id", References: "id"}),
+			Toys:      field.Slice[models.Toy]{}.WithName("Toys").WithMetadata(field.AssociationMetadata{Kind: field.HasMany, ForeignKey: "owner_id", References: "id", PolymorphicType: "owner_type", PolymorphicID: "owner_id", PolymorphicValue: "users"}),
+			CompanyID: field.Null[int]{}.WithColumn("company_id").WithTable(alias),
+			Company:   field.Struct[models.Company]{}.WithName("Company").WithMetadata(field.AssociationMetadata{Kind: field.BelongsTo, ForeignKey: "company_id", References: "id"}),
+			ManagerID: field.Null[uint]{}.WithColumn("manager_id").WithTable(alias),
+			Manager:   field.Struct[models.User]{}.WithName("Manager").WithMetadata(field.AssociationMetadata{Kind: field.BelongsTo, ForeignKey: "manager_id", References: "id"}),
+			Team:      field.Slice[models.User]{}.WithName("Team").WithMetadata(field.AssociationMetadata{Kind: field.HasMany, ForeignKey: "manager_id", References: "id"}),
+			Languages: field.Slice[models.Language]{}.WithName("Languages").WithMetadata(field.AssociationMetadata{Kind: field.Many2Many, ForeignKey: "id", References: "code", JoinTable: "user_speaks", JoinForeignKey: "user_id", JoinReferences: "language_code"}),
+			Friends:   field.Slice[models.User]{}.WithName("Friends").WithMetadata(field.AssociationMetadata{Kind: field.Many2Many, ForeignKey: "id", References: "id", JoinTable: "user_friends", JoinForeignKey: "user_id", JoinReferences: "friend_id"}),
+			Role:      field.Enum[models.Role]{}.WithColumn("role").WithValues(models.RoleActive, models.RolePending).WithTable(alias),
+			IsAdult:   field.Bool{}.WithColumn("is_adult").WithTable(alias),
+			Profile:   field.JSON{}.WithColumn("profile").WithTable(alias),
+		},
+		Alias: alias,
+	}
+}
+
+// _UserAlias holds the User field helpers of an aliased table
+type _UserAlias struct {
+	_User
+	// Alias is the table alias, e.g. for clause.JoinTarget.As
+	Alias string
+}
+
+// Table returns the aliased clause.Table of User
+func (a _UserAlias) Table() clause.Table {
+	return clause.Table{Name: "users", Alias: a.Alias}
+}
+
+// UserColumns holds the column names of User
+var UserColumns = struct {
+	ID        string
+	CreatedAt string
+	UpdatedAt string
+	DeletedAt string
+	Name      string
+	Age       string
+	Birthday  string
+	Score     string
+	LastLogin string
+	CompanyID string
+	ManagerID string
+	Role      string
+	IsAdult   string
+	Profile   string
 }{
-	ID:        field.Number[uint]{}.WithColumn("id"),
-	CreatedAt: field.Time{}.WithColumn("created_at"),
-	UpdatedAt: field.Time{}.WithColumn("updated_at"),
-	DeletedAt: field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at"),
-	Name:      field.String{}.WithColumn("name"),
-	Age:       field.Number[int]{}.WithColumn("age"),
-	Birthday:  field.Time{}.WithColumn("birthday"),
-	Score:     field.Field[sql.NullInt64]{}.WithColumn("score"),
-	LastLogin: field.Time{}.WithColumn("last_login"),
-	Account:   field.Struct[models.Account]{}.WithName("Account"),
-	Pets:      field.Slice[models.Pet]{}.WithName("Pets"),
-	Toys:      field.Slice[models.Toy]{}.WithName("Toys"),
-	CompanyID: field.Number[int]{}.WithColumn("company_id"),
-	Company:   field.Struct[models.Company]{}.WithName("Company"),
-	ManagerID: field.Number[uint]{}.WithColumn("manager_id"),
-	Manager:   field.Struct[models.User]{}.WithName("Manager"),
-	Team:      field.Slice[models.User]{}.WithName("Team"),
-	Languages: field.Slice[models.Language]{}.WithName("Languages"),
-	Friends:   field.Slice[models.User]{}.WithName("Friends"),
-	Role:      field.String{}.WithColumn("role"),
-	IsAdult:   field.Bool{}.WithColumn("is_adult"),
-	Profile:   examples.JSON{}.WithColumn("profile"),
+	ID:        "id",
+	CreatedAt: "created_at",
+	UpdatedAt: "updated_at",
+	DeletedAt: "deleted_at",
+	Name:      "name",
+	Age:       "age",
+	Birthday:  "birthday",
+	Score:     "score",
+	LastLogin: "last_login",
+	CompanyID: "company_id",
+	ManagerID: "manager_id",
+	Role:      "role",
+	IsAdult:   "is_adult",
+	Profile:   "profile",
+}
+
+// AllFields returns all column fields of User, e.g. Select(User.AllFields()...)
+func (s _User) AllFields() []field.Selectable {
+	return []field.Selectable{
+		s.ID,
+		s.CreatedAt,
+		s.UpdatedAt,
+		s.DeletedAt,
+		s.Name,
+		s.Age,
+		s.Birthday,
+		s.Score,
+		s.LastLogin,
+		s.CompanyID,
+		s.ManagerID,
+		s.Role,
+		s.IsAdult,
+		s.Profile,
+	}
+}
+
+// AllColumns returns all columns of User
+func (s _User) AllColumns() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+		s.CreatedAt.Column(),
+		s.UpdatedAt.Column(),
+		s.DeletedAt.Column(),
+		s.Name.Column(),
+		s.Age.Column(),
+		s.Birthday.Column(),
+		s.Score.Column(),
+		s.LastLogin.Column(),
+		s.CompanyID.Column(),
+		s.ManagerID.Column(),
+		s.Role.Column(),
+		s.IsAdult.Column(),
+		s.Profile.Column(),
+	}
+}
+
+// PrimaryKey returns the primary key columns of User
+func (s _User) PrimaryKey() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// ByID returns the condition matching the primary key of User, e.g. Where(User.ByID(id))
+func (s _User) ByID(id uint) clause.Expression {
+	return s.ID.Eq(id)
+}
+
+// WithDeleted returns a scope including soft deleted User records, e.g. Scopes(User.WithDeleted())
+func (_User) WithDeleted() func(*gorm.Statement) {
+	return func(stmt *gorm.Statement) {
+		stmt.Unscoped = true
+	}
+}
+
+// OnlyDeleted returns a scope matching only soft deleted User records, e.g. Scopes(User.OnlyDeleted())
+func (s _User) OnlyDeleted() func(*gorm.Statement) {
+	return func(stmt *gorm.Statement) {
+		stmt.Unscoped = true
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "? IS NOT NULL", Vars: []any{s.DeletedAt.Column()}},
+		}})
+	}
+}
+
+// NotDeleted returns the condition matching User records that aren't soft deleted, e.g. for joined or unscoped queries
+func (s _User) NotDeleted() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{s.DeletedAt.Column()}}
+}
+
+// TableName returns the table name of User
+func (_User) TableName() string {
+	return "users"
+}
+
+// Table returns the clause.Table of User
+func (_User) Table() clause.Table {
+	return clause.Table{Name: "users"}
+}
+
+// UserScopes holds the scopes of User declared in genconfig.Config.Scopes, e.g. Scopes(UserScopes.ActiveAdults())
+var UserScopes = _UserScopes{}
+
+type _UserScopes struct{}
+
+// ActiveAdults adds the conditions User.Role.Eq(models.RoleActive), User.IsAdult.Eq(true)
+func (_UserScopes) ActiveAdults() func(*gorm.Statement) {
+	return func(stmt *gorm.Statement) {
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			User.Role.Eq(models.RoleActive),
+			User.IsAdult.Eq(true),
+		}})
+	}
+}
+
+// _UserFixtureSeq numbers the User fixtures, keeping their strings unique
+var _UserFixtureSeq atomic.Int64
+
+// NewUserFixture returns a User with default values for seeding tests, strings are unique, then applies the overrides
+func NewUserFixture(overrides ...func(*models.User)) models.User {
+	seq := _UserFixtureSeq.Add(1)
+	record := models.User{}
+	record.Name = fmt.Sprintf("name-%d", seq)
+	record.Role = models.RoleActive
+	for _, override := range overrides {
+		override(&record)
+	}
+	return record
+}
+
+// UserRepository is the CRUD repository of User, see NewUserRepository
+type UserRepository interface {
+	// GetByID returns the User with the primary key id, gorm.ErrRecordNotFound if none
+	GetByID(ctx context.Context, id uint) (models.User, error)
+	// List returns the User records matching all conds, e.g. List(ctx, User.ID.Gt(0))
+	List(ctx context.Context, conds ...clause.Expression) ([]models.User, error)
+	// Create inserts the record, filling its primary key and defaults
+	Create(ctx context.Context, record *models.User) error
+	// Update applies the assignments, e.g. field helper Set calls, to the User with the primary key id and returns the number of updated rows
+	Update(ctx context.Context, id uint, assignments ...clause.Assigner) (int, error)
+	// Delete deletes the User with the primary key id and returns the number of deleted rows
+	Delete(ctx context.Context, id uint) (int, error)
+}
+
+// NewUserRepository returns the UserRepository of db
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return _UserRepository{db: db}
+}
+
+type _UserRepository struct {
+	db *gorm.DB
+}
+
+func (r _UserRepository) GetByID(ctx context.Context, id uint) (models.User, error) {
+	return gorm.G[models.User](r.db).Where(User.ByID(id)).First(ctx)
+}
+
+func (r _UserRepository) List(ctx context.Context, conds ...clause.Expression) ([]models.User, error) {
+	return gorm.G[models.User](r.db).Scopes(func(stmt *gorm.Statement) {
+		if len(conds) > 0 {
+			stmt.AddClause(clause.Where{Exprs: conds})
+		}
+	}).Find(ctx)
+}
+
+func (r _UserRepository) Create(ctx context.Context, record *models.User) error {
+	return gorm.G[models.User](r.db).Create(ctx, record)
+}
+
+func (r _UserRepository) Update(ctx context.Context, id uint, assignments ...clause.Assigner) (int, error) {
+	return gorm.G[models.User](r.db).Where(User.ByID(id)).Set(assignments...).Update(ctx)
 }
 
-var Account = struct {
+func (r _UserRepository) Delete(ctx context.Context, id uint) (int, error) {
+	return gorm.G[models.User](r.db).Where(User.ByID(id)).Delete(ctx)
+}
+
+var Account = _Account{
+	ID:           field.Number[uint]{}.WithColumn("id"),
+	CreatedAt:    field.Time{}.WithColumn("created_at"),
+	UpdatedAt:    field.Time{}.WithColumn("updated_at"),
+	DeletedAt:    field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at"),
+	UserID:       field.Field[sql.NullInt64]{}.WithColumn("user_id"),
+	Number:       field.String{}.WithColumn("number"),
+	RewardPoints: field.Field[sql.NullInt64]{}.WithColumn("reward_points"),
+	LastUsedAt:   field.Null[time.Time]{}.WithColumn("last_used_at"),
+}
+
+type _Account struct {
 	ID           field.Number[uint]
 	CreatedAt    field.Time
 	UpdatedAt    field.Time
@@ -67,37 +325,413 @@ var Account = struct {
 	UserID       field.Field[sql.NullInt64]
 	Number       field.String
 	RewardPoints field.Field[sql.NullInt64]
-	LastUsedAt   field.Time
+	LastUsedAt   field.Null[time.Time]
+}
+
+// AccountAs returns the Account field helpers qualified with the table alias, e.g. for self joins
+func AccountAs(alias string) _AccountAlias {
+	return _AccountAlias{
+		_Account: _Account{
+			ID:           field.Number[uint]{}.WithColumn("id").WithTable(alias),
+			CreatedAt:    field.Time{}.WithColumn("created_at").WithTable(alias),
+			UpdatedAt:    field.Time{}.WithColumn("updated_at").WithTable(alias),
+			DeletedAt:    field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at").WithTable(alias),
+			UserID:       field.Field[sql.NullInt64]{}.WithColumn("user_id").WithTable(alias),
+			Number:       field.String{}.WithColumn("number").WithTable(alias),
+			RewardPoints: field.Field[sql.NullInt64]{}.WithColumn("reward_points").WithTable(alias),
+			LastUsedAt:   field.Null[time.Time]{}.WithColumn("last_used_at").WithTable(alias),
+		},
+		Alias: alias,
+	}
+}
+
+// _AccountAlias holds the Account field helpers of an aliased table
+type _AccountAlias struct {
+	_Account
+	// Alias is the table alias, e.g. for clause.JoinTarget.As
+	Alias string
+}
+
+// Table returns the aliased clause.Table of Account
+func (a _AccountAlias) Table() clause.Table {
+	return clause.Table{Name: "accounts", Alias: a.Alias}
+}
+
+// AccountColumns holds the column names of Account
+var AccountColumns = struct {
+	ID           string
+	CreatedAt    string
+	UpdatedAt    string
+	DeletedAt    string
+	UserID       string
+	Number       string
+	RewardPoints string
+	LastUsedAt   string
 }{
-	ID:           field.Number[uint]{}.WithColumn("id"),
-	CreatedAt:    field.Time{}.WithColumn("created_at"),
-	UpdatedAt:    field.Time{}.WithColumn("updated_at"),
-	DeletedAt:    field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at"),
-	UserID:       field.Field[sql.NullInt64]{}.WithColumn("user_id"),
-	Number:       field.String{}.WithColumn("number"),
-	RewardPoints: field.Field[sql.NullInt64]{}.WithColumn("reward_points"),
-	LastUsedAt:   field.Time{}.WithColumn("last_used_at"),
+	ID:           "id",
+	CreatedAt:    "created_at",
+	UpdatedAt:    "updated_at",
+	DeletedAt:    "deleted_at",
+	UserID:       "user_id",
+	Number:       "number",
+	RewardPoints: "reward_points",
+	LastUsedAt:   "last_used_at",
 }
 
-var Pet = struct {
-	ID        field.Number[uint]
-	CreatedAt field.Time
-	UpdatedAt field.Time
-	DeletedAt field.Field[gorm.DeletedAt]
-	UserID    field.Number[uint]
-	Name      field.String
-	Toy       field.Struct[models.Toy]
+// AllFields returns all column fields of Account, e.g. Select(Account.AllFields()...)
+func (s _Account) AllFields() []field.Selectable {
+	return []field.Selectable{
+		s.ID,
+		s.CreatedAt,
+		s.UpdatedAt,
+		s.DeletedAt,
+		s.UserID,
+		s.Number,
+		s.RewardPoints,
+		s.LastUsedAt,
+	}
+}
+
+// AllColumns returns all columns of Account
+func (s _Account) AllColumns() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+		s.CreatedAt.Column(),
+		s.UpdatedAt.Column(),
+		s.DeletedAt.Column(),
+		s.UserID.Column(),
+		s.Number.Column(),
+		s.RewardPoints.Column(),
+		s.LastUsedAt.Column(),
+	}
+}
+
+// PrimaryKey returns the primary key columns of Account
+func (s _Account) PrimaryKey() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// ByID returns the condition matching the primary key of Account, e.g. Where(Account.ByID(id))
+func (s _Account) ByID(id uint) clause.Expression {
+	return s.ID.Eq(id)
+}
+
+// WithDeleted returns a scope including soft deleted Account records, e.g. Scopes(Account.WithDeleted())
+func (_Account) WithDeleted() func(*gorm.Statement) {
+	return func(stmt *gorm.Statement) {
+		stmt.Unscoped = true
+	}
+}
+
+// OnlyDeleted returns a scope matching only soft deleted Account records, e.g. Scopes(Account.OnlyDeleted())
+func (s _Account) OnlyDeleted() func(*gorm.Statement) {
+	return func(stmt *gorm.Statement) {
+		stmt.Unscoped = true
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "? IS NOT NULL", Vars: []any{s.DeletedAt.Column()}},
+		}})
+	}
+}
+
+// NotDeleted returns the condition matching Account records that aren't soft deleted, e.g. for joined or unscoped queries
+func (s _Account) NotDeleted() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{s.DeletedAt.Column()}}
+}
+
+// TableName returns the table name of Account
+func (_Account) TableName() string {
+	return "accounts"
+}
+
+// Table returns the clause.Table of Account
+func (_Account) Table() clause.Table {
+	return clause.Table{Name: "accounts"}
+}
+
+// _AccountFixtureSeq numbers the Account fixtures, keeping their strings unique
+var _AccountFixtureSeq atomic.Int64
+
+// NewAccountFixture returns a Account with default values for seeding tests, strings are unique, then applies the overrides
+func NewAccountFixture(overrides ...func(*models.Account)) models.Account {
+	seq := _AccountFixtureSeq.Add(1)
+	record := models.Account{}
+	record.Number = fmt.Sprintf("number-%d", seq)
+	for _, override := range overrides {
+		override(&record)
+	}
+	return record
+}
+
+// AccountRepository is the CRUD repository of Account, see NewAccountRepository
+type AccountRepository interface {
+	// GetByID returns the Account with the primary key id, gorm.ErrRecordNotFound if none
+	GetByID(ctx context.Context, id uint) (models.Account, error)
+	// List returns the Account records matching all conds, e.g. List(ctx, Account.ID.Gt(0))
+	List(ctx context.Context, conds ...clause.Expression) ([]models.Account, error)
+	// Create inserts the record, filling its primary key and defaults
+	Create(ctx context.Context, record *models.Account) error
+	// Update applies the assignments, e.g. field helper Set calls, to the Account with the primary key id and returns the number of updated rows
+	Update(ctx context.Context, id uint, assignments ...clause.Assigner) (int, error)
+	// Delete deletes the Account with the primary key id and returns the number of deleted rows
+	Delete(ctx context.Context, id uint) (int, error)
+}
+
+// NewAccountRepository returns the AccountRepository of db
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return _AccountRepository{db: db}
+}
+
+type _AccountRepository struct {
+	db *gorm.DB
+}
+
+func (r _AccountRepository) GetByID(ctx context.Context, id uint) (models.Account, error) {
+	return gorm.G[models.Account](r.db).Where(Account.ByID(id)).First(ctx)
+}
+
+func (r _AccountRepository) List(ctx context.Context, conds ...clause.Expression) ([]models.Account, error) {
+	return gorm.G[models.Account](r.db).Scopes(func(stmt *gorm.Statement) {
+		if len(conds) > 0 {
+			stmt.AddClause(clause.Where{Exprs: conds})
+		}
+	}).Find(ctx)
+}
+
+func (r _AccountRepository) Create(ctx context.Context, record *models.Account) error {
+	return gorm.G[models.Account](r.db).Create(ctx, record)
+}
+
+func (r _AccountRepository) Update(ctx context.Context, id uint, assignments ...clause.Assigner) (int, error) {
+	return gorm.G[models.Account](r.db).Where(Account.ByID(id)).Set(assignments...).Update(ctx)
+}
+
+func (r _AccountRepository) Delete(ctx context.Context, id uint) (int, error) {
+	return gorm.G[models.Account](r.db).Where(Account.ByID(id)).Delete(ctx)
+}
+
+var Pet = _Pet{
+	ID:         field.Number[uint]{}.WithColumn("id"),
+	CreatedAt:  field.Time{}.WithColumn("created_at"),
+	UpdatedAt:  field.Time{}.WithColumn("updated_at"),
+	DeletedAt:  field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at"),
+	UserID:     field.Null[uint]{}.WithColumn("user_id"),
+	Name:       field.String{}.WithColumn("name"),
+	Toy:        field.Struct[models.Toy]{}.WithName("Toy").WithMetadata(field.AssociationMetadata{Kind: field.HasOne, ForeignKey: "owner_id", References: "id", PolymorphicType: "owner_type", PolymorphicID: "owner_id", PolymorphicValue: "pets"}),
+	Tags:       field.JSON{}.WithColumn("tags"),
+	Attributes: field.Map[string, string]{}.WithColumn("attributes"),
+}
+
+type _Pet struct {
+	ID         field.Number[uint]
+	CreatedAt  field.Time
+	UpdatedAt  field.Time
+	DeletedAt  field.Field[gorm.DeletedAt]
+	UserID     field.Null[uint]
+	Name       field.String
+	Toy        field.Struct[models.Toy]
+	Tags       field.JSON
+	Attributes field.Map[string, string]
+}
+
+// PetAs returns the Pet field helpers qualified with the table alias, e.g. for self joins
+func PetAs(alias string) _PetAlias {
+	return _PetAlias{
+		_Pet: _Pet{
+			ID:         field.Number[uint]{}.WithColumn("id").WithTable(alias),
+			CreatedAt:  field.Time{}.WithColumn("created_at").WithTable(alias),
+			UpdatedAt:  field.Time{}.WithColumn("updated_at").WithTable(alias),
+			DeletedAt:  field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at").WithTable(alias),
+			UserID:     field.Null[uint]{}.WithColumn("user_id").WithTable(alias),
+			Name:       field.String{}.WithColumn("name").WithTable(alias),
+			Toy:        field.Struct[models.Toy]{}.WithName("Toy").WithMetadata(field.AssociationMetadata{Kind: field.HasOne, ForeignKey: "owner_id", References: "id", PolymorphicType: "owner_type", PolymorphicID: "owner_id", PolymorphicValue: "pets"}),
+			Tags:       field.JSON{}.WithColumn("tags").WithTable(alias),
+			Attributes: field.Map[string, string]{}.WithColumn("attributes").WithTable(alias),
+		},
+		Alias: alias,
+	}
+}
+
+// _PetAlias holds the Pet field helpers of an aliased table
+type _PetAlias struct {
+	_Pet
+	// Alias is the table alias, e.g. for clause.JoinTarget.As
+	Alias string
+}
+
+// Table returns the aliased clause.Table of Pet
+func (a _PetAlias) Table() clause.Table {
+	return clause.Table{Name: "pets", Alias: a.Alias}
+}
+
+// PetColumns holds the column names of Pet
+var PetColumns = struct {
+	ID         string
+	CreatedAt  string
+	UpdatedAt  string
+	DeletedAt  string
+	UserID     string
+	Name       string
+	Tags       string
+	Attributes string
 }{
+	ID:         "id",
+	CreatedAt:  "created_at",
+	UpdatedAt:  "updated_at",
+	DeletedAt:  "deleted_at",
+	UserID:     "user_id",
+	Name:       "name",
+	Tags:       "tags",
+	Attributes: "attributes",
+}
+
+// AllFields returns all column fields of Pet, e.g. Select(Pet.AllFields()...)
+func (s _Pet) AllFields() []field.Selectable {
+	return []field.Selectable{
+		s.ID,
+		s.CreatedAt,
+		s.UpdatedAt,
+		s.DeletedAt,
+		s.UserID,
+		s.Name,
+		s.Tags,
+		s.Attributes,
+	}
+}
+
+// AllColumns returns all columns of Pet
+func (s _Pet) AllColumns() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+		s.CreatedAt.Column(),
+		s.UpdatedAt.Column(),
+		s.DeletedAt.Column(),
+		s.UserID.Column(),
+		s.Name.Column(),
+		s.Tags.Column(),
+		s.Attributes.Column(),
+	}
+}
+
+// PrimaryKey returns the primary key columns of Pet
+func (s _Pet) PrimaryKey() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// ByID returns the condition matching the primary key of Pet, e.g. Where(Pet.ByID(id))
+func (s _Pet) ByID(id uint) clause.Expression {
+	return s.ID.Eq(id)
+}
+
+// WithDeleted returns a scope including soft deleted Pet records, e.g. Scopes(Pet.WithDeleted())
+func (_Pet) WithDeleted() func(*gorm.Statement) {
+	return func(stmt *gorm.Statement) {
+		stmt.Unscoped = true
+	}
+}
+
+// OnlyDeleted returns a scope matching only soft deleted Pet records, e.g. Scopes(Pet.OnlyDeleted())
+func (s _Pet) OnlyDeleted() func(*gorm.Statement) {
+	return func(stmt *gorm.Statement) {
+		stmt.Unscoped = true
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "? IS NOT NULL", Vars: []any{s.DeletedAt.Column()}},
+		}})
+	}
+}
+
+// NotDeleted returns the condition matching Pet records that aren't soft deleted, e.g. for joined or unscoped queries
+func (s _Pet) NotDeleted() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{s.DeletedAt.Column()}}
+}
+
+// TableName returns the table name of Pet
+func (_Pet) TableName() string {
+	return "pets"
+}
+
+// Table returns the clause.Table of Pet
+func (_Pet) Table() clause.Table {
+	return clause.Table{Name: "pets"}
+}
+
+// _PetFixtureSeq numbers the Pet fixtures, keeping their strings unique
+var _PetFixtureSeq atomic.Int64
+
+// NewPetFixture returns a Pet with default values for seeding tests, strings are unique, then applies the overrides
+func NewPetFixture(overrides ...func(*models.Pet)) models.Pet {
+	seq := _PetFixtureSeq.Add(1)
+	record := models.Pet{}
+	record.Name = fmt.Sprintf("name-%d", seq)
+	for _, override := range overrides {
+		override(&record)
+	}
+	return record
+}
+
+// PetRepository is the CRUD repository of Pet, see NewPetRepository
+type PetRepository interface {
+	// GetByID returns the Pet with the primary key id, gorm.ErrRecordNotFound if none
+	GetByID(ctx context.Context, id uint) (models.Pet, error)
+	// List returns the Pet records matching all conds, e.g. List(ctx, Pet.ID.Gt(0))
+	List(ctx context.Context, conds ...clause.Expression) ([]models.Pet, error)
+	// Create inserts the record, filling its primary key and defaults
+	Create(ctx context.Context, record *models.Pet) error
+	// Update applies the assignments, e.g. field helper Set calls, to the Pet with the primary key id and returns the number of updated rows
+	Update(ctx context.Context, id uint, assignments ...clause.Assigner) (int, error)
+	// Delete deletes the Pet with the primary key id and returns the number of deleted rows
+	Delete(ctx context.Context, id uint) (int, error)
+}
+
+// NewPetRepository returns the PetRepository of db
+func NewPetRepository(db *gorm.DB) PetRepository {
+	return _PetRepository{db: db}
+}
+
+type _PetRepository struct {
+	db *gorm.DB
+}
+
+func (r _PetRepository) GetByID(ctx context.Context, id uint) (models.Pet, error) {
+	return gorm.G[models.Pet](r.db).Where(Pet.ByID(id)).First(ctx)
+}
+
+func (r _PetRepository) List(ctx context.Context, conds ...clause.Expression) ([]models.Pet, error) {
+	return gorm.G[models.Pet](r.db).Scopes(func(stmt *gorm.Statement) {
+		if len(conds) > 0 {
+			stmt.AddClause(clause.Where{Exprs: conds})
+		}
+	}).Find(ctx)
+}
+
+func (r _PetRepository) Create(ctx context.Context, record *models.Pet) error {
+	return gorm.G[models.Pet](r.db).Create(ctx, record)
+}
+
+func (r _PetRepository) Update(ctx context.Context, id uint, assignments ...clause.Assigner) (int, error) {
+	return gorm.G[models.Pet](r.db).Where(Pet.ByID(id)).Set(assignments...).Update(ctx)
+}
+
+func (r _PetRepository) Delete(ctx context.Context, id uint) (int, error) {
+	return gorm.G[models.Pet](r.db).Where(Pet.ByID(id)).Delete(ctx)
+}
+
+var Toy = _Toy{
 	ID:        field.Number[uint]{}.WithColumn("id"),
 	CreatedAt: field.Time{}.WithColumn("created_at"),
 	UpdatedAt: field.Time{}.WithColumn("updated_at"),
 	DeletedAt: field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at"),
-	UserID:    field.Number[uint]{}.WithColumn("user_id"),
 	Name:      field.String{}.WithColumn("name"),
-	Toy:       field.Struct[models.Toy]{}.WithName("Toy"),
+	OwnerID:   field.Number[uint]{}.WithColumn("owner_id"),
+	OwnerType: field.String{}.WithColumn("owner_type"),
+	CreatedBy: field.CreateOnly[string]{}.WithColumn("created_by"),
+	Code:      field.ReadOnly[string]{}.WithColumn("code"),
 }
 
-var Toy = struct {
+type _Toy struct {
 	ID        field.Number[uint]
 	CreatedAt field.Time
 	UpdatedAt field.Time
@@ -105,42 +739,784 @@ var Toy = struct {
 	Name      field.String
 	OwnerID   field.Number[uint]
 	OwnerType field.String
+	CreatedBy field.CreateOnly[string]
+	Code      field.ReadOnly[string]
+}
+
+// ToyAs returns the Toy field helpers qualified with the table alias, e.g. for self joins
+func ToyAs(alias string) _ToyAlias {
+	return _ToyAlias{
+		_Toy: _Toy{
+			ID:        field.Number[uint]{}.WithColumn("id").WithTable(alias),
+			CreatedAt: field.Time{}.WithColumn("created_at").WithTable(alias),
+			UpdatedAt: field.Time{}.WithColumn("updated_at").WithTable(alias),
+			DeletedAt: field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at").WithTable(alias),
+			Name:      field.String{}.WithColumn("name").WithTable(alias),
+			OwnerID:   field.Number[uint]{}.WithColumn("owner_id").WithTable(alias),
+			OwnerType: field.String{}.WithColumn("owner_type").WithTable(alias),
+			CreatedBy: field.CreateOnly[string]{}.WithColumn("created_by").WithTable(alias),
+			Code:      field.ReadOnly[string]{}.WithColumn("code").WithTable(alias),
+		},
+		Alias: alias,
+	}
+}
+
+// _ToyAlias holds the Toy field helpers of an aliased table
+type _ToyAlias struct {
+	_Toy
+	// Alias is the table alias, e.g. for clause.JoinTarget.As
+	Alias string
+}
+
+// Table returns the aliased clause.Table of Toy
+func (a _ToyAlias) Table() clause.Table {
+	return clause.Table{Name: "toys", Alias: a.Alias}
+}
+
+// ToyColumns holds the column names of Toy
+var ToyColumns = struct {
+	ID        string
+	CreatedAt string
+	UpdatedAt string
+	DeletedAt string
+	Name      string
+	OwnerID   string
+	OwnerType string
+	CreatedBy string
+	Code      string
 }{
-	ID:        field.Number[uint]{}.WithColumn("id"),
-	CreatedAt: field.Time{}.WithColumn("created_at"),
-	UpdatedAt: field.Time{}.WithColumn("updated_at"),
-	DeletedAt: field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at"),
-	Name:      field.String{}.WithColumn("name"),
-	OwnerID:   field.Number[uint]{}.WithColumn("owner_id"),
-	OwnerType: field.String{}.WithColumn("owner_type"),
+	ID:        "id",
+	CreatedAt: "created_at",
+	UpdatedAt: "updated_at",
+	DeletedAt: "deleted_at",
+	Name:      "name",
+	OwnerID:   "owner_id",
+	OwnerType: "owner_type",
+	CreatedBy: "created_by",
+	Code:      "code",
 }
 
-var Company = struct {
-	ID   field.Number[int]
-	Name field.String
+// AllFields returns all column fields of Toy, e.g. Select(Toy.AllFields()...)
+func (s _Toy) AllFields() []field.Selectable {
+	return []field.Selectable{
+		s.ID,
+		s.CreatedAt,
+		s.UpdatedAt,
+		s.DeletedAt,
+		s.Name,
+		s.OwnerID,
+		s.OwnerType,
+		s.CreatedBy,
+		s.Code,
+	}
+}
+
+// AllColumns returns all columns of Toy
+func (s _Toy) AllColumns() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+		s.CreatedAt.Column(),
+		s.UpdatedAt.Column(),
+		s.DeletedAt.Column(),
+		s.Name.Column(),
+		s.OwnerID.Column(),
+		s.OwnerType.Column(),
+		s.CreatedBy.Column(),
+		s.Code.Column(),
+	}
+}
+
+// PrimaryKey returns the primary key columns of Toy
+func (s _Toy) PrimaryKey() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// ByID returns the condition matching the primary key of Toy, e.g. Where(Toy.ByID(id))
+func (s _Toy) ByID(id uint) clause.Expression {
+	return s.ID.Eq(id)
+}
+
+// WithDeleted returns a scope including soft deleted Toy records, e.g. Scopes(Toy.WithDeleted())
+func (_Toy) WithDeleted() func(*gorm.Statement) {
+	return func(stmt *gorm.Statement) {
+		stmt.Unscoped = true
+	}
+}
+
+// OnlyDeleted returns a scope matching only soft deleted Toy records, e.g. Scopes(Toy.OnlyDeleted())
+func (s _Toy) OnlyDeleted() func(*gorm.Statement) {
+	return func(stmt *gorm.Statement) {
+		stmt.Unscoped = true
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "? IS NOT NULL", Vars: []any{s.DeletedAt.Column()}},
+		}})
+	}
+}
+
+// NotDeleted returns the condition matching Toy records that aren't soft deleted, e.g. for joined or unscoped queries
+func (s _Toy) NotDeleted() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{s.DeletedAt.Column()}}
+}
+
+// TableName returns the table name of Toy
+func (_Toy) TableName() string {
+	return "toys"
+}
+
+// Table returns the clause.Table of Toy
+func (_Toy) Table() clause.Table {
+	return clause.Table{Name: "toys"}
+}
+
+// _ToyFixtureSeq numbers the Toy fixtures, keeping their strings unique
+var _ToyFixtureSeq atomic.Int64
+
+// NewToyFixture returns a Toy with default values for seeding tests, strings are unique, then applies the overrides
+func NewToyFixture(overrides ...func(*models.Toy)) models.Toy {
+	seq := _ToyFixtureSeq.Add(1)
+	record := models.Toy{}
+	record.Name = fmt.Sprintf("name-%d", seq)
+	record.OwnerType = fmt.Sprintf("owner_type-%d", seq)
+	record.CreatedBy = fmt.Sprintf("created_by-%d", seq)
+	for _, override := range overrides {
+		override(&record)
+	}
+	return record
+}
+
+// ToyRepository is the CRUD repository of Toy, see NewToyRepository
+type ToyRepository interface {
+	// GetByID returns the Toy with the primary key id, gorm.ErrRecordNotFound if none
+	GetByID(ctx context.Context, id uint) (models.Toy, error)
+	// List returns the Toy records matching all conds, e.g. List(ctx, Toy.ID.Gt(0))
+	List(ctx context.Context, conds ...clause.Expression) ([]models.Toy, error)
+	// Create inserts the record, filling its primary key and defaults
+	Create(ctx context.Context, record *models.Toy) error
+	// Update applies the assignments, e.g. field helper Set calls, to the Toy with the primary key id and returns the number of updated rows
+	Update(ctx context.Context, id uint, assignments ...clause.Assigner) (int, error)
+	// Delete deletes the Toy with the primary key id and returns the number of deleted rows
+	Delete(ctx context.Context, id uint) (int, error)
+}
+
+// NewToyRepository returns the ToyRepository of db
+func NewToyRepository(db *gorm.DB) ToyRepository {
+	return _ToyRepository{db: db}
+}
+
+type _ToyRepository struct {
+	db *gorm.DB
+}
+
+func (r _ToyRepository) GetByID(ctx context.Context, id uint) (models.Toy, error) {
+	return gorm.G[models.Toy](r.db).Where(Toy.ByID(id)).First(ctx)
+}
+
+func (r _ToyRepository) List(ctx context.Context, conds ...clause.Expression) ([]models.Toy, error) {
+	return gorm.G[models.Toy](r.db).Scopes(func(stmt *gorm.Statement) {
+		if len(conds) > 0 {
+			stmt.AddClause(clause.Where{Exprs: conds})
+		}
+	}).Find(ctx)
+}
+
+func (r _ToyRepository) Create(ctx context.Context, record *models.Toy) error {
+	return gorm.G[models.Toy](r.db).Create(ctx, record)
+}
+
+func (r _ToyRepository) Update(ctx context.Context, id uint, assignments ...clause.Assigner) (int, error) {
+	return gorm.G[models.Toy](r.db).Where(Toy.ByID(id)).Set(assignments...).Update(ctx)
+}
+
+func (r _ToyRepository) Delete(ctx context.Context, id uint) (int, error) {
+	return gorm.G[models.Toy](r.db).Where(Toy.ByID(id)).Delete(ctx)
+}
+
+var Company = _Company{
+	ID:            field.Number[int]{}.WithColumn("id"),
+	Name:          field.String{}.WithColumn("name"),
+	Version:       field.Version[int]{}.WithColumn("version"),
+	UniqueIndexes: _CompanyUniqueIndexes{Name: field.UniqueIndex{}.WithName("idx_companies_name").WithColumns("name")},
+}
+
+type _Company struct {
+	ID      field.Number[int]
+	Name    field.String
+	Version field.Version[int]
+
+	// UniqueIndexes holds the unique indexes and constraints of Company, e.g. conflict targets of upserts
+	UniqueIndexes _CompanyUniqueIndexes
+}
+
+type _CompanyUniqueIndexes struct {
+	// Name is the unique index idx_companies_name
+	Name field.UniqueIndex
+}
+
+// CompanyAs returns the Company field helpers qualified with the table alias, e.g. for self joins
+func CompanyAs(alias string) _CompanyAlias {
+	return _CompanyAlias{
+		_Company: _Company{
+			ID:            field.Number[int]{}.WithColumn("id").WithTable(alias),
+			Name:          field.String{}.WithColumn("name").WithTable(alias),
+			Version:       field.Version[int]{}.WithColumn("version").WithTable(alias),
+			UniqueIndexes: _CompanyUniqueIndexes{Name: field.UniqueIndex{}.WithName("idx_companies_name").WithColumns("name")},
+		},
+		Alias: alias,
+	}
+}
+
+// _CompanyAlias holds the Company field helpers of an aliased table
+type _CompanyAlias struct {
+	_Company
+	// Alias is the table alias, e.g. for clause.JoinTarget.As
+	Alias string
+}
+
+// Table returns the aliased clause.Table of Company
+func (a _CompanyAlias) Table() clause.Table {
+	return clause.Table{Name: "companies", Alias: a.Alias}
+}
+
+// CompanyColumns holds the column names of Company
+var CompanyColumns = struct {
+	ID      string
+	Name    string
+	Version string
 }{
-	ID:   field.Number[int]{}.WithColumn("id"),
+	ID:      "id",
+	Name:    "name",
+	Version: "version",
+}
+
+// AllFields returns all column fields of Company, e.g. Select(Company.AllFields()...)
+func (s _Company) AllFields() []field.Selectable {
+	return []field.Selectable{
+		s.ID,
+		s.Name,
+		s.Version,
+	}
+}
+
+// AllColumns returns all columns of Company
+func (s _Company) AllColumns() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+		s.Name.Column(),
+		s.Version.Column(),
+	}
+}
+
+// PrimaryKey returns the primary key columns of Company
+func (s _Company) PrimaryKey() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// ByID returns the condition matching the primary key of Company, e.g. Where(Company.ByID(id))
+func (s _Company) ByID(id int) clause.Expression {
+	return s.ID.Eq(id)
+}
+
+// Schemas returns the column schemas declared in the gorm tags of Company by column name
+func (_Company) Schemas() map[string]field.ColumnSchema {
+	return map[string]field.ColumnSchema{
+		"name": {Size: 64},
+	}
+}
+
+// TableName returns the table name of Company
+func (_Company) TableName() string {
+	return "companies"
+}
+
+// Table returns the clause.Table of Company
+func (_Company) Table() clause.Table {
+	return clause.Table{Name: "companies"}
+}
+
+// _CompanyFixtureSeq numbers the Company fixtures, keeping their strings unique
+var _CompanyFixtureSeq atomic.Int64
+
+// NewCompanyFixture returns a Company with default values for seeding tests, strings are unique, then applies the overrides
+func NewCompanyFixture(overrides ...func(*models.Company)) models.Company {
+	seq := _CompanyFixtureSeq.Add(1)
+	record := models.Company{}
+	record.Name = fmt.Sprintf("name-%d", seq)
+	for _, override := range overrides {
+		override(&record)
+	}
+	return record
+}
+
+// CompanyRepository is the CRUD repository of Company, see NewCompanyRepository
+type CompanyRepository interface {
+	// GetByID returns the Company with the primary key id, gorm.ErrRecordNotFound if none
+	GetByID(ctx context.Context, id int) (models.Company, error)
+	// List returns the Company records matching all conds, e.g. List(ctx, Company.ID.Gt(0))
+	List(ctx context.Context, conds ...clause.Expression) ([]models.Company, error)
+	// Create inserts the record, filling its primary key and defaults
+	Create(ctx context.Context, record *models.Company) error
+	// Update applies the assignments, e.g. field helper Set calls, to the Company with the primary key id and returns the number of updated rows
+	Update(ctx context.Context, id int, assignments ...clause.Assigner) (int, error)
+	// Delete deletes the Company with the primary key id and returns the number of deleted rows
+	Delete(ctx context.Context, id int) (int, error)
+}
+
+// NewCompanyRepository returns the CompanyRepository of db
+func NewCompanyRepository(db *gorm.DB) CompanyRepository {
+	return _CompanyRepository{db: db}
+}
+
+type _CompanyRepository struct {
+	db *gorm.DB
+}
+
+func (r _CompanyRepository) GetByID(ctx context.Context, id int) (models.Company, error) {
+	return gorm.G[models.Company](r.db).Where(Company.ByID(id)).First(ctx)
+}
+
+func (r _CompanyRepository) List(ctx context.Context, conds ...clause.Expression) ([]models.Company, error) {
+	return gorm.G[models.Company](r.db).Scopes(func(stmt *gorm.Statement) {
+		if len(conds) > 0 {
+			stmt.AddClause(clause.Where{Exprs: conds})
+		}
+	}).Find(ctx)
+}
+
+func (r _CompanyRepository) Create(ctx context.Context, record *models.Company) error {
+	return gorm.G[models.Company](r.db).Create(ctx, record)
+}
+
+func (r _CompanyRepository) Update(ctx context.Context, id int, assignments ...clause.Assigner) (int, error) {
+	return gorm.G[models.Company](r.db).Where(Company.ByID(id)).Set(assignments...).Update(ctx)
+}
+
+func (r _CompanyRepository) Delete(ctx context.Context, id int) (int, error) {
+	return gorm.G[models.Company](r.db).Where(Company.ByID(id)).Delete(ctx)
+}
+
+var Language = _Language{
+	Code: field.String{}.WithColumn("code"),
 	Name: field.String{}.WithColumn("name"),
 }
 
-var Language = struct {
+type _Language struct {
 	Code field.String
 	Name field.String
+}
+
+// LanguageAs returns the Language field helpers qualified with the table alias, e.g. for self joins
+func LanguageAs(alias string) _LanguageAlias {
+	return _LanguageAlias{
+		_Language: _Language{
+			Code: field.String{}.WithColumn("code").WithTable(alias),
+			Name: field.String{}.WithColumn("name").WithTable(alias),
+		},
+		Alias: alias,
+	}
+}
+
+// _LanguageAlias holds the Language field helpers of an aliased table
+type _LanguageAlias struct {
+	_Language
+	// Alias is the table alias, e.g. for clause.JoinTarget.As
+	Alias string
+}
+
+// Table returns the aliased clause.Table of Language
+func (a _LanguageAlias) Table() clause.Table {
+	return clause.Table{Name: "languages", Alias: a.Alias}
+}
+
+// LanguageColumns holds the column names of Language
+var LanguageColumns = struct {
+	Code string
+	Name string
 }{
-	Code: field.String{}.WithColumn("code"),
-	Name: field.String{}.WithColumn("name"),
+	Code: "code",
+	Name: "name",
 }
 
-var CreditCard = struct {
-	ID        field.Number[uint]
-	CreatedAt field.Time
-	UpdatedAt field.Time
-	DeletedAt field.Field[gorm.DeletedAt]
-	Number    field.String
+// AllFields returns all column fields of Language, e.g. Select(Language.AllFields()...)
+func (s _Language) AllFields() []field.Selectable {
+	return []field.Selectable{
+		s.Code,
+		s.Name,
+	}
+}
+
+// AllColumns returns all columns of Language
+func (s _Language) AllColumns() []clause.Column {
+	return []clause.Column{
+		s.Code.Column(),
+		s.Name.Column(),
+	}
+}
+
+// PrimaryKey returns the primary key columns of Language
+func (s _Language) PrimaryKey() []clause.Column {
+	return []clause.Column{
+		s.Code.Column(),
+	}
+}
+
+// ByID returns the condition matching the primary key of Language, e.g. Where(Language.ByID(id))
+func (s _Language) ByID(id string) clause.Expression {
+	return s.Code.Eq(id)
+}
+
+// TableName returns the table name of Language
+func (_Language) TableName() string {
+	return "languages"
+}
+
+// Table returns the clause.Table of Language
+func (_Language) Table() clause.Table {
+	return clause.Table{Name: "languages"}
+}
+
+// _LanguageFixtureSeq numbers the Language fixtures, keeping their strings unique
+var _LanguageFixtureSeq atomic.Int64
+
+// NewLanguageFixture returns a Language with default values for seeding tests, strings are unique, then applies the overrides
+func NewLanguageFixture(overrides ...func(*models.Language)) models.Language {
+	seq := _LanguageFixtureSeq.Add(1)
+	record := models.Language{}
+	record.Name = fmt.Sprintf("name-%d", seq)
+	for _, override := range overrides {
+		override(&record)
+	}
+	return record
+}
+
+// LanguageRepository is the CRUD repository of Language, see NewLanguageRepository
+type LanguageRepository interface {
+	// GetByID returns the Language with the primary key id, gorm.ErrRecordNotFound if none
+	GetByID(ctx context.Context, id string) (models.Language, error)
+	// List returns the Language records matching all conds, e.g. List(ctx, Language.Code.Gt(0))
+	List(ctx context.Context, conds ...clause.Expression) ([]models.Language, error)
+	// Create inserts the record, filling its primary key and defaults
+	Create(ctx context.Context, record *models.Language) error
+	// Update applies the assignments, e.g. field helper Set calls, to the Language with the primary key id and returns the number of updated rows
+	Update(ctx context.Context, id string, assignments ...clause.Assigner) (int, error)
+	// Delete deletes the Language with the primary key id and returns the number of deleted rows
+	Delete(ctx context.Context, id string) (int, error)
+}
+
+// NewLanguageRepository returns the LanguageRepository of db
+func NewLanguageRepository(db *gorm.DB) LanguageRepository {
+	return _LanguageRepository{db: db}
+}
+
+type _LanguageRepository struct {
+	db *gorm.DB
+}
+
+func (r _LanguageRepository) GetByID(ctx context.Context, id string) (models.Language, error) {
+	return gorm.G[models.Language](r.db).Where(Language.ByID(id)).First(ctx)
+}
+
+func (r _LanguageRepository) List(ctx context.Context, conds ...clause.Expression) ([]models.Language, error) {
+	return gorm.G[models.Language](r.db).Scopes(func(stmt *gorm.Statement) {
+		if len(conds) > 0 {
+			stmt.AddClause(clause.Where{Exprs: conds})
+		}
+	}).Find(ctx)
+}
+
+func (r _LanguageRepository) Create(ctx context.Context, record *models.Language) error {
+	return gorm.G[models.Language](r.db).Create(ctx, record)
+}
+
+func (r _LanguageRepository) Update(ctx context.Context, id string, assignments ...clause.Assigner) (int, error) {
+	return gorm.G[models.Language](r.db).Where(Language.ByID(id)).Set(assignments...).Update(ctx)
+}
+
+func (r _LanguageRepository) Delete(ctx context.Context, id string) (int, error) {
+	return gorm.G[models.Language](r.db).Where(Language.ByID(id)).Delete(ctx)
+}
+
+// AdultUser lists the adult users, it's a database view
+var AdultUser = _AdultUser{
+	ID:   field.ReadOnly[uint]{}.WithColumn("id"),
+	Name: field.ReadOnly[string]{}.WithColumn("name"),
+	Age:  field.ReadOnly[int]{}.WithColumn("age"),
+}
+
+type _AdultUser struct {
+	ID   field.ReadOnly[uint]
+	Name field.ReadOnly[string]
+	Age  field.ReadOnly[int]
+}
+
+// AdultUserAs returns the AdultUser field helpers qualified with the table alias, e.g. for self joins
+func AdultUserAs(alias string) _AdultUserAlias {
+	return _AdultUserAlias{
+		_AdultUser: _AdultUser{
+			ID:   field.ReadOnly[uint]{}.WithColumn("id").WithTable(alias),
+			Name: field.ReadOnly[string]{}.WithColumn("name").WithTable(alias),
+			Age:  field.ReadOnly[int]{}.WithColumn("age").WithTable(alias),
+		},
+		Alias: alias,
+	}
+}
+
+// _AdultUserAlias holds the AdultUser field helpers of an aliased table
+type _AdultUserAlias struct {
+	_AdultUser
+	// Alias is the table alias, e.g. for clause.JoinTarget.As
+	Alias string
+}
+
+// Table returns the aliased clause.Table of AdultUser
+func (a _AdultUserAlias) Table() clause.Table {
+	return clause.Table{Name: "adult_users", Alias: a.Alias}
+}
+
+// AdultUserColumns holds the column names of AdultUser
+var AdultUserColumns = struct {
+	ID   string
+	Name string
+	Age  string
 }{
+	ID:   "id",
+	Name: "name",
+	Age:  "age",
+}
+
+// AllFields returns all column fields of AdultUser, e.g. Select(AdultUser.AllFields()...)
+func (s _AdultUser) AllFields() []field.Selectable {
+	return []field.Selectable{
+		s.ID,
+		s.Name,
+		s.Age,
+	}
+}
+
+// AllColumns returns all columns of AdultUser
+func (s _AdultUser) AllColumns() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+		s.Name.Column(),
+		s.Age.Column(),
+	}
+}
+
+// PrimaryKey returns the primary key columns of AdultUser
+func (s _AdultUser) PrimaryKey() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// ByID returns the condition matching the primary key of AdultUser, e.g. Where(AdultUser.ByID(id))
+func (s _AdultUser) ByID(id uint) clause.Expression {
+	return s.ID.Eq(id)
+}
+
+// TableName returns the table name of AdultUser
+func (_AdultUser) TableName() string {
+	return "adult_users"
+}
+
+// Table returns the clause.Table of AdultUser
+func (_AdultUser) Table() clause.Table {
+	return clause.Table{Name: "adult_users"}
+}
+
+// ViewName returns the view name of AdultUser
+func (_AdultUser) ViewName() string {
+	return "adult_users"
+}
+
+// CreateViewSQL returns the CREATE VIEW statement of AdultUser, e.g. for migrations
+func (_AdultUser) CreateViewSQL() string {
+	return "CREATE VIEW adult_users AS SELECT id, name, age FROM users WHERE is_adult = true AND deleted_at IS NULL"
+}
+
+var CreditCard = _CreditCard{
 	ID:        field.Number[uint]{}.WithColumn("id"),
 	CreatedAt: field.Time{}.WithColumn("created_at"),
 	UpdatedAt: field.Time{}.WithColumn("updated_at"),
 	DeletedAt: field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at"),
 	Number:    field.String{}.WithColumn("number"),
 }
+
+type _CreditCard struct {
+	ID        field.Number[uint]
+	CreatedAt field.Time
+	UpdatedAt field.Time
+	DeletedAt field.Field[gorm.DeletedAt]
+	Number    field.String
+}
+
+// CreditCardAs returns the CreditCard field helpers qualified with the table alias, e.g. for self joins
+func CreditCardAs(alias string) _CreditCardAlias {
+	return _CreditCardAlias{
+		_CreditCard: _CreditCard{
+			ID:        field.Number[uint]{}.WithColumn("id").WithTable(alias),
+			CreatedAt: field.Time{}.WithColumn("created_at").WithTable(alias),
+			UpdatedAt: field.Time{}.WithColumn("updated_at").WithTable(alias),
+			DeletedAt: field.Field[gorm.DeletedAt]{}.WithColumn("deleted_at").WithTable(alias),
+			Number:    field.String{}.WithColumn("number").WithTable(alias),
+		},
+		Alias: alias,
+	}
+}
+
+// _CreditCardAlias holds the CreditCard field helpers of an aliased table
+type _CreditCardAlias struct {
+	_CreditCard
+	// Alias is the table alias, e.g. for clause.JoinTarget.As
+	Alias string
+}
+
+// Table returns the aliased clause.Table of CreditCard
+func (a _CreditCardAlias) Table() clause.Table {
+	return clause.Table{Name: "credit_cards", Alias: a.Alias}
+}
+
+// CreditCardColumns holds the column names of CreditCard
+var CreditCardColumns = struct {
+	ID        string
+	CreatedAt string
+	UpdatedAt string
+	DeletedAt string
+	Number    string
+}{
+	ID:        "id",
+	CreatedAt: "created_at",
+	UpdatedAt: "updated_at",
+	DeletedAt: "deleted_at",
+	Number:    "number",
+}
+
+// AllFields returns all column fields of CreditCard, e.g. Select(CreditCard.AllFields()...)
+func (s _CreditCard) AllFields() []field.Selectable {
+	return []field.Selectable{
+		s.ID,
+		s.CreatedAt,
+		s.UpdatedAt,
+		s.DeletedAt,
+		s.Number,
+	}
+}
+
+// AllColumns returns all columns of CreditCard
+func (s _CreditCard) AllColumns() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+		s.CreatedAt.Column(),
+		s.UpdatedAt.Column(),
+		s.DeletedAt.Column(),
+		s.Number.Column(),
+	}
+}
+
+// PrimaryKey returns the primary key columns of CreditCard
+func (s _CreditCard) PrimaryKey() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// ByID returns the condition matching the primary key of CreditCard, e.g. Where(CreditCard.ByID(id))
+func (s _CreditCard) ByID(id uint) clause.Expression {
+	return s.ID.Eq(id)
+}
+
+// WithDeleted returns a scope including soft deleted CreditCard records, e.g. Scopes(CreditCard.WithDeleted())
+func (_CreditCard) WithDeleted() func(*gorm.Statement) {
+	return func(stmt *gorm.Statement) {
+		stmt.Unscoped = true
+	}
+}
+
+// OnlyDeleted returns a scope matching only soft deleted CreditCard records, e.g. Scopes(CreditCard.OnlyDeleted())
+func (s _CreditCard) OnlyDeleted() func(*gorm.Statement) {
+	return func(stmt *gorm.Statement) {
+		stmt.Unscoped = true
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "? IS NOT NULL", Vars: []any{s.DeletedAt.Column()}},
+		}})
+	}
+}
+
+// NotDeleted returns the condition matching CreditCard records that aren't soft deleted, e.g. for joined or unscoped queries
+func (s _CreditCard) NotDeleted() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{s.DeletedAt.Column()}}
+}
+
+// TableName returns the table name of CreditCard
+func (_CreditCard) TableName() string {
+	return "credit_cards"
+}
+
+// Table returns the clause.Table of CreditCard
+func (_CreditCard) Table() clause.Table {
+	return clause.Table{Name: "credit_cards"}
+}
+
+// _CreditCardFixtureSeq numbers the CreditCard fixtures, keeping their strings unique
+var _CreditCardFixtureSeq atomic.Int64
+
+// NewCreditCardFixture returns a CreditCard with default values for seeding tests, strings are unique, then applies the overrides
+func NewCreditCardFixture(overrides ...func(*models.CreditCard)) models.CreditCard {
+	seq := _CreditCardFixtureSeq.Add(1)
+	record := models.CreditCard{}
+	record.Number = fmt.Sprintf("number-%d", seq)
+	for _, override := range overrides {
+		override(&record)
+	}
+	return record
+}
+
+// CreditCardRepository is the CRUD repository of CreditCard, see NewCreditCardRepository
+type CreditCardRepository interface {
+	// GetByID returns the CreditCard with the primary key id, gorm.ErrRecordNotFound if none
+	GetByID(ctx context.Context, id uint) (models.CreditCard, error)
+	// List returns the CreditCard records matching all conds, e.g. List(ctx, CreditCard.ID.Gt(0))
+	List(ctx context.Context, conds ...clause.Expression) ([]models.CreditCard, error)
+	// Create inserts the record, filling its primary key and defaults
+	Create(ctx context.Context, record *models.CreditCard) error
+	// Update applies the assignments, e.g. field helper Set calls, to the CreditCard with the primary key id and returns the number of updated rows
+	Update(ctx context.Context, id uint, assignments ...clause.Assigner) (int, error)
+	// Delete deletes the CreditCard with the primary key id and returns the number of deleted rows
+	Delete(ctx context.Context, id uint) (int, error)
+}
+
+// NewCreditCardRepository returns the CreditCardRepository of db
+func NewCreditCardRepository(db *gorm.DB) CreditCardRepository {
+	return _CreditCardRepository{db: db}
+}
+
+type _CreditCardRepository struct {
+	db *gorm.DB
+}
+
+func (r _CreditCardRepository) GetByID(ctx context.Context, id uint) (models.CreditCard, error) {
+	return gorm.G[models.CreditCard](r.db).Where(CreditCard.ByID(id)).First(ctx)
+}
+
+func (r _CreditCardRepository) List(ctx context.Context, conds ...clause.Expression) ([]models.CreditCard, error) {
+	return gorm.G[models.CreditCard](r.db).Scopes(func(stmt *gorm.Statement) {
+		if len(conds) > 0 {
+			stmt.AddClause(clause.Where{Exprs: conds})
+		}
+	}).Find(ctx)
+}
+
+func (r _CreditCardRepository) Create(ctx context.Context, record *models.CreditCard) error {
+	return gorm.G[models.CreditCard](r.db).Create(ctx, record)
+}
+
+func (r _CreditCardRepository) Update(ctx context.Context, id uint, assignments ...clause.Assigner) (int, error) {
+	return gorm.G[models.CreditCard](r.db).Where(CreditCard.ByID(id)).Set(assignments...).Update(ctx)
+}
+
+func (r _CreditCardRepository) Delete(ctx context.Context, id uint) (int, error) {
+	return gorm.G[models.CreditCard](r.db).Where(CreditCard.ByID(id)).Delete(ctx)
+}
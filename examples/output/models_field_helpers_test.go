@@ -3,11 +3,13 @@ package examples
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"net"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
-	"gorm.io/cli/gorm/examples"
 	"gorm.io/cli/gorm/examples/models"
 	generated "gorm.io/cli/gorm/examples/output/models"
 	"gorm.io/cli/gorm/field"
@@ -344,14 +346,14 @@ func TestGeneratedModels_FieldTypes(t *testing.T) {
 		_ field.Field[gorm.DeletedAt] = generated.User.DeletedAt
 		_ field.String                = generated.User.Name
 		_ field.Number[int]           = generated.User.Age
-		_ field.Time                  = generated.User.Birthday
+		_ field.Null[time.Time]       = generated.User.Birthday
 		_ field.Field[sql.NullInt64]  = generated.User.Score
-		_ field.Time                  = generated.User.LastLogin
-		_ field.Number[int]           = generated.User.CompanyID
-		_ field.Number[uint]          = generated.User.ManagerID
-		_ field.String                = generated.User.Role
+		_ field.Null[time.Time]       = generated.User.LastLogin
+		_ field.Null[int]             = generated.User.CompanyID
+		_ field.Null[uint]            = generated.User.ManagerID
+		_ field.Enum[models.Role]     = generated.User.Role
 		_ field.Bool                  = generated.User.IsAdult
-		_ examples.JSON               = generated.User.Profile
+		_ field.JSON                  = generated.User.Profile
 
 		// Associations
 		_ field.Struct[models.Account] = generated.User.Account
@@ -376,9 +378,10 @@ func TestGeneratedModels_FieldTypes(t *testing.T) {
 		_ field.Time                  = generated.Pet.CreatedAt
 		_ field.Time                  = generated.Pet.UpdatedAt
 		_ field.Field[gorm.DeletedAt] = generated.Pet.DeletedAt
-		_ field.Number[uint]          = generated.Pet.UserID
+		_ field.Null[uint]            = generated.Pet.UserID
 		_ field.String                = generated.Pet.Name
 		_ field.Struct[models.Toy]    = generated.Pet.Toy
+		_ field.Map[string, string]   = generated.Pet.Attributes
 
 		// Toy
 		_ field.Number[uint]          = generated.Toy.ID
@@ -388,10 +391,13 @@ func TestGeneratedModels_FieldTypes(t *testing.T) {
 		_ field.String                = generated.Toy.Name
 		_ field.Number[uint]          = generated.Toy.OwnerID
 		_ field.String                = generated.Toy.OwnerType
+		_ field.CreateOnly[string]    = generated.Toy.CreatedBy
+		_ field.ReadOnly[string]      = generated.Toy.Code
 
 		// Company
-		_ field.Number[int] = generated.Company.ID
-		_ field.String      = generated.Company.Name
+		_ field.Number[int]  = generated.Company.ID
+		_ field.String       = generated.Company.Name
+		_ field.Version[int] = generated.Company.Version
 
 		// Language
 		_ field.String = generated.Language.Code
@@ -594,34 +600,18 @@ func TestFieldHelpers_NullTypes_DBChecks(t *testing.T) {
 	}
 }
 
-// SQLite JSON1 extension compatibility test: filter users by JSON attribute in Profile.
-func TestCustomFieldAsJSON(t *testing.T) {
+// SQLite JSON1 extension compatibility test: filter users by JSON attributes in Profile.
+func TestJSONField(t *testing.T) {
 	db := setupTestDB(t)
 	seedUsers(t, db)
 
-	expr := generated.User.Profile.Contains(`{"vip":true}`)
-	e, ok := expr.(clause.Expr)
-	if !ok {
-		t.Fatalf("expected clause.Expr, got %T", expr)
-	}
-	if e.SQL != "JSON_CONTAINS(?, ?)" {
-		t.Fatalf("unexpected SQL for JSON contains: %q", e.SQL)
-	}
-	if len(e.Vars) != 2 {
-		t.Fatalf("expected 2 vars, got %d", len(e.Vars))
-	}
-	if col, ok := e.Vars[0].(clause.Column); !ok || col.Name != "profile" {
-		t.Fatalf("expected first var to be clause.Column{Name:'profile'}, got %#v", e.Vars[0])
-	}
-
 	// Insert a user with a JSON profile marking vip=true
-	u := models.User{Name: "vip_user", Age: 23, Role: "active", IsAdult: true, Profile: `{"vip": true}`}
+	u := models.User{Name: "vip_user", Age: 23, Role: "active", IsAdult: true, Profile: `{"vip": true, "tags": ["a", "b"]}`}
 	if err := db.Create(&u).Error; err != nil {
 		t.Fatalf("failed to insert vip_user: %v", err)
 	}
 
 	ctx := context.Background()
-	// Use the JSON field helper's SQLiteEqual to filter by Profile.vip == 1
 	got, err := gorm.G[models.User](db).
 		Where(generated.User.Profile.Equal("$.vip", 1)).
 		Take(ctx)
@@ -635,4 +625,220 @@ func TestCustomFieldAsJSON(t *testing.T) {
 	if got.Name != "vip_user" {
 		t.Fatalf("expected to get vip_user, got %+v", got)
 	}
+
+	for name, expr := range map[string]clause.Expression{
+		"Exists":      generated.User.Profile.Exists("$.tags"),
+		"Contains":    generated.User.Profile.Contains(true),
+		"ArrayLength": generated.User.Profile.ArrayLength("$.tags").Eq(2),
+		"Extract":     generated.User.Profile.Extract("$.tags[1]").Eq("b"),
+	} {
+		cnt, err := gorm.G[models.User](db).Where(expr).Count(ctx, "*")
+		if err != nil {
+			t.Fatalf("%s: count failed: %v", name, err)
+		}
+		if cnt != 1 {
+			t.Errorf("%s: expected 1 user, got %d", name, cnt)
+		}
+	}
+
+	var row struct{ Tags int }
+	if err := db.Model(&models.User{}).Select("?", field.BuildSelectExpr(generated.User.Profile.ArrayLength("$.tags").As("tags"))).
+		Where(generated.User.Name.Eq("vip_user")).Scan(&row).Error; err != nil {
+		t.Fatalf("select array length failed: %v", err)
+	}
+	if row.Tags != 2 {
+		t.Errorf("expected 2 tags, got %d", row.Tags)
+	}
+
+	if _, err := gorm.G[models.User](db).Where(generated.User.ID.Eq(u.ID)).Set(generated.User.Profile.Set(map[string]any{"vip": false})).Update(ctx); err != nil {
+		t.Fatalf("json set failed: %v", err)
+	}
+	if got, err := gorm.G[models.User](db).Where(generated.User.ID.Eq(u.ID)).Take(ctx); err != nil || got.Profile != `{"vip":false}` {
+		t.Fatalf("expected the serialized profile, got %+v, %v", got, err)
+	}
+
+	// values that can't be serialized as JSON fail the statement
+	if _, err := gorm.G[models.User](db).Where(generated.User.ID.Eq(u.ID)).Set(generated.User.Profile.Set(make(chan int))).Update(ctx); err == nil || !strings.Contains(err.Error(), "invalid JSON value") {
+		t.Errorf("expected an invalid JSON value error of Set, got %v", err)
+	}
+	if _, err := gorm.G[models.User](db).Where(generated.User.Profile.Equal("$.vip", make(chan int))).Take(ctx); err == nil || !strings.Contains(err.Error(), "invalid JSON value") {
+		t.Errorf("expected an invalid JSON value error of Equal, got %v", err)
+	}
+}
+
+// SQLite JSON1 extension compatibility test: filter and update pets by keys of the Attributes map.
+func TestMapField(t *testing.T) {
+	db := setupTestDB(t)
+	pets := []models.Pet{
+		{Name: "rex", Attributes: map[string]string{"color": "brown", "size": "large"}},
+		{Name: "tom", Attributes: map[string]string{"color": "grey"}},
+	}
+	if err := db.Create(&pets).Error; err != nil {
+		t.Fatalf("failed to insert pets: %v", err)
+	}
+
+	ctx := context.Background()
+	got, err := gorm.G[models.Pet](db).Where(generated.Pet.Attributes.KeyEq("color", "grey")).Take(ctx)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no such function: json_extract") {
+			t.Skip("sqlite build does not include JSON1; skipping")
+		}
+		t.Fatalf("map key filter failed: %v", err)
+	}
+	if got.Name != "tom" {
+		t.Fatalf("expected to get tom, got %+v", got)
+	}
+
+	sized, err := gorm.G[models.Pet](db).Where(generated.Pet.Attributes.HasKey("size")).Find(ctx)
+	if err != nil {
+		t.Fatalf("map has key filter failed: %v", err)
+	}
+	if len(sized) != 1 || sized[0].Name != "rex" {
+		t.Fatalf("expected only rex to have a size, got %+v", sized)
+	}
+
+	if _, err := gorm.G[models.Pet](db).
+		Where(generated.Pet.Name.Eq("tom")).
+		Set(generated.Pet.Attributes.SetKey("size", "small")).
+		Update(ctx); err != nil {
+		t.Fatalf("map set key update failed: %v", err)
+	}
+	if _, err := gorm.G[models.Pet](db).
+		Where(generated.Pet.Name.Eq("rex")).
+		Set(generated.Pet.Attributes.DeleteKey("color")).
+		Update(ctx); err != nil {
+		t.Fatalf("map delete key update failed: %v", err)
+	}
+
+	var reloaded []models.Pet
+	if err := db.Order("name").Find(&reloaded).Error; err != nil {
+		t.Fatalf("failed to reload pets: %v", err)
+	}
+	if want := map[string]string{"size": "large"}; !reflect.DeepEqual(reloaded[0].Attributes, want) {
+		t.Errorf("expected rex attributes %v, got %v", want, reloaded[0].Attributes)
+	}
+	if want := map[string]string{"color": "grey", "size": "small"}; !reflect.DeepEqual(reloaded[1].Attributes, want) {
+		t.Errorf("expected tom attributes %v, got %v", want, reloaded[1].Attributes)
+	}
+
+	if _, err := gorm.G[models.Pet](db).
+		Where(generated.Pet.Name.Eq("rex")).
+		Set(generated.Pet.Attributes.Set(map[string]string{"color": "black"})).
+		Update(ctx); err != nil {
+		t.Fatalf("map set update failed: %v", err)
+	}
+	if rex, err := gorm.G[models.Pet](db).Where(generated.Pet.Name.Eq("rex")).Take(ctx); err != nil || !reflect.DeepEqual(rex.Attributes, map[string]string{"color": "black"}) {
+		t.Errorf("expected rex attributes replaced, got %+v, %v", rex.Attributes, err)
+	}
+}
+
+func TestRepository_CRUD(t *testing.T) {
+	db := setupTestDB(t)
+	seedUsers(t, db)
+
+	ctx := context.Background()
+	repo := generated.NewUserRepository(db)
+
+	erin := models.User{Name: "erin", Age: 25, Role: "active", IsAdult: true}
+	if err := repo.Create(ctx, &erin); err != nil || erin.ID == 0 {
+		t.Fatalf("Create failed: %v, id %d", err, erin.ID)
+	}
+
+	got, err := repo.GetByID(ctx, erin.ID)
+	if err != nil || got.Name != "erin" {
+		t.Fatalf("GetByID failed: %v, got %+v", err, got)
+	}
+
+	all, err := repo.List(ctx)
+	if err != nil || len(all) != 5 {
+		t.Fatalf("expected 5 users without conds, got %d, %v", len(all), err)
+	}
+	active, err := repo.List(ctx, generated.User.Role.Eq("active"), generated.User.Age.Gte(20))
+	if err != nil || len(active) != 2 {
+		t.Fatalf("expected 2 active users of 20 or more, got %d, %v", len(active), err)
+	}
+
+	if rows, err := repo.Update(ctx, erin.ID, generated.User.Age.Set(26)); err != nil || rows != 1 {
+		t.Fatalf("Update failed: %v, rows %d", err, rows)
+	}
+	if got, err := repo.GetByID(ctx, erin.ID); err != nil || got.Age != 26 {
+		t.Fatalf("expected updated age 26, got %d, %v", got.Age, err)
+	}
+
+	if rows, err := repo.Delete(ctx, erin.ID); err != nil || rows != 1 {
+		t.Fatalf("Delete failed: %v, rows %d", err, rows)
+	}
+	if _, err := repo.GetByID(ctx, erin.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected gorm.ErrRecordNotFound after Delete, got %v", err)
+	}
+}
+
+// Network address helpers on SQLite, where addresses are stored as text.
+func TestIPField(t *testing.T) {
+	type Host struct {
+		ID   uint
+		Addr string
+	}
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&Host{}); err != nil {
+		t.Fatalf("failed to migrate hosts: %v", err)
+	}
+	if err := db.Create(&[]Host{{Addr: "10.0.0.1"}, {Addr: "10.0.0.2"}, {Addr: "::1"}}).Error; err != nil {
+		t.Fatalf("failed to insert hosts: %v", err)
+	}
+
+	addr := field.IP[net.IP]{}.WithColumn("addr")
+	for name, tt := range map[string]struct {
+		expr clause.Expression
+		want int64
+	}{
+		"Eq":              {addr.Eq(net.ParseIP("10.0.0.1")), 1},
+		"In":              {addr.In(net.ParseIP("10.0.0.1"), net.ParseIP("::1")), 2},
+		"SameFamily IPv4": {addr.SameFamily(net.ParseIP("127.0.0.1")), 2},
+		"SameFamily IPv6": {addr.SameFamily(net.ParseIP("fe80::1")), 1},
+		"SameFamily nil":  {addr.SameFamily(nil), 0},
+	} {
+		var cnt int64
+		if err := db.Model(&Host{}).Where(tt.expr).Count(&cnt).Error; err != nil {
+			t.Fatalf("%s: count failed: %v", name, err)
+		}
+		if cnt != tt.want {
+			t.Errorf("%s: expected %d hosts, got %d", name, tt.want, cnt)
+		}
+	}
+}
+
+// Pointer fields of the helpers converting values write NULL with SetPtr(nil) and SetNull.
+func TestFieldHelpers_SetPtr(t *testing.T) {
+	type Job struct {
+		ID      uint
+		Addr    *string
+		Elapsed *int64
+	}
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&Job{}); err != nil {
+		t.Fatalf("failed to migrate jobs: %v", err)
+	}
+	job := Job{}
+	if err := db.Create(&job).Error; err != nil {
+		t.Fatalf("failed to insert job: %v", err)
+	}
+
+	ctx := context.Background()
+	addr := field.IP[net.IP]{}.WithColumn("addr")
+	elapsed := field.Duration{}.WithColumn("elapsed").WithUnit(time.Second)
+	ip, d := net.ParseIP("10.0.0.1"), 30*time.Second
+	if _, err := gorm.G[Job](db).Where("id = ?", job.ID).Set(addr.SetPtr(&ip), elapsed.SetPtr(&d)).Update(ctx); err != nil {
+		t.Fatalf("SetPtr update failed: %v", err)
+	}
+	if got, err := gorm.G[Job](db).Where("id = ?", job.ID).First(ctx); err != nil || got.Addr == nil || *got.Addr != "10.0.0.1" || got.Elapsed == nil || *got.Elapsed != 30 {
+		t.Fatalf("expected addr 10.0.0.1 and elapsed 30, got %+v, %v", got, err)
+	}
+
+	if _, err := gorm.G[Job](db).Where("id = ?", job.ID).Set(addr.SetPtr(nil), elapsed.SetNull()).Update(ctx); err != nil {
+		t.Fatalf("NULL update failed: %v", err)
+	}
+	if got, err := gorm.G[Job](db).Where("id = ?", job.ID).First(ctx); err != nil || got.Addr != nil || got.Elapsed != nil {
+		t.Fatalf("expected NULL addr and elapsed, got %+v, %v", got, err)
+	}
 }
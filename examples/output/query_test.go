@@ -2,11 +2,19 @@ package examples
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"gorm.io/cli/gorm/examples"
 	"gorm.io/cli/gorm/examples/models"
+	generated "gorm.io/cli/gorm/examples/output/models"
+	"gorm.io/cli/gorm/gen"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func TestUserQueries(t *testing.T) {
@@ -44,6 +52,30 @@ func TestUserQueries(t *testing.T) {
 		}
 	})
 
+	t.Run("Test FilterByAllowedColumn", func(t *testing.T) {
+		query := Query[models.User](db)
+		users, err := query.FilterByAllowedColumn(context.Background(), "role", "special")
+		if err != nil || len(users) != 1 || users[0].Role != "special" {
+			t.Errorf("expected one 'special' user, got: %+v, error: %v", users, err)
+		}
+
+		if _, err := query.FilterByAllowedColumn(context.Background(), "age", "28"); err == nil || !strings.Contains(err.Error(), `invalid column "age", must be one of name, role`) {
+			t.Errorf("expected an error of the column missing in the whitelist, got: %v", err)
+		}
+	})
+
+	t.Run("Test FilterByModelColumn", func(t *testing.T) {
+		query := Query[models.User](db)
+		users, err := query.FilterByModelColumn(context.Background(), "role", "special")
+		if err != nil || len(users) != 1 || users[0].Role != "special" {
+			t.Errorf("expected one 'special' user, got: %+v, error: %v", users, err)
+		}
+
+		if _, err := query.FilterByModelColumn(context.Background(), "1=1 OR role", "special"); err == nil || !strings.Contains(err.Error(), `invalid column "1=1 OR role", must be a column of User`) {
+			t.Errorf("expected an error of the column missing in the model, got: %v", err)
+		}
+	})
+
 	t.Run("Test QueryWith", func(t *testing.T) {
 		query := Query[models.User](db)
 		result, err := query.QueryWith(context.Background(), models.User{Name: "dan"})
@@ -89,6 +121,457 @@ func TestUserQueries(t *testing.T) {
 		}
 	})
 
+	t.Run("Test FindCreatedWithin", func(t *testing.T) {
+		query := Query[models.User](db)
+		results, err := query.FindCreatedWithin(context.Background(), time.Hour)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != len(users) {
+			t.Errorf("expected %d users, got: %d", len(users), len(results))
+		}
+
+		results, err = query.FindCreatedWithin(context.Background(), -time.Hour)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("expected no users created in the future, got: %d", len(results))
+		}
+	})
+
+	t.Run("Test FindAdultsByNamePrefix", func(t *testing.T) {
+		query := Query[models.User](db)
+		results, err := query.FindAdultsByNamePrefix(context.Background(), []string{"a", "b", "d"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 2 || results[0].Name != "alice" || results[1].Name != "dan" {
+			t.Errorf("expected alice and dan, got: %+v", results)
+		}
+
+		results, err = query.FindAdultsByNamePrefix(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 3 {
+			t.Errorf("expected the 3 adults, got: %+v", results)
+		}
+	})
+
+	t.Run("Test Page", func(t *testing.T) {
+		query := Query[models.User](db)
+		results, err := query.Page(context.Background(), 2, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 2 || results[0].ID != users[1].ID || results[1].ID != users[2].ID {
+			t.Errorf("expected the 2nd and 3rd users, got: %+v", results)
+		}
+
+		results, err = query.Page(context.Background(), -1, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != len(users)-3 {
+			t.Errorf("expected %d users after offset 3, got: %d", len(users)-3, len(results))
+		}
+	})
+
+	t.Run("Test SortBy", func(t *testing.T) {
+		query := Query[models.User](db)
+		results, err := query.SortBy(context.Background(), "age", "DESC")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 1; i < len(results); i++ {
+			if results[i-1].Age < results[i].Age {
+				t.Fatalf("expected users sorted by age descending, got: %+v", results)
+			}
+		}
+
+		if _, err := query.SortBy(context.Background(), "age; DROP TABLE users", ""); err == nil {
+			t.Error("expected an error for a column that isn't allowed")
+		}
+		if _, err := query.SortBy(context.Background(), "name", "sideways"); err == nil {
+			t.Error("expected an error for an invalid direction")
+		}
+	})
+
+	t.Run("Test FindByNameInsensitive", func(t *testing.T) {
+		query := Query[models.User](db)
+		results, err := query.FindByNameInsensitive(context.Background(), "ALICE")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 1 || results[0].Name != "alice" {
+			t.Errorf("expected user alice, got: %+v", results)
+		}
+	})
+
+	t.Run("Test OldestOfRole", func(t *testing.T) {
+		query := Query[models.User](db)
+		result, err := query.OldestOfRole(context.Background(), "active")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var want models.User
+		db.Unscoped().Where("role = ?", "active").Order("age DESC, id").First(&want)
+		if result.ID != want.ID {
+			t.Errorf("expected the oldest active user %+v, got: %+v", want, result)
+		}
+	})
+
+	t.Run("Test SearchByNameAndAge", func(t *testing.T) {
+		query := Query[models.User](db)
+		results, err := query.SearchByNameAndAge(context.Background(), "  ", 30)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 1 || results[0].Name != "cathy" {
+			t.Errorf("expected user cathy, got: %+v", results)
+		}
+	})
+
+	t.Run("Test FindByNameLike", func(t *testing.T) {
+		query := Query[models.User](db)
+		results, err := query.FindByNameLike(context.Background(), "ca%")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected, err := gorm.G[models.User](db.Unscoped()).Where("name LIKE ?", "ca%").Order("id").Find(context.Background())
+		if err != nil || len(results) == 0 || len(results) != len(expected) || results[0].ID != expected[0].ID {
+			t.Errorf("expected %+v, got: %+v, err: %v", expected, results, err)
+		}
+	})
+
+	t.Run("Test AdultsByRole", func(t *testing.T) {
+		query := Query[models.User](db)
+		results, err := query.AdultsByRole(context.Background(), "active")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected, err := gorm.G[models.User](db.Unscoped()).Where("is_adult = ? AND role = ?", true, "active").Order("id").Find(context.Background())
+		if err != nil || len(results) == 0 || len(results) != len(expected) || results[0].ID != expected[0].ID {
+			t.Errorf("expected %+v, got: %+v, err: %v", expected, results, err)
+		}
+	})
+
+	t.Run("Test FindByRoles", func(t *testing.T) {
+		query := Query[models.User](db)
+		results, err := query.FindByRoles(context.Background(), []models.Role{models.RolePending})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected, err := gorm.G[models.User](db.Unscoped()).Where("role IN ?", []models.Role{models.RolePending}).Order("id").Find(context.Background())
+		if err != nil || len(results) == 0 || len(results) != len(expected) || results[0].ID != expected[0].ID {
+			t.Errorf("expected %+v, got: %+v, err: %v", expected, results, err)
+		}
+
+		results, err = query.FindByRoles(context.Background(), nil)
+		if err != nil || len(results) != 0 {
+			t.Errorf("expected no user without roles, got: %+v, err: %v", results, err)
+		}
+	})
+
+	t.Run("Test AgeBetween", func(t *testing.T) {
+		query := Query[models.User](db)
+		results, err := query.Where(query.AgeBetween(25, 35)).Where(generated.User.Role.Eq("pending")).Find(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 1 || results[0].Name != "cathy" {
+			t.Errorf("expected user cathy, got: %+v", results)
+		}
+	})
+
+	t.Run("Test RenameByID", func(t *testing.T) {
+		query := Query[models.User](db)
+		rows, err := query.RenameByID(context.Background(), "daniel", users[3].ID)
+		if err != nil || rows != 1 {
+			t.Fatalf("expected 1 row renamed, got %d, err: %v", rows, err)
+		}
+		got, err := gorm.G[models.User](db).Where("id = ?", users[3].ID).First(context.Background())
+		if err != nil || got.Name != "daniel" || got.Age != users[3].Age {
+			t.Errorf("expected user renamed to daniel, got: %+v, err: %v", got, err)
+		}
+	})
+
+	t.Run("Test CountByRole", func(t *testing.T) {
+		query := Query[models.User](db)
+		count, err := query.CountByRole(context.Background(), "pending")
+		if err != nil || count != 2 {
+			t.Errorf("expected 2 pending users, got %d, err: %v", count, err)
+		}
+	})
+
+	t.Run("Test CountAdultsByRole", func(t *testing.T) {
+		want, err := gorm.G[models.User](db).Where("is_adult = ? AND role = ?", true, "pending").Count(context.Background(), "*")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		count, err := Query[models.User](db).CountAdultsByRole(context.Background(), "pending")
+		if err != nil || count != want {
+			t.Errorf("expected %d pending adults, got %d, err: %v", want, count, err)
+		}
+	})
+
+	t.Run("Test SwapRoles", func(t *testing.T) {
+		query := Query[models.User](db)
+		pending, _ := query.CountByRole(context.Background(), "pending")
+		active, _ := query.CountByRole(context.Background(), "active")
+
+		if err := query.SwapRoles(context.Background(), "pending", "active"); err != nil {
+			t.Fatalf("SwapRoles error: %v", err)
+		}
+		if count, err := query.CountByRole(context.Background(), "pending"); err != nil || count != active {
+			t.Errorf("expected %d pending users, got %d, err: %v", active, count, err)
+		}
+		if count, err := query.CountByRole(context.Background(), "active"); err != nil || count != pending {
+			t.Errorf("expected %d active users, got %d, err: %v", pending, count, err)
+		}
+
+		if err := query.SwapRoles(context.Background(), "active", "pending"); err != nil {
+			t.Fatalf("SwapRoles error: %v", err)
+		}
+		if count, err := query.CountByRole(context.Background(), "pending"); err != nil || count != pending {
+			t.Errorf("expected %d pending users after swapping back, got %d, err: %v", pending, count, err)
+		}
+	})
+
+	t.Run("Test DeleteByRole", func(t *testing.T) {
+		query := Query[models.User](db)
+		rows, err := query.DeleteByRole(context.Background(), "special")
+		if err != nil || rows != 1 {
+			t.Fatalf("expected 1 row deleted, got %d, err: %v", rows, err)
+		}
+		if count, err := query.CountByRole(context.Background(), "special"); err != nil || count != 0 {
+			t.Errorf("expected no special users left, got %d, err: %v", count, err)
+		}
+	})
+
+	t.Run("Test Insert", func(t *testing.T) {
+		query := Query[models.User](db)
+		now := time.Now()
+		if err := query.Insert(context.Background(), models.User{Name: "erin", Age: 25, Role: models.RoleActive, IsAdult: true, Model: gorm.Model{CreatedAt: now, UpdatedAt: now}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := gorm.G[models.User](db).Where("name = ?", "erin").First(context.Background())
+		if err != nil || got.ID == 0 || got.Age != 25 || got.Role != models.RoleActive || !got.IsAdult {
+			t.Errorf("expected inserted user erin, got: %+v, err: %v", got, err)
+		}
+	})
+
+	t.Run("Test InsertWithID", func(t *testing.T) {
+		query := Query[models.User](db)
+		id, err := query.InsertWithID(context.Background(), models.User{Name: "frank", Age: 33, Role: models.RoleActive})
+		if err != nil || id == 0 {
+			t.Fatalf("expected a generated id, got %d, err: %v", id, err)
+		}
+		got, err := gorm.G[models.User](db).Where("id = ?", id).First(context.Background())
+		if err != nil || got.Name != "frank" {
+			t.Errorf("expected user frank with id %d, got: %+v, err: %v", id, got, err)
+		}
+	})
+
+	t.Run("Test MarkMinorsPending", func(t *testing.T) {
+		query := Query[models.User](db)
+		rows, err := query.MarkMinorsPending(context.Background(), 18)
+		if err != nil || rows != 1 {
+			t.Fatalf("expected 1 row affected, got %d, err: %v", rows, err)
+		}
+		got, err := gorm.G[models.User](db).Where("name = ?", "bob").First(context.Background())
+		if err != nil || got.Role != models.RolePending {
+			t.Errorf("expected bob to be pending, got: %+v, err: %v", got, err)
+		}
+	})
+
+	t.Run("Test InsertBatch", func(t *testing.T) {
+		query := Query[models.User](db)
+		batch := []models.User{
+			{Name: "batch1", Age: 21, Role: models.RoleActive, IsAdult: true},
+			{Name: "batch2", Age: 12, Role: models.RolePending},
+			{Name: "batch3", Age: 45, Role: models.RoleActive, IsAdult: true},
+		}
+		rows, err := query.InsertBatch(context.Background(), batch)
+		if err != nil || rows != 3 {
+			t.Fatalf("expected 3 rows affected, got %d, err: %v", rows, err)
+		}
+		got, err := gorm.G[models.User](db).Where("name LIKE ?", "batch%").Order("name").Find(context.Background())
+		if err != nil || len(got) != 3 || got[1].Name != "batch2" || got[1].Age != 12 || got[2].IsAdult != true {
+			t.Errorf("expected the batch users, got: %+v, err: %v", got, err)
+		}
+	})
+
+	t.Run("Test TouchByID", func(t *testing.T) {
+		query := Query[models.User](db)
+		result, err := query.TouchByID(context.Background(), users[0].ID, time.Now())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rows, err := result.RowsAffected(); err != nil || rows != 1 {
+			t.Errorf("expected 1 row affected, got %d, err: %v", rows, err)
+		}
+	})
+
+	t.Run("Test StreamByRole", func(t *testing.T) {
+		query := Query[models.User](db)
+		want, err := gorm.G[models.User](db).Where("role = ?", models.RoleActive).Order("id").Find(context.Background())
+		if err != nil || len(want) < 2 {
+			t.Fatalf("expected several active users, got %d, err: %v", len(want), err)
+		}
+
+		var names []string
+		for user, err := range query.StreamByRole(context.Background(), string(models.RoleActive)) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			names = append(names, user.Name)
+		}
+		if len(names) != len(want) || names[0] != want[0].Name {
+			t.Errorf("expected %d users starting with %s, got: %v", len(want), want[0].Name, names)
+		}
+
+		// breaking out of the loop stops reading the rows
+		for user := range query.StreamByRole(context.Background(), string(models.RoleActive)) {
+			if user.Name != want[0].Name {
+				t.Errorf("expected user %s, got: %+v", want[0].Name, user)
+			}
+			break
+		}
+	})
+
+	t.Run("Test EachAdult", func(t *testing.T) {
+		query := Query[models.User](db)
+		want, err := gorm.G[models.User](db).Where("is_adult = ?", true).Find(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var count int
+		if err := query.EachAdult(context.Background(), func(user models.User) error {
+			if !user.IsAdult {
+				t.Errorf("expected an adult, got: %+v", user)
+			}
+			count++
+			return nil
+		}); err != nil || count != len(want) {
+			t.Errorf("expected %d adults, got %d, err: %v", len(want), count, err)
+		}
+
+		errStop := errors.New("stop")
+		count = 0
+		if err := query.EachAdult(context.Background(), func(models.User) error {
+			count++
+			return errStop
+		}); !errors.Is(err, errStop) || count != 1 {
+			t.Errorf("expected the callback error after 1 user, got %d users, err: %v", count, err)
+		}
+	})
+
+	t.Run("Test CountPerRole", func(t *testing.T) {
+		stats, err := Query[models.User](db).CountPerRole(context.Background())
+		if err != nil {
+			t.Fatalf("CountPerRole error: %v", err)
+		}
+		if len(stats) == 0 {
+			t.Fatalf("expected role stats, got none")
+		}
+		for _, stat := range stats {
+			// raw SQL counts soft deleted records too
+			var total int64
+			if err := db.Model(&models.User{}).Unscoped().Where("role = ?", stat.Role).Count(&total).Error; err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stat.Total != total {
+				t.Errorf("expected %d records of role %q, got %d", total, stat.Role, stat.Total)
+			}
+		}
+	})
+
+	t.Run("Test OlderThanRoleAverage", func(t *testing.T) {
+		// raw SQL includes soft deleted records too
+		var users []models.User
+		if err := db.Unscoped().Order("id").Find(&users).Error; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sums, counts := map[string]float64{}, map[string]float64{}
+		for _, user := range users {
+			sums[string(user.Role)] += float64(user.Age)
+			counts[string(user.Role)]++
+		}
+		var want []uint
+		for _, user := range users {
+			if float64(user.Age) > sums[string(user.Role)]/counts[string(user.Role)] {
+				want = append(want, user.ID)
+			}
+		}
+
+		got, err := Query[models.User](db).OlderThanRoleAverage(context.Background())
+		if err != nil {
+			t.Fatalf("OlderThanRoleAverage error: %v", err)
+		}
+		var ids []uint
+		for _, user := range got {
+			ids = append(ids, user.ID)
+		}
+		if !slices.Equal(ids, want) {
+			t.Errorf("expected users %v, got %v", want, ids)
+		}
+	})
+
+	t.Run("Test RoleSummary", func(t *testing.T) {
+		query := Query[models.User](db)
+		rows, err := query.RoleSummary(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected, err := query.CountPerRole(context.Background())
+		if err != nil || len(rows) != len(expected) {
+			t.Fatalf("expected %d rows, got: %+v, err: %v", len(expected), rows, err)
+		}
+		for i, row := range rows {
+			if row["role"] != models.Role(expected[i].Role) || row["total"] != expected[i].Total || row["max_age"] == nil {
+				t.Errorf("expected role %s with %d records, got: %+v", expected[i].Role, expected[i].Total, row)
+			}
+		}
+	})
+
+	t.Run("Test StatsByRole", func(t *testing.T) {
+		query := Query[models.User](db)
+		row, err := query.StatsByRole(context.Background(), "pending")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected, err := gorm.G[models.User](db.Unscoped()).Where("role = ?", "pending").Count(context.Background(), "*")
+		if err != nil || row["total"] != expected || len(row) != 2 {
+			t.Errorf("expected %d pending records, got: %+v, err: %v", expected, row, err)
+		}
+	})
+
+	t.Run("Test CountAdults", func(t *testing.T) {
+		query := Query[models.User](db)
+		count, err := query.CountAdults(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected, err := gorm.G[models.User](db.Unscoped()).Where("is_adult = ?", true).Count(context.Background(), "*")
+		if err != nil || count != expected || count == 0 {
+			t.Errorf("expected %d adults, got %d, err: %v", expected, count, err)
+		}
+	})
+
+	t.Run("Test ExistsByName", func(t *testing.T) {
+		query := Query[models.User](db)
+		if exists, err := query.ExistsByName(context.Background(), "cathy"); err != nil || !exists {
+			t.Errorf("expected cathy to exist, got %v, err: %v", exists, err)
+		}
+		if exists, err := query.ExistsByName(context.Background(), "nobody"); err != nil || exists {
+			t.Errorf("expected nobody not to exist, got %v, err: %v", exists, err)
+		}
+	})
+
 	t.Run("Test UpdateInfo", func(t *testing.T) {
 		query := Query[models.User](db)
 		// Pick any user and set Age to 40; is_adult should be true
@@ -105,3 +588,25 @@ func TestUserQueries(t *testing.T) {
 		}
 	})
 }
+
+func TestPreviewSQL(t *testing.T) {
+	table := clause.Table{Name: clause.CurrentTable}
+	for _, tc := range []struct {
+		method     string
+		args       map[string]any
+		wantSQL    string
+		wantParams []any
+	}{
+		{"SearchByNameAndAge", map[string]any{"name": "jinzhu", "age": 18, "notEmpty": examples.NotEmpty, "inRange": examples.InRange}, "SELECT * FROM ? WHERE name=? AND age=?", []any{table, "jinzhu", 18}},
+		{"SearchByNameAndAge", map[string]any{"name": " ", "age": 200, "notEmpty": examples.NotEmpty, "inRange": examples.InRange}, "SELECT * FROM ?", []any{table}},
+		{"FindAdultsByNamePrefix", map[string]any{"prefixes": []string{"a", "b"}}, "SELECT * FROM ? WHERE is_adult = true AND ( name LIKE ? OR name LIKE ? ) ORDER BY id", []any{table, "a%", "b%"}},
+	} {
+		sql, params, err := gen.PreviewSQL("..", "Query", tc.method, tc.args)
+		if err != nil {
+			t.Fatalf("PreviewSQL %s error: %v", tc.method, err)
+		}
+		if sql != tc.wantSQL || !reflect.DeepEqual(params, tc.wantParams) {
+			t.Errorf("PreviewSQL %s, expected %q %v, got %q %v", tc.method, tc.wantSQL, tc.wantParams, sql, params)
+		}
+	}
+}
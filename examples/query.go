@@ -2,11 +2,13 @@ package examples
 
 import (
 	"database/sql"
+	"iter"
 	"time"
 
 	"gorm.io/cli/gorm/examples/models"
 	"gorm.io/cli/gorm/field"
 	"gorm.io/cli/gorm/genconfig"
+	"gorm.io/gorm/clause"
 )
 
 var _ = genconfig.Config{
@@ -16,11 +18,27 @@ var _ = genconfig.Config{
 	},
 	FieldNameMap: map[string]any{
 		"date": field.Time{},
-		"json": JSON{},
 	},
 	IncludeStructs: []any{},
+	ExcludeStructs: []any{RoleStats{}},
+	Scopes: map[string]any{
+		"User.ActiveAdults": []string{`Role.Eq(models.RoleActive)`, `IsAdult.Eq(true)`},
+	},
+	QueryModels: map[string]any{
+		"Query": models.User{},
+	},
+	TemplateVars: map[string]string{
+		"adult": "is_adult = true",
+	},
+	SQLFuncs: map[string]any{
+		"notEmpty": NotEmpty,
+		"inRange":  InRange,
+	},
 }
 
+// AdultsOfRoleSQL is the SQL template of Query.AdultsByRole, shared with tests and debugging tools
+const AdultsOfRoleSQL = "SELECT * FROM @@table WHERE is_adult = true AND role = @role ORDER BY id"
+
 type Query[T any] interface {
 	// GetByID query data by id and return it as struct
 	//
@@ -30,6 +48,12 @@ type Query[T any] interface {
 	// SELECT * FROM @@table WHERE @@column=@value
 	FilterWithColumn(column string, value string) (T, error)
 
+	// SELECT * FROM @@table WHERE @@column in (name, role) = @value ORDER BY id
+	FilterByAllowedColumn(column string, value string) ([]T, error)
+
+	// SELECT * FROM @@table WHERE @@column in (*) = @value ORDER BY id
+	FilterByModelColumn(column string, value string) ([]T, error)
+
 	// SELECT * FROM users
 	//   {{if user.ID > 0}}
 	//       WHERE id=@user.ID
@@ -70,4 +94,178 @@ type Query[T any] interface {
 	//    {{end}}
 	//  {{end}}
 	FilterWithTime(start, end time.Time) ([]T, error)
+
+	// FindCreatedWithin finds the records created during the last period, computed when the query runs
+	//
+	// SELECT * FROM @@table WHERE created_at > @{time.Now().Add(-period)} ORDER BY id
+	FindCreatedWithin(period time.Duration) ([]T, error)
+
+	// FindAdultsByNamePrefix finds the adults whose name starts with one of the prefixes, all adults without prefixes
+	//
+	// SELECT * FROM @@table WHERE is_adult = true
+	// {{for _, prefix := range prefixes}}
+	//   {{if $first}}AND ({{end}}name LIKE @{prefix + "%"}{{if $last}}){{else}} OR{{end}}
+	// {{end}}
+	// ORDER BY id
+	FindAdultsByNamePrefix(prefixes []string) ([]T, error)
+
+	// Page returns a page of records, rendered as LIMIT/OFFSET or OFFSET/FETCH per dialect
+	//
+	// SELECT * FROM @@table ORDER BY id {{limit @size offset @offset}}
+	Page(size, offset int) ([]T, error)
+
+	// SortBy returns the records sorted by one of the allowed columns
+	//
+	// SELECT * FROM @@table {{orderBy @column allow="name,age,created_at" dir=@direction}}
+	SortBy(column, direction string) ([]T, error)
+
+	// FindByNameInsensitive finds records by name ignoring case, with the syntax of the current dialect
+	//
+	// SELECT * FROM @@table WHERE
+	// {{if dialect "postgres"}}
+	//   name ILIKE @name
+	// {{else if dialect "mysql"}}
+	//   name = @name COLLATE utf8mb4_general_ci
+	// {{else}}
+	//   LOWER(name) = LOWER(@name)
+	// {{end}}
+	FindByNameInsensitive(name string) ([]T, error)
+
+	// OldestOfRole returns the oldest record of the role, records without an age last
+	//
+	// postgres: SELECT * FROM @@table WHERE role = @role ORDER BY age DESC NULLS LAST, id LIMIT 1
+	// mysql, sqlite: SELECT * FROM @@table WHERE role = @role
+	//   ORDER BY age IS NULL, age DESC, id LIMIT 1
+	// default: SELECT * FROM @@table WHERE role = @role ORDER BY age DESC, id LIMIT 1
+	OldestOfRole(role string) (T, error)
+
+	// FindByNameLike finds the records whose name matches the LIKE pattern
+	//
+	// sql("SELECT * FROM @@table WHERE name LIKE @pattern ORDER BY id")
+	//
+	// The SQL is declared with sql(...), so the paragraphs around it stay documentation.
+	FindByNameLike(pattern string) ([]T, error)
+
+	// AdultsByRole finds the adult records of the role, its SQL is the AdultsOfRoleSQL constant
+	//
+	// sqlconst: AdultsOfRoleSQL
+	AdultsByRole(role string) ([]T, error)
+
+	// FindByRoles finds the records of the roles, none when roles is empty
+	//
+	// SELECT * FROM @@table {{where}} {{in "role" @roles}} {{end}} ORDER BY id
+	FindByRoles(roles []models.Role) ([]T, error)
+
+	// SearchByNameAndAge filters by name unless blank and by age when it's plausible
+	//
+	// SELECT * FROM @@table
+	// {{where}}
+	//   {{if notEmpty(name)}} name=@name {{end}}
+	//   {{if inRange(age, 1, 150)}} AND age=@age {{end}}
+	// {{end}}
+	SearchByNameAndAge(name string, age int) ([]T, error)
+
+	// AgeBetween matches the records whose age is in the range, to combine with other conditions
+	//
+	// expr("age BETWEEN @from AND @to")
+	AgeBetween(from, to int) clause.Expression
+
+	// RenameByID renames the record, it returns the rows affected
+	//
+	// update("name=@name WHERE id=@id")
+	RenameByID(name string, id uint) (int, error)
+
+	// DeleteByRole deletes the records of the role, it returns the rows affected
+	//
+	// delete("role = @role")
+	DeleteByRole(role string) (int, error)
+
+	// CountByRole counts the records of the role
+	//
+	// count("role = @role")
+	CountByRole(role string) (int64, error)
+
+	// CountAdultsByRole counts the adult records of the role, ${adult} is declared in TemplateVars
+	//
+	// count("${adult} AND role = @role")
+	CountAdultsByRole(role string) (int64, error)
+
+	// CountAdults counts the adult records, scanned directly into the count
+	//
+	// SELECT COUNT(*) FROM @@table WHERE is_adult = true -- soft deleted records too
+	CountAdults() (int64, error)
+
+	// ExistsByName reports whether a record has the name
+	//
+	// SELECT EXISTS(SELECT 1 FROM @@table WHERE name = @name)
+	ExistsByName(name string) (bool, error)
+
+	// SwapRoles swaps the roles a and b of the records, the statements run in a transaction
+	//
+	// UPDATE @@table SET role = '~swap' WHERE role = @a;
+	// UPDATE @@table SET role = @a WHERE role = @b;
+	// UPDATE @@table SET role = @b WHERE role = '~swap'
+	SwapRoles(a, b string) error
+
+	// Insert inserts the record with the columns of its fields, the id is generated by the database
+	//
+	// insert(@@table, @user.*)
+	Insert(user models.User) error
+
+	// InsertWithID inserts the record and returns the id generated by the database
+	//
+	// insert(@@table, @user.*)
+	InsertWithID(user models.User) (lastInsertId int64, err error)
+
+	// MarkMinorsPending sets the role of the records younger than age to pending, it returns the rows affected
+	//
+	// UPDATE @@table SET role = 'pending' WHERE age < @age
+	MarkMinorsPending(age int) (rowsAffected int64, err error)
+
+	// InsertBatch inserts the records, two per INSERT statement, it returns the rows affected
+	//
+	// INSERT INTO @@table (name, age, role, is_adult) VALUES {{values @users (Name, Age, Role, IsAdult) batch=2}}
+	InsertBatch(users []models.User) (rowsAffected int64, err error)
+
+	// TouchByID sets the update time of the record
+	//
+	// UPDATE @@table SET updated_at = @at WHERE id = @id
+	TouchByID(id uint, at time.Time) (sql.Result, error)
+
+	// StreamByRole iterates over the records of the role, scanning a row at a time
+	//
+	// SELECT * FROM @@table WHERE role = @role ORDER BY id
+	StreamByRole(role string) iter.Seq2[T, error]
+
+	// EachAdult calls fn with each adult record, scanning a row at a time, it stops at the first error
+	//
+	// SELECT * FROM @@table WHERE is_adult = true ORDER BY id
+	EachAdult(fn func(T) error) error
+
+	// CountPerRole returns the number of records of each role, scanned into RoleStats
+	//
+	// timeout: 3s
+	// SELECT role, count(*) AS total FROM @@table GROUP BY role ORDER BY role
+	CountPerRole() ([]RoleStats, error)
+
+	// RoleSummary returns the number of records and the maximum age of each role, as rows keyed by column
+	//
+	// SELECT role, count(*) AS total, MAX(age) AS max_age FROM @@table GROUP BY role ORDER BY role
+	RoleSummary() ([]map[string]any, error)
+
+	// StatsByRole returns the number of records and the maximum age of the role, as a row keyed by column
+	//
+	// SELECT count(*) AS total, MAX(age) AS max_age FROM @@table WHERE role = @role
+	StatsByRole(role string) (map[string]any, error)
+
+	// OlderThanRoleAverage returns the records older than the average age of their role
+	//
+	// SELECT u.* FROM @@table(as=u) WHERE u.age > (SELECT AVG(r.age) FROM @@table(as=r) WHERE r.role = u.role) ORDER BY u.id
+	OlderThanRoleAverage() ([]T, error)
+}
+
+// RoleStats is the number of records of a role
+type RoleStats struct {
+	Role  string
+	Total int64
 }
@@ -0,0 +1,16 @@
+package examples
+
+import "strings"
+
+// NotEmpty reports whether s has non-blank content.
+//
+// It is registered with genconfig.Config.SQLFuncs as notEmpty, so SQL templates
+// can use it in conditions, e.g. {{if notEmpty(name)}}.
+func NotEmpty(s string) bool {
+	return strings.TrimSpace(s) != ""
+}
+
+// InRange reports whether v is within [min, max].
+func InRange(v, min, max int) bool {
+	return v >= min && v <= max
+}
@@ -11,6 +11,7 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// QueryUser should match pattern "Query*"
 func QueryUser[T any](db *gorm.DB, opts ...clause.Expression) _QueryUserInterface[T] {
 	return _QueryUserImpl[T]{
 		Interface: typed.G[T](db, opts...),
@@ -38,6 +39,7 @@ func (e _QueryUserImpl[T]) ByID(ctx context.Context, id int) (T, error) {
 	return result, err
 }
 
+// QueryOrder should match pattern "Query*"
 func QueryOrder[T any](db *gorm.DB, opts ...clause.Expression) _QueryOrderInterface[T] {
 	return _QueryOrderImpl[T]{
 		Interface: typed.G[T](db, opts...),
@@ -11,6 +11,7 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// I1 has a simple select
 func I1[T any](db *gorm.DB, opts ...clause.Expression) _I1Interface[T] {
 	return _I1Impl[T]{
 		Interface: typed.G[T](db, opts...),
@@ -4,12 +4,85 @@ package whitelist
 
 import (
 	"gorm.io/cli/gorm/field"
+	"gorm.io/gorm/clause"
 )
 
-var S1 = struct {
+var S1 = _S1{
+	ID:   field.Number[int]{}.WithColumn("id"),
+	Name: field.String{}.WithColumn("name"),
+}
+
+type _S1 struct {
 	ID   field.Number[int]
 	Name field.String
+}
+
+// S1As returns the S1 field helpers qualified with the table alias, e.g. for self joins
+func S1As(alias string) _S1Alias {
+	return _S1Alias{
+		_S1: _S1{
+			ID:   field.Number[int]{}.WithColumn("id").WithTable(alias),
+			Name: field.String{}.WithColumn("name").WithTable(alias),
+		},
+		Alias: alias,
+	}
+}
+
+// _S1Alias holds the S1 field helpers of an aliased table
+type _S1Alias struct {
+	_S1
+	// Alias is the table alias, e.g. for clause.JoinTarget.As
+	Alias string
+}
+
+// Table returns the aliased clause.Table of S1
+func (a _S1Alias) Table() clause.Table {
+	return clause.Table{Name: "s1", Alias: a.Alias}
+}
+
+// S1Columns holds the column names of S1
+var S1Columns = struct {
+	ID   string
+	Name string
 }{
-	ID:   field.Number[int]{}.WithColumn("id"),
-	Name: field.String{}.WithColumn("name"),
+	ID:   "id",
+	Name: "name",
+}
+
+// AllFields returns all column fields of S1, e.g. Select(S1.AllFields()...)
+func (s _S1) AllFields() []field.Selectable {
+	return []field.Selectable{
+		s.ID,
+		s.Name,
+	}
+}
+
+// AllColumns returns all columns of S1
+func (s _S1) AllColumns() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+		s.Name.Column(),
+	}
+}
+
+// PrimaryKey returns the primary key columns of S1
+func (s _S1) PrimaryKey() []clause.Column {
+	return []clause.Column{
+		s.ID.Column(),
+	}
+}
+
+// ByID returns the condition matching the primary key of S1, e.g. Where(S1.ByID(id))
+func (s _S1) ByID(id int) clause.Expression {
+	return s.ID.Eq(id)
+}
+
+// TableName returns the table name of S1
+func (_S1) TableName() string {
+	return "s1"
+}
+
+// Table returns the clause.Table of S1
+func (_S1) Table() clause.Table {
+	return clause.Table{Name: "s1"}
 }
@@ -2,12 +2,16 @@ package examples
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"gorm.io/cli/gorm/examples/models"
 	generated "gorm.io/cli/gorm/examples/typed/models"
 	"gorm.io/cli/gorm/field"
 	"gorm.io/cli/gorm/typed"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func TestFieldHelpers_MultipleConditions_FindIntoSlice(t *testing.T) {
@@ -127,3 +131,366 @@ func TestFieldHelpers_Create_WithSetAssignments(t *testing.T) {
 	}
 }
 
+func TestFieldHelpers_Select_AllFields(t *testing.T) {
+	db := setupTestDB(t)
+	seedUsers(t, db)
+
+	users, err := typed.G[models.User](db).
+		Select(generated.User.AllFields()...).
+		Where(generated.User.Name.Eq("alice")).
+		Find(context.Background())
+	if err != nil {
+		t.Fatalf("Select(AllFields()...) failed: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "alice" || users[0].Age != 20 {
+		t.Fatalf("unexpected users selected with all fields: %+v", users)
+	}
+
+	if got, want := len(generated.User.AllColumns()), len(generated.User.AllFields()); got != want {
+		t.Fatalf("expected %d columns, got %d", want, got)
+	}
+}
+
+func TestFieldHelpers_Enum(t *testing.T) {
+	db := setupTestDB(t)
+	seedUsers(t, db)
+
+	if values := generated.User.Role.Values(); len(values) != 2 || values[0] != models.RoleActive || values[1] != models.RolePending {
+		t.Fatalf("unexpected enum values: %v", values)
+	}
+
+	users, err := typed.G[models.User](db).
+		Where(generated.User.Role.In(models.RolePending)).
+		Find(context.Background())
+	if err != nil {
+		t.Fatalf("Where(Role.In(...)) failed: %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 pending users, got %d", len(users))
+	}
+}
+
+func TestFieldHelpers_JSON(t *testing.T) {
+	db := setupTestDB(t)
+
+	pets := []models.Pet{
+		{Name: "rex", Tags: []string{"dog", "good"}},
+		{Name: "tom", Tags: []string{"cat"}},
+	}
+	if err := db.Create(&pets).Error; err != nil {
+		t.Fatalf("failed to seed pets: %v", err)
+	}
+
+	got, err := typed.G[models.Pet](db).
+		Where(generated.Pet.Tags.Contains("good")).
+		Find(context.Background())
+	if err != nil {
+		t.Fatalf("Where(Tags.Contains(...)) failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "rex" {
+		t.Fatalf("expected rex, got %+v", got)
+	}
+
+	got, err = typed.G[models.Pet](db).
+		Where(generated.Pet.Tags.Equal("$[0]", "cat")).
+		Find(context.Background())
+	if err != nil {
+		t.Fatalf("Where(Tags.Equal(...)) failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "tom" {
+		t.Fatalf("expected tom, got %+v", got)
+	}
+
+	if _, err := typed.G[models.Pet](db).
+		Where(generated.Pet.Name.Eq("tom")).
+		Set(generated.Pet.Tags.Set([]string{"cat", "lazy"})).
+		Update(context.Background()); err != nil {
+		t.Fatalf("Set(Tags.Set(...)) failed: %v", err)
+	}
+	pet, err := typed.G[models.Pet](db).Where(generated.Pet.Name.Eq("tom")).Take(context.Background())
+	if err != nil || len(pet.Tags) != 2 || pet.Tags[1] != "lazy" {
+		t.Fatalf("expected updated tags, got %+v, err %v", pet.Tags, err)
+	}
+}
+
+func TestFieldHelpers_PermissionTags(t *testing.T) {
+	db := setupTestDB(t)
+
+	// create-only fields can still be set on create, read-only fields only be queried
+	if err := typed.G[models.Toy](db).
+		Set(generated.Toy.Name.Set("ball"), generated.Toy.CreatedBy.Set("alice")).
+		Create(context.Background()); err != nil {
+		t.Fatalf("Set(CreatedBy.Set(...)).Create failed: %v", err)
+	}
+
+	toys, err := typed.G[models.Toy](db).
+		Where(generated.Toy.CreatedBy.Eq("alice"), generated.Toy.Code.IsNull()).
+		Select(generated.Toy.AllFields()...).
+		Find(context.Background())
+	if err != nil {
+		t.Fatalf("Where(CreatedBy.Eq(...)) failed: %v", err)
+	}
+	if len(toys) != 1 || toys[0].Name != "ball" {
+		t.Fatalf("expected the created toy, got %+v", toys)
+	}
+}
+
+func TestFieldHelpers_Nullable(t *testing.T) {
+	db := setupTestDB(t)
+	seedUsers(t, db)
+
+	birthday := time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)
+	managerID := uint(1)
+	if _, err := typed.G[models.User](db).
+		Where(generated.User.Name.Eq("bob")).
+		Set(generated.User.Birthday.Set(&birthday), generated.User.ManagerID.Set(&managerID), generated.User.CompanyID.Set(nil)).
+		Update(context.Background()); err != nil {
+		t.Fatalf("Set(pointer values) failed: %v", err)
+	}
+
+	bob, err := typed.G[models.User](db).Where(generated.User.Name.Eq("bob")).First(context.Background())
+	if err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if bob.Birthday == nil || !bob.Birthday.Equal(birthday) || bob.ManagerID == nil || *bob.ManagerID != 1 || bob.CompanyID != nil {
+		t.Fatalf("unexpected nullable values: birthday=%v manager=%v company=%v", bob.Birthday, bob.ManagerID, bob.CompanyID)
+	}
+
+	if _, err := typed.G[models.User](db).
+		Where(generated.User.Name.Eq("bob")).
+		Set(generated.User.Birthday.Set(nil), generated.User.ManagerID.SetNull()).
+		Update(context.Background()); err != nil {
+		t.Fatalf("Set(nil) failed: %v", err)
+	}
+
+	count, err := typed.G[models.User](db).
+		Where(generated.User.Name.Eq("bob"), generated.User.Birthday.IsNull(), generated.User.ManagerID.IsNull()).
+		Count(context.Background(), "*")
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected birthday and manager of bob set to NULL, got %d rows", count)
+	}
+}
+
+func TestFieldHelpers_PrimaryKey(t *testing.T) {
+	db := setupTestDB(t)
+	seedUsers(t, db)
+
+	alice, err := typed.G[models.User](db).Where(generated.User.Name.Eq("alice")).First(context.Background())
+	if err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+
+	got, err := typed.G[models.User](db).Where(generated.User.ByID(alice.ID)).First(context.Background())
+	if err != nil {
+		t.Fatalf("ByID failed: %v", err)
+	}
+	if got.Name != "alice" {
+		t.Fatalf("expected alice, got %q", got.Name)
+	}
+
+	if pk := generated.User.PrimaryKey(); len(pk) != 1 || pk[0].Name != "id" {
+		t.Fatalf("unexpected primary key of User: %v", pk)
+	}
+	if pk := generated.Language.PrimaryKey(); len(pk) != 1 || pk[0].Name != "code" {
+		t.Fatalf("unexpected primary key of Language: %v", pk)
+	}
+}
+
+func TestFieldHelpers_UniqueIndexes_OnConflict(t *testing.T) {
+	db := setupTestDB(t)
+
+	if err := db.Create(&models.Company{Name: "acme"}).Error; err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+
+	onConflict := clause.OnConflict{Columns: generated.Company.UniqueIndexes.Name.Columns(), DoNothing: true}
+	if err := db.Clauses(onConflict).Create(&models.Company{Name: "acme"}).Error; err != nil {
+		t.Fatalf("Create with OnConflict(UniqueIndexes.Name) failed: %v", err)
+	}
+
+	count, err := typed.G[models.Company](db).Where(generated.Company.Name.Eq("acme")).Count(context.Background(), "*")
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected the conflicting company to be skipped, got %d rows", count)
+	}
+	if name := generated.Company.UniqueIndexes.Name.Name(); name != "idx_companies_name" {
+		t.Fatalf("unexpected index name %q", name)
+	}
+}
+
+func TestFieldHelpers_Schema(t *testing.T) {
+	if schema := generated.Company.Schemas()["name"]; schema != (field.ColumnSchema{Size: 64}) {
+		t.Fatalf("unexpected schema of Company.Name: %+v", schema)
+	}
+}
+
+func TestFieldHelpers_UpdateVersioned(t *testing.T) {
+	db := setupTestDB(t)
+
+	company := models.Company{Name: "acme"}
+	if err := db.Create(&company).Error; err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+
+	byID := typed.G[models.Company](db).Where(generated.Company.ByID(company.ID))
+	if err := typed.UpdateVersioned(context.Background(), byID, generated.Company.Version, company.Version, generated.Company.Name.Set("acme inc")); err != nil {
+		t.Fatalf("UpdateVersioned failed: %v", err)
+	}
+
+	// The stale version doesn't match anymore
+	err := typed.UpdateVersioned(context.Background(), byID, generated.Company.Version, company.Version, generated.Company.Name.Set("acme corp"))
+	if !errors.Is(err, typed.ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict with a stale version, got %v", err)
+	}
+
+	got, err := byID.First(context.Background())
+	if err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if got.Name != "acme inc" || got.Version != company.Version+1 {
+		t.Fatalf("expected name acme inc at version %d, got %q at version %d", company.Version+1, got.Name, got.Version)
+	}
+}
+
+func TestFieldHelpers_SoftDeleteScopes(t *testing.T) {
+	db := setupTestDB(t)
+	seedUsers(t, db)
+
+	if _, err := typed.G[models.User](db).Where(generated.User.Name.Eq("bob")).Delete(context.Background()); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	count := func(q typed.ChainInterface[models.User]) int64 {
+		t.Helper()
+		n, err := q.Count(context.Background(), "*")
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		return n
+	}
+
+	users := typed.G[models.User](db)
+	if n := count(users.Scopes(generated.User.WithDeleted())); n != 4 {
+		t.Errorf("expected 4 users with deleted, got %d", n)
+	}
+	if n := count(users.Scopes(generated.User.OnlyDeleted())); n != 1 {
+		t.Errorf("expected 1 deleted user, got %d", n)
+	}
+	if n := count(users.Scopes(generated.User.WithDeleted()).Where(generated.User.NotDeleted())); n != 3 {
+		t.Errorf("expected 3 users not deleted, got %d", n)
+	}
+}
+
+func TestFieldHelpers_View(t *testing.T) {
+	db := setupTestDB(t)
+	seedUsers(t, db)
+
+	if err := db.Exec(generated.AdultUser.CreateViewSQL()).Error; err != nil {
+		t.Fatalf("failed to create view %s: %v", generated.AdultUser.ViewName(), err)
+	}
+
+	adults, err := typed.G[models.AdultUser](db).Where(generated.AdultUser.Age.Gte(30)).Find(context.Background())
+	if err != nil {
+		t.Fatalf("Find on view failed: %v", err)
+	}
+	if len(adults) != 2 {
+		t.Fatalf("expected 2 adults of 30 or more, got %d", len(adults))
+	}
+}
+
+func TestScopes_FromConfig(t *testing.T) {
+	db := setupTestDB(t)
+	seedUsers(t, db)
+
+	users, err := typed.G[models.User](db).Scopes(generated.UserScopes.ActiveAdults()).Find(context.Background())
+	if err != nil {
+		t.Fatalf("Scopes(ActiveAdults) failed: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "alice" {
+		t.Fatalf("expected only alice as active adult, got %+v", users)
+	}
+
+	count, err := typed.G[models.User](db).Scopes(generated.UserScopes.ActiveAdults()).Where(generated.User.Age.Gt(20)).Count(context.Background(), "*")
+	if err != nil {
+		t.Fatalf("Scopes(ActiveAdults) with Where failed: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected no active adult older than 20, got %d", count)
+	}
+}
+
+func TestRepository_CRUD(t *testing.T) {
+	db := setupTestDB(t)
+	seedUsers(t, db)
+
+	ctx := context.Background()
+	repo := generated.NewUserRepository(db)
+
+	erin := models.User{Name: "erin", Age: 25, Role: "active", IsAdult: true}
+	if err := repo.Create(ctx, &erin); err != nil || erin.ID == 0 {
+		t.Fatalf("Create failed: %v, id %d", err, erin.ID)
+	}
+
+	got, err := repo.GetByID(ctx, erin.ID)
+	if err != nil || got.Name != "erin" {
+		t.Fatalf("GetByID failed: %v, got %+v", err, got)
+	}
+
+	all, err := repo.List(ctx)
+	if err != nil || len(all) != 5 {
+		t.Fatalf("expected 5 users without conds, got %d, %v", len(all), err)
+	}
+	active, err := repo.List(ctx, generated.User.Role.Eq("active"), generated.User.Age.Gte(20))
+	if err != nil || len(active) != 2 {
+		t.Fatalf("expected 2 active users of 20 or more, got %d, %v", len(active), err)
+	}
+
+	if rows, err := repo.Update(ctx, erin.ID, generated.User.Age.Set(26)); err != nil || rows != 1 {
+		t.Fatalf("Update failed: %v, rows %d", err, rows)
+	}
+	if got, err := repo.GetByID(ctx, erin.ID); err != nil || got.Age != 26 {
+		t.Fatalf("expected updated age 26, got %d, %v", got.Age, err)
+	}
+
+	if rows, err := repo.Delete(ctx, erin.ID); err != nil || rows != 1 {
+		t.Fatalf("Delete failed: %v, rows %d", err, rows)
+	}
+	if _, err := repo.GetByID(ctx, erin.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected gorm.ErrRecordNotFound after Delete, got %v", err)
+	}
+}
+
+func TestFixtures(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	first, second := generated.NewUserFixture(), generated.NewUserFixture(func(u *models.User) {
+		u.Age = 42
+	})
+	if first.Name == "" || first.Name == second.Name || first.Role != models.RoleActive {
+		t.Fatalf("expected unique names and the first role, got %+v and %+v", first, second)
+	}
+	if second.Age != 42 {
+		t.Fatalf("expected overridden age 42, got %d", second.Age)
+	}
+
+	repo := generated.NewUserRepository(db)
+	for _, user := range []*models.User{&first, &second} {
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatalf("Create fixture failed: %v", err)
+		}
+	}
+
+	company := generated.NewCompanyFixture()
+	if err := generated.NewCompanyRepository(db).Create(ctx, &company); err != nil {
+		t.Fatalf("Create company fixture failed: %v", err)
+	}
+	if n, err := typed.G[models.Company](db).Where(generated.Company.Name.Eq(company.Name)).Count(ctx, "*"); err != nil || n != 1 {
+		t.Fatalf("expected the company fixture to be created, got %d, %v", n, err)
+	}
+}
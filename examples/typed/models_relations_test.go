@@ -2,11 +2,15 @@ package examples
 
 import (
 	"context"
+	"sort"
+	"sync"
 	"testing"
 
 	"gorm.io/cli/gorm/examples/models"
 	generated "gorm.io/cli/gorm/examples/typed/models"
+	"gorm.io/cli/gorm/field"
 	"gorm.io/cli/gorm/typed"
+	"gorm.io/gorm/schema"
 )
 
 func TestAssociation_Create_SingleParent(t *testing.T) {
@@ -111,4 +115,128 @@ func TestAssociation_Update_WithConditions(t *testing.T) {
 	if len(pets) != 1 {
 		t.Fatalf("expected 1 updated pet, got %d", len(pets))
 	}
-}
\ No newline at end of file
+}
+func TestAssociationMetadata_MatchesSchema(t *testing.T) {
+	s, err := schema.Parse(&models.User{}, &sync.Map{}, schema.NamingStrategy{IdentifierMaxLength: 64})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	kinds := map[schema.RelationshipType]field.AssociationKind{
+		schema.HasOne:    field.HasOne,
+		schema.HasMany:   field.HasMany,
+		schema.BelongsTo: field.BelongsTo,
+		schema.Many2Many: field.Many2Many,
+	}
+
+	associations := map[string]field.AssociationMetadata{
+		"Account":   generated.User.Account.Metadata(),
+		"Pets":      generated.User.Pets.Metadata(),
+		"Toys":      generated.User.Toys.Metadata(),
+		"Company":   generated.User.Company.Metadata(),
+		"Manager":   generated.User.Manager.Metadata(),
+		"Team":      generated.User.Team.Metadata(),
+		"Languages": generated.User.Languages.Metadata(),
+		"Friends":   generated.User.Friends.Metadata(),
+	}
+
+	for name, metadata := range associations {
+		rel := s.Relationships.Relations[name]
+		if rel == nil {
+			t.Fatalf("relationship %s not found", name)
+		}
+
+		if metadata.Kind != kinds[rel.Type] {
+			t.Errorf("%s: expected kind %v, got %v", name, kinds[rel.Type], metadata.Kind)
+		}
+
+		if rel.JoinTable != nil {
+			if metadata.JoinTable != rel.JoinTable.Table {
+				t.Errorf("%s: expected join table %v, got %v", name, rel.JoinTable.Table, metadata.JoinTable)
+			}
+			for _, ref := range rel.References {
+				expected := metadata.JoinReferences
+				if ref.OwnPrimaryKey {
+					expected = metadata.JoinForeignKey
+				}
+				if ref.ForeignKey.DBName != expected {
+					t.Errorf("%s: unexpected join table column %v, expected %v", name, ref.ForeignKey.DBName, expected)
+				}
+			}
+			continue
+		}
+
+		if rel.Polymorphic != nil {
+			if metadata.PolymorphicType != rel.Polymorphic.PolymorphicType.DBName || metadata.PolymorphicID != rel.Polymorphic.PolymorphicID.DBName || metadata.PolymorphicValue != rel.Polymorphic.Value {
+				t.Errorf("%s: unexpected polymorphic metadata %+v, expected %+v", name, metadata, rel.Polymorphic)
+			}
+		}
+
+		for _, ref := range rel.References {
+			if ref.PrimaryKey == nil {
+				continue
+			}
+			if ref.ForeignKey.DBName != metadata.ForeignKey || ref.PrimaryKey.DBName != metadata.References {
+				t.Errorf("%s: expected foreign key %v references %v, got %+v", name, ref.ForeignKey.DBName, ref.PrimaryKey.DBName, metadata)
+			}
+		}
+	}
+}
+
+func TestAssociation_Join(t *testing.T) {
+	db := setupTestDB(t)
+
+	company := models.Company{Name: "acme"}
+	if err := db.Create(&company).Error; err != nil {
+		t.Fatalf("failed to create company: %v", err)
+	}
+	seedUsers(t, db, models.User{Name: "erin", Age: 25, CompanyID: &company.ID})
+
+	users, err := typed.G[models.User](db).
+		Joins(generated.User.Company.Join(), nil).
+		Where(generated.Company.Name.WithTable("Company").Eq("acme")).
+		Find(context.Background())
+	if err != nil {
+		t.Fatalf("Joins(Company.Join()) failed: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "erin" || users[0].Company.Name != "acme" {
+		t.Fatalf("expected erin joined with acme, got %+v", users)
+	}
+
+	users, err = typed.G[models.User](db).
+		Joins(generated.User.Company.LeftJoin(), nil).
+		Find(context.Background())
+	if err != nil {
+		t.Fatalf("Joins(Company.LeftJoin()) failed: %v", err)
+	}
+	if len(users) != 5 {
+		t.Fatalf("expected all 5 users with left join, got %d", len(users))
+	}
+}
+
+func TestAssociation_SelfJoinAlias(t *testing.T) {
+	db := setupTestDB(t)
+
+	users := seedUsers(t, db)
+	// alice manages bob and cathy
+	if err := db.Model(&models.User{}).Where("name IN ?", []string{"bob", "cathy"}).Update("manager_id", users[0].ID).Error; err != nil {
+		t.Fatalf("failed to set managers: %v", err)
+	}
+
+	managers := generated.UserAs("managers")
+	if tbl := managers.Table(); tbl.Name != "users" || tbl.Alias != "managers" {
+		t.Fatalf("unexpected aliased table: %+v", tbl)
+	}
+
+	found, err := typed.G[models.User](db).
+		Joins(generated.User.Manager.Join().As(managers.Alias), nil).
+		Where(managers.Name.Eq("alice"), managers.Age.Gt(18)).
+		Find(context.Background())
+	if err != nil {
+		t.Fatalf("self join with alias failed: %v", err)
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+	if len(found) != 2 || found[0].Name != "bob" || found[1].Name != "cathy" {
+		t.Fatalf("expected bob and cathy managed by alice, got %+v", found)
+	}
+}
@@ -4,11 +4,16 @@ package examples
 
 import (
 	"context"
+	"database/sql"
+	"iter"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
+	"gorm.io/cli/gorm/examples"
 	"gorm.io/cli/gorm/examples/models"
+	"gorm.io/cli/gorm/field"
 	"gorm.io/cli/gorm/typed"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -17,24 +22,90 @@ import (
 func Query[T any](db *gorm.DB, opts ...clause.Expression) _QueryInterface[T] {
 	return _QueryImpl[T]{
 		Interface: typed.G[T](db, opts...),
+		db:        db.Clauses(opts...),
 	}
 }
 
 type _QueryInterface[T any] interface {
 	typed.Interface[T]
+	// GetByID query data by id and return it as struct
 	GetByID(ctx context.Context, id int) (T, error)
 	FilterWithColumn(ctx context.Context, column string, value string) (T, error)
+	FilterByAllowedColumn(ctx context.Context, column string, value string) ([]T, error)
+	FilterByModelColumn(ctx context.Context, column string, value string) ([]T, error)
 	QueryWith(ctx context.Context, user models.User) (T, error)
 	UpdateInfo(ctx context.Context, user models.User, id int) error
 	Filter(ctx context.Context, users []models.User) ([]T, error)
 	FilterByNameAndAge(ctx context.Context, name string, age int) _QueryInterface[T]
 	FilterWithTime(ctx context.Context, start time.Time, end time.Time) ([]T, error)
+	// FindCreatedWithin finds the records created during the last period, computed when the query runs
+	FindCreatedWithin(ctx context.Context, period time.Duration) ([]T, error)
+	// FindAdultsByNamePrefix finds the adults whose name starts with one of the prefixes, all adults without prefixes
+	FindAdultsByNamePrefix(ctx context.Context, prefixes []string) ([]T, error)
+	// Page returns a page of records, rendered as LIMIT/OFFSET or OFFSET/FETCH per dialect
+	Page(ctx context.Context, size int, offset int) ([]T, error)
+	// SortBy returns the records sorted by one of the allowed columns
+	SortBy(ctx context.Context, column string, direction string) ([]T, error)
+	// FindByNameInsensitive finds records by name ignoring case, with the syntax of the current dialect
+	FindByNameInsensitive(ctx context.Context, name string) ([]T, error)
+	// OldestOfRole returns the oldest record of the role, records without an age last
+	OldestOfRole(ctx context.Context, role string) (T, error)
+	// FindByNameLike finds the records whose name matches the LIKE pattern
+	//
+	// The SQL is declared with sql(...), so the paragraphs around it stay documentation.
+	FindByNameLike(ctx context.Context, pattern string) ([]T, error)
+	// AdultsByRole finds the adult records of the role, its SQL is the AdultsOfRoleSQL constant
+	AdultsByRole(ctx context.Context, role string) ([]T, error)
+	// FindByRoles finds the records of the roles, none when roles is empty
+	FindByRoles(ctx context.Context, roles []models.Role) ([]T, error)
+	// SearchByNameAndAge filters by name unless blank and by age when it's plausible
+	SearchByNameAndAge(ctx context.Context, name string, age int) ([]T, error)
+	// AgeBetween matches the records whose age is in the range, to combine with other conditions
+	AgeBetween(from int, to int) clause.Expression
+	// RenameByID renames the record, it returns the rows affected
+	RenameByID(ctx context.Context, name string, id uint) (int, error)
+	// DeleteByRole deletes the records of the role, it returns the rows affected
+	DeleteByRole(ctx context.Context, role string) (int, error)
+	// CountByRole counts the records of the role
+	CountByRole(ctx context.Context, role string) (int64, error)
+	// CountAdultsByRole counts the adult records of the role, ${adult} is declared in TemplateVars
+	CountAdultsByRole(ctx context.Context, role string) (int64, error)
+	// CountAdults counts the adult records, scanned directly into the count
+	CountAdults(ctx context.Context) (int64, error)
+	// ExistsByName reports whether a record has the name
+	ExistsByName(ctx context.Context, name string) (bool, error)
+	// SwapRoles swaps the roles a and b of the records, the statements run in a transaction
+	SwapRoles(ctx context.Context, a string, b string) error
+	// Insert inserts the record with the columns of its fields, the id is generated by the database
+	Insert(ctx context.Context, user models.User) error
+	// InsertWithID inserts the record and returns the id generated by the database
+	InsertWithID(ctx context.Context, user models.User) (int64, error)
+	// MarkMinorsPending sets the role of the records younger than age to pending, it returns the rows affected
+	MarkMinorsPending(ctx context.Context, age int) (int64, error)
+	// InsertBatch inserts the records, two per INSERT statement, it returns the rows affected
+	InsertBatch(ctx context.Context, users []models.User) (int64, error)
+	// TouchByID sets the update time of the record
+	TouchByID(ctx context.Context, id uint, at time.Time) (sql.Result, error)
+	// StreamByRole iterates over the records of the role, scanning a row at a time
+	StreamByRole(ctx context.Context, role string) iter.Seq2[T, error]
+	// EachAdult calls fn with each adult record, scanning a row at a time, it stops at the first error
+	EachAdult(ctx context.Context, fn func(T) error) error
+	// CountPerRole returns the number of records of each role, scanned into RoleStats
+	CountPerRole(ctx context.Context) ([]examples.RoleStats, error)
+	// RoleSummary returns the number of records and the maximum age of each role, as rows keyed by column
+	RoleSummary(ctx context.Context) ([]map[string]any, error)
+	// StatsByRole returns the number of records and the maximum age of the role, as a row keyed by column
+	StatsByRole(ctx context.Context, role string) (map[string]any, error)
+	// OlderThanRoleAverage returns the records older than the average age of their role
+	OlderThanRoleAverage(ctx context.Context) ([]T, error)
 }
 
 type _QueryImpl[T any] struct {
 	typed.Interface[T]
+	db *gorm.DB
 }
 
+// GetByID query data by id and return it as struct
 func (e _QueryImpl[T]) GetByID(ctx context.Context, id int) (T, error) {
 	var sb strings.Builder
 	params := make([]any, 0, 2)
@@ -59,6 +130,30 @@ func (e _QueryImpl[T]) FilterWithColumn(ctx context.Context, column string, valu
 	return result, err
 }
 
+func (e _QueryImpl[T]) FilterByAllowedColumn(ctx context.Context, column string, value string) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 5)
+
+	sb.WriteString("SELECT * FROM ? WHERE ? = ? ORDER BY id")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, field.AllowedColumn(column, "name", "role"), value)
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+func (e _QueryImpl[T]) FilterByModelColumn(ctx context.Context, column string, value string) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 3)
+
+	sb.WriteString("SELECT * FROM ? WHERE ? = ? ORDER BY id")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, field.AllowedColumn(column), value)
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
 func (e _QueryImpl[T]) QueryWith(ctx context.Context, user models.User) (T, error) {
 	var sb strings.Builder
 	params := make([]any, 0, 2)
@@ -180,3 +275,592 @@ func (e _QueryImpl[T]) FilterWithTime(ctx context.Context, start time.Time, end
 	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
 	return result, err
 }
+
+// FindCreatedWithin finds the records created during the last period, computed when the query runs
+func (e _QueryImpl[T]) FindCreatedWithin(ctx context.Context, period time.Duration) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 2)
+
+	sb.WriteString("SELECT * FROM ? WHERE created_at > ? ORDER BY id")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, time.Now().Add(-period))
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// FindAdultsByNamePrefix finds the adults whose name starts with one of the prefixes, all adults without prefixes
+func (e _QueryImpl[T]) FindAdultsByNamePrefix(ctx context.Context, prefixes []string) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 5)
+
+	sb.WriteString("SELECT * FROM ? WHERE is_adult = true")
+	params = append(params, clause.Table{Name: clause.CurrentTable})
+	{
+		loopLen := len(prefixes)
+		loopIndex := 0
+		for _, prefix := range prefixes {
+			loopFirst := loopIndex == 0
+			loopLast := loopIndex == loopLen-1
+			if loopFirst {
+				sb.WriteString(" AND (")
+			}
+			sb.WriteString(" name LIKE ?")
+			params = append(params, prefix+"%")
+			if loopLast {
+				sb.WriteString(" )")
+			} else {
+				sb.WriteString(" OR")
+			}
+			loopIndex++
+		}
+	}
+	sb.WriteString(" ORDER BY id")
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// Page returns a page of records, rendered as LIMIT/OFFSET or OFFSET/FETCH per dialect
+func (e _QueryImpl[T]) Page(ctx context.Context, size int, offset int) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 3)
+
+	sb.WriteString("SELECT * FROM ? ORDER BY id")
+	params = append(params, clause.Table{Name: clause.CurrentTable})
+	sb.WriteString(" ?")
+	params = append(params, field.Paginate(int(size), int(offset)))
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// SortBy returns the records sorted by one of the allowed columns
+func (e _QueryImpl[T]) SortBy(ctx context.Context, column string, direction string) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 6)
+
+	sb.WriteString("SELECT * FROM ?")
+	params = append(params, clause.Table{Name: clause.CurrentTable})
+	sb.WriteString(" ?")
+	params = append(params, field.OrderBy(string(column), string(direction), "name", "age", "created_at"))
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// FindByNameInsensitive finds records by name ignoring case, with the syntax of the current dialect
+func (e _QueryImpl[T]) FindByNameInsensitive(ctx context.Context, name string) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 5)
+
+	sb.WriteString("SELECT * FROM ? WHERE")
+	params = append(params, clause.Table{Name: clause.CurrentTable})
+	{
+		dialects := make([]field.DialectSQL, 0, 3)
+		{
+			var tmp strings.Builder
+			params := make([]any, 0)
+			tmp.WriteString(" name ILIKE ?")
+			params = append(params, name)
+			dialects = append(dialects, field.DialectSQL{Dialects: []string{"postgres"}, SQL: strings.TrimSpace(tmp.String()), Vars: params})
+		}
+		{
+			var tmp strings.Builder
+			params := make([]any, 0)
+			tmp.WriteString(" name = ? COLLATE utf8mb4_general_ci")
+			params = append(params, name)
+			dialects = append(dialects, field.DialectSQL{Dialects: []string{"mysql"}, SQL: strings.TrimSpace(tmp.String()), Vars: params})
+		}
+		{
+			var tmp strings.Builder
+			params := make([]any, 0)
+			tmp.WriteString(" LOWER(name) = LOWER(?)")
+			params = append(params, name)
+			dialects = append(dialects, field.DialectSQL{SQL: strings.TrimSpace(tmp.String()), Vars: params})
+		}
+		sb.WriteString(" ?")
+		params = append(params, field.Dialect(dialects...))
+	}
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// OldestOfRole returns the oldest record of the role, records without an age last
+func (e _QueryImpl[T]) OldestOfRole(ctx context.Context, role string) (T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 7)
+
+	{
+		dialects := make([]field.DialectSQL, 0, 3)
+		{
+			var tmp strings.Builder
+			params := make([]any, 0)
+			tmp.WriteString(" SELECT * FROM ? WHERE role = ? ORDER BY age DESC NULLS LAST, id LIMIT 1")
+			params = append(params, clause.Table{Name: clause.CurrentTable}, role)
+			dialects = append(dialects, field.DialectSQL{Dialects: []string{"postgres"}, SQL: strings.TrimSpace(tmp.String()), Vars: params})
+		}
+		{
+			var tmp strings.Builder
+			params := make([]any, 0)
+			tmp.WriteString(" SELECT * FROM ? WHERE role = ?")
+			params = append(params, clause.Table{Name: clause.CurrentTable}, role)
+			tmp.WriteString(" ORDER BY age IS NULL, age DESC, id LIMIT 1")
+			dialects = append(dialects, field.DialectSQL{Dialects: []string{"mysql", "sqlite"}, SQL: strings.TrimSpace(tmp.String()), Vars: params})
+		}
+		{
+			var tmp strings.Builder
+			params := make([]any, 0)
+			tmp.WriteString(" SELECT * FROM ? WHERE role = ? ORDER BY age DESC, id LIMIT 1")
+			params = append(params, clause.Table{Name: clause.CurrentTable}, role)
+			dialects = append(dialects, field.DialectSQL{SQL: strings.TrimSpace(tmp.String()), Vars: params})
+		}
+		sb.WriteString("?")
+		params = append(params, field.Dialect(dialects...))
+	}
+
+	var result T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// FindByNameLike finds the records whose name matches the LIKE pattern
+//
+// The SQL is declared with sql(...), so the paragraphs around it stay documentation.
+func (e _QueryImpl[T]) FindByNameLike(ctx context.Context, pattern string) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 2)
+
+	sb.WriteString("SELECT * FROM ? WHERE name LIKE ? ORDER BY id")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, pattern)
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// AdultsByRole finds the adult records of the role, its SQL is the AdultsOfRoleSQL constant
+func (e _QueryImpl[T]) AdultsByRole(ctx context.Context, role string) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 2)
+
+	sb.WriteString("SELECT * FROM ? WHERE is_adult = true AND role = ? ORDER BY id")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, role)
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// FindByRoles finds the records of the roles, none when roles is empty
+func (e _QueryImpl[T]) FindByRoles(ctx context.Context, roles []models.Role) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 2)
+
+	sb.WriteString("SELECT * FROM ?")
+	params = append(params, clause.Table{Name: clause.CurrentTable})
+	{
+		var tmp strings.Builder
+		if len(roles) > 0 {
+			tmp.WriteString(" role IN ?")
+			params = append(params, roles)
+		} else {
+			tmp.WriteString(" 1 = 0")
+		}
+		c := strings.TrimSpace(tmp.String())
+		if c != "" {
+			reTrim := regexp.MustCompile(`(?i)^\s*(?:and|or)\s+|\s+(?:and|or)\s*$`)
+			c = reTrim.ReplaceAllString(c, "")
+			sb.WriteString(" WHERE ")
+			sb.WriteString(c)
+		}
+	}
+	sb.WriteString(" ORDER BY id")
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// SearchByNameAndAge filters by name unless blank and by age when it's plausible
+func (e _QueryImpl[T]) SearchByNameAndAge(ctx context.Context, name string, age int) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 3)
+
+	sb.WriteString("SELECT * FROM ?")
+	params = append(params, clause.Table{Name: clause.CurrentTable})
+	{
+		var tmp strings.Builder
+		if examples.NotEmpty(name) {
+			tmp.WriteString(" name=?")
+			params = append(params, name)
+		}
+		if examples.InRange(age, 1, 150) {
+			tmp.WriteString(" AND age=?")
+			params = append(params, age)
+		}
+		c := strings.TrimSpace(tmp.String())
+		if c != "" {
+			reTrim := regexp.MustCompile(`(?i)^\s*(?:and|or)\s+|\s+(?:and|or)\s*$`)
+			c = reTrim.ReplaceAllString(c, "")
+			sb.WriteString(" WHERE ")
+			sb.WriteString(c)
+		}
+	}
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// AgeBetween matches the records whose age is in the range, to combine with other conditions
+func (e _QueryImpl[T]) AgeBetween(from int, to int) clause.Expression {
+	var sb strings.Builder
+	params := make([]any, 0, 2)
+
+	sb.WriteString("age BETWEEN ? AND ?")
+	params = append(params, from, to)
+
+	return clause.Expr{SQL: sb.String(), Vars: params}
+}
+
+// RenameByID renames the record, it returns the rows affected
+func (e _QueryImpl[T]) RenameByID(ctx context.Context, name string, id uint) (int, error) {
+	var where clause.Expr
+	{
+		var sb strings.Builder
+		params := make([]any, 0, 1)
+
+		sb.WriteString("id=?")
+		params = append(params, id)
+
+		where = clause.Expr{SQL: sb.String(), Vars: params}
+	}
+
+	var sb strings.Builder
+	params := make([]any, 0, 1)
+
+	sb.WriteString("name=?")
+	params = append(params, name)
+
+	return e.Where(where).Set(field.Assignments(sb.String(), params...)).Update(ctx)
+}
+
+// DeleteByRole deletes the records of the role, it returns the rows affected
+func (e _QueryImpl[T]) DeleteByRole(ctx context.Context, role string) (int, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 1)
+
+	sb.WriteString("role = ?")
+	params = append(params, role)
+
+	return e.Where(clause.Expr{SQL: sb.String(), Vars: params}).Delete(ctx)
+}
+
+// CountByRole counts the records of the role
+func (e _QueryImpl[T]) CountByRole(ctx context.Context, role string) (int64, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 1)
+
+	sb.WriteString("role = ?")
+	params = append(params, role)
+
+	return e.Where(clause.Expr{SQL: sb.String(), Vars: params}).Count(ctx, "*")
+}
+
+// CountAdultsByRole counts the adult records of the role, ${adult} is declared in TemplateVars
+func (e _QueryImpl[T]) CountAdultsByRole(ctx context.Context, role string) (int64, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 1)
+
+	sb.WriteString("is_adult = true AND role = ?")
+	params = append(params, role)
+
+	return e.Where(clause.Expr{SQL: sb.String(), Vars: params}).Count(ctx, "*")
+}
+
+// CountAdults counts the adult records, scanned directly into the count
+func (e _QueryImpl[T]) CountAdults(ctx context.Context) (int64, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 1)
+
+	sb.WriteString("SELECT COUNT(*) FROM ? WHERE is_adult = true")
+	params = append(params, clause.Table{Name: clause.CurrentTable})
+	sb.WriteString(" /* soft deleted records too */")
+
+	var r T
+	var result int64
+	err := e.db.WithContext(ctx).Model(r).Raw(sb.String(), params...).Row().Scan(&result)
+	return result, err
+}
+
+// ExistsByName reports whether a record has the name
+func (e _QueryImpl[T]) ExistsByName(ctx context.Context, name string) (bool, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 2)
+
+	sb.WriteString("SELECT EXISTS(SELECT 1 FROM ? WHERE name = ?)")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, name)
+
+	var r T
+	var result bool
+	err := e.db.WithContext(ctx).Model(r).Raw(sb.String(), params...).Row().Scan(&result)
+	return result, err
+}
+
+// SwapRoles swaps the roles a and b of the records, the statements run in a transaction
+func (e _QueryImpl[T]) SwapRoles(ctx context.Context, a string, b string) error {
+	return e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		q := gorm.G[T](tx)
+		{
+			var sb strings.Builder
+			params := make([]any, 0, 2)
+
+			sb.WriteString("UPDATE ? SET role = '~swap' WHERE role = ?")
+			params = append(params, clause.Table{Name: clause.CurrentTable}, a)
+
+			if err := q.Exec(ctx, sb.String(), params...); err != nil {
+				return err
+			}
+		}
+		{
+			var sb strings.Builder
+			params := make([]any, 0, 3)
+
+			sb.WriteString("UPDATE ? SET role = ? WHERE role = ?")
+			params = append(params, clause.Table{Name: clause.CurrentTable}, a, b)
+
+			if err := q.Exec(ctx, sb.String(), params...); err != nil {
+				return err
+			}
+		}
+		{
+			var sb strings.Builder
+			params := make([]any, 0, 2)
+
+			sb.WriteString("UPDATE ? SET role = ? WHERE role = '~swap'")
+			params = append(params, clause.Table{Name: clause.CurrentTable}, b)
+
+			if err := q.Exec(ctx, sb.String(), params...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Insert inserts the record with the columns of its fields, the id is generated by the database
+func (e _QueryImpl[T]) Insert(ctx context.Context, user models.User) error {
+	var sb strings.Builder
+	params := make([]any, 0, 14)
+
+	sb.WriteString("INSERT INTO ? (created_at, updated_at, deleted_at, name, age, birthday, score, last_login, company_id, manager_id, role, is_adult, profile) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, user.CreatedAt, user.UpdatedAt, user.DeletedAt, user.Name, user.Age, user.Birthday, user.Score, user.LastLogin, user.CompanyID, user.ManagerID, user.Role, user.IsAdult, user.Profile)
+
+	return e.Exec(ctx, sb.String(), params...)
+}
+
+// InsertWithID inserts the record and returns the id generated by the database
+func (e _QueryImpl[T]) InsertWithID(ctx context.Context, user models.User) (int64, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 14)
+
+	sb.WriteString("INSERT INTO ? (created_at, updated_at, deleted_at, name, age, birthday, score, last_login, company_id, manager_id, role, is_adult, profile) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, user.CreatedAt, user.UpdatedAt, user.DeletedAt, user.Name, user.Age, user.Birthday, user.Score, user.LastLogin, user.CompanyID, user.ManagerID, user.Role, user.IsAdult, user.Profile)
+
+	var r T
+	tx := e.db.WithContext(ctx).Session(&gorm.Session{DryRun: true}).Model(r).Exec(sb.String(), params...)
+	if tx.Error != nil {
+		return 0, tx.Error
+	}
+	result, err := tx.Statement.ConnPool.ExecContext(ctx, tx.Statement.SQL.String(), tx.Statement.Vars...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// MarkMinorsPending sets the role of the records younger than age to pending, it returns the rows affected
+func (e _QueryImpl[T]) MarkMinorsPending(ctx context.Context, age int) (int64, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 2)
+
+	sb.WriteString("UPDATE ? SET role = 'pending' WHERE age < ?")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, age)
+
+	var r T
+	tx := e.db.WithContext(ctx).Model(r).Exec(sb.String(), params...)
+	return tx.RowsAffected, tx.Error
+}
+
+// InsertBatch inserts the records, two per INSERT statement, it returns the rows affected
+func (e _QueryImpl[T]) InsertBatch(ctx context.Context, users []models.User) (int64, error) {
+	var rowsAffected int64
+	err := e.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
+		for users := range slices.Chunk(users, 2) {
+			var sb strings.Builder
+			params := make([]any, 0, 5)
+
+			sb.WriteString("INSERT INTO ? (name, age, role, is_adult) VALUES")
+			params = append(params, clause.Table{Name: clause.CurrentTable})
+			sb.WriteString(" ")
+			for i, value := range users {
+				if i > 0 {
+					sb.WriteString(",")
+				}
+				sb.WriteString("(?,?,?,?)")
+				params = append(params, value.Name, value.Age, value.Role, value.IsAdult)
+			}
+
+			var r T
+			tx := db.Model(r).Exec(sb.String(), params...)
+			if tx.Error != nil {
+				return tx.Error
+			}
+			rowsAffected += tx.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rowsAffected, nil
+}
+
+// TouchByID sets the update time of the record
+func (e _QueryImpl[T]) TouchByID(ctx context.Context, id uint, at time.Time) (sql.Result, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 3)
+
+	sb.WriteString("UPDATE ? SET updated_at = ? WHERE id = ?")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, at, id)
+
+	var r T
+	tx := e.db.WithContext(ctx).Session(&gorm.Session{DryRun: true}).Model(r).Exec(sb.String(), params...)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return tx.Statement.ConnPool.ExecContext(ctx, tx.Statement.SQL.String(), tx.Statement.Vars...)
+}
+
+// StreamByRole iterates over the records of the role, scanning a row at a time
+func (e _QueryImpl[T]) StreamByRole(ctx context.Context, role string) iter.Seq2[T, error] {
+	var sb strings.Builder
+	params := make([]any, 0, 2)
+
+	sb.WriteString("SELECT * FROM ? WHERE role = ? ORDER BY id")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, role)
+
+	return func(yield func(T, error) bool) {
+		rows, err := e.Raw(sb.String(), params...).Rows(ctx)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		db := e.db.WithContext(ctx)
+		for rows.Next() {
+			var row T
+			if err := db.ScanRows(rows, &row); err != nil {
+				yield(row, err)
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// EachAdult calls fn with each adult record, scanning a row at a time, it stops at the first error
+func (e _QueryImpl[T]) EachAdult(ctx context.Context, fn func(T) error) error {
+	var sb strings.Builder
+	params := make([]any, 0, 1)
+
+	sb.WriteString("SELECT * FROM ? WHERE is_adult = true ORDER BY id")
+	params = append(params, clause.Table{Name: clause.CurrentTable})
+
+	rows, err := e.Raw(sb.String(), params...).Rows(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	db := e.db.WithContext(ctx)
+	for rows.Next() {
+		var row T
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// CountPerRole returns the number of records of each role, scanned into RoleStats
+func (e _QueryImpl[T]) CountPerRole(ctx context.Context) ([]examples.RoleStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var sb strings.Builder
+	params := make([]any, 0, 1)
+
+	sb.WriteString("SELECT role, count(*) AS total FROM ? GROUP BY role ORDER BY role")
+	params = append(params, clause.Table{Name: clause.CurrentTable})
+
+	var result []examples.RoleStats
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// RoleSummary returns the number of records and the maximum age of each role, as rows keyed by column
+func (e _QueryImpl[T]) RoleSummary(ctx context.Context) ([]map[string]any, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 1)
+
+	sb.WriteString("SELECT role, count(*) AS total, MAX(age) AS max_age FROM ? GROUP BY role ORDER BY role")
+	params = append(params, clause.Table{Name: clause.CurrentTable})
+
+	var result []map[string]any
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// StatsByRole returns the number of records and the maximum age of the role, as a row keyed by column
+func (e _QueryImpl[T]) StatsByRole(ctx context.Context, role string) (map[string]any, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 2)
+
+	sb.WriteString("SELECT count(*) AS total, MAX(age) AS max_age FROM ? WHERE role = ?")
+	params = append(params, clause.Table{Name: clause.CurrentTable}, role)
+
+	var result map[string]any
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
+
+// OlderThanRoleAverage returns the records older than the average age of their role
+func (e _QueryImpl[T]) OlderThanRoleAverage(ctx context.Context) ([]T, error) {
+	var sb strings.Builder
+	params := make([]any, 0, 2)
+
+	sb.WriteString("SELECT u.* FROM ? WHERE u.age > (SELECT AVG(r.age) FROM ? WHERE r.role = u.role) ORDER BY u.id")
+	params = append(params, clause.Table{Name: clause.CurrentTable, Alias: "u"}, clause.Table{Name: clause.CurrentTable, Alias: "r"})
+
+	var result []T
+	err := e.Raw(sb.String(), params...).Scan(ctx, &result)
+	return result, err
+}
@@ -0,0 +1,146 @@
+// Package field provides type-safe field operations for GORM query builder.
+package field
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// Array represents a Postgres array column, e.g. a pq.StringArray field or a []string field tagged
+// `gorm:"type:text[]"`. Values are bound as array literals like {"a","b"}, typed by the column they are
+// compared with or assigned to.
+type Array[T any] struct {
+	column clause.Column
+}
+
+// Column returns the underlying column for this field
+func (a Array[T]) Column() clause.Column { return a.column }
+
+// WithColumn creates a new Array field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	tags := field.Array[string]{}.WithColumn("tags")
+func (a Array[T]) WithColumn(name string) Array[T] {
+	column := a.column
+	column.Name = name
+	return Array[T]{column: column}
+}
+
+// WithTable creates a new Array field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	tags := field.Array[string]{}.WithColumn("tags")
+//	postTags := tags.WithTable("posts")
+func (a Array[T]) WithTable(name string) Array[T] {
+	column := a.column
+	column.Table = name
+	return Array[T]{column: column}
+}
+
+// Query functions
+
+// Eq creates an equality comparison expression (field = values).
+func (a Array[T]) Eq(values []T) clause.Expression {
+	return clause.Expr{SQL: "? = ?", Vars: []any{a.column, arrayLiteral(values)}}
+}
+
+// Contains creates an expression checking whether the array contains all values (field @> values).
+//
+// Example:
+//
+//	// Generate: WHERE "tags" @> '{"go","sql"}'
+//	condition := generated.Post.Tags.Contains("go", "sql")
+func (a Array[T]) Contains(values ...T) clause.Expression {
+	return clause.Expr{SQL: "? @> ?", Vars: []any{a.column, arrayLiteral(values)}}
+}
+
+// ContainedBy creates an expression checking whether all elements of the array are in values (field <@ values).
+func (a Array[T]) ContainedBy(values ...T) clause.Expression {
+	return clause.Expr{SQL: "? <@ ?", Vars: []any{a.column, arrayLiteral(values)}}
+}
+
+// Overlaps creates an expression checking whether the array has any element in common with values (field && values).
+//
+// Example:
+//
+//	// Generate: WHERE "tags" && '{"go","rust"}'
+//	condition := generated.Post.Tags.Overlaps("go", "rust")
+func (a Array[T]) Overlaps(values ...T) clause.Expression {
+	return clause.Expr{SQL: "? && ?", Vars: []any{a.column, arrayLiteral(values)}}
+}
+
+// Any creates an expression checking whether value is an element of the array (value = ANY(field)).
+func (a Array[T]) Any(value T) clause.Expression {
+	return clause.Expr{SQL: "? = ANY(?)", Vars: []any{value, a.column}}
+}
+
+// Len creates an expression of the number of elements of the array (cardinality(field)), 0 for empty arrays.
+//
+// Example:
+//
+//	// Generate: WHERE cardinality("tags") > 3
+//	condition := generated.Post.Tags.Len().Gt(3)
+func (a Array[T]) Len() ValueExpression {
+	return valueExpr{clause.Expr{SQL: "cardinality(?)", Vars: []any{a.column}}}
+}
+
+// IsNull creates a NULL check expression (field IS NULL).
+func (a Array[T]) IsNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{a.column}}
+}
+
+// IsNotNull creates a NOT NULL check expression (field IS NOT NULL).
+func (a Array[T]) IsNotNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{a.column}}
+}
+
+// Set functions for UPDATE operations
+
+// Set creates an assignment expression for UPDATE operations (field = values).
+func (a Array[T]) Set(values []T) clause.Assignment {
+	return clause.Assignment{Column: a.column, Value: arrayLiteral(values)}
+}
+
+// SetExpr creates an assignment expression for UPDATE operations (field = expression).
+func (a Array[T]) SetExpr(expr clause.Expression) clause.Assignment {
+	return clause.Assignment{Column: a.column, Value: expr}
+}
+
+// Append creates an expression appending values to the array (field || values).
+//
+// Example:
+//
+//	// Generate: UPDATE posts SET tags = "tags" || '{"go"}'
+//	gorm.G[Post](db).Where(generated.Post.ID.Eq(1)).Set(generated.Post.Tags.Append("go")).Update(ctx)
+func (a Array[T]) Append(values ...T) AssignerExpression {
+	return colOpExpr{col: a.column, sql: "? || ?", vars: []any{a.column, arrayLiteral(values)}}
+}
+
+// Remove creates an expression removing all elements equal to value from the array (array_remove(field, value)).
+func (a Array[T]) Remove(value T) AssignerExpression {
+	return colOpExpr{col: a.column, sql: "array_remove(?, ?)", vars: []any{a.column, value}}
+}
+
+// buildSelectArg allows Array to be passed to Select(...)
+func (a Array[T]) buildSelectArg() any { return a.column }
+
+// As creates an alias for this column usable in Select(...)
+func (a Array[T]) As(alias string) Selectable {
+	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{a.column, clause.Column{Name: alias}}}}
+}
+
+// arrayLiteral renders values as a Postgres array literal, e.g. {"a","b"}, elements are quoted
+// so they may contain commas, braces and spaces
+func arrayLiteral[T any](values []T) string {
+	elems := make([]string, len(values))
+	for i, v := range values {
+		elems[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(fmt.Sprint(v)) + `"`
+	}
+	return "{" + strings.Join(elems, ",") + "}"
+}
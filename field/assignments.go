@@ -0,0 +1,86 @@
+package field
+
+import (
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// Assignments creates the assignments of a SQL SET list with its vars, used by the update(...) methods
+// of SQL templates. Assignments are separated by top-level commas, a leading SET keyword is ignored and
+// vars are bound to the ? placeholders of each value in order. A ? column is bound to its clause.Column var.
+//
+// Example:
+//
+//	// Generate: UPDATE users SET name='jinzhu',age=age+1 WHERE id = 1
+//	gorm.G[User](db).Where("id = ?", 1).Set(field.Assignments("name=?, age=age+?", "jinzhu", 1)).Update(ctx)
+func Assignments(sql string, vars ...any) clause.Set {
+	sql = strings.TrimSpace(sql)
+	if len(sql) >= 3 && strings.EqualFold(sql[:3], "SET") && (len(sql) == 3 || sql[3] == ' ' || sql[3] == '\t') {
+		sql = strings.TrimSpace(sql[3:])
+	}
+
+	var set clause.Set
+	for _, item := range splitTopLevel(sql, ',') {
+		column, value, _ := strings.Cut(item, "=")
+		column, value = strings.TrimSpace(column), strings.TrimSpace(value)
+		if column == "" && value == "" {
+			continue
+		}
+
+		assignment := clause.Assignment{Column: clause.Column{Name: column, Raw: true}}
+		if n := countPlaceholders(column); n > 0 && len(vars) >= n {
+			if c, ok := vars[0].(clause.Column); ok && column == "?" {
+				assignment.Column = c
+			}
+			vars = vars[n:]
+		}
+		n := min(countPlaceholders(value), len(vars))
+		assignment.Value = clause.Expr{SQL: value, Vars: vars[:n]}
+		vars = vars[n:]
+		set = append(set, assignment)
+	}
+	return set
+}
+
+// splitTopLevel splits the SQL by sep outside of parentheses and quoted strings
+func splitTopLevel(sql string, sep byte) (parts []string) {
+	var depth, start int
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, sql[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, sql[start:])
+}
+
+// countPlaceholders returns the number of ? placeholders of the SQL outside of quoted strings
+func countPlaceholders(sql string) (n int) {
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			n++
+		}
+	}
+	return n
+}
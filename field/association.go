@@ -17,29 +17,93 @@ type Slice[T any] struct {
 // associationWithConditions represents a field with conditions that can be applied to both Struct and Slice
 type associationWithConditions[T any] struct {
 	name       string
+	metadata   AssociationMetadata
 	conditions []clause.Expression
 }
 
+// AssociationKind is the kind of an association
+type AssociationKind string
+
+const (
+	HasOne    AssociationKind = "has_one"
+	HasMany   AssociationKind = "has_many"
+	BelongsTo AssociationKind = "belongs_to"
+	Many2Many AssociationKind = "many_to_many"
+)
+
+// AssociationMetadata describes how an association is stored, as derived from the gorm tags
+// of the relation field by the generator. Values are column and table names.
+type AssociationMetadata struct {
+	Kind AssociationKind
+	// ForeignKey is the foreign key column, it belongs to the parent table for belongs to
+	// associations, to the associated table for has one/has many associations, and to the
+	// join table for many2many associations
+	ForeignKey string
+	// References is the column referenced by the foreign key
+	References string
+	// PolymorphicType and PolymorphicID are the owner type and ID columns of polymorphic associations,
+	// PolymorphicValue is the value stored in the owner type column
+	PolymorphicType  string
+	PolymorphicID    string
+	PolymorphicValue string
+	// JoinTable, JoinForeignKey and JoinReferences are the join table of many2many associations
+	// and its columns referencing the parent and the associated table
+	JoinTable      string
+	JoinForeignKey string
+	JoinReferences string
+}
+
 // WithName creates a new Struct with the specified field name
 func (s Struct[T]) WithName(name string) Struct[T] {
-	return Struct[T]{associationWithConditions[T]{name: name}}
+	return Struct[T]{associationWithConditions[T]{name: name, metadata: s.metadata}}
+}
+
+// WithMetadata creates a new Struct with the specified association metadata
+func (s Struct[T]) WithMetadata(metadata AssociationMetadata) Struct[T] {
+	return Struct[T]{associationWithConditions[T]{name: s.name, metadata: metadata}}
 }
 
 // Name returns the association name (field name on the parent model)
 func (s Struct[T]) Name() string { return s.name }
 
+// Metadata returns the foreign keys, references and join table of the association
+func (s Struct[T]) Metadata() AssociationMetadata { return s.metadata }
+
+// Join returns an INNER JOIN target of the association for Joins(...),
+// the joined table and ON condition are derived from the association by GORM.
+//
+// Example:
+//
+//	gorm.G[User](db).Joins(generated.User.Company.Join(), nil).Find(ctx)
+func (s Struct[T]) Join() clause.JoinTarget { return clause.InnerJoin.Association(s.name) }
+
+// LeftJoin returns a LEFT JOIN target of the association for Joins(...)
+func (s Struct[T]) LeftJoin() clause.JoinTarget { return clause.LeftJoin.Association(s.name) }
+
+// RightJoin returns a RIGHT JOIN target of the association for Joins(...)
+func (s Struct[T]) RightJoin() clause.JoinTarget { return clause.RightJoin.Association(s.name) }
+
 // WithName creates a new Slice with the specified field name
 func (s Slice[T]) WithName(name string) Slice[T] {
-	return Slice[T]{associationWithConditions[T]{name: name}}
+	return Slice[T]{associationWithConditions[T]{name: name, metadata: s.metadata}}
+}
+
+// WithMetadata creates a new Slice with the specified association metadata
+func (s Slice[T]) WithMetadata(metadata AssociationMetadata) Slice[T] {
+	return Slice[T]{associationWithConditions[T]{name: s.name, metadata: metadata}}
 }
 
 // Name returns the association name (field name on the parent model)
 func (s Slice[T]) Name() string { return s.name }
 
+// Metadata returns the foreign keys, references and join table of the association
+func (s Slice[T]) Metadata() AssociationMetadata { return s.metadata }
+
 // Where adds conditions to a Struct field
 func (s Struct[T]) Where(conditions ...clause.Expression) associationWithConditions[T] {
 	return associationWithConditions[T]{
 		name:       s.name,
+		metadata:   s.metadata,
 		conditions: conditions,
 	}
 }
@@ -48,6 +112,7 @@ func (s Struct[T]) Where(conditions ...clause.Expression) associationWithConditi
 func (s Slice[T]) Where(conditions ...clause.Expression) associationWithConditions[T] {
 	return associationWithConditions[T]{
 		name:       s.name,
+		metadata:   s.metadata,
 		conditions: conditions,
 	}
 }
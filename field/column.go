@@ -0,0 +1,54 @@
+package field
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AllowedColumn creates a column expression of a column name chosen at runtime, used by the
+// @@column in (name, age) placeholders of SQL templates. The name must be one of allowed, or a column
+// of the statement's model when allowed is empty, otherwise the statement fails with an error instead of
+// interpolating the name.
+//
+// Example:
+//
+//	// Generate: SELECT * FROM users WHERE `age` = 18
+//	db.Raw("SELECT * FROM users WHERE ? = ?", field.AllowedColumn("age", "name", "age"), 18)
+func AllowedColumn(name string, allowed ...string) clause.Expression {
+	return allowedColumnExpr{name: name, allowed: allowed}
+}
+
+type allowedColumnExpr struct {
+	name    string
+	allowed []string
+}
+
+func (e allowedColumnExpr) Build(builder clause.Builder) {
+	if len(e.allowed) > 0 {
+		if !slices.Contains(e.allowed, e.name) {
+			builder.AddError(fmt.Errorf("invalid column %q, must be one of %s", e.name, strings.Join(e.allowed, ", ")))
+			return
+		}
+		builder.WriteQuoted(clause.Column{Name: e.name})
+		return
+	}
+
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok || stmt.Model == nil && stmt.Schema == nil {
+		builder.AddError(errors.New("invalid column, the columns of the model are required to check it"))
+		return
+	}
+	if stmt.Schema == nil && stmt.AddError(stmt.Parse(stmt.Model)) != nil {
+		return
+	}
+	if _, ok := stmt.Schema.FieldsByDBName[e.name]; !ok {
+		builder.AddError(fmt.Errorf("invalid column %q, must be a column of %s", e.name, stmt.Schema.Name))
+		return
+	}
+	builder.WriteQuoted(clause.Column{Name: e.name})
+}
@@ -0,0 +1,248 @@
+// Package field provides type-safe field operations for GORM query builder.
+package field
+
+import (
+	"gorm.io/gorm/clause"
+)
+
+// Decimal represents an exact numeric field, e.g. a github.com/shopspring/decimal.Decimal field or
+// another decimal type implementing sql.Scanner and driver.Valuer. Values are bound as they are, so
+// the driver receives the exact value returned by their Value method instead of a float.
+type Decimal[T any] struct {
+	column clause.Column
+}
+
+// Column returns the underlying column for this field
+func (d Decimal[T]) Column() clause.Column { return d.column }
+
+// WithColumn creates a new Decimal field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	price := field.Decimal[decimal.Decimal]{}.WithColumn("price")
+func (d Decimal[T]) WithColumn(name string) Decimal[T] {
+	column := d.column
+	column.Name = name
+	return Decimal[T]{column: column}
+}
+
+// WithTable creates a new Decimal field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	price := field.Decimal[decimal.Decimal]{}.WithColumn("price")
+//	orderPrice := price.WithTable("orders")
+func (d Decimal[T]) WithTable(name string) Decimal[T] {
+	column := d.column
+	column.Table = name
+	return Decimal[T]{column: column}
+}
+
+// Query functions
+
+// Eq creates an equality comparison expression (field = value).
+func (d Decimal[T]) Eq(value T) clause.Expression {
+	return clause.Eq{Column: d.column, Value: value}
+}
+
+// EqExpr creates an equality comparison expression (field = expression).
+func (d Decimal[T]) EqExpr(expr clause.Expression) clause.Expression {
+	return clause.Eq{Column: d.column, Value: expr}
+}
+
+// Neq creates a not equal comparison expression (field != value).
+func (d Decimal[T]) Neq(value T) clause.Expression {
+	return clause.Neq{Column: d.column, Value: value}
+}
+
+// NeqExpr creates a not equal comparison expression (field != expression).
+func (d Decimal[T]) NeqExpr(expr clause.Expression) clause.Expression {
+	return clause.Neq{Column: d.column, Value: expr}
+}
+
+// Gt creates a greater than comparison expression (field > value).
+func (d Decimal[T]) Gt(value T) clause.Expression {
+	return clause.Gt{Column: d.column, Value: value}
+}
+
+// GtExpr creates a greater than comparison expression (field > expression).
+func (d Decimal[T]) GtExpr(expr clause.Expression) clause.Expression {
+	return clause.Gt{Column: d.column, Value: expr}
+}
+
+// Gte creates a greater than or equal comparison expression (field >= value).
+func (d Decimal[T]) Gte(value T) clause.Expression {
+	return clause.Gte{Column: d.column, Value: value}
+}
+
+// GteExpr creates a greater than or equal comparison expression (field >= expression).
+func (d Decimal[T]) GteExpr(expr clause.Expression) clause.Expression {
+	return clause.Gte{Column: d.column, Value: expr}
+}
+
+// Lt creates a less than comparison expression (field < value).
+func (d Decimal[T]) Lt(value T) clause.Expression {
+	return clause.Lt{Column: d.column, Value: value}
+}
+
+// LtExpr creates a less than comparison expression (field < expression).
+func (d Decimal[T]) LtExpr(expr clause.Expression) clause.Expression {
+	return clause.Lt{Column: d.column, Value: expr}
+}
+
+// Lte creates a less than or equal comparison expression (field <= value).
+func (d Decimal[T]) Lte(value T) clause.Expression {
+	return clause.Lte{Column: d.column, Value: value}
+}
+
+// LteExpr creates a less than or equal comparison expression (field <= expression).
+func (d Decimal[T]) LteExpr(expr clause.Expression) clause.Expression {
+	return clause.Lte{Column: d.column, Value: expr}
+}
+
+// Between creates a range comparison expression (field BETWEEN v1 AND v2).
+//
+// Example:
+//
+//	// Generate: WHERE price >= 9.99 AND price <= 19.99
+//	condition := generated.Product.Price.Between(decimal.RequireFromString("9.99"), decimal.RequireFromString("19.99"))
+func (d Decimal[T]) Between(v1, v2 T) clause.Expression {
+	return clause.And(
+		clause.Gte{Column: d.column, Value: v1},
+		clause.Lte{Column: d.column, Value: v2},
+	)
+}
+
+// In creates an IN comparison expression (field IN (values...)).
+func (d Decimal[T]) In(values ...T) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for i, v := range values {
+		interfaceValues[i] = v
+	}
+	return clause.IN{Column: d.column, Values: interfaceValues}
+}
+
+// NotIn creates a NOT IN comparison expression (field NOT IN (values...)).
+func (d Decimal[T]) NotIn(values ...T) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for i, v := range values {
+		interfaceValues[i] = v
+	}
+	return clause.Not(clause.IN{Column: d.column, Values: interfaceValues})
+}
+
+// IsNull creates a NULL check expression (field IS NULL).
+func (d Decimal[T]) IsNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{d.column}}
+}
+
+// IsNotNull creates a NOT NULL check expression (field IS NOT NULL).
+func (d Decimal[T]) IsNotNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{d.column}}
+}
+
+// Set functions for UPDATE operations
+
+// Set creates an assignment expression for UPDATE operations (field = value).
+func (d Decimal[T]) Set(val T) clause.Assignment {
+	return clause.Assignment{Column: d.column, Value: val}
+}
+
+// SetExpr creates an assignment expression for UPDATE operations (field = expression).
+func (d Decimal[T]) SetExpr(expr clause.Expression) clause.Assignment {
+	return clause.Assignment{Column: d.column, Value: expr}
+}
+
+// Arithmetic expressions, the database computes them with its exact numeric type
+
+// Incr creates an increment expression (field + value).
+//
+// Example:
+//
+//	// Generate: UPDATE accounts SET balance = balance + 10.50
+//	gorm.G[Account](db).Where(generated.Account.ID.Eq(1)).Set(generated.Account.Balance.Incr(amount)).Update(ctx)
+func (d Decimal[T]) Incr(value T) AssignerExpression {
+	return colOpExpr{col: d.column, sql: "? + ?", vars: []any{d.column, value}}
+}
+
+// Decr creates a decrement expression (field - value).
+func (d Decimal[T]) Decr(value T) AssignerExpression {
+	return colOpExpr{col: d.column, sql: "? - ?", vars: []any{d.column, value}}
+}
+
+// Mul creates a multiplication expression (field * value).
+func (d Decimal[T]) Mul(value T) AssignerExpression {
+	return colOpExpr{col: d.column, sql: "? * ?", vars: []any{d.column, value}}
+}
+
+// Div creates a division expression (field / value).
+func (d Decimal[T]) Div(value T) AssignerExpression {
+	return colOpExpr{col: d.column, sql: "? / ?", vars: []any{d.column, value}}
+}
+
+// Rounding expressions
+
+// Round creates a rounding expression (ROUND(field, places)), it is also an assigner rounding the
+// stored value, e.g. Set(generated.Order.Total.Round(2)).
+func (d Decimal[T]) Round(places int) AssignerExpression {
+	return colOpExpr{col: d.column, sql: "ROUND(?, ?)", vars: []any{d.column, places}}
+}
+
+// Floor creates an expression of the largest integer not greater than the field (FLOOR(field)).
+func (d Decimal[T]) Floor() clause.Expression {
+	return clause.Expr{SQL: "FLOOR(?)", Vars: []any{d.column}}
+}
+
+// Ceil creates an expression of the smallest integer not less than the field (CEIL(field)).
+func (d Decimal[T]) Ceil() clause.Expression {
+	return clause.Expr{SQL: "CEIL(?)", Vars: []any{d.column}}
+}
+
+// Aggregate expressions, usable in Select(...) and compared in HAVING conditions
+
+// Sum creates an aggregate expression of the sum of the field (SUM(field)).
+//
+// Example:
+//
+//	// Generate: SELECT SUM(amount) AS total FROM payments
+//	gorm.G[Payment](db).Select(generated.Payment.Amount.Sum().As("total"))
+func (d Decimal[T]) Sum() ValueExpression {
+	return valueExpr{clause.Expr{SQL: "SUM(?)", Vars: []any{d.column}}}
+}
+
+// Avg creates an aggregate expression of the average of the field (AVG(field)).
+func (d Decimal[T]) Avg() ValueExpression {
+	return valueExpr{clause.Expr{SQL: "AVG(?)", Vars: []any{d.column}}}
+}
+
+// Min creates an aggregate expression of the minimum of the field (MIN(field)).
+func (d Decimal[T]) Min() ValueExpression {
+	return valueExpr{clause.Expr{SQL: "MIN(?)", Vars: []any{d.column}}}
+}
+
+// Max creates an aggregate expression of the maximum of the field (MAX(field)).
+func (d Decimal[T]) Max() ValueExpression {
+	return valueExpr{clause.Expr{SQL: "MAX(?)", Vars: []any{d.column}}}
+}
+
+// Order expressions for sorting operations
+
+// Asc creates an ascending order expression for ORDER BY clauses.
+func (d Decimal[T]) Asc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: d.column, Desc: false}
+}
+
+// Desc creates a descending order expression for ORDER BY clauses.
+func (d Decimal[T]) Desc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: d.column, Desc: true}
+}
+
+// buildSelectArg allows Decimal to be passed to Select(...)
+func (d Decimal[T]) buildSelectArg() any { return d.column }
+
+// As creates an alias for this column usable in Select(...)
+func (d Decimal[T]) As(alias string) Selectable {
+	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{d.column, clause.Column{Name: alias}}}}
+}
@@ -0,0 +1,56 @@
+package field
+
+import (
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DialectSQL is a SQL fragment with its vars used for some dialects, or for the other dialects
+// when Dialects is empty.
+type DialectSQL struct {
+	Dialects []string // names of the dialectors, e.g. mysql, postgres, sqlite, sqlserver
+	SQL      string
+	Vars     []any
+}
+
+// Dialect creates an expression rendering the first fragment declared for the dialect of the
+// statement, or the fragment without dialects, used by the {{if dialect "name"}} blocks of SQL templates.
+// Nothing is rendered when no fragment matches.
+//
+// Example:
+//
+//	// Generate: name ILIKE 'jinzhu' on postgres, LOWER(name) = LOWER('jinzhu') otherwise
+//	db.Raw("SELECT * FROM users WHERE ?", field.Dialect(
+//		field.DialectSQL{Dialects: []string{"postgres"}, SQL: "name ILIKE ?", Vars: []any{"jinzhu"}},
+//		field.DialectSQL{SQL: "LOWER(name) = LOWER(?)", Vars: []any{"jinzhu"}},
+//	))
+func Dialect(fragments ...DialectSQL) clause.Expression {
+	return dialectExpr{fragments: fragments}
+}
+
+type dialectExpr struct {
+	fragments []DialectSQL
+}
+
+func (e dialectExpr) Build(builder clause.Builder) {
+	var dialect string
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		dialect = stmt.Dialector.Name()
+	}
+
+	fallback := -1
+	for i, fragment := range e.fragments {
+		if slices.Contains(fragment.Dialects, dialect) {
+			clause.Expr{SQL: fragment.SQL, Vars: fragment.Vars}.Build(builder)
+			return
+		}
+		if len(fragment.Dialects) == 0 && fallback < 0 {
+			fallback = i
+		}
+	}
+	if fallback >= 0 {
+		clause.Expr{SQL: e.fragments[fallback].SQL, Vars: e.fragments[fallback].Vars}.Build(builder)
+	}
+}
@@ -0,0 +1,249 @@
+// Package field provides type-safe field operations for GORM query builder.
+package field
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// Duration represents a time.Duration field. Durations are stored as integer nanoseconds by default,
+// like gorm stores time.Duration values, WithUnit and WithInterval change the storage convention so
+// time.Duration values are converted to the unit of the column, e.g. seconds or a Postgres INTERVAL.
+type Duration struct {
+	column   clause.Column
+	unit     time.Duration
+	interval bool
+}
+
+// Column returns the underlying column for this field
+func (d Duration) Column() clause.Column { return d.column }
+
+// WithColumn creates a new Duration field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	timeout := field.Duration{}.WithColumn("timeout")
+func (d Duration) WithColumn(name string) Duration {
+	column := d.column
+	column.Name = name
+	return Duration{column: column, unit: d.unit, interval: d.interval}
+}
+
+// WithTable creates a new Duration field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	timeout := field.Duration{}.WithColumn("timeout")
+//	jobTimeout := timeout.WithTable("jobs")
+func (d Duration) WithTable(name string) Duration {
+	column := d.column
+	column.Table = name
+	return Duration{column: column, unit: d.unit, interval: d.interval}
+}
+
+// WithUnit creates a new Duration field stored as an integer number of unit, e.g. time.Second
+// for a column of seconds. Values are truncated to the unit.
+//
+// Example:
+//
+//	// Generate: WHERE timeout > 90 for 90 * time.Second
+//	timeout := field.Duration{}.WithColumn("timeout").WithUnit(time.Second)
+func (d Duration) WithUnit(unit time.Duration) Duration {
+	return Duration{column: d.column, unit: unit}
+}
+
+// WithInterval creates a new Duration field stored as a Postgres INTERVAL.
+//
+// Example:
+//
+//	// Generate: WHERE ttl > CAST('90000000 microseconds' AS INTERVAL) for 90 * time.Second
+//	ttl := field.Duration{}.WithColumn("ttl").WithInterval()
+func (d Duration) WithInterval() Duration {
+	return Duration{column: d.column, interval: true}
+}
+
+// Query functions
+
+// Eq creates an equality comparison expression (field = value).
+func (d Duration) Eq(value time.Duration) clause.Expression {
+	return clause.Eq{Column: d.column, Value: d.value(value)}
+}
+
+// EqExpr creates an equality comparison expression (field = expression).
+func (d Duration) EqExpr(expr clause.Expression) clause.Expression {
+	return clause.Eq{Column: d.column, Value: expr}
+}
+
+// Neq creates a not equal comparison expression (field != value).
+func (d Duration) Neq(value time.Duration) clause.Expression {
+	return clause.Neq{Column: d.column, Value: d.value(value)}
+}
+
+// NeqExpr creates a not equal comparison expression (field != expression).
+func (d Duration) NeqExpr(expr clause.Expression) clause.Expression {
+	return clause.Neq{Column: d.column, Value: expr}
+}
+
+// Gt creates a greater than comparison expression (field > value).
+//
+// Example:
+//
+//	// Generate: WHERE elapsed > 5000 with WithUnit(time.Millisecond)
+//	condition := generated.Job.Elapsed.Gt(5 * time.Second)
+func (d Duration) Gt(value time.Duration) clause.Expression {
+	return clause.Gt{Column: d.column, Value: d.value(value)}
+}
+
+// GtExpr creates a greater than comparison expression (field > expression).
+func (d Duration) GtExpr(expr clause.Expression) clause.Expression {
+	return clause.Gt{Column: d.column, Value: expr}
+}
+
+// Gte creates a greater than or equal comparison expression (field >= value).
+func (d Duration) Gte(value time.Duration) clause.Expression {
+	return clause.Gte{Column: d.column, Value: d.value(value)}
+}
+
+// GteExpr creates a greater than or equal comparison expression (field >= expression).
+func (d Duration) GteExpr(expr clause.Expression) clause.Expression {
+	return clause.Gte{Column: d.column, Value: expr}
+}
+
+// Lt creates a less than comparison expression (field < value).
+func (d Duration) Lt(value time.Duration) clause.Expression {
+	return clause.Lt{Column: d.column, Value: d.value(value)}
+}
+
+// LtExpr creates a less than comparison expression (field < expression).
+func (d Duration) LtExpr(expr clause.Expression) clause.Expression {
+	return clause.Lt{Column: d.column, Value: expr}
+}
+
+// Lte creates a less than or equal comparison expression (field <= value).
+func (d Duration) Lte(value time.Duration) clause.Expression {
+	return clause.Lte{Column: d.column, Value: d.value(value)}
+}
+
+// LteExpr creates a less than or equal comparison expression (field <= expression).
+func (d Duration) LteExpr(expr clause.Expression) clause.Expression {
+	return clause.Lte{Column: d.column, Value: expr}
+}
+
+// Between creates a range comparison expression (field BETWEEN v1 AND v2).
+func (d Duration) Between(v1, v2 time.Duration) clause.Expression {
+	return clause.And(
+		clause.Gte{Column: d.column, Value: d.value(v1)},
+		clause.Lte{Column: d.column, Value: d.value(v2)},
+	)
+}
+
+// In creates an IN comparison expression (field IN (values...)).
+func (d Duration) In(values ...time.Duration) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for i, v := range values {
+		interfaceValues[i] = d.value(v)
+	}
+	return clause.IN{Column: d.column, Values: interfaceValues}
+}
+
+// NotIn creates a NOT IN comparison expression (field NOT IN (values...)).
+func (d Duration) NotIn(values ...time.Duration) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for i, v := range values {
+		interfaceValues[i] = d.value(v)
+	}
+	return clause.Not(clause.IN{Column: d.column, Values: interfaceValues})
+}
+
+// IsNull creates a NULL check expression (field IS NULL).
+func (d Duration) IsNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{d.column}}
+}
+
+// IsNotNull creates a NOT NULL check expression (field IS NOT NULL).
+func (d Duration) IsNotNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{d.column}}
+}
+
+// Set functions for UPDATE operations
+
+// Set creates an assignment expression for UPDATE operations (field = value), value is converted
+// to the storage convention of the field.
+func (d Duration) Set(val time.Duration) clause.Assignment {
+	return clause.Assignment{Column: d.column, Value: d.value(val)}
+}
+
+// SetPtr creates an assignment expression for UPDATE operations of a *time.Duration field, a nil value
+// sets NULL.
+//
+// Example:
+//
+//	// Generate: UPDATE jobs SET elapsed = NULL
+//	gorm.G[Job](db).Where(generated.Job.ID.Eq(1)).Set(generated.Job.Elapsed.SetPtr(nil)).Update(ctx)
+func (d Duration) SetPtr(val *time.Duration) clause.Assignment {
+	if val == nil {
+		return d.SetNull()
+	}
+	return d.Set(*val)
+}
+
+// SetNull creates an assignment expression setting the field to NULL (field = NULL).
+func (d Duration) SetNull() clause.Assignment {
+	return clause.Assignment{Column: d.column, Value: nil}
+}
+
+// SetExpr creates an assignment expression for UPDATE operations (field = expression).
+func (d Duration) SetExpr(expr clause.Expression) clause.Assignment {
+	return clause.Assignment{Column: d.column, Value: expr}
+}
+
+// Add creates an expression extending the duration (field + value).
+//
+// Example:
+//
+//	// Generate: UPDATE jobs SET timeout = timeout + 30 with WithUnit(time.Second)
+//	gorm.G[Job](db).Where(generated.Job.ID.Eq(1)).Set(generated.Job.Timeout.Add(30 * time.Second)).Update(ctx)
+func (d Duration) Add(value time.Duration) AssignerExpression {
+	return colOpExpr{col: d.column, sql: "? + ?", vars: []any{d.column, d.value(value)}}
+}
+
+// Sub creates an expression shortening the duration (field - value).
+func (d Duration) Sub(value time.Duration) AssignerExpression {
+	return colOpExpr{col: d.column, sql: "? - ?", vars: []any{d.column, d.value(value)}}
+}
+
+// Order expressions for sorting operations
+
+// Asc creates an ascending order expression for ORDER BY clauses.
+func (d Duration) Asc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: d.column, Desc: false}
+}
+
+// Desc creates a descending order expression for ORDER BY clauses.
+func (d Duration) Desc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: d.column, Desc: true}
+}
+
+// buildSelectArg allows Duration to be passed to Select(...)
+func (d Duration) buildSelectArg() any { return d.column }
+
+// As creates an alias for this column usable in Select(...)
+func (d Duration) As(alias string) Selectable {
+	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{d.column, clause.Column{Name: alias}}}}
+}
+
+// value converts v to the storage convention of the field, INTERVAL values keep the
+// microsecond precision of Postgres intervals
+func (d Duration) value(v time.Duration) any {
+	switch {
+	case d.interval:
+		return clause.Expr{SQL: "CAST(? AS INTERVAL)", Vars: []any{fmt.Sprintf("%d microseconds", v.Microseconds())}}
+	case d.unit > 0:
+		return int64(v / d.unit)
+	}
+	return int64(v)
+}
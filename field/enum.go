@@ -0,0 +1,181 @@
+// Package field provides type-safe field operations for GORM query builder.
+package field
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidEnumValue is returned by Enum.SetChecked for values that are not in the set of values of the enum
+var ErrInvalidEnumValue = errors.New("invalid enum value")
+
+// Enum represents a field of a defined string or integer type with a known set of values,
+// e.g. `type Role string` declared together with `const RoleAdmin Role = "admin"`.
+// It provides type-safe operations for building SQL queries.
+type Enum[T ~string | constraints.Integer] struct {
+	column clause.Column
+	values []T
+}
+
+// Column returns the underlying column for this field
+func (e Enum[T]) Column() clause.Column { return e.column }
+
+// WithColumn creates a new Enum field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	role := field.Enum[models.Role]{}.WithColumn("role")
+func (e Enum[T]) WithColumn(name string) Enum[T] {
+	column := e.column
+	column.Name = name
+	return Enum[T]{column: column, values: e.values}
+}
+
+// WithTable creates a new Enum field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	role := field.Enum[models.Role]{}.WithColumn("role")
+//	userRole := role.WithTable("users")
+func (e Enum[T]) WithTable(name string) Enum[T] {
+	column := e.column
+	column.Table = name
+	return Enum[T]{column: column, values: e.values}
+}
+
+// WithValues creates a new Enum field with the specified set of valid values.
+//
+// Example:
+//
+//	role := field.Enum[models.Role]{}.WithColumn("role").WithValues(models.RoleAdmin, models.RoleUser)
+func (e Enum[T]) WithValues(values ...T) Enum[T] {
+	return Enum[T]{column: e.column, values: values}
+}
+
+// Values returns the set of valid values of the enum.
+func (e Enum[T]) Values() []T {
+	return append([]T(nil), e.values...)
+}
+
+// IsValid reports whether value is in the set of valid values of the enum,
+// enums without values accept any value.
+//
+// Example:
+//
+//	if !generated.User.Role.IsValid(models.Role(input)) {
+//		return fmt.Errorf("unknown role %q", input)
+//	}
+func (e Enum[T]) IsValid(value T) bool {
+	return len(e.values) == 0 || slices.Contains(e.values, value)
+}
+
+// Query functions
+
+// Eq creates an equality comparison expression (field = value).
+func (e Enum[T]) Eq(value T) clause.Expression {
+	return clause.Eq{Column: e.column, Value: value}
+}
+
+// EqExpr creates an equality comparison expression (field = expression).
+func (e Enum[T]) EqExpr(expr clause.Expression) clause.Expression {
+	return clause.Eq{Column: e.column, Value: expr}
+}
+
+// Neq creates a not equal comparison expression (field != value).
+func (e Enum[T]) Neq(value T) clause.Expression {
+	return clause.Neq{Column: e.column, Value: value}
+}
+
+// NeqExpr creates a not equal comparison expression (field != expression).
+func (e Enum[T]) NeqExpr(expr clause.Expression) clause.Expression {
+	return clause.Neq{Column: e.column, Value: expr}
+}
+
+// In creates an IN comparison expression (field IN (values...)).
+func (e Enum[T]) In(values ...T) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for i, v := range values {
+		interfaceValues[i] = v
+	}
+	return clause.IN{Column: e.column, Values: interfaceValues}
+}
+
+// NotIn creates a NOT IN comparison expression (field NOT IN (values...)).
+func (e Enum[T]) NotIn(values ...T) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for i, v := range values {
+		interfaceValues[i] = v
+	}
+	return clause.Not(clause.IN{Column: e.column, Values: interfaceValues})
+}
+
+// IsNull creates a NULL check expression (field IS NULL).
+func (e Enum[T]) IsNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{e.column}}
+}
+
+// IsNotNull creates a NOT NULL check expression (field IS NOT NULL).
+func (e Enum[T]) IsNotNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{e.column}}
+}
+
+// Set functions for UPDATE operations
+
+// Set creates an assignment expression for UPDATE operations (field = value).
+func (e Enum[T]) Set(val T) clause.Assignment {
+	return clause.Assignment{Column: e.column, Value: val}
+}
+
+// SetChecked creates an assignment expression for UPDATE operations (field = value) like Set, but returns
+// an error wrapping ErrInvalidEnumValue if value is not in the set of valid values of the enum, so invalid
+// values are rejected before hitting the database.
+//
+// Example:
+//
+//	assignment, err := generated.User.Role.SetChecked(models.Role(input))
+//	if err != nil {
+//		return err
+//	}
+//	gorm.G[User](db).Where(generated.User.ID.Eq(1)).Set(assignment).Update(ctx)
+func (e Enum[T]) SetChecked(val T) (clause.Assignment, error) {
+	if !e.IsValid(val) {
+		values := make([]string, len(e.values))
+		for i, v := range e.values {
+			values[i] = fmt.Sprint(v)
+		}
+		return clause.Assignment{}, fmt.Errorf("%w %v for column %q, must be one of %s", ErrInvalidEnumValue, val, e.column.Name, strings.Join(values, ", "))
+	}
+	return e.Set(val), nil
+}
+
+// SetExpr creates an assignment expression for UPDATE operations (field = expression).
+func (e Enum[T]) SetExpr(expr clause.Expression) clause.Assignment {
+	return clause.Assignment{Column: e.column, Value: expr}
+}
+
+// Order expressions for sorting operations
+
+// Asc creates an ascending order expression for ORDER BY clauses.
+func (e Enum[T]) Asc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: e.column, Desc: false}
+}
+
+// Desc creates a descending order expression for ORDER BY clauses.
+func (e Enum[T]) Desc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: e.column, Desc: true}
+}
+
+// buildSelectArg allows Enum to be passed to Select(...)
+func (e Enum[T]) buildSelectArg() any { return e.column }
+
+// As creates an alias for this column usable in Select(...)
+func (e Enum[T]) As(alias string) Selectable {
+	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{e.column, clause.Column{Name: alias}}}}
+}
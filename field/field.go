@@ -194,6 +194,60 @@ type selectExpr struct{ clause.Expression }
 
 func (e selectExpr) buildSelectArg() any { return e.Expression }
 
+// ValueExpression is a value computed from a column, e.g. SUM(amount) or a JSON path extraction,
+// usable in Select(...) with an alias and compared in WHERE or HAVING conditions.
+type ValueExpression interface {
+	clause.Expression
+	Selectable
+	As(alias string) Selectable
+	Eq(value any) clause.Expression
+	Neq(value any) clause.Expression
+	Gt(value any) clause.Expression
+	Gte(value any) clause.Expression
+	Lt(value any) clause.Expression
+	Lte(value any) clause.Expression
+}
+
+// valueExpr is the ValueExpression of a computed value
+type valueExpr struct{ clause.Expression }
+
+func (e valueExpr) buildSelectArg() any { return e.Expression }
+
+// As creates an alias for the value usable in Select(...), e.g. SUM(amount) AS total
+func (e valueExpr) As(alias string) Selectable {
+	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{e.Expression, clause.Column{Name: alias}}}}
+}
+
+// Eq creates an equality comparison of the value (value = v)
+func (e valueExpr) Eq(value any) clause.Expression {
+	return clause.Expr{SQL: "? = ?", Vars: []any{e.Expression, value}}
+}
+
+// Neq creates a not equal comparison of the value (value <> v)
+func (e valueExpr) Neq(value any) clause.Expression {
+	return clause.Expr{SQL: "? <> ?", Vars: []any{e.Expression, value}}
+}
+
+// Gt creates a greater than comparison of the value, e.g. HAVING SUM(amount) > 100
+func (e valueExpr) Gt(value any) clause.Expression {
+	return clause.Expr{SQL: "? > ?", Vars: []any{e.Expression, value}}
+}
+
+// Gte creates a greater than or equal comparison of the value (value >= v)
+func (e valueExpr) Gte(value any) clause.Expression {
+	return clause.Expr{SQL: "? >= ?", Vars: []any{e.Expression, value}}
+}
+
+// Lt creates a less than comparison of the value (value < v)
+func (e valueExpr) Lt(value any) clause.Expression {
+	return clause.Expr{SQL: "? < ?", Vars: []any{e.Expression, value}}
+}
+
+// Lte creates a less than or equal comparison of the value (value <= v)
+func (e valueExpr) Lte(value any) clause.Expression {
+	return clause.Expr{SQL: "? <= ?", Vars: []any{e.Expression, value}}
+}
+
 // As creates a column alias usable in Select(...), e.g. SELECT col AS alias
 func (f Field[T]) As(alias string) Selectable {
 	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{f.column, clause.Column{Name: alias}}}}
@@ -0,0 +1,71 @@
+package field
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// QueryHook is called around the queries of the generated query methods, declared with
+// genconfig.Config.QueryHook, e.g. to record metrics or tracing spans of every query.
+//
+// Example:
+//
+//	type tracer struct{}
+//
+//	func (tracer) Before(ctx context.Context, method, sql string, args []any) context.Context {
+//		ctx, _ = otel.Tracer("queries").Start(ctx, method)
+//		return ctx
+//	}
+//
+//	func (tracer) After(ctx context.Context, method, sql string, args []any, duration time.Duration, err error) {
+//		trace.SpanFromContext(ctx).End()
+//	}
+//
+//	var Tracer field.QueryHook = tracer{}
+type QueryHook interface {
+	// Before is called with the SQL and the args of the query of the method, e.g. Query.GetByID,
+	// before it runs, the query runs with the returned context
+	Before(ctx context.Context, method, sql string, args []any) context.Context
+	// After is called with the context returned by Before once the method is done, with the
+	// duration of the query and the error returned by the method
+	After(ctx context.Context, method, sql string, args []any, duration time.Duration, err error)
+}
+
+// QueryTrace calls a QueryHook around the query of a generated method, methods running several
+// statements call Before with the first one and After with all of them, separated by ";\n".
+type QueryTrace struct {
+	hook   QueryHook
+	method string
+	ctx    context.Context
+	sql    []string
+	args   []any
+	start  time.Time
+}
+
+// NewQueryTrace returns the trace of a call of the method, traced by hook when it isn't nil
+func NewQueryTrace(hook QueryHook, method string) *QueryTrace {
+	return &QueryTrace{hook: hook, method: method}
+}
+
+// Start records the SQL of a statement of the method, it calls Before with the first statement and
+// returns the context to run the statements with
+func (t *QueryTrace) Start(ctx context.Context, sql string, args []any) context.Context {
+	if t.hook == nil {
+		return ctx
+	}
+	t.sql, t.args = append(t.sql, sql), append(t.args, args...)
+	if t.ctx == nil {
+		t.ctx = t.hook.Before(ctx, t.method, sql, args)
+		t.start = time.Now()
+	}
+	return t.ctx
+}
+
+// End calls After with the error of the method, unless no statement was started
+func (t *QueryTrace) End(err error) {
+	if t.hook == nil || t.ctx == nil {
+		return
+	}
+	t.hook.After(t.ctx, t.method, strings.Join(t.sql, ";\n"), t.args, time.Since(t.start), err)
+}
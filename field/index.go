@@ -0,0 +1,42 @@
+package field
+
+import (
+	"gorm.io/gorm/clause"
+)
+
+// UniqueIndex represents a unique index or unique constraint of a model, as derived from
+// the `uniqueIndex` and `unique` gorm tags by the generator. It's usually used as the
+// conflict target of upserts.
+//
+// Example:
+//
+//	gorm.G[User](db, clause.OnConflict{
+//		Columns:   generated.User.UniqueIndexes.Email.Columns(),
+//		UpdateAll: true,
+//	}).Create(ctx, &user)
+type UniqueIndex struct {
+	name    string
+	columns []string
+}
+
+// WithName creates a new UniqueIndex with the specified index or constraint name
+func (i UniqueIndex) WithName(name string) UniqueIndex {
+	return UniqueIndex{name: name, columns: i.columns}
+}
+
+// WithColumns creates a new UniqueIndex with the specified column names, in index order
+func (i UniqueIndex) WithColumns(columns ...string) UniqueIndex {
+	return UniqueIndex{name: i.name, columns: columns}
+}
+
+// Name returns the index or constraint name
+func (i UniqueIndex) Name() string { return i.name }
+
+// Columns returns the indexed columns in index order
+func (i UniqueIndex) Columns() []clause.Column {
+	columns := make([]clause.Column, len(i.columns))
+	for idx, name := range i.columns {
+		columns[idx] = clause.Column{Name: name}
+	}
+	return columns
+}
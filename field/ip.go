@@ -0,0 +1,367 @@
+// Package field provides type-safe field operations for GORM query builder.
+package field
+
+import (
+	"fmt"
+	"net/netip"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IP represents an IP address field, e.g. a net.IP or netip.Addr field, stored in a Postgres inet
+// column or as a string. Values are bound in their string form; network operations use the inet
+// operators of Postgres and INET6_ATON on MySQL.
+type IP[T fmt.Stringer] struct {
+	column clause.Column
+}
+
+// Column returns the underlying column for this field
+func (i IP[T]) Column() clause.Column { return i.column }
+
+// WithColumn creates a new IP field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	addr := field.IP[netip.Addr]{}.WithColumn("remote_addr")
+func (i IP[T]) WithColumn(name string) IP[T] {
+	column := i.column
+	column.Name = name
+	return IP[T]{column: column}
+}
+
+// WithTable creates a new IP field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	addr := field.IP[netip.Addr]{}.WithColumn("remote_addr")
+//	sessionAddr := addr.WithTable("sessions")
+func (i IP[T]) WithTable(name string) IP[T] {
+	column := i.column
+	column.Table = name
+	return IP[T]{column: column}
+}
+
+// Query functions
+
+// Eq creates an equality comparison expression (field = value).
+func (i IP[T]) Eq(value T) clause.Expression {
+	return clause.Eq{Column: i.column, Value: value.String()}
+}
+
+// EqExpr creates an equality comparison expression (field = expression).
+func (i IP[T]) EqExpr(expr clause.Expression) clause.Expression {
+	return clause.Eq{Column: i.column, Value: expr}
+}
+
+// Neq creates a not equal comparison expression (field != value).
+func (i IP[T]) Neq(value T) clause.Expression {
+	return clause.Neq{Column: i.column, Value: value.String()}
+}
+
+// NeqExpr creates a not equal comparison expression (field != expression).
+func (i IP[T]) NeqExpr(expr clause.Expression) clause.Expression {
+	return clause.Neq{Column: i.column, Value: expr}
+}
+
+// In creates an IN comparison expression (field IN (values...)).
+func (i IP[T]) In(values ...T) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for idx, v := range values {
+		interfaceValues[idx] = v.String()
+	}
+	return clause.IN{Column: i.column, Values: interfaceValues}
+}
+
+// NotIn creates a NOT IN comparison expression (field NOT IN (values...)).
+func (i IP[T]) NotIn(values ...T) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for idx, v := range values {
+		interfaceValues[idx] = v.String()
+	}
+	return clause.Not(clause.IN{Column: i.column, Values: interfaceValues})
+}
+
+// ContainedBy creates an expression checking whether the address is in network. SQLite has no
+// network functions, the statement fails with an error there.
+//
+// Example:
+//
+//	// Generate: WHERE "remote_addr"::inet <<= '10.0.0.0/8'::inet on Postgres,
+//	// WHERE INET6_ATON(`remote_addr`) BETWEEN 0x0a000000 AND 0x0affffff AND LENGTH(INET6_ATON(`remote_addr`)) = 4 on MySQL
+//	condition := generated.Session.RemoteAddr.ContainedBy(netip.MustParsePrefix("10.0.0.0/8"))
+func (i IP[T]) ContainedBy(network netip.Prefix) clause.Expression {
+	return ipExpr{column: i.column, network: network, op: ipContainedBy}
+}
+
+// SameFamily creates an expression checking whether the address is of the family of value, IPv4 or IPv6.
+// An invalid value, e.g. a nil net.IP, matches no rows.
+//
+// Example:
+//
+//	// Generate: WHERE family("remote_addr"::inet) = 4 on Postgres, WHERE IS_IPV4(`remote_addr`) on MySQL
+//	condition := generated.Session.RemoteAddr.SameFamily(netip.MustParseAddr("127.0.0.1"))
+func (i IP[T]) SameFamily(value T) clause.Expression {
+	addr, err := netip.ParseAddr(value.String())
+	if err != nil {
+		// an invalid address, e.g. a nil net.IP, has no family
+		return clause.Expr{SQL: "1 = 0"}
+	}
+	return ipExpr{column: i.column, ipv6: !addr.Unmap().Is4(), op: ipSameFamily}
+}
+
+// IsNull creates a NULL check expression (field IS NULL).
+func (i IP[T]) IsNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{i.column}}
+}
+
+// IsNotNull creates a NOT NULL check expression (field IS NOT NULL).
+func (i IP[T]) IsNotNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{i.column}}
+}
+
+// Set functions for UPDATE operations
+
+// Set creates an assignment expression for UPDATE operations (field = value).
+func (i IP[T]) Set(val T) clause.Assignment {
+	return clause.Assignment{Column: i.column, Value: val.String()}
+}
+
+// SetPtr creates an assignment expression for UPDATE operations of a *T field, a nil value sets NULL.
+func (i IP[T]) SetPtr(val *T) clause.Assignment {
+	if val == nil {
+		return i.SetNull()
+	}
+	return i.Set(*val)
+}
+
+// SetNull creates an assignment expression setting the field to NULL (field = NULL).
+func (i IP[T]) SetNull() clause.Assignment {
+	return clause.Assignment{Column: i.column, Value: nil}
+}
+
+// SetExpr creates an assignment expression for UPDATE operations (field = expression).
+func (i IP[T]) SetExpr(expr clause.Expression) clause.Assignment {
+	return clause.Assignment{Column: i.column, Value: expr}
+}
+
+// Order expressions for sorting operations
+
+// Asc creates an ascending order expression for ORDER BY clauses.
+func (i IP[T]) Asc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: i.column, Desc: false}
+}
+
+// Desc creates a descending order expression for ORDER BY clauses.
+func (i IP[T]) Desc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: i.column, Desc: true}
+}
+
+// buildSelectArg allows IP to be passed to Select(...)
+func (i IP[T]) buildSelectArg() any { return i.column }
+
+// As creates an alias for this column usable in Select(...)
+func (i IP[T]) As(alias string) Selectable {
+	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{i.column, clause.Column{Name: alias}}}}
+}
+
+// CIDR represents a network field, e.g. a netip.Prefix field stored in a Postgres cidr or inet column.
+// Network operations use the inet operators of Postgres, other dialects fail with an error.
+type CIDR struct {
+	column clause.Column
+}
+
+// Column returns the underlying column for this field
+func (c CIDR) Column() clause.Column { return c.column }
+
+// WithColumn creates a new CIDR field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	network := field.CIDR{}.WithColumn("network")
+func (c CIDR) WithColumn(name string) CIDR {
+	column := c.column
+	column.Name = name
+	return CIDR{column: column}
+}
+
+// WithTable creates a new CIDR field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	network := field.CIDR{}.WithColumn("network")
+//	subnetNetwork := network.WithTable("subnets")
+func (c CIDR) WithTable(name string) CIDR {
+	column := c.column
+	column.Table = name
+	return CIDR{column: column}
+}
+
+// Query functions
+
+// Eq creates an equality comparison expression (field = value).
+func (c CIDR) Eq(value netip.Prefix) clause.Expression {
+	return clause.Eq{Column: c.column, Value: value.String()}
+}
+
+// EqExpr creates an equality comparison expression (field = expression).
+func (c CIDR) EqExpr(expr clause.Expression) clause.Expression {
+	return clause.Eq{Column: c.column, Value: expr}
+}
+
+// Neq creates a not equal comparison expression (field != value).
+func (c CIDR) Neq(value netip.Prefix) clause.Expression {
+	return clause.Neq{Column: c.column, Value: value.String()}
+}
+
+// NeqExpr creates a not equal comparison expression (field != expression).
+func (c CIDR) NeqExpr(expr clause.Expression) clause.Expression {
+	return clause.Neq{Column: c.column, Value: expr}
+}
+
+// In creates an IN comparison expression (field IN (values...)).
+func (c CIDR) In(values ...netip.Prefix) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for i, v := range values {
+		interfaceValues[i] = v.String()
+	}
+	return clause.IN{Column: c.column, Values: interfaceValues}
+}
+
+// Contains creates an expression checking whether the network contains addr.
+//
+// Example:
+//
+//	// Generate: WHERE "network"::inet >>= '10.1.2.3'::inet
+//	condition := generated.Subnet.Network.Contains(netip.MustParseAddr("10.1.2.3"))
+func (c CIDR) Contains(addr netip.Addr) clause.Expression {
+	return ipExpr{column: c.column, network: netip.PrefixFrom(addr, addr.BitLen()), op: ipContains}
+}
+
+// ContainedBy creates an expression checking whether the network is a subnet of network, or equal to it.
+func (c CIDR) ContainedBy(network netip.Prefix) clause.Expression {
+	return ipExpr{column: c.column, network: network, op: ipNetworkContainedBy}
+}
+
+// SameFamily creates an expression checking whether the network is of the family of addr, IPv4 or IPv6.
+// An invalid addr matches no rows.
+func (c CIDR) SameFamily(addr netip.Addr) clause.Expression {
+	if !addr.IsValid() {
+		return clause.Expr{SQL: "1 = 0"}
+	}
+	return ipExpr{column: c.column, ipv6: !addr.Unmap().Is4(), op: ipNetworkSameFamily}
+}
+
+// IsNull creates a NULL check expression (field IS NULL).
+func (c CIDR) IsNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{c.column}}
+}
+
+// IsNotNull creates a NOT NULL check expression (field IS NOT NULL).
+func (c CIDR) IsNotNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{c.column}}
+}
+
+// Set functions for UPDATE operations
+
+// Set creates an assignment expression for UPDATE operations (field = value).
+func (c CIDR) Set(val netip.Prefix) clause.Assignment {
+	return clause.Assignment{Column: c.column, Value: val.String()}
+}
+
+// SetPtr creates an assignment expression for UPDATE operations of a *netip.Prefix field, a nil value sets NULL.
+func (c CIDR) SetPtr(val *netip.Prefix) clause.Assignment {
+	if val == nil {
+		return c.SetNull()
+	}
+	return c.Set(*val)
+}
+
+// SetNull creates an assignment expression setting the field to NULL (field = NULL).
+func (c CIDR) SetNull() clause.Assignment {
+	return clause.Assignment{Column: c.column, Value: nil}
+}
+
+// SetExpr creates an assignment expression for UPDATE operations (field = expression).
+func (c CIDR) SetExpr(expr clause.Expression) clause.Assignment {
+	return clause.Assignment{Column: c.column, Value: expr}
+}
+
+// buildSelectArg allows CIDR to be passed to Select(...)
+func (c CIDR) buildSelectArg() any { return c.column }
+
+// As creates an alias for this column usable in Select(...)
+func (c CIDR) As(alias string) Selectable {
+	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{c.column, clause.Column{Name: alias}}}}
+}
+
+type ipOp int
+
+const (
+	ipContainedBy ipOp = iota
+	ipSameFamily
+	ipContains
+	ipNetworkContainedBy
+	ipNetworkSameFamily
+)
+
+// ipExpr renders network operations with the functions of the current dialect
+type ipExpr struct {
+	column  clause.Column
+	network netip.Prefix
+	ipv6    bool
+	op      ipOp
+}
+
+func (e ipExpr) Build(builder clause.Builder) {
+	var dialect string
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		dialect = stmt.Dialector.Name()
+	}
+
+	family := 4
+	if e.ipv6 {
+		family = 6
+	}
+
+	switch {
+	case dialect == "postgres":
+		switch e.op {
+		case ipContainedBy, ipNetworkContainedBy:
+			clause.Expr{SQL: "?::inet <<= ?::inet", Vars: []any{e.column, e.network.String()}}.Build(builder)
+		case ipContains:
+			clause.Expr{SQL: "?::inet >>= ?::inet", Vars: []any{e.column, e.network.Addr().String()}}.Build(builder)
+		case ipSameFamily, ipNetworkSameFamily:
+			clause.Expr{SQL: "family(?::inet) = ?", Vars: []any{e.column, family}}.Build(builder)
+		}
+	case e.op == ipContainedBy && dialect == "mysql":
+		network := e.network.Masked()
+		first, last := network.Addr().AsSlice(), network.Addr().AsSlice()
+		for bit := network.Bits(); bit < len(last)*8; bit++ {
+			last[bit/8] |= 1 << (7 - bit%8)
+		}
+		clause.Expr{
+			SQL:  "INET6_ATON(?) BETWEEN ? AND ? AND LENGTH(INET6_ATON(?)) = ?",
+			Vars: []any{e.column, first, last, e.column, len(first)},
+		}.Build(builder)
+	case e.op == ipSameFamily && dialect == "mysql":
+		if e.ipv6 {
+			clause.Expr{SQL: "IS_IPV6(?)", Vars: []any{e.column}}.Build(builder)
+		} else {
+			clause.Expr{SQL: "IS_IPV4(?)", Vars: []any{e.column}}.Build(builder)
+		}
+	case e.op == ipSameFamily:
+		// IPv6 addresses are the only ones written with colons
+		if e.ipv6 {
+			clause.Expr{SQL: "instr(?, ':') > 0", Vars: []any{e.column}}.Build(builder)
+		} else {
+			clause.Expr{SQL: "instr(?, ':') = 0", Vars: []any{e.column}}.Build(builder)
+		}
+	default:
+		builder.AddError(fmt.Errorf("network operation on column %q is not supported by the %s dialect", e.column.Name, dialect))
+	}
+}
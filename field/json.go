@@ -0,0 +1,231 @@
+// Package field provides type-safe field operations for GORM query builder.
+package field
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JSON represents a field stored as JSON, e.g. a field tagged `gorm:"serializer:json"` or `gen:"json"`.
+// It provides JSON path and containment operations for building SQL queries, rendered with the JSON
+// functions of MySQL, the jsonb operators of Postgres and the JSON1 functions of SQLite.
+type JSON struct {
+	column clause.Column
+}
+
+// Column returns the underlying column for this field
+func (j JSON) Column() clause.Column { return j.column }
+
+// WithColumn creates a new JSON field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	tags := field.JSON{}.WithColumn("tags")
+func (j JSON) WithColumn(name string) JSON {
+	column := j.column
+	column.Name = name
+	return JSON{column: column}
+}
+
+// WithTable creates a new JSON field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	tags := field.JSON{}.WithColumn("tags")
+//	petTags := tags.WithTable("pets")
+func (j JSON) WithTable(name string) JSON {
+	column := j.column
+	column.Table = name
+	return JSON{column: column}
+}
+
+// Query functions
+
+// Equal creates an expression comparing the value at the JSON path with value,
+// path must be a valid JSON path like "$.vip" or "$[0]".
+func (j JSON) Equal(path string, value any) clause.Expression {
+	return jsonExpr{column: j.column, path: path, value: value, op: jsonEqual}
+}
+
+// Contains creates an expression checking whether the JSON document contains value,
+// e.g. an element of a JSON array.
+func (j JSON) Contains(value any) clause.Expression {
+	return jsonExpr{column: j.column, value: value, op: jsonContains}
+}
+
+// Exists creates an expression checking whether the JSON document has a value at the JSON path.
+//
+// Example:
+//
+//	// Generate: WHERE JSON_CONTAINS_PATH(`profile`, 'one', '$.vip') on MySQL,
+//	// WHERE "profile"::jsonb #> '{vip}' IS NOT NULL on Postgres,
+//	// WHERE json_type(`profile`, '$.vip') IS NOT NULL on SQLite
+//	condition := generated.User.Profile.Exists("$.vip")
+func (j JSON) Exists(path string) clause.Expression {
+	return jsonExpr{column: j.column, path: path, op: jsonExists}
+}
+
+// Extract creates an expression of the JSON value at the JSON path, usable in Select(...) and comparisons.
+//
+// Example:
+//
+//	// Generate: SELECT JSON_EXTRACT(`profile`, '$.address.city') AS city on MySQL
+//	gorm.G[User](db).Select(generated.User.Profile.Extract("$.address.city").As("city"))
+func (j JSON) Extract(path string) ValueExpression {
+	return valueExpr{jsonExpr{column: j.column, path: path, op: jsonExtract}}
+}
+
+// ArrayLength creates an expression of the length of the JSON array at the JSON path, use "$" for
+// the length of the document itself.
+//
+// Example:
+//
+//	// Generate: WHERE JSON_LENGTH(`tags`, '$') > 2 on MySQL,
+//	// WHERE jsonb_array_length("tags"::jsonb #> '{}') > 2 on Postgres
+//	condition := generated.Pet.Tags.ArrayLength("$").Gt(2)
+func (j JSON) ArrayLength(path string) ValueExpression {
+	return valueExpr{jsonExpr{column: j.column, path: path, op: jsonArrayLength}}
+}
+
+// IsNull creates a NULL check expression (field IS NULL).
+func (j JSON) IsNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{j.column}}
+}
+
+// IsNotNull creates a NOT NULL check expression (field IS NOT NULL).
+func (j JSON) IsNotNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{j.column}}
+}
+
+// Set functions for UPDATE operations
+
+// Set creates an assignment expression for UPDATE operations, value is serialized as JSON when the
+// statement is built, failing it if value can't be serialized.
+func (j JSON) Set(value any) clause.Assignment {
+	return clause.Assignment{Column: j.column, Value: jsonValue{column: j.column, value: value}}
+}
+
+// SetExpr creates an assignment expression for UPDATE operations (field = expression).
+func (j JSON) SetExpr(expr clause.Expression) clause.Assignment {
+	return clause.Assignment{Column: j.column, Value: expr}
+}
+
+// buildSelectArg allows JSON to be passed to Select(...)
+func (j JSON) buildSelectArg() any { return j.column }
+
+// As creates an alias for this column usable in Select(...)
+func (j JSON) As(alias string) Selectable {
+	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{j.column, clause.Column{Name: alias}}}}
+}
+
+type jsonOp int
+
+const (
+	jsonEqual jsonOp = iota
+	jsonContains
+	jsonExists
+	jsonExtract
+	jsonArrayLength
+)
+
+// jsonExpr renders JSON operations with the functions of the current dialect
+type jsonExpr struct {
+	column clause.Column
+	path   string
+	value  any
+	op     jsonOp
+}
+
+func (e jsonExpr) Build(builder clause.Builder) {
+	var dialect string
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		dialect = stmt.Dialector.Name()
+	}
+
+	data, err := json.Marshal(e.value)
+	if err != nil {
+		builder.AddError(fmt.Errorf("invalid JSON value of column %q: %w", e.column.Name, err))
+		return
+	}
+	switch e.op {
+	case jsonEqual:
+		switch dialect {
+		case "mysql":
+			clause.Expr{SQL: "JSON_EXTRACT(?, ?) = CAST(? AS JSON)", Vars: []any{e.column, e.path, string(data)}}.Build(builder)
+		case "postgres":
+			clause.Expr{SQL: "?::jsonb #> ? = ?::jsonb", Vars: []any{e.column, postgresJSONPath(e.path), string(data)}}.Build(builder)
+		default:
+			clause.Expr{SQL: "json_extract(?, ?) = ?", Vars: []any{sqliteJSON(e.column), e.path, e.value}}.Build(builder)
+		}
+	case jsonContains:
+		switch dialect {
+		case "mysql":
+			clause.Expr{SQL: "JSON_CONTAINS(?, ?)", Vars: []any{e.column, string(data)}}.Build(builder)
+		case "postgres":
+			clause.Expr{SQL: "?::jsonb @> ?::jsonb", Vars: []any{e.column, string(data)}}.Build(builder)
+		default:
+			clause.Expr{SQL: "EXISTS (SELECT 1 FROM json_each(?) WHERE value = ?)", Vars: []any{sqliteJSON(e.column), e.value}}.Build(builder)
+		}
+	case jsonExists:
+		switch dialect {
+		case "mysql":
+			clause.Expr{SQL: "JSON_CONTAINS_PATH(?, 'one', ?)", Vars: []any{e.column, e.path}}.Build(builder)
+		case "postgres":
+			clause.Expr{SQL: "?::jsonb #> ? IS NOT NULL", Vars: []any{e.column, postgresJSONPath(e.path)}}.Build(builder)
+		default:
+			clause.Expr{SQL: "json_type(?, ?) IS NOT NULL", Vars: []any{sqliteJSON(e.column), e.path}}.Build(builder)
+		}
+	case jsonExtract:
+		switch dialect {
+		case "mysql":
+			clause.Expr{SQL: "JSON_EXTRACT(?, ?)", Vars: []any{e.column, e.path}}.Build(builder)
+		case "postgres":
+			clause.Expr{SQL: "?::jsonb #> ?", Vars: []any{e.column, postgresJSONPath(e.path)}}.Build(builder)
+		default:
+			clause.Expr{SQL: "json_extract(?, ?)", Vars: []any{sqliteJSON(e.column), e.path}}.Build(builder)
+		}
+	case jsonArrayLength:
+		switch dialect {
+		case "mysql":
+			clause.Expr{SQL: "JSON_LENGTH(?, ?)", Vars: []any{e.column, e.path}}.Build(builder)
+		case "postgres":
+			clause.Expr{SQL: "jsonb_array_length(?::jsonb #> ?)", Vars: []any{e.column, postgresJSONPath(e.path)}}.Build(builder)
+		default:
+			clause.Expr{SQL: "json_array_length(?, ?)", Vars: []any{sqliteJSON(e.column), e.path}}.Build(builder)
+		}
+	}
+}
+
+// jsonValue is a value bound as its JSON text, the errors of serializing it are added to the statement
+type jsonValue struct {
+	column clause.Column
+	value  any
+}
+
+func (v jsonValue) Build(builder clause.Builder) {
+	data, err := json.Marshal(v.value)
+	if err != nil {
+		builder.AddError(fmt.Errorf("invalid JSON value of column %q: %w", v.column.Name, err))
+		return
+	}
+	builder.AddVar(builder, string(data))
+}
+
+// sqliteJSON guards the JSON1 functions against values that are not valid JSON like empty strings,
+// they fail with "malformed JSON" instead of returning NULL
+func sqliteJSON(column clause.Column) clause.Expression {
+	return clause.Expr{SQL: "CASE WHEN json_valid(?) THEN ? END", Vars: []any{column, column}}
+}
+
+// postgresJSONPath converts a JSON path like "$.a.b[0]" to the text array form "{a,b,0}" used by #>
+func postgresJSONPath(path string) string {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	return "{" + strings.Join(strings.FieldsFunc(path, func(r rune) bool { return r == '.' }), ",") + "}"
+}
@@ -0,0 +1,187 @@
+package field
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Map represents a map field stored as a JSON object, e.g. a map[string]string field tagged
+// `gorm:"serializer:json"`. It provides key lookup and key update operations for building SQL queries.
+type Map[K comparable, V any] struct {
+	column clause.Column
+}
+
+// Column returns the underlying column for this field
+func (m Map[K, V]) Column() clause.Column { return m.column }
+
+// WithColumn creates a new Map field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	labels := field.Map[string, string]{}.WithColumn("labels")
+func (m Map[K, V]) WithColumn(name string) Map[K, V] {
+	column := m.column
+	column.Name = name
+	return Map[K, V]{column: column}
+}
+
+// WithTable creates a new Map field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	labels := field.Map[string, string]{}.WithColumn("labels")
+//	petLabels := labels.WithTable("pets")
+func (m Map[K, V]) WithTable(name string) Map[K, V] {
+	column := m.column
+	column.Table = name
+	return Map[K, V]{column: column}
+}
+
+// Query functions
+
+// KeyEq creates an expression comparing the value of key with value.
+//
+// Example:
+//
+//	// Generate: WHERE json_extract(labels, '$."color"') = 'red'
+//	condition := labels.KeyEq("color", "red")
+func (m Map[K, V]) KeyEq(key K, value V) clause.Expression {
+	return mapExpr{column: m.column, key: fmt.Sprint(key), value: value, op: mapKeyEq}
+}
+
+// HasKey creates an expression checking whether the map contains key.
+//
+// Example:
+//
+//	// Generate: WHERE json_type(labels, '$."color"') IS NOT NULL
+//	condition := labels.HasKey("color")
+func (m Map[K, V]) HasKey(key K) clause.Expression {
+	return mapExpr{column: m.column, key: fmt.Sprint(key), op: mapHasKey}
+}
+
+// IsNull creates a NULL check expression (field IS NULL).
+func (m Map[K, V]) IsNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{m.column}}
+}
+
+// IsNotNull creates a NOT NULL check expression (field IS NOT NULL).
+func (m Map[K, V]) IsNotNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{m.column}}
+}
+
+// Set functions for UPDATE operations
+
+// Set creates an assignment expression for UPDATE operations, value is serialized as JSON when the
+// statement is built, failing it if value can't be serialized.
+func (m Map[K, V]) Set(value map[K]V) clause.Assignment {
+	return clause.Assignment{Column: m.column, Value: jsonValue{column: m.column, value: value}}
+}
+
+// SetExpr creates an assignment expression for UPDATE operations (field = expression).
+func (m Map[K, V]) SetExpr(expr clause.Expression) clause.Assignment {
+	return clause.Assignment{Column: m.column, Value: expr}
+}
+
+// SetKey creates an assignment expression for UPDATE operations setting the value of key,
+// the other keys of the map are kept.
+//
+// Example:
+//
+//	// Generate: SET labels = json_set(labels, '$."color"', json('"red"'))
+//	assignment := labels.SetKey("color", "red")
+func (m Map[K, V]) SetKey(key K, value V) clause.Assignment {
+	return clause.Assignment{Column: m.column, Value: mapExpr{column: m.column, key: fmt.Sprint(key), value: value, op: mapSetKey}}
+}
+
+// DeleteKey creates an assignment expression for UPDATE operations removing key from the map.
+//
+// Example:
+//
+//	// Generate: SET labels = json_remove(labels, '$."color"')
+//	assignment := labels.DeleteKey("color")
+func (m Map[K, V]) DeleteKey(key K) clause.Assignment {
+	return clause.Assignment{Column: m.column, Value: mapExpr{column: m.column, key: fmt.Sprint(key), op: mapDeleteKey}}
+}
+
+// buildSelectArg allows Map to be passed to Select(...)
+func (m Map[K, V]) buildSelectArg() any { return m.column }
+
+// As creates an alias for this column usable in Select(...)
+func (m Map[K, V]) As(alias string) Selectable {
+	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{m.column, clause.Column{Name: alias}}}}
+}
+
+type mapOp int
+
+const (
+	mapKeyEq mapOp = iota
+	mapHasKey
+	mapSetKey
+	mapDeleteKey
+)
+
+// mapExpr renders map operations with the JSON functions of the current dialect
+type mapExpr struct {
+	column clause.Column
+	key    string
+	value  any
+	op     mapOp
+}
+
+func (e mapExpr) Build(builder clause.Builder) {
+	var dialect string
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		dialect = stmt.Dialector.Name()
+	}
+
+	data, err := json.Marshal(e.value)
+	if err != nil {
+		builder.AddError(fmt.Errorf("invalid JSON value of column %q: %w", e.column.Name, err))
+		return
+	}
+	path := `$."` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(e.key) + `"`
+	switch e.op {
+	case mapKeyEq:
+		switch dialect {
+		case "mysql":
+			clause.Expr{SQL: "JSON_EXTRACT(?, ?) = CAST(? AS JSON)", Vars: []any{e.column, path, string(data)}}.Build(builder)
+		case "postgres":
+			clause.Expr{SQL: "?::jsonb -> ? = ?::jsonb", Vars: []any{e.column, e.key, string(data)}}.Build(builder)
+		default:
+			clause.Expr{SQL: "json_extract(?, ?) = ?", Vars: []any{e.column, path, e.value}}.Build(builder)
+		}
+	case mapHasKey:
+		switch dialect {
+		case "mysql":
+			clause.Expr{SQL: "JSON_CONTAINS_PATH(?, 'one', ?)", Vars: []any{e.column, path}}.Build(builder)
+		case "postgres":
+			clause.Expr{SQL: "jsonb_exists(?::jsonb, ?)", Vars: []any{e.column, e.key}}.Build(builder)
+		default:
+			clause.Expr{SQL: "json_type(?, ?) IS NOT NULL", Vars: []any{e.column, path}}.Build(builder)
+		}
+	case mapSetKey:
+		switch dialect {
+		case "mysql":
+			clause.Expr{SQL: "JSON_SET(COALESCE(?, '{}'), ?, CAST(? AS JSON))", Vars: []any{e.column, path, string(data)}}.Build(builder)
+		case "postgres":
+			clause.Expr{SQL: "jsonb_set(COALESCE(?::jsonb, '{}'), ARRAY[?], ?::jsonb)", Vars: []any{e.column, e.key, string(data)}}.Build(builder)
+		default:
+			clause.Expr{SQL: "json_set(COALESCE(?, '{}'), ?, json(?))", Vars: []any{e.column, path, string(data)}}.Build(builder)
+		}
+	case mapDeleteKey:
+		switch dialect {
+		case "mysql":
+			clause.Expr{SQL: "JSON_REMOVE(?, ?)", Vars: []any{e.column, path}}.Build(builder)
+		case "postgres":
+			clause.Expr{SQL: "?::jsonb - ?", Vars: []any{e.column, e.key}}.Build(builder)
+		default:
+			clause.Expr{SQL: "json_remove(?, ?)", Vars: []any{e.column, path}}.Build(builder)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package field
+
+import "gorm.io/gorm/clause"
+
+// Null represents a nullable column of values of type T, e.g. a *time.Time or *int field. Set takes a
+// pointer where nil writes NULL, and NULL values are matched with IsNull and IsNotNull.
+type Null[T any] struct {
+	valueField[T]
+}
+
+// WithColumn creates a new Null field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	birthday := field.Null[time.Time]{}.WithColumn("birthday")
+func (n Null[T]) WithColumn(name string) Null[T] {
+	column := n.column
+	column.Name = name
+	return Null[T]{valueField[T]{column: column}}
+}
+
+// WithTable creates a new Null field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	birthday := field.Null[time.Time]{}.WithColumn("birthday")
+//	userBirthday := birthday.WithTable("users")
+func (n Null[T]) WithTable(name string) Null[T] {
+	column := n.column
+	column.Table = name
+	return Null[T]{valueField[T]{column: column}}
+}
+
+// Set functions for UPDATE operations
+
+// Set creates an assignment expression for UPDATE operations (field = value), a nil value sets NULL.
+//
+// Example:
+//
+//	// Generate: UPDATE users SET birthday = NULL
+//	gorm.G[User](db).Where(generated.User.ID.Eq(1)).Set(generated.User.Birthday.Set(nil)).Update(ctx)
+func (n Null[T]) Set(value *T) clause.Assignment {
+	if value == nil {
+		return n.SetNull()
+	}
+	return clause.Assignment{Column: n.column, Value: *value}
+}
+
+// SetNull creates an assignment expression setting the field to NULL (field = NULL).
+func (n Null[T]) SetNull() clause.Assignment {
+	return clause.Assignment{Column: n.column, Value: nil}
+}
+
+// SetExpr creates an assignment expression for UPDATE operations (field = expression).
+func (n Null[T]) SetExpr(expr clause.Expression) clause.Assignment {
+	return clause.Assignment{Column: n.column, Value: expr}
+}
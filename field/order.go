@@ -0,0 +1,53 @@
+package field
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// OrderBy creates an ORDER BY expression sorting by a column chosen at runtime, used by the {{orderBy}}
+// directive of SQL templates. The column must be one of allowed and the direction ASC, DESC or empty
+// (case-insensitive), otherwise the statement fails with an error instead of interpolating the value.
+// An empty column renders nothing.
+//
+// Example:
+//
+//	// Generate: ORDER BY `age` DESC
+//	db.Raw("SELECT * FROM users ?", field.OrderBy("age", "desc", "name", "age"))
+func OrderBy(column, direction string, allowed ...string) clause.Expression {
+	return orderByExpr{column: column, direction: direction, allowed: allowed}
+}
+
+type orderByExpr struct {
+	column, direction string
+	allowed           []string
+}
+
+func (e orderByExpr) Build(builder clause.Builder) {
+	if e.column == "" {
+		return
+	}
+	if !slices.Contains(e.allowed, e.column) {
+		builder.AddError(fmt.Errorf("invalid order by column %q, must be one of %s", e.column, strings.Join(e.allowed, ", ")))
+		return
+	}
+
+	var desc bool
+	switch strings.ToUpper(e.direction) {
+	case "", "ASC":
+	case "DESC":
+		desc = true
+	default:
+		builder.AddError(fmt.Errorf("invalid order by direction %q, must be ASC or DESC", e.direction))
+		return
+	}
+
+	builder.WriteString("ORDER BY ")
+	builder.WriteQuoted(clause.Column{Name: e.column})
+	if desc {
+		builder.WriteString(" DESC")
+	}
+}
@@ -0,0 +1,64 @@
+package field
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Paginate creates a pagination expression rendered with the syntax of the current dialect, used by the
+// {{limit}} and {{offset}} directives of SQL templates. A negative limit means no limit and a non-positive
+// offset means no offset, same as gorm's Limit and Offset.
+//
+// Example:
+//
+//	// Generate: LIMIT 10 OFFSET 20 (mysql, postgres, sqlite)
+//	//           OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY (sqlserver, oracle)
+//	db.Raw("SELECT * FROM users ORDER BY id ?", field.Paginate(10, 20))
+func Paginate(limit, offset int) clause.Expression {
+	return paginateExpr{limit: limit, offset: offset}
+}
+
+// paginateExpr renders LIMIT/OFFSET, or OFFSET/FETCH for dialects without LIMIT
+type paginateExpr struct {
+	limit, offset int
+}
+
+func (e paginateExpr) Build(builder clause.Builder) {
+	var dialect string
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		dialect = stmt.Dialector.Name()
+	}
+
+	var written bool
+	write := func(sql string, vars ...any) {
+		if written {
+			builder.WriteByte(' ')
+		}
+		clause.Expr{SQL: sql, Vars: vars}.Build(builder)
+		written = true
+	}
+
+	switch dialect {
+	case "sqlserver", "oracle":
+		// sqlserver requires an OFFSET before FETCH, and an ORDER BY clause in the query
+		if e.offset > 0 || dialect == "sqlserver" && e.limit >= 0 {
+			write("OFFSET ? ROWS", max(e.offset, 0))
+		}
+		if e.limit >= 0 && written {
+			write("FETCH NEXT ? ROWS ONLY", e.limit)
+		} else if e.limit >= 0 {
+			write("FETCH FIRST ? ROWS ONLY", e.limit)
+		}
+	default:
+		if e.limit >= 0 {
+			write("LIMIT ?", e.limit)
+		} else if e.offset > 0 && dialect == "mysql" { // OFFSET requires a LIMIT
+			write("LIMIT 18446744073709551615")
+		} else if e.offset > 0 && dialect != "postgres" {
+			write("LIMIT -1")
+		}
+		if e.offset > 0 {
+			write("OFFSET ?", e.offset)
+		}
+	}
+}
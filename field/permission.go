@@ -0,0 +1,79 @@
+package field
+
+import "gorm.io/gorm/clause"
+
+// ReadOnly represents a column of values of type T that can't be written according to the gorm
+// permission tags of the field, e.g. `gorm:"->"`, or the columns of a database view. It only provides
+// query operations, so assigning the field fails at compile time.
+type ReadOnly[T any] struct {
+	valueField[T]
+}
+
+// WithColumn creates a new ReadOnly field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	total := field.ReadOnly[int]{}.WithColumn("total")
+func (r ReadOnly[T]) WithColumn(name string) ReadOnly[T] {
+	column := r.column
+	column.Name = name
+	return ReadOnly[T]{valueField[T]{column: column}}
+}
+
+// WithTable creates a new ReadOnly field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	total := field.ReadOnly[int]{}.WithColumn("total")
+//	orderTotal := total.WithTable("orders")
+func (r ReadOnly[T]) WithTable(name string) ReadOnly[T] {
+	column := r.column
+	column.Table = name
+	return ReadOnly[T]{valueField[T]{column: column}}
+}
+
+// CreateOnly represents a column of values of type T that is only written on create according to the
+// gorm permission tags of the field, e.g. `gorm:"<-:create"`. Values are set as is, it has no update
+// assigners like Incr or Concat that derive the value from the current one.
+type CreateOnly[T any] struct {
+	valueField[T]
+}
+
+// WithColumn creates a new CreateOnly field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	createdBy := field.CreateOnly[string]{}.WithColumn("created_by")
+func (c CreateOnly[T]) WithColumn(name string) CreateOnly[T] {
+	column := c.column
+	column.Name = name
+	return CreateOnly[T]{valueField[T]{column: column}}
+}
+
+// WithTable creates a new CreateOnly field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	createdBy := field.CreateOnly[string]{}.WithColumn("created_by")
+//	postCreatedBy := createdBy.WithTable("posts")
+func (c CreateOnly[T]) WithTable(name string) CreateOnly[T] {
+	column := c.column
+	column.Table = name
+	return CreateOnly[T]{valueField[T]{column: column}}
+}
+
+// Set functions for UPDATE operations
+
+// Set creates an assignment expression for UPDATE operations (field = value).
+func (c CreateOnly[T]) Set(value T) clause.Assignment {
+	return clause.Assignment{Column: c.column, Value: value}
+}
+
+// SetExpr creates an assignment expression for UPDATE operations (field = expression).
+func (c CreateOnly[T]) SetExpr(expr clause.Expression) clause.Assignment {
+	return clause.Assignment{Column: c.column, Value: expr}
+}
@@ -0,0 +1,13 @@
+package field
+
+// ColumnSchema describes the column of a field as declared with the `type`, `size`, `precision`,
+// `scale` and `not null` gorm tags, zero values mean the tag isn't set. It's returned by the
+// Schemas method of generated model helpers by column name.
+type ColumnSchema struct {
+	// Type is the database type of the column, e.g. varchar(100)
+	Type      string
+	Size      int
+	Precision int
+	Scale     int
+	NotNull   bool
+}
@@ -0,0 +1,181 @@
+// Package field provides type-safe field operations for GORM query builder.
+package field
+
+import (
+	"encoding/hex"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UUID represents a UUID field, e.g. a github.com/google/uuid.UUID or github.com/gofrs/uuid.UUID field.
+// Values are bound in their canonical string form, cast to the uuid type on Postgres and compared
+// as char(36) text on the other dialects.
+type UUID[T ~[16]byte] struct {
+	column clause.Column
+}
+
+// Column returns the underlying column for this field
+func (u UUID[T]) Column() clause.Column { return u.column }
+
+// WithColumn creates a new UUID field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	id := field.UUID[uuid.UUID]{}.WithColumn("id")
+func (u UUID[T]) WithColumn(name string) UUID[T] {
+	column := u.column
+	column.Name = name
+	return UUID[T]{column: column}
+}
+
+// WithTable creates a new UUID field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	id := field.UUID[uuid.UUID]{}.WithColumn("id")
+//	userID := id.WithTable("users")
+func (u UUID[T]) WithTable(name string) UUID[T] {
+	column := u.column
+	column.Table = name
+	return UUID[T]{column: column}
+}
+
+// Query functions
+
+// Eq creates an equality comparison expression (field = value).
+//
+// Example:
+//
+//	// Generate: WHERE id = CAST('7c9e6679-7425-40de-944b-e07fc1f90ae7' AS UUID) on postgres,
+//	// WHERE id = '7c9e6679-7425-40de-944b-e07fc1f90ae7' otherwise
+//	condition := generated.User.ID.Eq(id)
+func (u UUID[T]) Eq(value T) clause.Expression {
+	return clause.Eq{Column: u.column, Value: uuidValue(value)}
+}
+
+// EqExpr creates an equality comparison expression (field = expression).
+func (u UUID[T]) EqExpr(expr clause.Expression) clause.Expression {
+	return clause.Eq{Column: u.column, Value: expr}
+}
+
+// Neq creates a not equal comparison expression (field != value).
+func (u UUID[T]) Neq(value T) clause.Expression {
+	return clause.Neq{Column: u.column, Value: uuidValue(value)}
+}
+
+// NeqExpr creates a not equal comparison expression (field != expression).
+func (u UUID[T]) NeqExpr(expr clause.Expression) clause.Expression {
+	return clause.Neq{Column: u.column, Value: expr}
+}
+
+// In creates an IN comparison expression (field IN (values...)).
+func (u UUID[T]) In(values ...T) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for i, v := range values {
+		interfaceValues[i] = uuidValue(v)
+	}
+	return clause.IN{Column: u.column, Values: interfaceValues}
+}
+
+// NotIn creates a NOT IN comparison expression (field NOT IN (values...)).
+func (u UUID[T]) NotIn(values ...T) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for i, v := range values {
+		interfaceValues[i] = uuidValue(v)
+	}
+	return clause.Not(clause.IN{Column: u.column, Values: interfaceValues})
+}
+
+// IsNull creates a NULL check expression (field IS NULL).
+func (u UUID[T]) IsNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{u.column}}
+}
+
+// IsNotNull creates a NOT NULL check expression (field IS NOT NULL).
+func (u UUID[T]) IsNotNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{u.column}}
+}
+
+// Set functions for UPDATE operations
+
+// Set creates an assignment expression for UPDATE operations (field = value).
+func (u UUID[T]) Set(val T) clause.Assignment {
+	return clause.Assignment{Column: u.column, Value: uuidValue(val)}
+}
+
+// SetPtr creates an assignment expression for UPDATE operations of a *T field, a nil value sets NULL.
+//
+// Example:
+//
+//	// Generate: UPDATE users SET parent_key = NULL
+//	gorm.G[User](db).Where(generated.User.ID.Eq(id)).Set(generated.User.ParentKey.SetPtr(nil)).Update(ctx)
+func (u UUID[T]) SetPtr(val *T) clause.Assignment {
+	if val == nil {
+		return u.SetNull()
+	}
+	return u.Set(*val)
+}
+
+// SetNull creates an assignment expression setting the field to NULL (field = NULL).
+func (u UUID[T]) SetNull() clause.Assignment {
+	return clause.Assignment{Column: u.column, Value: nil}
+}
+
+// SetExpr creates an assignment expression for UPDATE operations (field = expression).
+func (u UUID[T]) SetExpr(expr clause.Expression) clause.Assignment {
+	return clause.Assignment{Column: u.column, Value: expr}
+}
+
+// Order expressions for sorting operations
+
+// Asc creates an ascending order expression for ORDER BY clauses.
+func (u UUID[T]) Asc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: u.column, Desc: false}
+}
+
+// Desc creates a descending order expression for ORDER BY clauses.
+func (u UUID[T]) Desc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: u.column, Desc: true}
+}
+
+// buildSelectArg allows UUID to be passed to Select(...)
+func (u UUID[T]) buildSelectArg() any { return u.column }
+
+// As creates an alias for this column usable in Select(...)
+func (u UUID[T]) As(alias string) Selectable {
+	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{u.column, clause.Column{Name: alias}}}}
+}
+
+// uuidValue is a UUID bound with the cast of the current dialect
+type uuidValue [16]byte
+
+// String returns the canonical form of the UUID, e.g. 7c9e6679-7425-40de-944b-e07fc1f90ae7
+func (v uuidValue) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], v[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], v[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], v[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], v[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], v[10:])
+	return string(buf[:])
+}
+
+func (v uuidValue) Build(builder clause.Builder) {
+	var dialect string
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		dialect = stmt.Dialector.Name()
+	}
+
+	if dialect == "postgres" {
+		clause.Expr{SQL: "CAST(? AS UUID)", Vars: []any{v.String()}}.Build(builder)
+		return
+	}
+	builder.AddVar(builder, v.String())
+}
@@ -0,0 +1,130 @@
+package field
+
+import "gorm.io/gorm/clause"
+
+// valueField provides the query operations shared by the Null, ReadOnly and CreateOnly helpers,
+// comparisons of the column with values of type T, NULL checks and ordering
+type valueField[T any] struct {
+	column clause.Column
+}
+
+// Column returns the underlying column for this field
+func (v valueField[T]) Column() clause.Column { return v.column }
+
+// Query functions
+
+// Eq creates an equality comparison expression (field = value).
+func (v valueField[T]) Eq(value T) clause.Expression {
+	return clause.Eq{Column: v.column, Value: value}
+}
+
+// EqExpr creates an equality comparison expression (field = expression).
+func (v valueField[T]) EqExpr(expr clause.Expression) clause.Expression {
+	return clause.Eq{Column: v.column, Value: expr}
+}
+
+// Neq creates a not equal comparison expression (field != value).
+func (v valueField[T]) Neq(value T) clause.Expression {
+	return clause.Neq{Column: v.column, Value: value}
+}
+
+// NeqExpr creates a not equal comparison expression (field != expression).
+func (v valueField[T]) NeqExpr(expr clause.Expression) clause.Expression {
+	return clause.Neq{Column: v.column, Value: expr}
+}
+
+// Gt creates a greater than comparison expression (field > value).
+func (v valueField[T]) Gt(value T) clause.Expression {
+	return clause.Gt{Column: v.column, Value: value}
+}
+
+// GtExpr creates a greater than comparison expression (field > expression).
+func (v valueField[T]) GtExpr(expr clause.Expression) clause.Expression {
+	return clause.Gt{Column: v.column, Value: expr}
+}
+
+// Gte creates a greater than or equal comparison expression (field >= value).
+func (v valueField[T]) Gte(value T) clause.Expression {
+	return clause.Gte{Column: v.column, Value: value}
+}
+
+// GteExpr creates a greater than or equal comparison expression (field >= expression).
+func (v valueField[T]) GteExpr(expr clause.Expression) clause.Expression {
+	return clause.Gte{Column: v.column, Value: expr}
+}
+
+// Lt creates a less than comparison expression (field < value).
+func (v valueField[T]) Lt(value T) clause.Expression {
+	return clause.Lt{Column: v.column, Value: value}
+}
+
+// LtExpr creates a less than comparison expression (field < expression).
+func (v valueField[T]) LtExpr(expr clause.Expression) clause.Expression {
+	return clause.Lt{Column: v.column, Value: expr}
+}
+
+// Lte creates a less than or equal comparison expression (field <= value).
+func (v valueField[T]) Lte(value T) clause.Expression {
+	return clause.Lte{Column: v.column, Value: value}
+}
+
+// LteExpr creates a less than or equal comparison expression (field <= expression).
+func (v valueField[T]) LteExpr(expr clause.Expression) clause.Expression {
+	return clause.Lte{Column: v.column, Value: expr}
+}
+
+// Between creates a range comparison expression (field BETWEEN v1 AND v2).
+func (v valueField[T]) Between(v1, v2 T) clause.Expression {
+	return clause.And(
+		clause.Gte{Column: v.column, Value: v1},
+		clause.Lte{Column: v.column, Value: v2},
+	)
+}
+
+// In creates an IN comparison expression (field IN (values...)).
+func (v valueField[T]) In(values ...T) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for i, value := range values {
+		interfaceValues[i] = value
+	}
+	return clause.IN{Column: v.column, Values: interfaceValues}
+}
+
+// NotIn creates a NOT IN comparison expression (field NOT IN (values...)).
+func (v valueField[T]) NotIn(values ...T) clause.Expression {
+	interfaceValues := make([]any, len(values))
+	for i, value := range values {
+		interfaceValues[i] = value
+	}
+	return clause.Not(clause.IN{Column: v.column, Values: interfaceValues})
+}
+
+// IsNull creates a NULL check expression (field IS NULL).
+func (v valueField[T]) IsNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NULL", Vars: []any{v.column}}
+}
+
+// IsNotNull creates a NOT NULL check expression (field IS NOT NULL).
+func (v valueField[T]) IsNotNull() clause.Expression {
+	return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{v.column}}
+}
+
+// Order expressions for sorting operations
+
+// Asc creates an ascending order expression for ORDER BY clauses.
+func (v valueField[T]) Asc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: v.column, Desc: false}
+}
+
+// Desc creates a descending order expression for ORDER BY clauses.
+func (v valueField[T]) Desc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: v.column, Desc: true}
+}
+
+// buildSelectArg allows the field to be passed to Select(...)
+func (v valueField[T]) buildSelectArg() any { return v.column }
+
+// As creates an alias for this column usable in Select(...)
+func (v valueField[T]) As(alias string) Selectable {
+	return selectExpr{clause.Expr{SQL: "? AS ?", Vars: []any{v.column, clause.Column{Name: alias}}}}
+}
@@ -0,0 +1,49 @@
+package field
+
+import (
+	"golang.org/x/exp/constraints"
+	"gorm.io/gorm/clause"
+)
+
+// Version represents the integer version column of optimistic locking, i.e. a field tagged
+// `gen:"version"` or named after genconfig.Config.VersionField. It provides the operations of Number
+// besides Lock and Bump, see typed.UpdateVersioned.
+type Version[T constraints.Integer] struct {
+	Number[T]
+}
+
+// WithColumn creates a new Version field with the specified column name.
+// This method allows you to change the column name while keeping other properties.
+//
+// Example:
+//
+//	version := field.Version[int]{}.WithColumn("version")
+func (v Version[T]) WithColumn(name string) Version[T] {
+	return Version[T]{v.Number.WithColumn(name)}
+}
+
+// WithTable creates a new Version field with the specified table name.
+// This method is useful when working with joins and you need to qualify the column with a table name.
+//
+// Example:
+//
+//	version := field.Version[int]{}.WithColumn("version")
+//	companyVersion := version.WithTable("companies")
+func (v Version[T]) WithTable(name string) Version[T] {
+	return Version[T]{v.Number.WithTable(name)}
+}
+
+// Lock creates the condition matching the expected version for optimistic locking (field = version).
+//
+// Example:
+//
+//	// Generate: WHERE version = 3
+//	condition := generated.Company.Version.Lock(3)
+func (v Version[T]) Lock(version T) clause.Expression {
+	return v.Eq(version)
+}
+
+// Bump creates the assignment incrementing the version for optimistic locking (field = field + 1).
+func (v Version[T]) Bump() clause.Assigner {
+	return v.Incr(1)
+}
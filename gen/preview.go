@@ -0,0 +1,24 @@
+// Package gen exposes the SQL templates of the query interfaces read by the generator to the tests of the
+// packages declaring them.
+package gen
+
+import internalgen "gorm.io/cli/gorm/internal/gen"
+
+// PreviewSQL renders the SQL template of the method of the interface declared in the Go files of input with
+// the args, by parameter name, and returns the SQL and params the generated method would pass to gorm, so
+// tests can assert the behavior of templates without generating and compiling the package.
+//
+// The template is parsed and rendered into the Go code of the generated method like `gorm gen` does, and
+// the code is run in-process against the args: conditions, loops and @{expr} placeholders are Go
+// expressions evaluated against them. Functions of genconfig.Config.SQLFuncs are called from the args by
+// their name, and package-level functions or values by their qualified name, e.g. "time.Now". The SET list
+// and the WHERE condition of update(...) methods are joined by a WHERE keyword.
+//
+// Example:
+//
+//	sql, params, err := gen.PreviewSQL("./models", "Query", "FilterByName", map[string]any{"name": "jinzhu"})
+//	// sql: SELECT * FROM ? WHERE name = ?
+//	// params: clause.Table{Name: clause.CurrentTable}, "jinzhu"
+func PreviewSQL(input, interfaceName, methodName string, args map[string]any) (sql string, params []any, err error) {
+	return internalgen.PreviewSQL(input, interfaceName, methodName, args)
+}
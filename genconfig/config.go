@@ -21,11 +21,122 @@ package genconfig
 //
 // The generator will prioritize FieldNameMap over FieldTypeMap when deciding
 // which wrapper type to use for a field.
+//
+// The generator parses source files regardless of build constraints, so the
+// config can live in a dedicated gorm.gen.go guarded by a build tag that
+// regular builds never set, keeping this package out of shipped binaries:
+//
+//	//go:build gorm
+//
+//	package models
+//
+//	import "gorm.io/cli/gorm/genconfig"
+//
+//	var _ = genconfig.Config{OutPath: "query"}
 type Config struct {
 	// OutPath overrides the CLI output path for files in the same package
 	// where this Config literal is found.
 	OutPath string
 
+	// OutPackage overrides the package name of the generated files, which
+	// defaults to the name of the source package, e.g. "query".
+	// Same as the `--package` CLI flag, the flag takes precedence.
+	OutPackage string
+
+	// Header replaces the "Code generated ... DO NOT EDIT." comment at the top of
+	// generated files, e.g. to add a SPDX license line. Keep a standard
+	// "// Code generated ... DO NOT EDIT." line in it so generated files are
+	// recognized by Go tooling and skipped on the next run.
+	Header string
+
+	// HeaderFile is the path of a file whose contents are used as Header.
+	HeaderFile string
+
+	// TemplateDir is a directory of *.tmpl files overriding the templates of the
+	// generated files, e.g. pkg.tmpl, see internal/gen/templates for the defaults.
+	// Other *.tmpl files of the directory can be used as partials with
+	// {{template "name.tmpl" .}}. Same as the `--template-dir` CLI flag, the flag takes precedence.
+	TemplateDir string
+
+	// TemplateFuncs declares functions of the templates, keyed by name, next to the
+	// builtin lower, upper, snake, camel, lowerCamel, plural and singular functions.
+	// Values are either a template snippet executed with the argument as dot, or a
+	// map[string]string lookup table returning unknown values as is, e.g.
+	//   TemplateFuncs: map[string]any{
+	//       "fkName": `fk_{{snake .}}`,
+	//       "pgType": map[string]string{"string": "text", "int64": "bigint"},
+	//   }
+	TemplateFuncs map[string]any
+
+	// SQLFragments declares named SQL template fragments, keyed by name, included in
+	// the SQL templates of query methods with {{include "name"}}, e.g.
+	//   SQLFragments: map[string]string{"activeFilter": `role = 'active' AND deleted_at IS NULL`}
+	// Fragments can also be declared in the doc comment of a query interface with
+	// {{define "name"}} ... {{end}} blocks, which take precedence over configs.
+	SQLFragments map[string]string
+
+	// TemplateVars declares SQL snippets expanded in the SQL templates of query methods and
+	// fragments at generation time, keyed by name, e.g.
+	//   TemplateVars: map[string]string{"softDelete": "deleted_at IS NULL"}
+	// expands `WHERE ${softDelete}` to `WHERE deleted_at IS NULL`; write \${name} to keep it as is.
+	TemplateVars map[string]string
+
+	// SQLFuncs registers Go functions callable in the {{if}} and {{for}} conditions of SQL
+	// templates, keyed by the name used in templates, e.g.
+	//   SQLFuncs: map[string]any{"notEmpty": helpers.NotEmpty, "inRange": helpers.InRange}
+	// allows {{if notEmpty(user.Name)}}, the generated code calls helpers.NotEmpty and
+	// imports its package.
+	SQLFuncs map[string]any
+
+	// PlaceholderPrefix changes the prefix of the @param and @@column placeholders of SQL
+	// templates, e.g. "$" for $name and $$column, so SQL Server @variables are kept as is.
+	// The prefix escaped with a backslash is written literally, e.g. \@ or \$. Prefixes
+	// doubling into SQL operators, like ":" and Postgres ::text casts, are rejected.
+	PlaceholderPrefix string
+
+	// StrictPlaceholders rejects ambiguous placeholders of SQL templates instead of keeping
+	// them as is: a prefix without a name, or placeholders inside quoted string literals.
+	StrictPlaceholders bool
+
+	// Dialect is the dialect SQL templates are written for, one of mysql, postgres, sqlite,
+	// sqlserver, oracle, clickhouse or gaussdb. Hand-written ordinal placeholders in its
+	// convention, e.g. $1 for postgres, :1 for oracle, @p1 for sqlserver, or ? for mysql, bind
+	// the parameters of the method at their positions, and are rendered with the bind vars of
+	// the dialect the query runs on like @name placeholders. They're kept as is by default.
+	Dialect string
+
+	// QueryModels binds query interfaces to the model their SQL templates query, keyed by
+	// interface name, e.g. map[string]any{"UserQuery": models.User{}}. The columns of the
+	// conditions, SET lists and ORDER BY or GROUP BY lists of the templates must exist on
+	// the model, so typos fail generation. Same as a `model: models.User` line in the
+	// doc comment of the interface, which takes precedence.
+	QueryModels map[string]any
+
+	// SkipMultiStatementTransaction runs the ;-separated statements of raw SQL methods
+	// returning only an error one after the other, instead of in a transaction rolled
+	// back when one of them fails.
+	SkipMultiStatementTransaction bool
+
+	// StripSQLComments drops the -- and /* */ comments of SQL templates from the generated SQL,
+	// which keeps them by default, -- comments being rewritten as /* */ comments. Optimizer hints
+	// like /*+ INDEX(users idx_name) */ and MySQL /*! ... */ comments are always kept.
+	StripSQLComments bool
+
+	// QueryHook is an exported variable implementing field.QueryHook, e.g. tracing.Hook, called
+	// around the query of each generated method running one, with the method name, its SQL and
+	// args, the duration and the error, e.g. to record metrics or tracing spans. Iterators aren't
+	// traced, and the SQL of update(...), delete(...) and count(...) methods is their template,
+	// the statement itself being built by gorm.
+	QueryHook any
+
+	// ContextParam controls the `ctx context.Context` parameter of generated query methods:
+	//   - "inject" (default): methods without a ctx parameter get one prepended
+	//   - "require": methods must declare a ctx parameter, generation fails otherwise
+	//   - "omit": signatures are generated as declared, methods without a ctx
+	//     parameter run with context.Background()
+	// Same as the `--context` CLI flag, the flag takes precedence.
+	ContextParam string
+
 	// FieldTypeMap maps a Go type instance (key) to a wrapper type instance (value).
 	// Example: map[any]any{ sql.NullTime{}: field.Time{} }
 	// The generator reads the AST to extract the type expressions from both
@@ -38,6 +149,69 @@ type Config struct {
 
 	FileLevel bool
 
+	// Scope controls how far the config reaches, ignored with FileLevel:
+	//   - "subtree" (default): files of this package and of packages in subdirectories
+	//   - "package": files of this package only
+	Scope string
+
+	// NoInherit ignores the configs of parent directories for the files this
+	// config applies to, so a package can opt out of inherited filters and mappings.
+	NoInherit bool
+
+	// IncludeTests processes the _test.go files this config applies to, which
+	// are skipped by default, e.g. to generate helpers of test-only models.
+	// Same as the `--tests` CLI flag.
+	IncludeTests bool
+
+	// CRUD generates a repository of each model with a single primary key, e.g. a
+	// UserRepository interface with GetByID, List, Create, Update and Delete methods
+	// and its NewUserRepository(db) constructor. Views and generic models are skipped.
+	// Same as the `--crud` CLI flag.
+	CRUD bool
+
+	// Factories generates a fixture factory of each model, e.g.
+	//   func NewUserFixture(overrides ...func(*models.User)) models.User
+	// returning a model with unique strings, the first enum constants and the current time
+	// as defaults, for seeding tests. Views and generic models are skipped.
+	// Same as the `--factories` CLI flag.
+	Factories bool
+
+	// MergeOutput generates all interfaces and structs of a package into a single
+	// `<package>_gen.go` file instead of mirroring the source files layout.
+	// Same as the `--single-file` CLI flag.
+	MergeOutput bool
+
+	// Scopes declares named reusable conditions of the generated models, keyed by
+	// "<Model>.<Scope>" with field helper expressions of the model as values, e.g.
+	//   Scopes: map[string]any{"User.ActiveAdults": []string{`Age.Gte(18)`, `Role.Eq("active")`}}
+	// generates `UserScopes.ActiveAdults()` for the typed Scopes(...) API.
+	Scopes map[string]any
+
+	// Views declares structs mapped to database views, with the same selector rules as
+	// IncludeStructs. Same as a `//gorm:view` line in the struct's doc comment, which may
+	// be followed by the SELECT statement of the view to generate its CREATE VIEW DDL.
+	// Views get read-only field helpers and a ViewName method.
+	Views []any
+
+	// VersionField is the name of the integer fields used as optimistic lock versions, e.g.
+	// "Version", fields of other types with this name are skipped. Fields tagged
+	// `gen:"version"` are version fields regardless and must be integers. The helpers
+	// of version fields get Lock and Bump methods for typed.UpdateVersioned.
+	VersionField string
+
+	// DurationUnit is how time.Duration fields are stored, so their field.Duration helpers
+	// convert values to the unit of the column:
+	//   - "ns" (default): integer nanoseconds, as gorm stores time.Duration values
+	//   - "us", "ms" or "s": integer microseconds, milliseconds or seconds
+	//   - "interval": Postgres INTERVAL columns
+	// Fields tagged `gorm:"type:interval"` are stored as intervals regardless.
+	DurationUnit string
+
+	// RequireMarker only generates the interfaces and structs annotated with a
+	// `//gorm:generate` line in their doc comment, other types are skipped.
+	// Same as the `--opt-in` CLI flag.
+	RequireMarker bool
+
 	// IncludeInterfaces is an optional whitelist for interface types to process.
 	// If non-empty, only interfaces that match one of the provided selectors will be generated.
 	// Supported selectors:
@@ -60,4 +234,14 @@ type Config struct {
 	// ExcludeStructs is an optional blacklist for struct types to skip.
 	// Applied after IncludeStructs filtering. Same selector rules as IncludeStructs.
 	ExcludeStructs []any
+
+	// IncludeFields is an optional whitelist for fields of the generated structs, as
+	// "<Struct>.<Field>" selectors with the same pattern rules as IncludeStructs,
+	// e.g. "User.Name", "User.*". Only structs matched by the struct part of a
+	// selector are narrowed, other structs keep all their fields.
+	IncludeFields []any
+
+	// ExcludeFields is an optional blacklist for fields to leave out of the generated
+	// helpers, e.g. "User.Profile", "*.DeletedAt". Applied after IncludeFields.
+	ExcludeFields []any
 }
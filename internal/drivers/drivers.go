@@ -0,0 +1,16 @@
+// Package drivers holds the database drivers the CLI opens the databases of --verify-db and the schema
+// command with. The generator doesn't import them, main registers them, so the packages importing the
+// generator, e.g. the gen package exposing PreviewSQL to tests, don't link the drivers.
+package drivers
+
+import (
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Dialectors open the database of a DSN by driver name, sqlite is registered by cgo builds only, see sqlite.go
+var Dialectors = map[string]func(dsn string) gorm.Dialector{
+	"mysql":    mysql.Open,
+	"postgres": postgres.Open,
+}
@@ -0,0 +1,11 @@
+//go:build cgo
+
+package drivers
+
+import "gorm.io/driver/sqlite"
+
+// The SQLite driver wraps the C library, builds without cgo leave it out so the rest of the CLI
+// still builds as a static binary
+func init() {
+	Dialectors["sqlite"] = sqlite.Open
+}
@@ -0,0 +1,159 @@
+package gen
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+	"gorm.io/gorm/schema"
+)
+
+// associationMetadata mirrors field.AssociationMetadata
+type associationMetadata struct {
+	Kind             string
+	ForeignKey       string
+	References       string
+	PolymorphicType  string
+	PolymorphicID    string
+	PolymorphicValue string
+	JoinTable        string
+	JoinForeignKey   string
+	JoinReferences   string
+}
+
+// literal renders the metadata as a field.AssociationMetadata composite literal
+func (m associationMetadata) literal() string {
+	kinds := map[string]string{"has_one": "HasOne", "has_many": "HasMany", "belongs_to": "BelongsTo", "many_to_many": "Many2Many"}
+	values := []string{"Kind: field." + kinds[m.Kind]}
+
+	v := reflect.ValueOf(m)
+	for i := 1; i < v.NumField(); i++ {
+		if value := v.Field(i).String(); value != "" {
+			values = append(values, fmt.Sprintf("%s: %q", v.Type().Field(i).Name, value))
+		}
+	}
+	return "field.AssociationMetadata{" + strings.Join(values, ", ") + "}"
+}
+
+// AssociationMetadata returns the field.AssociationMetadata literal of a relation field for template generation
+func (f Field) AssociationMetadata() string {
+	return f.association().literal()
+}
+
+// association returns the metadata of a relation field, derived from the gorm tags of the field and the
+// related structs, following the conventions GORM uses to guess relations; structs declared outside the
+// package only get tag values.
+func (f Field) association() associationMetadata {
+	var (
+		ns       = schema.NamingStrategy{IdentifierMaxLength: 64}
+		tags     = schema.ParseTagSetting(reflect.StructTag(f.Tag).Get("gorm"), ";")
+		isSlice  = strings.HasPrefix(strings.TrimPrefix(f.GoType, "*"), "[]")
+		elemType = strings.TrimLeft(f.GoType, "[]*")
+		elemName = elemType
+		owner    = f.file.findStruct(f.owner)
+		related  *Struct
+		m        associationMetadata
+	)
+
+	// Only structs of the same package can be inspected
+	if idx := strings.LastIndex(elemType, "."); idx >= 0 {
+		elemName = elemType[idx+1:]
+		if pkg := elemType[:idx]; pkg == f.file.PackagePath || pkg == f.file.Package {
+			related = f.file.findStruct(elemName)
+		}
+	} else {
+		related = f.file.findStruct(elemName)
+	}
+
+	// column returns the column of the field name in s, falling back to the naming strategy
+	column := func(s *Struct, name string) string {
+		if s != nil {
+			for _, field := range s.Fields {
+				if field.Name == name {
+					return field.DBName
+				}
+			}
+		}
+		return ns.ColumnName("", name)
+	}
+
+	ownerPK, relatedPK := owner.primaryField(), related.primaryField()
+	switch {
+	case tags["MANY2MANY"] != "":
+		m.Kind = "many_to_many"
+		m.JoinTable = ns.JoinTableName(tags["MANY2MANY"])
+		m.ForeignKey = column(owner, cmp.Or(tags["FOREIGNKEY"], ownerPK.Name))
+		m.References = column(related, cmp.Or(tags["REFERENCES"], relatedPK.Name))
+
+		relatedName := elemName
+		if elemName == f.owner {
+			relatedName = inflection.Singular(f.Name)
+		}
+		m.JoinForeignKey = ns.ColumnName(m.JoinTable, cmp.Or(tags["JOINFOREIGNKEY"], f.owner+ownerPK.Name))
+		m.JoinReferences = ns.ColumnName(m.JoinTable, cmp.Or(tags["JOINREFERENCES"], relatedName+relatedPK.Name))
+	case tags["POLYMORPHIC"] != "" || tags["POLYMORPHICTYPE"] != "":
+		m.Kind = "has_one"
+		if isSlice {
+			m.Kind = "has_many"
+		}
+
+		polymorphic := tags["POLYMORPHIC"]
+		m.PolymorphicType = column(related, cmp.Or(tags["POLYMORPHICTYPE"], polymorphic+"Type"))
+		m.PolymorphicID = column(related, cmp.Or(tags["POLYMORPHICID"], polymorphic+"ID"))
+		m.PolymorphicValue = tags["POLYMORPHICVALUE"]
+		if m.PolymorphicValue == "" && owner != nil {
+			m.PolymorphicValue = owner.TableName()
+		}
+		m.ForeignKey = m.PolymorphicID
+		m.References = column(owner, cmp.Or(tags["REFERENCES"], ownerPK.Name))
+	default:
+		// GORM guesses belongs to first for struct fields: the foreign key is declared on the owner
+		belongsToKey := cmp.Or(tags["FOREIGNKEY"], f.Name+relatedPK.Name)
+		if !isSlice && owner != nil && owner.HasField(belongsToKey) {
+			m.Kind = "belongs_to"
+			m.ForeignKey = column(owner, belongsToKey)
+			m.References = column(related, cmp.Or(tags["REFERENCES"], relatedPK.Name))
+		} else {
+			m.Kind = "has_one"
+			if isSlice {
+				m.Kind = "has_many"
+			}
+			m.ForeignKey = column(related, cmp.Or(tags["FOREIGNKEY"], f.owner+ownerPK.Name))
+			m.References = column(owner, cmp.Or(tags["REFERENCES"], ownerPK.Name))
+		}
+	}
+
+	return m
+}
+
+// primaryField returns the primary key field of the struct, the first field tagged with primaryKey or named ID
+func (s *Struct) primaryField() Field {
+	if s != nil {
+		if fields := s.PrimaryFields(); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return Field{Name: "ID", DBName: "id"}
+}
+
+// findStruct returns the struct declared in the package of the file by name, or nil
+func (p *File) findStruct(name string) *Struct {
+	if p == nil || p.Generator == nil {
+		return nil
+	}
+
+	dir := filepath.Dir(p.inputPath)
+	for pth, f := range p.Generator.Files {
+		if filepath.Dir(pth) == dir {
+			for i := range f.allStructs {
+				if f.allStructs[i].Name == name {
+					return &f.allStructs[i]
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+package gen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const cacheFileName = "cache.json"
+
+// depsLoadMode loads the import graph of the inputs without parsing or type checking them
+const depsLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedModule
+
+// genCache records a content hash for every generated file, so repeat runs can skip
+// files whose inputs and applicable configs haven't changed. The hash of all inputs of
+// the last run is recorded too, so an unchanged run is skipped before loading them.
+type genCache struct {
+	dir     string
+	Inputs  *cacheInputs      `json:"inputs,omitempty"`
+	Entries map[string]string `json:"entries"`
+}
+
+// cacheInputs is the hash of everything a run depends on, see Generator.inputsKey
+type cacheInputs struct {
+	Input string   `json:"input"`
+	Hash  string   `json:"hash"`
+	Files []string `json:"files,omitempty"` // SQL and template files read by the run
+}
+
+// generatorVersion is the hash of the embedded templates and the build of the generator, so upgrading
+// the CLI regenerates the files of its previous version
+var generatorVersion = sync.OnceValue(func() string {
+	h := sha256.New()
+	entries, _ := fs.ReadDir(defaultTemplates, "templates")
+	for _, entry := range entries {
+		content, _ := fs.ReadFile(defaultTemplates, "templates/"+entry.Name())
+		fmt.Fprintf(h, "%s\x00%d\x00", entry.Name(), len(content))
+		h.Write(content)
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		io.WriteString(h, info.String())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+})
+
+// loadGenCache loads the cache stored in dir, a missing or corrupted cache starts empty
+func loadGenCache(dir string) *genCache {
+	c := &genCache{dir: dir, Entries: map[string]string{}}
+	if content, err := os.ReadFile(filepath.Join(dir, cacheFileName)); err == nil {
+		if err := json.Unmarshal(content, c); err != nil || c.Entries == nil {
+			c.Inputs, c.Entries = nil, map[string]string{}
+		}
+	}
+	return c
+}
+
+// fresh reports whether outPath was generated from inputs with the same hash and still exists
+func (c *genCache) fresh(outPath, hash string) bool {
+	if c.Entries[outPath] != hash {
+		return false
+	}
+	_, err := os.Stat(outPath)
+	return err == nil
+}
+
+// upToDate reports whether the last run had the same inputs and all its generated files still exist
+func (c *genCache) upToDate(inputs *cacheInputs) bool {
+	if c.Inputs == nil || c.Inputs.Input != inputs.Input || c.Inputs.Hash != inputs.Hash || len(c.Entries) == 0 {
+		return false
+	}
+	for outPath := range c.Entries {
+		if _, err := os.Stat(outPath); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// save writes the cache back to its directory
+func (c *genCache) save() error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory %v, got error %v", c.dir, err)
+	}
+
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.dir, cacheFileName), content, 0o640)
+}
+
+// upToDate reports whether the inputs are unchanged since the last run with the cache, so loading and
+// parsing them can be skipped. The hash of the inputs is kept, Gen records it for the next run.
+func (g *Generator) upToDate(input string) (bool, error) {
+	cache := loadGenCache(g.cacheDir)
+
+	var files []string
+	if cache.Inputs != nil {
+		files = cache.Inputs.Files
+	}
+	inputs, err := g.inputsKey(input, files)
+	if err != nil {
+		return false, err
+	}
+	g.inputs = inputs
+	return cache.upToDate(inputs), nil
+}
+
+// inputsKey returns the hash of everything a run on input depends on: the templates, generator options,
+// the Go files under the input, the sources of the packages they import and files, the SQL and template
+// files read by the last run
+func (g *Generator) inputsKey(input string, files []string) (*cacheInputs, error) {
+	absInput, err := filepath.Abs(input)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(absInput)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00typed=%v\x00crud=%v\x00factories=%v\x00tests=%v\x00package=%s\x00context=%s\x00comments=%v\x00optin=%v\x00single=%v\x00includetests=%v\x00symlinks=%v\x00templates=%s\x00docs=%s\x00diagram=%s\x00schema=%s\x00validate=%s\x00prune=%v\x00out=%s\x00",
+		generatorVersion(), g.Typed, g.crud, g.factories, g.withTests, g.outPackage, g.contextParam, g.stripComments, g.optIn, g.singleFile, g.includeTests, g.followSymlinks, g.templateDir, g.docs, g.diagram, g.apiSchema, g.validateSQL, g.prune, g.outPath)
+
+	sources := slices.Clone(files)
+	if g.templateDir != "" {
+		templates, err := templateFiles(g.templateDir)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, templates...)
+	}
+	if info.IsDir() {
+		err = g.walk(absInput, func(pth string) {
+			if !shouldSkipFile(pth) {
+				sources = append(sources, pth)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		sources = append(sources, absInput, filepath.Join(filepath.Dir(absInput), configFileName))
+	}
+	if err := hashFiles(h, sources); err != nil {
+		return nil, err
+	}
+
+	deps := g.dependencies(absInput)
+	if err := deps.hash(h, deps.roots...); err != nil {
+		return nil, err
+	}
+
+	return &cacheInputs{Input: absInput, Hash: hex.EncodeToString(h.Sum(nil)), Files: files}, nil
+}
+
+// recordInputs records the hash of the inputs of the run in cache, the hash computed before processing
+// them is kept unless the run read other SQL or template files than the last one
+func (g *Generator) recordInputs(cache *genCache) error {
+	if g.input == "" {
+		cache.Inputs = nil
+		return nil
+	}
+
+	var files []string
+	for _, file := range g.Files {
+		files = append(files, file.sqlFiles()...)
+		if dir := file.templateDir(); dir != "" {
+			templates, err := templateFiles(dir)
+			if err != nil {
+				return err
+			}
+			files = append(files, templates...)
+		}
+	}
+	sort.Strings(files)
+	files = slices.Compact(files)
+
+	inputs := g.inputs
+	if inputs == nil || inputs.Input != g.input || !slices.Equal(inputs.Files, files) {
+		var err error
+		if inputs, err = g.inputsKey(g.input, files); err != nil {
+			return err
+		}
+	}
+	cache.Inputs = inputs
+	return nil
+}
+
+// dependencies returns the import graph of the packages under input, it's loaded once per run
+func (g *Generator) dependencies(absInput string) *depGraph {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.deps == nil {
+		dir := absInput
+		if info, err := os.Stat(absInput); err == nil && !info.IsDir() {
+			dir = filepath.Dir(absInput)
+		}
+		pattern := dir + "/..."
+		if dir != absInput {
+			pattern = dir
+		}
+		g.deps = loadDepGraph(findGoModDir(filepath.Join(dir, "_")), pattern)
+	}
+	return g.deps
+}
+
+// depGraph is the import graph of the input packages, used to invalidate the cache when a package they
+// depend on changes, e.g. the embedded struct of a shared package
+type depGraph struct {
+	roots  []string                     // import paths of the input packages
+	pkgs   map[string]*packages.Package // packages by import path
+	dirs   map[string]string            // import paths by package directory
+	hashes map[string]string            // hashes of the package sources by import path
+}
+
+// loadDepGraph loads the import graph of the packages matching patterns, failures are not fatal,
+// like with packageLoader the graph is empty then
+func loadDepGraph(dir string, patterns ...string) *depGraph {
+	d := &depGraph{pkgs: map[string]*packages.Package{}, dirs: map[string]string{}, hashes: map[string]string{}}
+	roots, err := packages.Load(&packages.Config{Mode: depsLoadMode, Dir: dir}, patterns...)
+	if err != nil {
+		return d
+	}
+
+	packages.Visit(roots, nil, func(pkg *packages.Package) {
+		d.pkgs[pkg.PkgPath] = pkg
+		for _, f := range pkg.GoFiles {
+			d.dirs[filepath.Dir(f)] = pkg.PkgPath
+		}
+	})
+	for _, pkg := range roots {
+		d.roots = append(d.roots, pkg.PkgPath)
+	}
+	sort.Strings(d.roots)
+	return d
+}
+
+// hash writes the hashes of the packages imported by roots, directly or indirectly, to h. Standard
+// library packages are skipped, module dependencies are identified by their version, packages of the
+// main module or replaced modules by the contents of their files.
+func (d *depGraph) hash(h io.Writer, roots ...string) error {
+	seen := map[string]bool{}
+	var visit func(pkgPath string)
+	visit = func(pkgPath string) {
+		if seen[pkgPath] || d.pkgs[pkgPath] == nil {
+			return
+		}
+		seen[pkgPath] = true
+		for imp := range d.pkgs[pkgPath].Imports {
+			visit(imp)
+		}
+	}
+	for _, root := range roots {
+		if pkg := d.pkgs[root]; pkg != nil {
+			for imp := range pkg.Imports {
+				visit(imp)
+			}
+		}
+	}
+
+	pkgPaths := make([]string, 0, len(seen))
+	for pkgPath := range seen {
+		if d.pkgs[pkgPath].Module != nil {
+			pkgPaths = append(pkgPaths, pkgPath)
+		}
+	}
+	sort.Strings(pkgPaths)
+
+	for _, pkgPath := range pkgPaths {
+		hash, ok := d.hashes[pkgPath]
+		if !ok {
+			pkg := d.pkgs[pkgPath]
+			if mod := pkg.Module; mod != nil && !mod.Main && mod.Replace == nil && mod.Version != "" {
+				hash = mod.Path + "@" + mod.Version
+			} else {
+				// generated files of the package may be rewritten by the run itself
+				files := slices.DeleteFunc(append(slices.Clone(pkg.GoFiles), pkg.IgnoredFiles...), func(pth string) bool {
+					return shouldSkipFile(pth)
+				})
+				ph := sha256.New()
+				if err := hashFiles(ph, files); err != nil {
+					return err
+				}
+				hash = hex.EncodeToString(ph.Sum(nil))
+			}
+			d.hashes[pkgPath] = hash
+		}
+		fmt.Fprintf(h, "%s\x00%s\x00", pkgPath, hash)
+	}
+	return nil
+}
+
+// hashFiles writes the names and contents of the sorted files to h, missing files are hashed as empty
+func hashFiles(h io.Writer, files []string) error {
+	files = slices.Clone(files)
+	sort.Strings(files)
+	for _, pth := range slices.Compact(files) {
+		content, err := os.ReadFile(pth)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", pth, len(content))
+		h.Write(content)
+	}
+	return nil
+}
+
+// cacheKey returns the content hash of everything the generated output of the file depends on:
+// the templates, generator options, the sources of the file's package and the packages it imports,
+// the applicable config files and the SQL files of query methods
+func (p *File) cacheKey(configFiles []string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00typed=%v\x00crud=%v\x00factories=%v\x00tests=%v\x00package=%s\x00context=%s\x00comments=%v\x00optin=%v\x00single=%v\x00%s\x00", generatorVersion(), p.Generator.Typed, p.Generator.crud, p.Generator.factories, p.Generator.withTests, p.Generator.outPackage, p.Generator.contextParam, p.Generator.stripComments, p.Generator.optIn, p.Generator.singleFile, p.Header)
+
+	sources := append(append([]string{}, configFiles...), p.sqlFiles()...)
+	if dir := p.templateDir(); dir != "" {
+		files, err := templateFiles(dir)
+		if err != nil {
+			return "", err
+		}
+		sources = append(sources, files...)
+	}
+	dir := filepath.Dir(p.inputPath)
+	for pth := range p.Generator.Files {
+		if filepath.Dir(pth) == dir {
+			sources = append(sources, pth)
+		}
+	}
+	if err := hashFiles(h, sources); err != nil {
+		return "", err
+	}
+
+	if p.Generator.input != "" {
+		deps := p.Generator.dependencies(p.Generator.input)
+		if pkgPath, ok := deps.dirs[dir]; ok {
+			if err := deps.hash(h, pkgPath); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
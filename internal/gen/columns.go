@@ -0,0 +1,310 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"strings"
+	"unicode"
+)
+
+// columnRef is a column referenced by a SQL template, see sqlColumnRefs
+type columnRef struct {
+	Name      string
+	Qualifier string // table or alias qualifying the column, e.g. u of u.name
+	Offset    int    // byte offset of the reference in the template
+}
+
+// Kinds of the tokens of a SQL template, see tokenizeSQL
+const (
+	sqlIdent = iota
+	sqlPlaceholder
+	sqlLiteral
+	sqlSymbol
+)
+
+// sqlToken is a token of a SQL template
+type sqlToken struct {
+	text   string
+	kind   int
+	offset int
+}
+
+// sqlKeywords are the SQL keywords that aren't column names
+var sqlKeywords = map[string]bool{}
+
+func init() {
+	for _, kw := range strings.Fields(`ALL AND ANY AS ASC BETWEEN BY CASE CROSS CURRENT_DATE CURRENT_TIME
+		CURRENT_TIMESTAMP DEFAULT DELETE DESC DISTINCT ELSE END ESCAPE EXCEPT EXISTS FALSE FETCH FIRST FOR
+		FROM FULL GROUP HAVING ILIKE IN INNER INSERT INTERSECT INTERVAL INTO IS JOIN LAST LEFT LIKE LIMIT
+		NOT NULL NULLS OFFSET ON OR ORDER OUTER RETURNING RIGHT ROWS SELECT SET SOME THEN TRUE UNION
+		UNKNOWN UPDATE USING VALUES WHEN WHERE WITH`) {
+		sqlKeywords[kw] = true
+	}
+}
+
+// tokenizeSQL splits the SQL template into tokens, template directives and comments are skipped but the
+// column of {{in}} directives, quoted strings are literals and escaped characters are dropped
+func tokenizeSQL(tmpl, prefix string) (tokens []sqlToken) {
+	isIdent := func(c byte) bool {
+		return c == '_' || c == '$' || c < 0x80 && unicode.IsLetter(rune(c)) || c >= '0' && c <= '9'
+	}
+
+	for i := 0; i < len(tmpl); {
+		c := tmpl[i]
+		switch {
+		case strings.HasPrefix(tmpl[i:], "{{"):
+			end := strings.Index(tmpl[i:], "}}")
+			if end < 0 {
+				return tokens
+			}
+			// the column of {{in "status" statuses}} is compared like the column of status IN (...)
+			if m := inRegexp.FindStringSubmatchIndex(strings.TrimSpace(tmpl[i+2 : i+end])); m != nil {
+				offset := i + 2 + strings.Index(tmpl[i+2:i+end], `"`) + 1
+				tokens = append(tokens,
+					sqlToken{text: tmpl[offset : offset+m[3]-m[2]], kind: sqlIdent, offset: offset},
+					sqlToken{text: "IN", kind: sqlIdent, offset: offset})
+			}
+			i += end + 2
+		case strings.HasPrefix(tmpl[i:], "--"):
+			end := strings.IndexByte(tmpl[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+		case strings.HasPrefix(tmpl[i:], "/*"):
+			end := strings.Index(tmpl[i:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 2
+		case c == '\\':
+			i += 2
+		case c == '\'' || c == '"' || c == '`':
+			end := strings.IndexByte(tmpl[i+1:], c)
+			if end < 0 {
+				return tokens
+			}
+			tokens = append(tokens, sqlToken{text: tmpl[i : i+end+2], kind: sqlLiteral, offset: i})
+			i += end + 2
+		case strings.HasPrefix(tmpl[i:], prefix+"{") && inlineExprEnd(tmpl, i+len(prefix)) > 0:
+			end := inlineExprEnd(tmpl, i+len(prefix))
+			tokens = append(tokens, sqlToken{text: tmpl[i:end], kind: sqlPlaceholder, offset: i})
+			i = end
+		case strings.HasPrefix(tmpl[i:], prefix):
+			j := i + len(prefix)
+			if strings.HasPrefix(tmpl[j:], prefix) {
+				j += len(prefix)
+			}
+			for j < len(tmpl) && (isIdent(tmpl[j]) || tmpl[j] == '.') {
+				j++
+			}
+			if m := tableAliasRegexp.FindString(tmpl[j:]); m != "" && tmpl[i:j] == prefix+prefix+"table" {
+				j += len(m)
+			} else if m := identWhitelistRegexp.FindString(tmpl[j:]); m != "" && strings.HasPrefix(tmpl[i:], prefix+prefix) {
+				j += len(m)
+			}
+			tokens = append(tokens, sqlToken{text: tmpl[i:j], kind: sqlPlaceholder, offset: i})
+			i = j
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(tmpl) && (isIdent(tmpl[j]) || tmpl[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, sqlToken{text: tmpl[i:j], kind: sqlLiteral, offset: i})
+			i = j
+		case isIdent(c):
+			j := i
+			for j < len(tmpl) && (isIdent(tmpl[j]) || tmpl[j] == '.' && j+1 < len(tmpl) && isIdent(tmpl[j+1])) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{text: tmpl[i:j], kind: sqlIdent, offset: i})
+			i = j
+		case unicode.IsSpace(rune(c)):
+			i++
+		default:
+			n := 1
+			for _, op := range []string{"<>", "!=", "<=", ">=", "||", "::"} {
+				if strings.HasPrefix(tmpl[i:], op) {
+					n = len(op)
+				}
+			}
+			tokens = append(tokens, sqlToken{text: tmpl[i : i+n], kind: sqlSymbol, offset: i})
+			i += n
+		}
+	}
+	return tokens
+}
+
+// sqlColumnRefs returns the columns of the table referenced by the SQL template: the columns compared
+// in conditions or assigned by SET lists, and the columns of ORDER BY and GROUP BY lists. Unqualified
+// columns are only returned when the template queries the table alone, e.g. FROM @@table, columns
+// qualified with another table and aliases of the select list are skipped.
+func sqlColumnRefs(tmpl, prefix, table string) (refs []columnRef) {
+	tokens := tokenizeSQL(tmpl, prefix)
+	upper := func(i int) string {
+		if i < 0 || i >= len(tokens) || tokens[i].kind != sqlIdent {
+			return ""
+		}
+		return strings.ToUpper(tokens[i].text)
+	}
+	text := func(i int) string {
+		if i < 0 || i >= len(tokens) {
+			return ""
+		}
+		return tokens[i].text
+	}
+
+	qualifiers := []string{table}
+	var aliases []string
+	var foreign bool // the template queries other tables, unqualified columns are ambiguous
+	for i := range tokens {
+		switch upper(i) {
+		case "FROM", "JOIN", "UPDATE", "INTO":
+			next := text(i + 1)
+			if m := tableAliasRegexp.FindStringSubmatch(strings.TrimPrefix(next, prefix+prefix+"table")); m != nil {
+				qualifiers = append(qualifiers, m[1])
+				continue
+			}
+			if next != prefix+prefix+"table" && !strings.EqualFold(next, table) {
+				foreign = true
+				continue
+			}
+			alias := i + 2
+			if upper(alias) == "AS" {
+				alias++
+			}
+			if upper(alias) != "" && !sqlKeywords[upper(alias)] {
+				qualifiers = append(qualifiers, tokens[alias].text)
+			}
+		case "AS":
+			if upper(i+1) != "" {
+				aliases = append(aliases, tokens[i+1].text)
+			}
+		}
+	}
+
+	inList := false // in an ORDER BY or GROUP BY list
+	for i, t := range tokens {
+		kw := upper(i)
+		if kw == "BY" && (upper(i-1) == "ORDER" || upper(i-1) == "GROUP") {
+			inList = true
+			continue
+		}
+		if t.kind != sqlIdent || sqlKeywords[kw] || text(i+1) == "(" || text(i-1) == "." {
+			if inList && (sqlKeywords[kw] && kw != "ASC" && kw != "DESC" && kw != "NULLS" && kw != "FIRST" && kw != "LAST" || text(i) == ")") {
+				inList = false
+			}
+			continue
+		}
+
+		var ref bool
+		switch next := text(i + 1); {
+		case inList:
+			ref = (text(i-1) == "," || upper(i-1) == "BY") && (next == "" || next == "," || next == ")" || sqlKeywords[upper(i+1)])
+		case next == "=" || next == "<>" || next == "!=" || next == "<" || next == ">" || next == "<=" || next == ">=":
+			ref = true
+		default:
+			switch upper(i + 1) {
+			case "IN", "LIKE", "ILIKE", "IS", "BETWEEN":
+				ref = true
+			case "NOT":
+				ref = upper(i+2) == "IN" || upper(i+2) == "LIKE" || upper(i+2) == "ILIKE" || upper(i+2) == "BETWEEN"
+			}
+		}
+		if !ref || upper(i-1) == "AS" {
+			continue
+		}
+
+		qualifier, name, qualified := strings.Cut(t.text, ".")
+		if !qualified {
+			qualifier, name = "", t.text
+		}
+		if qualified && !containsFold(qualifiers, qualifier) || !qualified && (foreign || containsFold(aliases, name)) {
+			continue
+		}
+		refs = append(refs, columnRef{Name: name, Qualifier: qualifier, Offset: t.offset})
+	}
+	return refs
+}
+
+// containsFold reports whether the names contain name, ignoring case
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryModel returns the full type of the model the interface is bound to, from a `model: models.User`
+// line of its doc comment or genconfig.Config.QueryModels, the closest config wins
+func (p *File) queryModel(iface Interface) string {
+	if iface.model != "" {
+		return iface.model
+	}
+	for _, cfg := range p.applicableConfigs {
+		if model, ok := cfg.QueryModels[iface.Name].(string); ok {
+			return model
+		}
+	}
+	return ""
+}
+
+// parseModelAnnotation returns the full type of the model of a `model: models.User` line of the doc
+// comment of a query interface, types without package are declared in the package of the file
+func (p *File) parseModelAnnotation(doc string) string {
+	for _, line := range strings.Split(doc, "\n") {
+		if model, ok := strings.CutPrefix(strings.TrimSpace(line), "model:"); ok {
+			model = strings.TrimSpace(model)
+			if pkg, name, ok := strings.Cut(model, "."); ok {
+				return p.getFullImportPath(pkg) + "." + name
+			}
+			return p.PackagePath + "." + model
+		}
+	}
+	return ""
+}
+
+// validateTemplateColumns checks the columns referenced by the SQL templates of the interface bound to
+// a model exist on the model, see sqlColumnRefs
+func (p *File) validateTemplateColumns(iface Interface) error {
+	model := p.queryModel(iface)
+	if model == "" {
+		return nil
+	}
+
+	dot := strings.LastIndex(model, ".")
+	spec, src := p.loadTypeSpec(model[:dot], model[dot+1:])
+	if spec == nil {
+		return fmt.Errorf("model %s of interface %s not found", model, iface.Name)
+	}
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		return fmt.Errorf("model %s of interface %s must be a struct", model, iface.Name)
+	}
+	s := src.processStructType(spec, st, src.Package)
+	var columns []string
+	for _, f := range s.ColumnFields() {
+		columns = append(columns, f.DBName)
+	}
+
+	for _, m := range iface.Methods {
+		for _, tmpl := range m.SQL.templates() {
+			for _, ref := range sqlColumnRefs(tmpl, m.placeholderPrefix, s.TableName()) {
+				if containsFold(columns, ref.Name) {
+					continue
+				}
+				msg := fmt.Sprintf("unknown column %s of model %s in %s.%s", ref.Name, s.Name, iface.Name, m.Name)
+				line := strings.Count(tmpl[:ref.Offset], "\n") + 1
+				col := ref.Offset - strings.LastIndex(tmpl[:ref.Offset], "\n")
+				if pos := m.sqlPosition(tmpl, line, col); pos.IsValid() {
+					return fmt.Errorf("%s: %s", pos, msg)
+				}
+				return errors.New(msg)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package gen
+
+import (
+	"path"
+	"strings"
+)
+
+// Formats of the generated ER diagrams, see the --diagram flag
+const (
+	diagramMermaid = "mermaid"
+	diagramDot     = "dot"
+)
+
+// diagramExtensions are the file extensions of the diagram formats, rendered by the <format>.tmpl templates
+var diagramExtensions = map[string]string{diagramMermaid: "mmd", diagramDot: "dot"}
+
+// Entity is a struct of an ER diagram
+type Entity struct {
+	Name       string
+	Table      string
+	Attributes []Attribute
+}
+
+// Attribute is a column of an ER diagram entity
+type Attribute struct {
+	Name string
+	Type string
+	Keys []string // PK, FK or UK
+}
+
+// Link is a relation of an ER diagram
+type Link struct {
+	From  string
+	To    string
+	Field string
+	Kind  string // has one, has many, belongs to or many to many
+}
+
+// MermaidType returns the attribute type with the characters allowed by Mermaid, e.g. sql_NullTime of sql.NullTime
+func (a Attribute) MermaidType() string {
+	typ := strings.TrimLeft(a.Type, "*")
+	if elem, ok := strings.CutPrefix(typ, "[]"); ok {
+		typ = strings.TrimLeft(elem, "*") + "[]"
+	}
+	return strings.NewReplacer(".", "_", "*", "", " ", "").Replace(typ)
+}
+
+// KeyList returns the keys of the attribute separated by commas, e.g. PK,FK
+func (a Attribute) KeyList() string {
+	return strings.Join(a.Keys, ",")
+}
+
+// DotLabel returns the Graphviz record label of the entity, its name and table above its columns
+func (e Entity) DotLabel() string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`).Replace
+	var b strings.Builder
+	b.WriteString("{" + escape(e.Name+" ("+e.Table+")") + "|")
+	for _, a := range e.Attributes {
+		b.WriteString(escape(a.Name + " : " + a.Type))
+		if len(a.Keys) > 0 {
+			b.WriteString(" " + escape(a.KeyList()))
+		}
+		b.WriteString(`\l`)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// Cardinality returns the Mermaid relationship of the link, e.g. ||--o{ for has many
+func (l Link) Cardinality() string {
+	switch l.Kind {
+	case "has many":
+		return "||--o{"
+	case "belongs to":
+		return "}o--||"
+	case "many to many":
+		return "}o--o{"
+	}
+	return "||--o|"
+}
+
+// Entities returns the structs of the package as ER diagram entities, views excluded
+func (p docPackage) Entities() (entities []Entity) {
+	foreignKeys := map[string]bool{} // <struct>.<column>
+	for _, link := range p.links() {
+		if link.Kind == "belongs to" {
+			foreignKeys[link.From+"."+link.foreignKey] = true
+		} else if link.Kind != "many to many" {
+			foreignKeys[link.To+"."+link.foreignKey] = true
+		}
+	}
+
+	for _, s := range p.Structs {
+		if s.IsView() {
+			continue
+		}
+		entity := Entity{Name: s.Name, Table: s.TableName()}
+		for _, f := range s.ColumnFields() {
+			attr := Attribute{Name: f.DBName, Type: f.DocGoType()}
+			for _, key := range strings.Split(s.DocKeys(f), ", ") {
+				switch {
+				case key == "primary key":
+					attr.Keys = append(attr.Keys, "PK")
+				case strings.HasPrefix(key, "unique"):
+					attr.Keys = append(attr.Keys, "UK")
+				}
+			}
+			if foreignKeys[s.Name+"."+f.DBName] {
+				attr.Keys = append(attr.Keys, "FK")
+			}
+			entity.Attributes = append(entity.Attributes, attr)
+		}
+		entities = append(entities, entity)
+	}
+	return entities
+}
+
+// Links returns the relations of the structs of the package for ER diagrams
+func (p docPackage) Links() (links []Link) {
+	for _, l := range p.links() {
+		links = append(links, l.Link)
+	}
+	return links
+}
+
+// link is a Link with the foreign key column of the relation
+type link struct {
+	Link
+	foreignKey string
+}
+
+func (p docPackage) links() (links []link) {
+	for _, s := range p.Structs {
+		if s.IsView() {
+			continue
+		}
+		for _, r := range s.Relations() {
+			to := r.Model
+			if idx := strings.LastIndex(path.Base(to), "."); idx >= 0 {
+				to = path.Base(to)[idx+1:]
+			}
+			links = append(links, link{Link: Link{From: s.Name, To: to, Field: r.Field, Kind: r.Kind}, foreignKey: r.ForeignKey})
+		}
+	}
+	return links
+}
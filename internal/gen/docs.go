@@ -0,0 +1,199 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Formats of the generated documentation, see the --docs flag
+const docsMarkdown = "md"
+
+var docsFormats = []string{docsMarkdown}
+
+// docPackage is the documentation of a generated package, rendered by the docs.tmpl template
+type docPackage struct {
+	Name       string
+	Header     string
+	Structs    []Struct
+	Interfaces []Interface
+
+	dir    string   // output directory of the package
+	root   string   // output root of the directory
+	inputs []string // input files of the package
+	file   *File    // first file of the package, its templates render the documentation
+}
+
+// HeaderLines returns the lines of the header, e.g. for comments of diagrams
+func (p docPackage) HeaderLines() []string {
+	return strings.Split(p.Header, "\n")
+}
+
+// Relation is a relation of a struct for documentation
+type Relation struct {
+	Field      string
+	Kind       string // has one, has many, belongs to or many to many
+	Model      string
+	ForeignKey string
+	References string
+	JoinTable  string
+}
+
+// Relations returns the relations of the struct for documentation
+func (s Struct) Relations() (relations []Relation) {
+	for _, f := range s.Fields {
+		if !f.IsAssociation() {
+			continue
+		}
+		m := f.association()
+		relations = append(relations, Relation{
+			Field:      f.Name,
+			Kind:       strings.ReplaceAll(m.Kind, "_", " "),
+			Model:      strings.TrimLeft(path.Base(f.GoType), "[]*"),
+			ForeignKey: m.ForeignKey,
+			References: m.References,
+			JoinTable:  m.JoinTable,
+		})
+	}
+	return relations
+}
+
+// DocGoType returns the Go type of the field without import paths for documentation, e.g. sql.NullTime
+func (f Field) DocGoType() string {
+	elem := strings.TrimLeft(f.GoType, "[]*")
+	return f.GoType[:len(f.GoType)-len(elem)] + path.Base(elem)
+}
+
+// DocDBType returns the database type declared in the gorm tags of the field for documentation, e.g. varchar(64)
+func (f Field) DocDBType() string {
+	c := f.columnSchema()
+	switch {
+	case c.Precision > 0 && c.Scale > 0:
+		return fmt.Sprintf("%s(%d,%d)", c.Type, c.Precision, c.Scale)
+	case c.Precision > 0:
+		return fmt.Sprintf("%s(%d)", c.Type, c.Precision)
+	case c.Size > 0:
+		return fmt.Sprintf("%s(%d)", c.Type, c.Size)
+	}
+	return c.Type
+}
+
+// Nullable reports whether the field can hold NULL for documentation: pointers, sql.Null* and
+// gorm.DeletedAt fields not declared NOT NULL
+func (f Field) Nullable() bool {
+	if f.columnSchema().NotNull {
+		return false
+	}
+	goType := strings.TrimPrefix(f.GoType, "*")
+	return goType != f.GoType || strings.HasPrefix(goType, "database/sql.Null") || goType == "gorm.io/gorm.DeletedAt"
+}
+
+// DocKeys returns the keys the column of the field belongs to for documentation, e.g. primary key, unique (idx_users_email)
+func (s Struct) DocKeys(f Field) string {
+	var keys []string
+	if slices.ContainsFunc(s.PrimaryFields(), func(p Field) bool { return p.Name == f.Name }) {
+		keys = append(keys, "primary key")
+	}
+	for _, index := range s.UniqueIndexes() {
+		if slices.Contains(index.Columns, f.DBName) {
+			keys = append(keys, fmt.Sprintf("unique (%s)", index.IndexName))
+		}
+	}
+	return strings.Join(keys, ", ")
+}
+
+// Description returns the doc comment text of the struct for documentation
+func (s Struct) Description() string {
+	return strings.TrimSpace(s.Doc)
+}
+
+// Description returns the doc comment text of the interface for documentation
+func (i Interface) Description() string {
+	return strings.TrimSpace(i.Doc)
+}
+
+// Description returns the description of the method without its SQL template for documentation
+func (m Method) Description() string {
+	return strings.TrimSpace(extractDescription(m.Doc, m.Name))
+}
+
+// DocSignature returns the signature of the generated method for documentation, e.g. GetByID(ctx context.Context, id int) (T, error)
+func (m Method) DocSignature() string {
+	result := m.ResultString()
+	if strings.Contains(result, ",") {
+		result = "(" + result + ")"
+	}
+	return fmt.Sprintf("%s(%s) %s", m.Name, m.ParamsString(), result)
+}
+
+// DocSQL returns the SQL template of the method for documentation, chain methods show the clause they add
+func (m Method) DocSQL() string {
+	switch {
+	case m.SQL.Raw != "":
+		return strings.TrimSpace(m.SQL.Raw)
+	case m.SQL.Where != "":
+		return "WHERE " + strings.TrimSpace(m.SQL.Where)
+	case m.SQL.Select != "":
+		return "SELECT " + strings.TrimSpace(m.SQL.Select)
+	case m.SQL.Expr != "":
+		return strings.TrimSpace(m.SQL.Expr)
+	case m.SQL.Update != "":
+		return "UPDATE @@table SET " + strings.TrimSpace(m.SQL.Update)
+	case m.SQL.Delete != "":
+		return "DELETE FROM @@table WHERE " + strings.TrimSpace(m.SQL.Delete)
+	case m.SQL.Insert != "":
+		return m.insertSQL()
+	case m.SQL.Count != "":
+		return "SELECT COUNT(*) FROM @@table WHERE " + strings.TrimSpace(m.SQL.Count)
+	}
+	return ""
+}
+
+// docPackages groups the structs and interfaces of the outputs by output directory, in output order
+func docPackages(outputs []*output) (packages []*docPackage) {
+	byDir := map[string]*docPackage{}
+	for _, out := range outputs {
+		dir := filepath.Dir(out.path)
+		pkg, ok := byDir[dir]
+		if !ok {
+			header := strings.ReplaceAll(strings.TrimPrefix(out.file.Header, "// "), "\n// ", "\n")
+			pkg = &docPackage{Name: out.file.OutPackage(), Header: header, dir: dir, root: out.root, file: out.file}
+			byDir[dir] = pkg
+			packages = append(packages, pkg)
+		}
+		pkg.inputs = append(pkg.inputs, out.inputs...)
+		pkg.Structs = append(pkg.Structs, out.file.Structs...)
+		pkg.Interfaces = append(pkg.Interfaces, out.file.Interfaces...)
+	}
+	return packages
+}
+
+// writeDocs renders the template tmplName with the docPackage of each output directory into
+// <package>.<ext> files of the directory, e.g. models/models.md
+func writeDocs(w *outputWriter, outputs []*output, tmplName, ext string, templateOf func(*File) (*template.Template, error)) error {
+	for _, pkg := range docPackages(outputs) {
+		tmpl, err := templateOf(pkg.file)
+		if err != nil {
+			return err
+		}
+
+		var results bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&results, tmplName, pkg); err != nil {
+			return fmt.Errorf("failed to render %v of package %v, got error %v", tmplName, pkg.Name, err)
+		}
+		if err := w.writeDoc(pkg, filepath.Join(pkg.dir, pkg.Name+"."+ext), results.Bytes(), pkg.HeaderLines()[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDoc writes the generated documentation of the package to docPath, unless it's unchanged,
+// existing files are overwritten if they contain hint, see outputWriter.write
+func (w *outputWriter) writeDoc(pkg *docPackage, docPath string, content []byte, hint string) error {
+	return w.write(pkg.root, docPath, content, pkg.inputs, strings.TrimSpace(hint), fmt.Sprintf("Generating docs %s...\n", docPath))
+}
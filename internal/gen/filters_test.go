@@ -65,10 +65,10 @@ func TestFilters_Whitelist(t *testing.T) {
 		t.Fatalf("expected I2 to be filtered out by whitelist")
 	}
 	// S1 var present; S2 not present
-	if !strings.Contains(content, "var S1 = struct") {
+	if !strings.Contains(content, "var S1 = _S1{") {
 		t.Fatalf("expected S1 helper struct to be generated")
 	}
-	if strings.Contains(content, "var S2 = struct") {
+	if strings.Contains(content, "var S2 = _S2{") {
 		t.Fatalf("expected S2 to be filtered out by whitelist")
 	}
 }
@@ -98,10 +98,10 @@ func TestFilters_Blacklist(t *testing.T) {
 		t.Fatalf("expected I1 to be generated")
 	}
 	// S2 excluded; S1 included
-	if strings.Contains(content, "var S2 = struct") {
+	if strings.Contains(content, "var S2 = _S2{") {
 		t.Fatalf("expected S2 to be excluded by blacklist")
 	}
-	if !strings.Contains(content, "var S1 = struct") {
+	if !strings.Contains(content, "var S1 = _S1{") {
 		t.Fatalf("expected S1 to be generated")
 	}
 }
@@ -130,7 +130,7 @@ func TestFilters_TwoLevel(t *testing.T) {
 	if !strings.Contains(rIface, "func I1[") || !strings.Contains(rIface, "func I2[") || strings.Contains(rIface, "func I3[") {
 		t.Fatalf("root: expected I1, I2 to be generated, I3 not generated")
 	}
-	if !strings.Contains(rModels, "var S1 = struct") || !strings.Contains(rModels, "var S2 = struct") || strings.Contains(rModels, "var S3 = struct") {
+	if !strings.Contains(rModels, "var S1 = _S1{") || !strings.Contains(rModels, "var S2 = _S2{") || strings.Contains(rModels, "var S3 = _S3{") {
 		t.Fatalf("root: expected S1, S2 to be generated, S3 not generated")
 	}
 
@@ -147,10 +147,10 @@ func TestFilters_TwoLevel(t *testing.T) {
 	if !strings.Contains(nIface, "func I1[") {
 		t.Fatalf("nested: expected I1 to be generated")
 	}
-	if strings.Contains(nModels, "var S2 = struct") || strings.Contains(nModels, "var S3 = struct") {
+	if strings.Contains(nModels, "var S2 = _S2{") || strings.Contains(nModels, "var S3 = _S3{") {
 		t.Fatalf("nested: S2 and S3 should be excluded by parent+child config")
 	}
-	if !strings.Contains(nModels, "var S1 = struct") {
+	if !strings.Contains(nModels, "var S1 = _S1{") {
 		t.Fatalf("nested: expected S1 to be generated")
 	}
 }
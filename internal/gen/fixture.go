@@ -0,0 +1,73 @@
+package gen
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// FixtureField is a field set by the fixture factory of a struct, with the Go expression of its default value
+type FixtureField struct {
+	Name  string
+	Value string
+}
+
+// Fixture reports whether a fixture factory is generated for the struct for template generation,
+// views and generic structs have none
+func (s Struct) Fixture() bool {
+	return s.file != nil && s.file.factories() && !s.view && s.TypeParams == ""
+}
+
+// FixtureFields returns the fields the fixture factory of the struct sets for template generation, see
+// Field.fixtureValue. The other fields keep their zero value, filled by GORM or the database on create.
+func (s Struct) FixtureFields() (fields []FixtureField) {
+	primaryFields := s.PrimaryFields()
+	for _, f := range s.ColumnFields() {
+		if slices.ContainsFunc(primaryFields, func(p Field) bool { return p.Name == f.Name }) {
+			continue
+		}
+		if value := f.fixtureValue("seq"); value != "" {
+			fields = append(fields, FixtureField{Name: f.Name, Value: value})
+		}
+	}
+	return fields
+}
+
+// fixtureValue returns the Go expression of the default value of the field in fixtures, or "" to keep the
+// zero value. Strings are made unique with the seq variable so unique indexes don't conflict, enums use
+// their first declared constant and times the current time. Fields that GORM or the database fill, e.g.
+// auto timestamps, defaults and versions, and nullable fields are left zero.
+func (f Field) fixtureValue(seq string) string {
+	if f.IsAssociation() || f.DefaultValue() != "" || f.isVersion() || strings.HasPrefix(f.GoType, "*") {
+		return ""
+	}
+	if creatable, _ := f.permissions(); !creatable {
+		return ""
+	}
+
+	tags := schema.ParseTagSetting(reflect.StructTag(f.Tag).Get("gorm"), ";")
+	if _, ok := tags["AUTOCREATETIME"]; ok || f.Name == "CreatedAt" {
+		return ""
+	}
+	if _, ok := tags["AUTOUPDATETIME"]; ok || f.Name == "UpdatedAt" {
+		return ""
+	}
+	if _, ok := tags["AUTOINCREMENT"]; ok {
+		return ""
+	}
+
+	// Columns mapped to other helpers, e.g. JSON strings, keep their zero value
+	switch typ := f.Type(); {
+	case f.GoType == "string" && typ == "field.String":
+		return fmt.Sprintf("fmt.Sprintf(%q, %s)", f.DBName+"-%d", seq)
+	case f.GoType == "time.Time" && typ == "field.Time":
+		return "time.Now()"
+	}
+	if values := f.enumValues(); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/jinzhu/inflection"
+	"gorm.io/gorm/schema"
+)
+
+// builtinFuncs are the functions available to every template, including the overrides of a template directory
+var builtinFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"snake":      func(s string) string { return schema.NamingStrategy{}.ColumnName("", s) },
+	"camel":      camelCase,
+	"lowerCamel": func(s string) string { return lowerFirst(camelCase(s)) },
+	"plural":     inflection.Plural,
+	"singular":   inflection.Singular,
+}
+
+// camelCase converts snake_case, kebab-case or space separated words to CamelCase, e.g. user_id to UserID
+func camelCase(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' || unicode.IsSpace(r) }) {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// lowerFirst lowers the leading upper case letters of s, keeping the last one of an initialism upper case, e.g. IDCard to idCard
+func lowerFirst(s string) string {
+	runes := []rune(s)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) || i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// commonInitialisms are the initialisms kept upper case by camelCase, as golint does
+var commonInitialisms = map[string]bool{"API": true, "ID": true, "IP": true, "JSON": true, "SQL": true, "URL": true, "UUID": true, "HTTP": true}
+
+// templateFuncs returns the template functions of the file, the builtin functions and the functions
+// declared with genconfig.Config.TemplateFuncs; the closest config wins when a name is declared more than once
+func (p *File) templateFuncs() (template.FuncMap, error) {
+	funcs := template.FuncMap{}
+	for name, fn := range builtinFuncs {
+		funcs[name] = fn
+	}
+
+	declared := map[string]bool{}
+	for _, cfg := range p.applicableConfigs {
+		for name, value := range cfg.TemplateFuncs {
+			if declared[name] {
+				continue
+			}
+			declared[name] = true
+
+			switch v := value.(type) {
+			case map[string]string: // lookup table, unknown values are returned as is
+				funcs[name] = func(s string) string {
+					if mapped, ok := v[s]; ok {
+						return mapped
+					}
+					return s
+				}
+			case string: // template snippet executed with the argument as dot
+				snippet, err := template.New(name).Funcs(builtinFuncs).Parse(v)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse template func %s, got error %v", name, err)
+				}
+				funcs[name] = func(arg any) (string, error) {
+					var b strings.Builder
+					err := snippet.Execute(&b, arg)
+					return b.String(), err
+				}
+			default:
+				return nil, fmt.Errorf("invalid template func %s, must be a template string or a map[string]string", name)
+			}
+		}
+	}
+	return funcs, nil
+}
+
+// templateFuncsKey identifies the template functions declared in the applicable configs, templates are
+// parsed once per template directory and functions
+func (p *File) templateFuncsKey() string {
+	var keys []string
+	for _, cfg := range p.applicableConfigs {
+		for name, value := range cfg.TemplateFuncs {
+			keys = append(keys, fmt.Sprintf("%p.%s=%v", cfg, name, value))
+		}
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "\x00")
+}
@@ -2,24 +2,94 @@ package gen
 
 import (
 	"fmt"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
-var defaultOutPath = "./g"
+var (
+	defaultOutPath  = "./g"
+	defaultCacheDir = ".gorm-cache"
+)
 
 func New() *cobra.Command {
-	var typed bool
-	var input, output string
+	var typed, cache, singleFile, optIn, includeTests, followSymlinks, crud, withTests, factories, check, prune, stripComments bool
+	var input, output, outPackage, contextParam, templateDir, docs, diagram, apiSchema, verifyDB, driver, validateSQL string
 
 	cmd := &cobra.Command{
 		Use:   "gen",
 		Short: "Generate GORM query code from raw SQL interfaces",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if outPackage != "" && !token.IsIdentifier(outPackage) {
+				return fmt.Errorf("invalid package name %q", outPackage)
+			}
+			if contextParam != "" && !slices.Contains(contextParams, contextParam) {
+				return fmt.Errorf("invalid context policy %q, must be one of %s", contextParam, strings.Join(contextParams, ", "))
+			}
+
+			if docs != "" && !slices.Contains(docsFormats, docs) {
+				return fmt.Errorf("invalid docs format %q, must be one of %s", docs, strings.Join(docsFormats, ", "))
+			}
+
+			if _, ok := diagramExtensions[diagram]; diagram != "" && !ok {
+				return fmt.Errorf("invalid diagram format %q, must be one of %s, %s", diagram, diagramMermaid, diagramDot)
+			}
+
+			if _, ok := schemaExtensions[apiSchema]; apiSchema != "" && !ok {
+				return fmt.Errorf("invalid schema format %q, must be one of %s, %s", apiSchema, schemaOpenAPI, schemaJSONSchema)
+			}
+
+			if verifyDB != "" {
+				if err := validateSchemaDriver(driver); err != nil {
+					return err
+				}
+			}
+
+			if validateSQL != "" && validateSQL != sqlCheckGeneric && !slices.Contains(sqlDialects, validateSQL) {
+				return fmt.Errorf("invalid SQL dialect %q, must be one of %s, %s", validateSQL, sqlCheckGeneric, strings.Join(sqlDialects, ", "))
+			}
+
 			g := Generator{
-				Typed:   typed,
-				Files:   map[string]*File{},
-				outPath: output,
+				Typed:      typed,
+				Files:      map[string]*File{},
+				outPath:    output,
+				singleFile: singleFile,
+				optIn:      optIn,
+				outPackage: outPackage,
+
+				includeTests:   includeTests,
+				contextParam:   contextParam,
+				followSymlinks: followSymlinks,
+				templateDir:    templateDir,
+				crud:           crud,
+				withTests:      withTests,
+				factories:      factories,
+				docs:           docs,
+				diagram:        diagram,
+				apiSchema:      apiSchema,
+				check:          check,
+				prune:          prune,
+				stripComments:  stripComments,
+				validateSQL:    validateSQL,
+			}
+			if cache {
+				g.cacheDir = defaultCacheDir
+
+				// Skip loading the inputs at all when nothing changed, the database and the outputs
+				// are still checked with --verify-db and --check
+				if !check && verifyDB == "" {
+					upToDate, err := g.upToDate(input)
+					if err != nil {
+						return fmt.Errorf("error hashing the inputs of %s: %v", input, err)
+					}
+					if upToDate {
+						fmt.Printf("Skipping %s, inputs unchanged since the last run\n", input)
+						return nil
+					}
+				}
 			}
 
 			err := g.Process(input)
@@ -28,10 +98,24 @@ func New() *cobra.Command {
 			}
 
 			err = g.Gen()
-			if err != nil {
+			if err != nil && check {
+				// outdated files are reported with a non-zero exit code for CI, the usage doesn't help
+				cmd.SilenceUsage = true
+				return err
+			} else if err != nil {
 				return fmt.Errorf("error render template got error: %v", err)
 			}
 
+			if verifyDB != "" {
+				db, err := openSchemaDB(driver, verifyDB)
+				if err != nil {
+					return fmt.Errorf("error connecting to the database: %v", err)
+				}
+				if err := g.verifyColumns(db); err != nil {
+					return fmt.Errorf("error verifying columns: %v", err)
+				}
+			}
+
 			return nil
 		},
 	}
@@ -39,7 +123,146 @@ func New() *cobra.Command {
 	cmd.Flags().BoolVarP(&typed, "typed", "t", true, "Generated Typed API")
 	cmd.Flags().StringVarP(&output, "output", "o", defaultOutPath, "Directory to place generated code")
 	cmd.Flags().StringVarP(&input, "input", "i", "", "Path to Go interface file with raw SQL annotations")
+	cmd.Flags().BoolVar(&cache, "cache", false, "Skip files whose inputs are unchanged since the last run, tracked in "+defaultCacheDir)
+	cmd.Flags().StringVar(&outPackage, "package", "", "Package name of the generated code, defaults to the source package name")
+	cmd.Flags().BoolVar(&singleFile, "single-file", false, "Generate each package into a single <package>_gen.go file")
+	cmd.Flags().BoolVar(&optIn, "opt-in", false, "Only generate interfaces and structs annotated with a "+generateMarker+" comment")
+	cmd.Flags().BoolVar(&includeTests, "tests", false, "Process _test.go files, skipped unless included with IncludeTests in genconfig.Config")
+	cmd.Flags().BoolVar(&followSymlinks, "follow-symlinks", false, "Traverse symlinked directories of the input, e.g. shared model packages")
+	cmd.Flags().StringVar(&templateDir, "template-dir", "", "Directory of *.tmpl files overriding the default templates, e.g. pkg.tmpl")
+	cmd.Flags().BoolVar(&crud, "crud", false, "Generate a CRUD repository of each model with a single primary key, e.g. NewUserRepository(db)")
+	cmd.Flags().BoolVar(&factories, "factories", false, "Generate a fixture factory of each model, e.g. NewUserFixture(overrides...) for seeding tests")
+	cmd.Flags().StringVar(&docs, "docs", "", "Generate the documentation of each generated package in the format: md")
+	cmd.Flags().StringVar(&diagram, "diagram", "", "Generate the ER diagram of each generated package in the format: mermaid or dot")
+	cmd.Flags().StringVar(&apiSchema, "schema", "", "Generate the schemas of the models of each generated package in the format: openapi or jsonschema")
+	cmd.Flags().BoolVar(&withTests, "with-tests", false, "Generate a <file>_test.go skeleton with table-driven tests of the interface methods, kept once written")
+	cmd.Flags().StringVar(&verifyDB, "verify-db", "", "Data source name of a database to verify the generated columns exist on, e.g. user:pass@tcp(127.0.0.1:3306)/db")
+	cmd.Flags().StringVar(&driver, "driver", "mysql", "Driver of the --verify-db database: mysql, postgres or sqlite (cgo builds only)")
+	cmd.Flags().StringVar(&contextParam, "context", "", "Policy of the ctx parameter of query methods: inject (default), require or omit")
+	cmd.Flags().BoolVar(&check, "check", false, "Report generated files that are missing, outdated or stale instead of writing them, failing if any")
+	cmd.Flags().BoolVar(&stripComments, "strip-sql-comments", false, "Drop the comments of SQL templates from the generated SQL, except optimizer hints like /*+ ... */")
+	cmd.Flags().StringVar(&validateSQL, "validate-sql", "", "Check the syntax of SQL templates for a dialect, e.g. sqlite (checked by the SQLite parser) or mysql, postgres (common checks only)")
+	cmd.Flags().Lookup("validate-sql").NoOptDefVal = sqlCheckGeneric
+	cmd.Flags().BoolVar(&prune, "prune", false, "Remove files of previous runs that are no longer generated, tracked in the "+manifestFileName+" of the output")
 	cmd.MarkFlagRequired("input")
 
 	return cmd
 }
+
+func NewClean() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove the generated files listed in the " + manifestFileName + " of an output directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cleanOutputs(output); err != nil {
+				return fmt.Errorf("error cleaning %s: %v", output, err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", defaultOutPath, "Directory of the generated code")
+
+	return cmd
+}
+
+func NewSchema() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Inspect the database schema of GORM models",
+	}
+
+	var input, dsn, driver string
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare model structs to a live database, reporting missing columns, type mismatches and extra tables",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openSchemaDB(driver, dsn)
+			if err != nil {
+				return fmt.Errorf("error connecting to the database: %v", err)
+			}
+
+			g := Generator{Files: map[string]*File{}}
+			if err := g.Process(input); err != nil {
+				return fmt.Errorf("error processing %s: %v", input, err)
+			}
+
+			diffs, err := g.diffSchema(db)
+			if err != nil {
+				return fmt.Errorf("error comparing schema: %v", err)
+			}
+			if len(diffs) == 0 {
+				fmt.Println("No schema differences found")
+				return nil
+			}
+
+			for _, diff := range diffs {
+				fmt.Println(diff)
+			}
+			// drift is reported with a non-zero exit code for CI, the usage doesn't help
+			cmd.SilenceUsage = true
+			return fmt.Errorf("found %d schema differences", len(diffs))
+		},
+	}
+
+	diffCmd.Flags().StringVarP(&input, "input", "i", "", "Path to the Go files or directories of the models")
+	diffCmd.Flags().StringVar(&dsn, "dsn", "", "Data source name of the database, e.g. user:pass@tcp(127.0.0.1:3306)/db?parseTime=true")
+	diffCmd.Flags().StringVar(&driver, "driver", "mysql", "Driver of the database: mysql, postgres or sqlite (cgo builds only)")
+	diffCmd.MarkFlagRequired("input")
+	diffCmd.MarkFlagRequired("dsn")
+
+	cmd.AddCommand(diffCmd)
+	return cmd
+}
+
+func NewProto() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proto",
+		Short: "Generate GORM models from protobuf messages",
+	}
+
+	var typed bool
+	var input, output, outPackage, helpersOutput string
+	var typeMap map[string]string
+	genCmd := &cobra.Command{
+		Use:   "gen",
+		Short: "Generate GORM models and their field helpers from .proto files or generated .pb.go structs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if outPackage == "" {
+				outPackage = filepath.Base(output)
+			}
+			if !token.IsIdentifier(outPackage) {
+				return fmt.Errorf("invalid package name %q", outPackage)
+			}
+
+			if err := genProto(input, output, outPackage, typeMap); err != nil {
+				return fmt.Errorf("error generating models from %s: %v", input, err)
+			}
+			if helpersOutput == "" {
+				return nil
+			}
+
+			g := Generator{Typed: typed, Files: map[string]*File{}, outPath: helpersOutput}
+			if err := g.Process(output); err != nil {
+				return fmt.Errorf("error processing %s: %v", output, err)
+			}
+			if err := g.Gen(); err != nil {
+				return fmt.Errorf("error render template got error: %v", err)
+			}
+			return nil
+		},
+	}
+
+	genCmd.Flags().StringVarP(&input, "input", "i", "", "Path to the .proto files or generated .pb.go files")
+	genCmd.Flags().StringVarP(&output, "output", "o", "./models", "Directory to place the generated models")
+	genCmd.Flags().StringVar(&outPackage, "package", "", "Package name of the generated models, defaults to the output directory name")
+	genCmd.Flags().StringVar(&helpersOutput, "helpers-output", defaultOutPath, "Directory to place the field helpers of the models, empty to skip them")
+	genCmd.Flags().BoolVarP(&typed, "typed", "t", true, "Generated Typed API")
+	genCmd.Flags().StringToStringVar(&typeMap, "type-map", nil, "Go types of proto types, e.g. google.protobuf.Timestamp=time.Time or google.protobuf.StringValue=github.com/x/types.Text")
+	genCmd.MarkFlagRequired("input")
+
+	cmd.AddCommand(genCmd)
+	return cmd
+}
@@ -2,42 +2,82 @@ package gen
 
 import (
 	"bytes"
+	"cmp"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"runtime"
 	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 	"unicode"
 
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/tools/imports"
 	"gorm.io/cli/gorm/genconfig"
+	"gorm.io/gorm/schema"
 )
 
 type (
 	Generator struct {
-		Typed   bool
-		Files   map[string]*File
-		outPath string
+		Typed          bool
+		Files          map[string]*File
+		outPath        string
+		cacheDir       string
+		input          string       // absolute path of the processed input, see Process
+		inputs         *cacheInputs // hash of the inputs computed before processing them, see upToDate
+		deps           *depGraph    // import graph of the input packages, see dependencies
+		singleFile     bool
+		optIn          bool
+		includeTests   bool // process _test.go files, see genconfig.Config.IncludeTests
+		followSymlinks bool // traverse symlinked directories of the input
+		outPackage     string
+		contextParam   string
+		templateDir    string // directory of templates overriding the defaults, see genconfig.Config.TemplateDir
+		crud           bool   // generate CRUD repositories of the models, see genconfig.Config.CRUD
+		withTests      bool   // generate test skeletons of the interfaces, see writeTestSkeleton
+		factories      bool   // generate fixture factories of the models, see genconfig.Config.Factories
+		docs           string // format of the generated package documentation, e.g. md
+		diagram        string // format of the generated ER diagrams, mermaid or dot
+		apiSchema      string // format of the generated model schemas, openapi or jsonschema
+		check          bool   // report outdated generated files instead of writing them, see outputWriter
+		prune          bool   // remove files of previous runs that are no longer generated, see genManifest
+		stripComments  bool   // drop the comments of SQL templates, see genconfig.Config.StripSQLComments
+		validateSQL    string // dialect the syntax of SQL templates is checked for, see sqlChecker
+		quiet          bool   // don't report the skipped generated files, see PreviewSQL
+		loader         *packageLoader
+		mu             sync.Mutex
 	}
 	File struct {
 		Package           string
 		PackagePath       string
+		Header            string
 		Imports           []Import
 		Interfaces        []Interface
 		Structs           []Struct
+		allStructs        []Struct // structs before applying filters
 		Config            *genconfig.Config
 		applicableConfigs []*genconfig.Config
 		inputPath         string
 		relPath           string
 		goModDir          string
+		tableNames        map[string]string
+		fset              *token.FileSet
+		embedding         []string // structs being processed, <package path>.<name>, to stop at embedding cycles
+		err               error    // first error found while walking the file, e.g. an invalid config
 		Generator         *Generator
 	}
 	Import struct {
@@ -49,6 +89,10 @@ type (
 		IfaceName string
 		Doc       string
 		Methods   []*Method
+
+		marked    bool              // annotated with the //gorm:generate marker
+		fragments map[string]string // SQL fragments declared with {{define}} in the doc comment
+		model     string            // full type of the model of a `model: models.User` doc line, see File.queryModel
 	}
 	Method struct {
 		Name      string
@@ -57,24 +101,61 @@ type (
 		Params    []Param
 		Result    []Param
 		Interface Interface
+
+		contextParam string
+		docLines     []docLine         // lines of the doc comment, to report the positions of SQL template errors
+		fragments    map[string]string // SQL fragments for {{include}} directives
+		funcs        map[string]string // qualified functions callable in SQL template conditions, by name
+		sqlFile      string            // path of the file the SQL template was loaded from, see loadSQLFile
+
+		placeholderPrefix  string        // prefix of the placeholders of the SQL template, e.g. @
+		strictPlaceholders bool          // reject ambiguous placeholders of the SQL template
+		stripComments      bool          // drop the comments of the SQL template from the generated SQL
+		timeout            time.Duration // deadline of the query, from a `timeout: 3s` annotation line
+		statementsTx       bool          // run the statements of multi-statement methods in a transaction
+		hook               string        // qualified variable of the field.QueryHook tracing the query, see genconfig.Config.QueryHook
+
+		insertTable  string  // table of an insert(...) method, e.g. @@table
+		insertParam  string  // struct parameter expanded by an insert(...) method, e.g. user of @user.*
+		insertFields []Field // fields of the struct parameter written by an insert(...) method
+	}
+	// docLine is a line of a doc comment with the source position of its text
+	docLine struct {
+		text string
+		pos  token.Position
 	}
 	Param struct {
 		Name string
 		Type string
 	}
 	Struct struct {
-		Name   string
-		Doc    string
-		Fields []Field
+		Name       string
+		Doc        string
+		TypeParams string // type parameter list of generic structs, e.g. [T any]
+		TypeArgs   string // type parameters as arguments, e.g. [T]
+		Fields     []Field
+		file       *File
+		marked     bool   // annotated with the //gorm:generate marker
+		view       bool   // a database view, annotated with the //gorm:view marker or matched by genconfig.Config.Views
+		viewSQL    string // SELECT statement of the view following the //gorm:view marker
+	}
+	// Scope is a named condition of a struct declared in genconfig.Config.Scopes
+	Scope struct {
+		Name  string
+		Exprs []string // field helper expressions, e.g. Age.Gte(18)
 	}
 	Field struct {
 		Name        string
+		Doc         string
 		DBName      string
 		GoType      string
 		NamedGoType string
 		Tag         string
+		owner       string
 		file        *File
 		field       *ast.Field
+		pos         token.Position
+		view        bool // the field belongs to a view, it's read-only
 	}
 )
 
@@ -85,64 +166,190 @@ func (g *Generator) Process(input string) error {
 		return err
 	}
 
+	// Load the packages under the input once, later type lookups are resolved from it.
+	// Failures are not fatal, the loader falls back to loading packages on demand.
+	if g.loader == nil {
+		g.loader = newPackageLoader()
+	}
+	absInput, _ := filepath.Abs(input)
+	g.input = absInput
+	if info.IsDir() {
+		g.loader.load(g.loader.goModDir(absInput), absInput+"/...")
+	} else {
+		g.loader.load(g.loader.goModDir(filepath.Dir(absInput)), filepath.Dir(absInput))
+	}
+
 	// Store the input root for relative path calculation
 	if info.IsDir() {
 		inputRoot, _ := filepath.Abs(input)
-		return filepath.Walk(input, func(path string, info os.FileInfo, err error) error {
-			if err == nil && !info.IsDir() {
-				return g.processFile(path, inputRoot)
+
+		var inputFiles, testFiles []string
+		err := g.walk(input, func(path string) {
+			if strings.HasSuffix(path, "_test.go") {
+				testFiles = append(testFiles, path)
+			} else {
+				inputFiles = append(inputFiles, path)
 			}
-			return err
 		})
+		if err != nil {
+			return err
+		}
+
+		// Parse files and load their types in parallel, configs are discovered from g.Files afterwards in Gen
+		process := func(files []string) error {
+			var eg errgroup.Group
+			eg.SetLimit(runtime.GOMAXPROCS(0))
+			for _, inputFile := range files {
+				eg.Go(func() error {
+					return g.processFile(inputFile, inputRoot)
+				})
+			}
+			return eg.Wait()
+		}
+		if err := process(inputFiles); err != nil {
+			return err
+		}
+
+		// Test files often declare helper types, or don't parse outside of go test,
+		// they are skipped unless included by the flag or the configs found so far
+		testFiles = slices.DeleteFunc(testFiles, func(pth string) bool { return !g.testsIncluded(pth) })
+		return process(testFiles)
 	}
 	inputRoot, _ := filepath.Abs(filepath.Dir(input))
+
+	// Pick up the config file of the package, it's not walked when a single file is given
+	if cfgFile := filepath.Join(filepath.Dir(input), configFileName); filepath.Base(input) != configFileName {
+		if _, err := os.Stat(cfgFile); err == nil {
+			if err := g.processFile(cfgFile, inputRoot); err != nil {
+				return err
+			}
+		}
+	}
 	return g.processFile(input, inputRoot)
 }
 
+// Scopes of a genconfig.Config, see genconfig.Config.Scope
+const (
+	configScopePackage = "package"
+	configScopeSubtree = "subtree"
+)
+
+// configFileName is the conventional name of a file holding only the genconfig.Config of a package,
+// usually guarded by a build tag so the config isn't compiled into regular builds
+const configFileName = "gorm.gen.go"
+
+// output is a generated file rendered from one or, when merging a package's output, several input files
+type output struct {
+	path        string
+	root        string // output root of the path, holding the manifest of the generated files
+	file        *File
+	inputs      []string
+	configFiles []string
+	merged      bool
+}
+
+// merge adds the interfaces, structs and imports of file to the output
+func (o *output) merge(file *File, configFiles []string) {
+	if !o.merged {
+		// copy the first file, it is still referenced from Generator.Files
+		f := *o.file
+		f.Imports = slices.Clone(f.Imports)
+		f.Interfaces = slices.Clone(f.Interfaces)
+		f.Structs = slices.Clone(f.Structs)
+		o.file, o.merged = &f, true
+	}
+
+	o.inputs = append(o.inputs, file.inputPath)
+	o.file.Interfaces = append(o.file.Interfaces, file.Interfaces...)
+	o.file.Structs = append(o.file.Structs, file.Structs...)
+	mergeImports(&o.file.Imports, file.Imports)
+	for _, cfgFile := range configFiles {
+		if !slices.Contains(o.configFiles, cfgFile) {
+			o.configFiles = append(o.configFiles, cfgFile)
+		}
+	}
+}
+
 // Gen generates code files from processed AST data
 func (g *Generator) Gen() error {
-	tmpl, _ := template.New("").Parse(pkgTmpl)
+	templates := map[string]*template.Template{} // parsed templates by template directory and functions
+	templateOf := func(file *File) (*template.Template, error) {
+		key := file.templateDir() + "\x00" + file.templateFuncsKey()
+		if tmpl, ok := templates[key]; ok {
+			return tmpl, nil
+		}
+		funcs, err := file.templateFuncs()
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := parseTemplates(file.templateDir(), funcs)
+		if err != nil {
+			return nil, err
+		}
+		templates[key] = tmpl
+		return tmpl, nil
+	}
 
-	// files contains config
-	filesWithCfg := []string{}
-	for pth, file := range g.Files {
-		if file.Config != nil {
-			filesWithCfg = append(filesWithCfg, pth)
+	filesWithCfg := g.filesWithConfig()
+
+	var checker *sqlChecker
+	if g.validateSQL != "" {
+		var err error
+		if checker, err = newSQLChecker(g.validateSQL); err != nil {
+			return err
 		}
+		defer checker.Close()
 	}
-	sort.Strings(filesWithCfg)
 
-	for _, file := range g.Files {
-		outPath := g.outPath
-		for i := len(filesWithCfg) - 1; i >= 0; i-- {
-			prefixPth := filesWithCfg[i]
-			curFile := g.Files[filesWithCfg[i]]
-			if !curFile.Config.FileLevel {
-				prefixPth = filepath.Dir(filesWithCfg[i])
-			}
+	var cache *genCache
+	if g.cacheDir != "" && !g.check {
+		cache = loadGenCache(g.cacheDir)
+	}
 
-			if strings.HasPrefix(file.inputPath, prefixPth) {
-				if outPath == defaultOutPath {
-					outPath = g.Files[filesWithCfg[i]].Config.OutPath
-				}
+	// Iterate files in a stable order, so merged outputs are deterministic
+	inputPaths := make([]string, 0, len(g.Files))
+	for pth := range g.Files {
+		inputPaths = append(inputPaths, pth)
+	}
+	sort.Strings(inputPaths)
 
-				cfg := g.Files[filesWithCfg[i]].Config
-				file.applicableConfigs = append(file.applicableConfigs, cfg)
-				mergeImports(&file.Imports, g.Files[filesWithCfg[i]].Imports)
-			}
+	var outputs []*output
+	outputsByPath := map[string]*output{}
+	for _, inputPath := range inputPaths {
+		file := g.Files[inputPath]
+		outPath, configFiles := g.applyConfigs(file, filesWithCfg)
+
+		header, err := file.header()
+		if err != nil {
+			return err
+		}
+		file.Header = header
+
+		// Skip outputs of previous runs generated with a custom header
+		if header != codeGenHint && shouldSkipFile(file.inputPath, headerHint(header)) {
+			continue
+		}
+
+		// Only keep types annotated with the marker comment in opt-in mode
+		if file.requireMarker() {
+			file.Interfaces = slices.DeleteFunc(file.Interfaces, func(i Interface) bool { return !i.marked })
+			file.Structs = slices.DeleteFunc(file.Structs, func(s Struct) bool { return !s.marked })
 		}
 
 		// Apply include/exclude filters from applicable configs
 		if len(file.applicableConfigs) > 0 {
-			var incI, excI, incS, excS []any
+			var incI, excI, incS, excS, incF, excF, views []any
 			for _, cfg := range file.applicableConfigs {
+				views = append(views, cfg.Views...)
 				incI = append(incI, cfg.IncludeInterfaces...)
 				excI = append(excI, cfg.ExcludeInterfaces...)
 				incS = append(incS, cfg.IncludeStructs...)
 				excS = append(excS, cfg.ExcludeStructs...)
+				incF = append(incF, cfg.IncludeFields...)
+				excF = append(excF, cfg.ExcludeFields...)
 			}
 
-			filePkgPath := getCurrentPackagePath(file.inputPath)
+			filePkgPath := file.PackagePath
 			matchAnyName := func(name string, patterns []any) bool {
 				name = filePkgPath + "." + stripGeneric(name)
 				for _, p := range patterns {
@@ -183,39 +390,248 @@ func (g *Generator) Gen() error {
 					}
 				}
 			}
+
+			for i := range file.Structs {
+				if st := &file.Structs[i]; !st.view && len(views) > 0 && matchAnyName(st.Name, views) {
+					st.asView()
+				}
+			}
+
+			if len(incF) > 0 || len(excF) > 0 {
+				for i := range file.Structs {
+					st := &file.Structs[i]
+
+					// IncludeFields only narrows structs matched by the struct part of a selector
+					var inc []any
+					for _, p := range incF {
+						if idx := strings.LastIndex(fmt.Sprint(p), "."); idx > 0 && matchAnyName(st.Name, []any{fmt.Sprint(p)[:idx]}) {
+							inc = append(inc, p)
+						}
+					}
+
+					// Fields are shared with allStructs, filter a copy
+					st.Fields = slices.DeleteFunc(slices.Clone(st.Fields), func(f Field) bool {
+						name := st.Name + "." + f.Name
+						return len(inc) > 0 && !matchAnyName(name, inc) || matchAnyName(name, excF)
+					})
+				}
+			}
 		}
 
 		if len(file.Interfaces) == 0 && len(file.Structs) == 0 {
 			continue
 		}
 
+		for _, st := range file.Structs {
+			if err := st.validateColumns(); err != nil {
+				return err
+			}
+			if err := st.validateScopes(); err != nil {
+				return err
+			}
+			if err := st.validateVersion(); err != nil {
+				return err
+			}
+		}
+
+		if err := file.prepareMethods(); err != nil {
+			return err
+		}
+		for _, iface := range file.Interfaces {
+			for _, m := range iface.Methods {
+				if err := file.validateParams(m); err != nil {
+					return err
+				}
+			}
+			if err := file.validateTemplateColumns(iface); err != nil {
+				return err
+			}
+			if checker != nil {
+				if err := file.validateSQL(iface, checker); err != nil {
+					return err
+				}
+			}
+		}
+
+		root := outPath
 		outPath = filepath.Join(outPath, file.relPath)
+		if file.mergeOutput() {
+			outPath = filepath.Join(filepath.Dir(outPath), file.Package+"_gen.go")
+			if out, ok := outputsByPath[outPath]; ok {
+				out.merge(file, configFiles)
+				continue
+			}
+		}
+
+		out := &output{path: outPath, root: root, file: file, inputs: []string{file.inputPath}, configFiles: configFiles}
+		outputs = append(outputs, out)
+		outputsByPath[outPath] = out
+	}
+
+	writer := newOutputWriter(g.check)
+	for _, out := range outputs {
+		outPath, file := out.path, out.file
+
+		var cacheKey string
+		if cache != nil {
+			key, err := file.cacheKey(out.configFiles)
+			if err != nil {
+				return fmt.Errorf("failed to compute cache key for %v, got error %v", file.inputPath, err)
+			}
+			if cache.fresh(outPath, key) {
+				writer.keep(out.root, outPath)
+				if g.withTests && len(file.Interfaces) > 0 {
+					if _, err := keepTestSkeleton(writer, out.root, outPath); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			cacheKey = key
+		}
+
+		tmpl, err := templateOf(file)
+		if err != nil {
+			return err
+		}
 
 		var results bytes.Buffer
-		if err := tmpl.Execute(&results, file); err != nil {
+		if err := tmpl.ExecuteTemplate(&results, pkgTmplName, file); err != nil {
 			return fmt.Errorf("failed to render template %v, got error %v", file.inputPath, err)
 		}
 
-		if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
-			return fmt.Errorf("failed to create directory for %v, got error %v", outPath, err)
+		result, err := imports.Process(outPath, results.Bytes(), nil)
+		if err != nil {
+			// keep the unformatted code around for debugging
+			if !g.check {
+				if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+					return fmt.Errorf("failed to create directory for %v, got error %v", outPath, err)
+				}
+				if err := os.WriteFile(outPath, results.Bytes(), 0o640); err != nil {
+					return fmt.Errorf("failed to write file %v, got error %v", outPath, err)
+				}
+			}
+			return fmt.Errorf("failed to format generated code for %v, got error %v", outPath, err)
+		}
+		if err := importConflict(outPath, result); err != nil {
+			return err
 		}
 
-		fmt.Printf("Generating file %s from %s...\n", outPath, file.inputPath)
-		if err := os.WriteFile(outPath, results.Bytes(), 0o640); err != nil {
-			return fmt.Errorf("failed to write file %v, got error %v", outPath, err)
+		// Leave unchanged files untouched, so their mtimes don't trigger rebuilds
+		message := fmt.Sprintf("Generating file %s from %s...\n", outPath, file.inputPath)
+		if err := writer.write(out.root, outPath, result, append(slices.Clone(out.inputs), file.sqlFiles()...), headerHint(file.Header), message); err != nil {
+			return err
 		}
 
-		if result, err := imports.Process(outPath, results.Bytes(), nil); err == nil {
-			if err := os.WriteFile(outPath, result, 0o640); err != nil {
-				return fmt.Errorf("failed to write file %v, got error %v", outPath, err)
+		if g.withTests && len(file.Interfaces) > 0 {
+			if err := writeTestSkeleton(writer, out.root, tmpl, file, outPath, out.inputs); err != nil {
+				return err
 			}
-		} else {
-			return fmt.Errorf("failed to format generated code for %v, got error %v", outPath, err)
+		}
+
+		if cache != nil {
+			cache.Entries[outPath] = cacheKey
+		}
+	}
+
+	if g.docs != "" {
+		if err := writeDocs(writer, outputs, docsTmplName, g.docs, templateOf); err != nil {
+			return err
 		}
 	}
+	if g.diagram != "" {
+		if err := writeDocs(writer, outputs, g.diagram+".tmpl", diagramExtensions[g.diagram], templateOf); err != nil {
+			return err
+		}
+	}
+	if g.apiSchema != "" {
+		if err := writeSchemas(writer, outputs, g.apiSchema); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.finish(g.prune); err != nil {
+		return err
+	}
+	if cache != nil {
+		if err := g.recordInputs(cache); err != nil {
+			return err
+		}
+		return cache.save()
+	}
 	return nil
 }
 
+// walk calls fn for every file under root. Symlinked directories are traversed with followSymlinks,
+// each directory is visited once so symlink cycles are skipped; files are reported under their link path.
+func (g *Generator) walk(root string, fn func(path string)) error {
+	if !g.followSymlinks {
+		return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				fn(path)
+			}
+			return err
+		})
+	}
+
+	visited := map[string]bool{}
+	var walkDir func(dir string) error
+	walkDir = func(dir string) error {
+		realDir, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			return err
+		}
+		if visited[realDir] {
+			return nil
+		}
+		visited[realDir] = true
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			pth := filepath.Join(dir, entry.Name())
+			info, err := os.Stat(pth) // follows symlinks
+			if err != nil {
+				if entry.Type()&os.ModeSymlink != 0 {
+					continue // dangling symlink
+				}
+				return err
+			}
+			if info.IsDir() {
+				if err := walkDir(pth); err != nil {
+					return err
+				}
+			} else {
+				fn(pth)
+			}
+		}
+		return nil
+	}
+	return walkDir(root)
+}
+
+// testsIncluded reports whether the _test.go file is processed, according to the --tests flag
+// and the IncludeTests option of the configs applying to its directory
+func (g *Generator) testsIncluded(testFile string) bool {
+	if g.includeTests {
+		return true
+	}
+
+	absPath, _ := filepath.Abs(testFile)
+	dir := filepath.Dir(absPath)
+	for pth, file := range g.Files {
+		if cfg := file.Config; cfg != nil && cfg.IncludeTests {
+			cfgDir := filepath.Dir(pth)
+			if cfgDir == dir || cfg.Scope != configScopePackage && strings.HasPrefix(dir, cfgDir+string(filepath.Separator)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // processFile processes a single Go file and extracts AST information
 func (g *Generator) processFile(inputFile, inputRoot string) error {
 	inputFile, err := filepath.Abs(inputFile)
@@ -224,7 +640,9 @@ func (g *Generator) processFile(inputFile, inputRoot string) error {
 	}
 
 	if shouldSkipFile(inputFile) {
-		fmt.Printf("Skipping generated file: %s\n", inputFile)
+		if !g.quiet {
+			fmt.Printf("Skipping generated file: %s\n", inputFile)
+		}
 		return nil
 	}
 
@@ -244,12 +662,13 @@ func (g *Generator) processFile(inputFile, inputRoot string) error {
 		Package:   f.Name.Name,
 		inputPath: inputFile,
 		relPath:   relPath,
-		goModDir:  findGoModDir(inputFile),
+		fset:      fileset,
 		Generator: g,
 	}
+	file.goModDir = file.loader().goModDir(filepath.Dir(inputFile))
 
 	// Add current package to imports for alias/path resolution and generation needs
-	if pkgPath := getCurrentPackagePath(inputFile); pkgPath != "" {
+	if pkgPath := file.loader().packagePath(file.goModDir, filepath.Dir(inputFile)); pkgPath != "" {
 		file.PackagePath = pkgPath
 		file.Imports = append(file.Imports, Import{
 			Name: file.Package,
@@ -258,12 +677,38 @@ func (g *Generator) processFile(inputFile, inputRoot string) error {
 	}
 
 	ast.Walk(file, f)
+	if file.err != nil {
+		return file.err
+	}
+	file.allStructs = slices.Clone(file.Structs)
 
 	// Store every processed file so configs in any file are discoverable
+	g.mu.Lock()
 	g.Files[file.inputPath] = file
+	g.mu.Unlock()
 	return nil
 }
 
+// DocComment returns the doc comment of the interface for template generation
+func (i Interface) DocComment() string {
+	return formatDoc(i.Doc)
+}
+
+// DocComment returns the doc comment of the method without its SQL template for template generation
+func (m Method) DocComment() string {
+	return formatDoc(extractDescription(m.Doc, m.Name))
+}
+
+// DocComment returns the doc comment of the struct for template generation
+func (s Struct) DocComment() string {
+	return formatDoc(s.Doc)
+}
+
+// DocComment returns the doc comment of the field for template generation
+func (f Field) DocComment() string {
+	return formatDoc(f.Doc)
+}
+
 // ImportPath returns formatted import path string for template generation
 func (p Import) ImportPath() string {
 	if path.Base(p.Path) == p.Name {
@@ -277,30 +722,94 @@ func (p Param) GoFullType() string {
 	return p.Type
 }
 
+// Policies of the ctx parameter of generated query methods, see genconfig.Config.ContextParam
+const (
+	contextInject  = "inject"
+	contextRequire = "require"
+	contextOmit    = "omit"
+)
+
+var contextParams = []string{contextInject, contextRequire, contextOmit}
+
+// hasContext reports whether the method declares a ctx parameter
+func (m Method) hasContext() bool {
+	return slices.ContainsFunc(m.Params, func(p Param) bool { return p.Name == "ctx" || p.Type == "context.Context" })
+}
+
 // ParamsString formats method parameters as a string for code generation
 func (m Method) ParamsString() string {
 	var parts []string
-	hasCtx := false
 
 	for _, p := range m.Params {
 		if p.Name == "ctx" || p.Type == "context.Context" {
-			hasCtx = true
 			p.Name = "ctx"
 		}
 
 		parts = append(parts, fmt.Sprintf("%s %s", p.Name, p.GoFullType()))
 	}
 
-	if !hasCtx {
+	if m.injectContext() {
 		parts = append([]string{"ctx context.Context"}, parts...)
 	}
 
 	return strings.Join(parts, ", ")
 }
 
+// injectContext reports whether a ctx parameter is prepended to the declared parameters of the method,
+// expr methods only build an expression and never get one
+func (m Method) injectContext() bool {
+	return !m.hasContext() && m.contextParam != contextOmit && m.SQL.Expr == ""
+}
+
+// ctx returns the context expression used by the method body
+func (m Method) ctx() string {
+	if m.timeout > 0 {
+		return "ctx"
+	}
+	return m.parentCtx()
+}
+
+// parentCtx returns the context of the method call, the parent of the context of its timeout
+func (m Method) parentCtx() string {
+	if !m.hasContext() && m.contextParam == contextOmit {
+		return "context.Background()"
+	}
+	return "ctx"
+}
+
+// withTimeout returns the statements deriving the ctx of the query with the method timeout
+func (m Method) withTimeout() string {
+	if m.timeout <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(`ctx, cancel := context.WithTimeout(%s, %s)
+defer cancel()
+
+`, m.parentCtx(), durationLiteral(m.timeout))
+}
+
+// durationLiteral formats the duration as a Go expression, e.g. 3 * time.Second
+func durationLiteral(d time.Duration) string {
+	for _, unit := range []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	} {
+		if d%unit.d == 0 {
+			return fmt.Sprintf("%d * %s", d/unit.d, unit.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", d)
+}
+
 // ResultString formats method return values as a string for code generation
 func (m Method) ResultString() string {
-	if m.SQL.Raw != "" {
+	if m.SQL.finish() {
 		var rets []string
 		for _, r := range m.Result {
 			rets = append(rets, r.GoFullType())
@@ -308,79 +817,1010 @@ func (m Method) ResultString() string {
 
 		return strings.Join(rets, ", ")
 	}
+	if m.SQL.Expr != "" {
+		return "clause.Expression"
+	}
 	return fmt.Sprintf("%sInterface[T]", m.Interface.IfaceName)
 }
 
-// Body generates the method body code for templates
-func (m Method) Body() string {
-	if m.SQL.Raw != "" {
-		return m.finishMethodBody()
+// Body generates the method body code for templates, the errors of rendering its SQL templates fail the
+// execution of the template
+func (m Method) Body() (string, error) {
+	body, err := m.body()
+	if err != nil || !m.traced() {
+		return body, err
 	}
-	return m.chainMethodBody()
+
+	results := "err"
+	if len(m.Result) == 2 {
+		results = "result, err"
+	}
+	return fmt.Sprintf(`trace := field.NewQueryTrace(%s, %q)
+%s := func() (%s) {
+%s
+}()
+trace.End(err)
+return %[3]s`, m.hook, m.Interface.Name+"."+m.Name, results, m.ResultString(), body), nil
 }
 
-// processSQL processes SQL template strings and returns formatted SQL snippet
-func (m Method) processSQL(sql string) string {
-	sqlSnippet, err := RenderSQLTemplate(sql)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to parsing SQL template for %s.%s %q: %v", m.Interface.Name, m.Name, m.SQL, err))
+// traced reports whether the query of the method is traced by the query hook, the body runs in a closure
+// so the hook gets the error of the method; iterators aren't traced
+func (m Method) traced() bool {
+	if m.hook == "" || !m.SQL.finish() {
+		return false
 	}
+	elem, callback := m.stream()
+	return elem == "" || callback != ""
+}
 
-	return sqlSnippet
+// traceStart returns the statement starting the trace of the query with its SQL and args
+func (m Method) traceStart(sql, args string) string {
+	if !m.traced() {
+		return ""
+	}
+	if m.ctx() != "ctx" {
+		return fmt.Sprintf("trace.Start(%s, %s, %s)\n", m.ctx(), sql, args)
+	}
+	return fmt.Sprintf("ctx = trace.Start(ctx, %s, %s)\n", sql, args)
 }
 
-// finishMethodBody generates method body for finishing SQL operations that return data
-func (m Method) finishMethodBody() string {
-	sqlSnippet := m.processSQL(m.SQL.Raw)
+// body generates the method body of the query
+func (m Method) body() (string, error) {
+	// iterators run their query when ranged over, the timeout starts in the iterator function instead
+	if elem, callback := m.stream(); elem != "" && callback == "" {
+		return m.finishMethodBody()
+	}
 
-	if len(m.Result) == 1 {
-		return fmt.Sprintf(`%s
-return e.Exec(ctx, sb.String(), params...)`, sqlSnippet)
+	var body string
+	var err error
+	switch {
+	case m.SQL.Raw != "":
+		body, err = m.finishMethodBody()
+	case m.SQL.Insert != "":
+		m.SQL.Raw = m.insertSQL()
+		body, err = m.finishMethodBody()
+	case m.SQL.Update != "", m.SQL.Delete != "", m.SQL.Count != "":
+		body, err = m.execMethodBody()
+	case m.SQL.Expr != "":
+		return m.exprMethodBody()
+	default:
+		return m.chainMethodBody()
 	}
+	return m.withTimeout() + body, err
+}
 
-	return fmt.Sprintf(`%s
-var result %s
-err := e.Raw(sb.String(), params...).Scan(ctx, &result)
-return result, err`, sqlSnippet, m.Result[0].GoFullType())
+// expandVars expands the ${name} template vars of the method's SQL templates and fragments, see
+// genconfig.Config.TemplateVars
+func (m *Method) expandVars(vars map[string]string) error {
+	for _, tmpl := range []*string{&m.SQL.Raw, &m.SQL.Where, &m.SQL.Select, &m.SQL.Expr, &m.SQL.Update, &m.SQL.Delete, &m.SQL.Count, &m.SQL.Insert} {
+		expanded, offset, err := expandTemplateVars(*tmpl, vars)
+		if err != nil {
+			line := strings.Count((*tmpl)[:offset], "\n") + 1
+			col := offset - strings.LastIndex((*tmpl)[:offset], "\n")
+			if pos := m.sqlPosition(*tmpl, line, col); pos.IsValid() {
+				return fmt.Errorf("%s: %v", pos, err)
+			}
+			return fmt.Errorf("method %s.%s: %v", m.Interface.Name, m.Name, err)
+		}
+		*tmpl = expanded
+	}
+	for name, fragment := range m.fragments {
+		expanded, _, err := expandTemplateVars(fragment, vars)
+		if err != nil {
+			return fmt.Errorf("fragment %q of %s.%s: %v", name, m.Interface.Name, m.Name, err)
+		}
+		m.fragments[name] = expanded
+	}
+	return nil
 }
 
-// chainMethodBody generates method body for chaining SQL operations that return interface
-func (m Method) chainMethodBody() string {
-	switch {
-	case m.SQL.Select != "":
-		return fmt.Sprintf(`%s
+// filesWithConfig returns the sorted paths of the files declaring a config
+func (g *Generator) filesWithConfig() []string {
+	filesWithCfg := []string{}
+	for pth, file := range g.Files {
+		if file.Config != nil {
+			filesWithCfg = append(filesWithCfg, pth)
+		}
+	}
+	sort.Strings(filesWithCfg)
+	return filesWithCfg
+}
 
-e.Select(sb.String(), params...)
+// applyConfigs appends the configs of the files with configs applying to the file to its applicable configs,
+// the closest first, and returns the output path of the file and the paths of the config files
+func (g *Generator) applyConfigs(file *File, filesWithCfg []string) (outPath string, configFiles []string) {
+	outPath, configFiles = g.outPath, []string{}
+	inheritStop := "" // directory of the closest config that doesn't inherit parent configs
+	for i := len(filesWithCfg) - 1; i >= 0; i-- {
+		prefixPth := filesWithCfg[i]
+		curFile := g.Files[filesWithCfg[i]]
+		cfgDir := filepath.Dir(filesWithCfg[i])
+		if !curFile.Config.FileLevel {
+			prefixPth = cfgDir
+		}
 
-return e`, m.processSQL(m.SQL.Select))
-	case m.SQL.Where != "":
-		return fmt.Sprintf(`%s
+		if inheritStop != "" && len(cfgDir) < len(inheritStop) {
+			continue
+		}
+		if curFile.Config.Scope == configScopePackage && filepath.Dir(file.inputPath) != cfgDir {
+			continue
+		}
 
-e.Where(clause.Expr{SQL: sb.String(), Vars: params})
+		if strings.HasPrefix(file.inputPath, prefixPth) {
+			if curFile.Config.NoInherit && inheritStop == "" {
+				inheritStop = cfgDir
+			}
+			if outPath == defaultOutPath {
+				outPath = g.Files[filesWithCfg[i]].Config.OutPath
+			}
 
-return e`, m.processSQL(m.SQL.Where))
+			cfg := g.Files[filesWithCfg[i]].Config
+			file.applicableConfigs = append(file.applicableConfigs, cfg)
+			configFiles = append(configFiles, filesWithCfg[i])
+			mergeImports(&file.Imports, g.Files[filesWithCfg[i]].Imports)
+		}
 	}
-	return ""
+	return outPath, configFiles
 }
 
-// parseFieldList converts AST field list to parameter slice for method signatures
-func (p *File) parseFieldList(fields *ast.FieldList) []Param {
-	if fields == nil {
-		return nil
+// prepareMethods sets up the methods of the interfaces with the options of the applicable configs: the
+// fragments, functions and placeholders of their SQL templates, their context policy and query hook; and
+// expands the template vars and ordinal placeholders of the templates
+func (p *File) prepareMethods() error {
+	contextParam := p.contextParam()
+	if !slices.Contains(contextParams, contextParam) {
+		return fmt.Errorf("invalid context policy %q, must be one of %s", contextParam, strings.Join(contextParams, ", "))
 	}
-
-	var params []Param
-	for _, field := range fields.List {
-		names := field.Names
-		if len(names) == 0 {
-			names = []*ast.Ident{{Name: ""}}
-		}
-
-		for _, n := range names {
-			params = append(params, Param{
-				Name: n.Name,
-				Type: p.parseFieldType(field.Type, "", false),
-			})
+	fragments, funcs, vars := p.sqlFragments(), p.sqlFuncs(), p.templateVars()
+	statementsTx := !slices.ContainsFunc(p.applicableConfigs, func(cfg *genconfig.Config) bool { return cfg.SkipMultiStatementTransaction })
+	placeholderPrefix, strictPlaceholders := p.placeholderPrefix(), p.strictPlaceholders()
+	stripComments, dialect := p.stripComments(), p.dialect()
+	hook := p.queryHook()
+	for _, iface := range p.Interfaces {
+		for _, m := range iface.Methods {
+			m.fragments = maps.Clone(fragments)
+			maps.Copy(m.fragments, iface.fragments)
+			m.funcs = funcs
+			m.placeholderPrefix, m.strictPlaceholders = placeholderPrefix, strictPlaceholders
+			m.stripComments = stripComments
+			m.contextParam = contextParam
+			m.statementsTx = statementsTx
+			m.hook = hook
+			if err := m.expandVars(vars); err != nil {
+				return err
+			}
+			if err := m.bindOrdinals(dialect); err != nil {
+				return err
+			}
+			if m.contextParam == contextRequire && !m.hasContext() && m.SQL.Expr == "" {
+				return fmt.Errorf("method %s.%s must declare a ctx context.Context parameter", iface.Name, m.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// bindOrdinals binds the hand-written ordinal placeholders of the method's SQL templates in the convention
+// of the dialect to its parameters, see genconfig.Config.Dialect
+func (m *Method) bindOrdinals(dialect string) error {
+	if dialect == "" {
+		return nil
+	}
+	var params []string
+	for _, p := range m.Params {
+		if p.Name != "ctx" && p.Type != "context.Context" {
+			params = append(params, p.Name)
+		}
+	}
+	for _, tmpl := range []*string{&m.SQL.Raw, &m.SQL.Where, &m.SQL.Select, &m.SQL.Expr, &m.SQL.Update, &m.SQL.Delete, &m.SQL.Count, &m.SQL.Insert} {
+		bound, offset, err := bindOrdinals(*tmpl, dialect, m.placeholderPrefix, params)
+		if err != nil {
+			line := strings.Count((*tmpl)[:offset], "\n") + 1
+			col := offset - strings.LastIndex((*tmpl)[:offset], "\n")
+			if pos := m.sqlPosition(*tmpl, line, col); pos.IsValid() {
+				return fmt.Errorf("%s: %v", pos, err)
+			}
+			return fmt.Errorf("method %s.%s: %v", m.Interface.Name, m.Name, err)
+		}
+		*tmpl = bound
+	}
+	return nil
+}
+
+// forVarsRegexp matches the variables declared by the {{for}} directives of SQL templates
+var forVarsRegexp = regexp.MustCompile(`\{\{\s*for\s+([\w\s,]+?)\s*:=\s*range\b`)
+
+// includeRegexp matches the names of the fragments included by SQL templates
+var includeRegexp = regexp.MustCompile(`\{\{\s*include\s+"([^"]+)"\s*\}\}`)
+
+// validateParams checks the placeholders of the method's SQL templates reference its parameters, the
+// variables of {{for}} loops or, for @@ident placeholders, string constants, and that its parameters are
+// used by the generated code, in placeholders or in the conditions of directives
+func (p *File) validateParams(m *Method) error {
+	type source struct{ tmpl, fragment string }
+	var sources []source
+	var include func(tmpl, fragment string)
+	include = func(tmpl, fragment string) {
+		if tmpl == "" || slices.ContainsFunc(sources, func(s source) bool { return s.fragment != "" && s.fragment == fragment }) {
+			return
+		}
+		tmpl = stripSQLComments(tmpl)
+		sources = append(sources, source{tmpl, fragment})
+		for _, match := range includeRegexp.FindAllStringSubmatch(tmpl, -1) {
+			include(m.fragments[match[1]], match[1])
+		}
+	}
+	for _, tmpl := range m.SQL.templates() {
+		include(tmpl, "")
+	}
+
+	defined := map[string]bool{}
+	for _, param := range m.Params {
+		defined[param.Name] = true
+	}
+	for _, src := range sources {
+		for _, match := range forVarsRegexp.FindAllStringSubmatch(src.tmpl, -1) {
+			for _, name := range strings.Split(match[1], ",") {
+				defined[strings.TrimSpace(name)] = true
+			}
+		}
+	}
+
+	isStringConst := func(name string) bool {
+		expr, err := parser.ParseExpr(name)
+		return err == nil && strLitValue(p.resolveValue(expr)) != nil
+	}
+	for _, src := range sources {
+		_, placeholders, _ := scanPlaceholders(src.tmpl, m.placeholderPrefix)
+		for _, ph := range placeholders {
+			prefix := cmp.Or(m.placeholderPrefix, defaultPlaceholderPrefix)
+			root, _, _ := strings.Cut(ph.Name, ".")
+			var msg string
+			switch {
+			case ph.Expr:
+				// identifiers of expressions may be packages or builtins, the compiler checks them, and
+				// the $index, $first and $last loop metadata are checked when parsing the template
+				if _, err := parser.ParseExpr(strings.ReplaceAll(ph.Name, "$", "")); err != nil {
+					msg = fmt.Sprintf("placeholder %s of %s.%s is an invalid Go expression: %v", ph.text(prefix), m.Interface.Name, m.Name, err)
+				}
+			case defined[root] || ph.Ident && root == "table":
+			case ph.Ident && isStringConst(ph.Name):
+			default:
+				msg = fmt.Sprintf("placeholder %s of %s.%s references undefined parameter %s", ph.text(prefix), m.Interface.Name, m.Name, root)
+			}
+			if msg == "" {
+				continue
+			}
+
+			if src.fragment != "" {
+				return fmt.Errorf("%s in fragment %q", msg, src.fragment)
+			}
+			line := strings.Count(src.tmpl[:ph.Offset], "\n") + 1
+			col := ph.Offset - strings.LastIndex(src.tmpl[:ph.Offset], "\n")
+			if pos := m.sqlPosition(src.tmpl, line, col); pos.IsValid() {
+				return fmt.Errorf("%s: %s", pos, msg)
+			}
+			return errors.New(msg)
+		}
+	}
+
+	used := m.usedNames()
+	for _, param := range m.Params {
+		if used != nil && param.Name != "" && param.Name != "_" && param.Name != "ctx" && param.Type != "context.Context" && !used[param.Name] {
+			return fmt.Errorf("parameter %s of %s.%s is never used by its SQL template", param.Name, m.Interface.Name, m.Name)
+		}
+	}
+	return nil
+}
+
+// usedNames returns the identifiers referenced by the generated method body, selected names excluded,
+// e.g. user of user.Name; it returns nil if the body can't be generated, its error is reported by the
+// generation of the method
+func (m Method) usedNames() map[string]bool {
+	body, err := m.Body()
+	if err != nil {
+		return nil
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package gen\nfunc _() {\n"+body+"\n}", 0)
+	if err != nil {
+		return nil
+	}
+	used := map[string]bool{}
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			ast.Inspect(n.X, visit)
+			return false
+		case *ast.Ident:
+			used[n.Name] = true
+		}
+		return true
+	}
+	ast.Inspect(file, visit)
+	return used
+}
+
+// processSQL processes SQL template strings and returns formatted SQL snippet
+func (m Method) processSQL(sql string) (string, error) {
+	return m.renderSQL(sql, m.funcs)
+}
+
+// renderSQL renders the SQL template of the method into the Go code building its SQL and params, calling
+// the functions of funcs in conditions; errors are reported at the position of the template
+func (m Method) renderSQL(sql string, funcs map[string]string) (string, error) {
+	sqlSnippet, err := renderSQLTemplate(sql, sqlTemplateOptions{
+		fragments: m.fragments,
+		funcs:     funcs,
+		prefix:    m.placeholderPrefix,
+		strict:    m.strictPlaceholders,
+
+		stripComments: m.stripComments,
+	})
+	if err != nil {
+		var tmplErr *SQLTemplateError
+		if errors.As(err, &tmplErr) {
+			if pos := m.sqlPosition(sql, tmplErr.Line, tmplErr.Column); pos.IsValid() {
+				return "", fmt.Errorf("%s: %s", pos, tmplErr.Msg)
+			}
+		}
+		return "", fmt.Errorf("Failed to parsing SQL template for %s.%s %q: %v", m.Interface.Name, m.Name, m.SQL, err)
+	}
+	return sqlSnippet, nil
+}
+
+// sqlPosition returns the source position of a line and column of the method's SQL template sql, found
+// in the lines of the doc comment or in its sqlfile, or an invalid position if it can't be found
+func (m Method) sqlPosition(sql string, line, col int) token.Position {
+	if m.sqlFile != "" {
+		return token.Position{Filename: m.sqlFile, Line: line, Column: col}
+	}
+
+	tmplLines := strings.Split(sql, "\n")
+	if line < 1 || line > len(tmplLines) {
+		return token.Position{}
+	}
+
+	for start := 0; start+len(tmplLines) <= len(m.docLines); start++ {
+		offsets := make([]int, 0, len(tmplLines)) // offsets of the template lines in the doc lines
+		for i, tmplLine := range tmplLines {
+			idx := strings.Index(m.docLines[start+i].text, strings.TrimRight(tmplLine, " \t"))
+			if idx < 0 {
+				break
+			}
+			offsets = append(offsets, idx)
+		}
+		if len(offsets) == len(tmplLines) {
+			pos := m.docLines[start+line-1].pos
+			pos.Column += offsets[line-1] + col - 1
+			pos.Offset += offsets[line-1] + col - 1
+			return pos
+		}
+	}
+	return token.Position{}
+}
+
+// finishMethodBody generates method body for finishing SQL operations that return data
+func (m Method) finishMethodBody() (string, error) {
+	if values, _ := m.valuesBatch(); values != nil {
+		return m.batchMethodBody(values)
+	}
+	if statements := m.statements(); statements != nil {
+		return m.statementsMethodBody(statements)
+	}
+
+	sqlSnippet, err := m.processSQL(m.SQL.Raw)
+	if err != nil {
+		return "", err
+	}
+	sqlSnippet += m.traceStart("sb.String()", "params")
+
+	if elem, callback := m.stream(); elem != "" {
+		return m.streamMethodBody(sqlSnippet, elem, callback), nil
+	}
+
+	if len(m.Result) == 1 {
+		return fmt.Sprintf(`%s
+return e.Exec(%s, sb.String(), params...)`, sqlSnippet, m.ctx()), nil
+	}
+
+	switch m.execResult() {
+	case execRowsAffected:
+		rowsAffected := "tx.RowsAffected"
+		if m.Result[0].Type == "int" {
+			rowsAffected = "int(tx.RowsAffected)"
+		}
+		return fmt.Sprintf(`%s
+var r T
+tx := e.db.WithContext(%s).Model(r).Exec(sb.String(), params...)
+return %s, tx.Error`, sqlSnippet, m.ctx(), rowsAffected), nil
+	case execLastInsertID, execSQLResult:
+		// gorm doesn't expose the sql.Result, the statement is built in dry run mode and executed with its conn pool
+		code := fmt.Sprintf(`%s
+var r T
+tx := e.db.WithContext(%[2]s).Session(&gorm.Session{DryRun: true}).Model(r).Exec(sb.String(), params...)
+if tx.Error != nil {
+	return %[3]s, tx.Error
+}
+`, sqlSnippet, m.ctx(), zeroValue(m.Result[0].Type))
+		if m.execResult() == execSQLResult {
+			return code + fmt.Sprintf(`return tx.Statement.ConnPool.ExecContext(%s, tx.Statement.SQL.String(), tx.Statement.Vars...)`, m.ctx()), nil
+		}
+		code += fmt.Sprintf(`result, err := tx.Statement.ConnPool.ExecContext(%s, tx.Statement.SQL.String(), tx.Statement.Vars...)
+if err != nil {
+	return 0, err
+}
+`, m.ctx())
+		if m.Result[0].Type == "int64" {
+			return code + "return result.LastInsertId()", nil
+		}
+		return code + `id, err := result.LastInsertId()
+return int(id), err`, nil
+	}
+
+	if m.scalarQuery() {
+		return fmt.Sprintf(`%s
+var r T
+var result %s
+err := e.db.WithContext(%s).Model(r).Raw(sb.String(), params...).Row().Scan(&result)
+return result, err`, sqlSnippet, m.Result[0].GoFullType(), m.ctx()), nil
+	}
+
+	return fmt.Sprintf(`%s
+var result %s
+err := e.Raw(sb.String(), params...).Scan(%s, &result)
+return result, err`, sqlSnippet, m.Result[0].GoFullType(), m.ctx()), nil
+}
+
+// scalarQueryRegexp matches the templates selecting a single COUNT(...) or EXISTS(...) value, in the first
+// branch of a leading {{if dialect}} block of dialect variants
+var scalarQueryRegexp = regexp.MustCompile(`(?is)^(?:\{\{\s*if\s+dialect\b[^}]*\}\}\s*)?SELECT\s+(?:COUNT|EXISTS)\s*\(`)
+
+// scalarQuery reports whether the raw SQL method returns the count or the existence selected by its
+// template as (int64, error), (int, error) or (bool, error), scanned directly from the row
+func (m Method) scalarQuery() bool {
+	if m.SQL.Raw == "" || len(m.Result) != 2 || m.execResult() != "" {
+		return false
+	}
+	switch m.Result[0].Type {
+	case "int64", "int", "bool":
+		return scalarQueryRegexp.MatchString(strings.TrimSpace(stripSQLComments(m.SQL.Raw)))
+	}
+	return false
+}
+
+// streamRegexp matches the iter.Seq2[T, error] result of methods streaming their rows
+var streamRegexp = regexp.MustCompile(`^iter\.Seq2\[(.+), error\]$`)
+
+// callbackRegexp matches the func(T) error callback parameter of methods streaming their rows
+var callbackRegexp = regexp.MustCompile(`^func\((.+)\) error$`)
+
+// stream returns the type of the rows streamed by a raw SQL method, from its iter.Seq2[T, error] result or
+// its func(T) error callback parameter, and the name of the callback parameter; elem is empty when the
+// method doesn't stream its rows
+func (m Method) stream() (elem, callback string) {
+	if m.SQL.Raw == "" || len(m.Result) != 1 {
+		return "", ""
+	}
+	if match := streamRegexp.FindStringSubmatch(m.Result[0].Type); match != nil {
+		return match[1], ""
+	}
+	if m.Result[0].Type == "error" {
+		for _, param := range m.Params {
+			if match := callbackRegexp.FindStringSubmatch(param.Type); match != nil && !strings.Contains(match[1], ",") {
+				return match[1], param.Name
+			}
+		}
+	}
+	return "", ""
+}
+
+// streamMethodBody generates method body for methods streaming their rows, each row is scanned when it's
+// read, either yielded to the iterator or passed to the callback, so the rows are never loaded at once
+func (m Method) streamMethodBody(sqlSnippet, elem, callback string) string {
+	if callback != "" {
+		return fmt.Sprintf(`%s
+
+rows, err := e.Raw(sb.String(), params...).Rows(%[2]s)
+if err != nil {
+	return err
+}
+defer rows.Close()
+
+db := e.db.WithContext(%[2]s)
+for rows.Next() {
+	var row %[3]s
+	if err := db.ScanRows(rows, &row); err != nil {
+		return err
+	}
+	if err := %[4]s(row); err != nil {
+		return err
+	}
+}
+return rows.Err()`, sqlSnippet, m.ctx(), elem, callback)
+	}
+
+	return fmt.Sprintf(`%s
+
+return func(yield func(%[3]s, error) bool) {
+	%[4]srows, err := e.Raw(sb.String(), params...).Rows(%[2]s)
+	if err != nil {
+		var zero %[3]s
+		yield(zero, err)
+		return
+	}
+	defer rows.Close()
+
+	db := e.db.WithContext(%[2]s)
+	for rows.Next() {
+		var row %[3]s
+		if err := db.ScanRows(rows, &row); err != nil {
+			yield(row, err)
+			return
+		}
+		if !yield(row, nil) {
+			return
+		}
+	}
+	if err := rows.Err(); err != nil {
+		var zero %[3]s
+		yield(zero, err)
+	}
+}`, sqlSnippet, m.ctx(), elem, m.withTimeout())
+}
+
+// Execution metadata returned by finish methods instead of scanned rows, see Method.execResult
+const (
+	execRowsAffected = "rowsAffected"
+	execLastInsertID = "lastInsertId"
+	execSQLResult    = "sql.Result"
+)
+
+// execResult returns the execution metadata returned by the finish method: the rows affected or the
+// last insert id of a (rowsAffected int64, err error) or (lastInsertId int64, err error) signature, or the
+// sql.Result of a (sql.Result, error) signature; it returns "" for methods scanning rows or returning an error
+func (m Method) execResult() string {
+	if m.SQL.Raw == "" && m.SQL.Insert == "" || len(m.Result) != 2 {
+		return ""
+	}
+	switch r := m.Result[0]; {
+	case r.Type == "sql.Result":
+		return execSQLResult
+	case r.Type != "int64" && r.Type != "int":
+		return ""
+	case r.Name == execRowsAffected:
+		return execRowsAffected
+	case r.Name == execLastInsertID:
+		return execLastInsertID
+	}
+	return ""
+}
+
+// zeroValue returns the zero value of the int64, int or sql.Result type of a result
+func zeroValue(typ string) string {
+	if typ == "sql.Result" {
+		return "nil"
+	}
+	return "0"
+}
+
+// UsesDB reports whether methods of the interface run statements with the *gorm.DB of its implementation,
+// to return execution metadata or scan streamed rows
+func (i Interface) UsesDB() bool {
+	return slices.ContainsFunc(i.Methods, func(m *Method) bool {
+		elem, _ := m.stream()
+		values, _ := m.valuesBatch()
+		return m.execResult() != "" || elem != "" || m.scalarQuery() || m.statementsTx && (m.statements() != nil || values != nil)
+	})
+}
+
+// chainMethodBody generates method body for chaining SQL operations that return interface
+func (m Method) chainMethodBody() (string, error) {
+	switch {
+	case m.SQL.Select != "":
+		sqlSnippet, err := m.processSQL(m.SQL.Select)
+		return fmt.Sprintf(`%s
+
+e.Select(sb.String(), params...)
+
+return e`, sqlSnippet), err
+	case m.SQL.Where != "":
+		sqlSnippet, err := m.processSQL(m.SQL.Where)
+		return fmt.Sprintf(`%s
+
+e.Where(clause.Expr{SQL: sb.String(), Vars: params})
+
+return e`, sqlSnippet), err
+	}
+	return "", nil
+}
+
+// exprMethodBody generates method body for expr methods, returning the rendered template as an expression
+// to combine with other conditions, e.g. in Where or Having
+func (m Method) exprMethodBody() (string, error) {
+	sqlSnippet, err := m.processSQL(m.SQL.Expr)
+	return fmt.Sprintf(`%s
+
+return clause.Expr{SQL: sb.String(), Vars: params}`, sqlSnippet), err
+}
+
+// execMethodBody generates method body for update(...), delete(...) and count(...) methods, which run the
+// statement with gorm and return the rows affected or the count
+func (m Method) execMethodBody() (string, error) {
+	var snippet, exec string
+	switch {
+	case m.SQL.Update != "":
+		set, where, _ := splitUpdateSQL(m.SQL.Update)
+		whereSnippet, err := m.processSQL(where)
+		if err != nil {
+			return "", err
+		}
+		setSnippet, err := m.processSQL(set)
+		if err != nil {
+			return "", err
+		}
+		snippet = fmt.Sprintf(`var where clause.Expr
+{
+%s
+where = clause.Expr{SQL: sb.String(), Vars: params}
+}
+
+%s%s`, whereSnippet, setSnippet, m.traceStart(`sb.String()+" WHERE "+where.SQL`, "slices.Concat(params, where.Vars)"))
+		exec = fmt.Sprintf("e.Where(where).Set(field.Assignments(sb.String(), params...)).Update(%s)", m.ctx())
+	case m.SQL.Delete != "":
+		sqlSnippet, err := m.processSQL(m.SQL.Delete)
+		if err != nil {
+			return "", err
+		}
+		snippet = sqlSnippet + m.traceStart("sb.String()", "params")
+		exec = fmt.Sprintf("e.Where(clause.Expr{SQL: sb.String(), Vars: params}).Delete(%s)", m.ctx())
+	case m.SQL.Count != "":
+		sqlSnippet, err := m.processSQL(m.SQL.Count)
+		if err != nil {
+			return "", err
+		}
+		snippet = sqlSnippet + m.traceStart("sb.String()", "params")
+		exec = fmt.Sprintf(`e.Where(clause.Expr{SQL: sb.String(), Vars: params}).Count(%s, "*")`, m.ctx())
+	}
+
+	if len(m.Result) == 1 {
+		return fmt.Sprintf(`%s
+
+_, err := %s
+return err`, snippet, exec), nil
+	}
+	return fmt.Sprintf(`%s
+
+return %s`, snippet, exec), nil
+}
+
+// splitUpdateSQL splits the template of an update(...) method at its last WHERE keyword outside of
+// directives, quotes and parentheses, into the SET list and the WHERE condition
+func splitUpdateSQL(tmpl string) (set, where string, ok bool) {
+	at, depth := -1, 0
+	var quote byte
+	for i := 0; i < len(tmpl); i++ {
+		switch c := tmpl[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case strings.HasPrefix(tmpl[i:], "{{"):
+			if end := strings.Index(tmpl[i:], "}}"); end >= 0 {
+				i += end + 1
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && len(tmpl)-i >= 5 && strings.EqualFold(tmpl[i:i+5], "WHERE") &&
+			(i == 0 || !isWordChar(tmpl[i-1])) && (i+5 == len(tmpl) || !isWordChar(tmpl[i+5])):
+			at = i
+		}
+	}
+	if at < 0 {
+		return tmpl, "", false
+	}
+	return strings.TrimSpace(tmpl[:at]), strings.TrimSpace(tmpl[at+5:]), true
+}
+
+// splitStatements splits the template at its ; separators outside of directives, quotes and comments, into
+// its statements that aren't empty or only made of comments
+func splitStatements(tmpl string) (statements []string) {
+	stripped := stripSQLComments(tmpl)
+	var start int
+	var quote byte
+	for i := 0; i <= len(stripped); i++ {
+		switch {
+		case i == len(stripped) || quote == 0 && stripped[i] == ';':
+			if strings.TrimSpace(stripped[start:i]) != "" {
+				statements = append(statements, strings.TrimSpace(tmpl[start:i]))
+			}
+			start = i + 1
+		case quote != 0:
+			if stripped[i] == quote {
+				quote = 0
+			}
+		case strings.HasPrefix(stripped[i:], "{{"):
+			if end := strings.Index(stripped[i:], "}}"); end >= 0 {
+				i += end + 1
+			}
+		case stripped[i] == '\'' || stripped[i] == '"' || stripped[i] == '`':
+			quote = stripped[i]
+		}
+	}
+	return statements
+}
+
+// statements returns the statements of a raw SQL method returning only an error, when its template has
+// more than one, they're executed one after the other, see Method.statementsMethodBody
+func (m Method) statements() []string {
+	if m.SQL.Raw == "" || len(m.Result) != 1 || m.Result[0].Type != "error" {
+		return nil
+	}
+	if elem, _ := m.stream(); elem != "" {
+		return nil
+	}
+	if statements := splitStatements(m.SQL.Raw); len(statements) > 1 {
+		return statements
+	}
+	return nil
+}
+
+// statementsMethodBody generates method body for methods executing several statements, in a transaction
+// unless genconfig.Config.SkipMultiStatementTransaction is set
+func (m Method) statementsMethodBody(statements []string) (string, error) {
+	exec := "e"
+	if m.statementsTx {
+		exec = "q"
+	}
+
+	var b strings.Builder
+	for _, stmt := range statements {
+		sqlSnippet, err := m.processSQL(stmt)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, `{
+%s
+if err := %s.Exec(%s, sb.String(), params...); err != nil {
+	return err
+}
+}
+`, sqlSnippet+m.traceStart("sb.String()", "params"), exec, m.ctx())
+	}
+
+	if !m.statementsTx {
+		return b.String() + "return nil", nil
+	}
+	return fmt.Sprintf(`return e.db.WithContext(%s).Transaction(func(tx *gorm.DB) error {
+q := gorm.G[T](tx)
+%sreturn nil
+})`, m.ctx(), b.String()), nil
+}
+
+// valuesDirectiveRegexp matches the {{values ...}} directives of SQL templates
+var valuesDirectiveRegexp = regexp.MustCompile(`{{\s*(values\s[^}]*?)\s*}}`)
+
+// valuesBatch returns the {{values}} directive of the raw SQL method with a batch size, nil when the method
+// executes its statement at once; a template can only batch one slice
+func (m Method) valuesBatch() (*ValuesNode, error) {
+	if m.SQL.Raw == "" {
+		return nil, nil
+	}
+	var batch *ValuesNode
+	for _, match := range valuesDirectiveRegexp.FindAllStringSubmatch(stripSQLComments(m.SQL.Raw), -1) {
+		values, err := parseValues(match[1])
+		if err != nil || values.Batch == 0 {
+			continue
+		}
+		if batch != nil {
+			return nil, errors.New("only one {{values}} directive can have a batch size")
+		}
+		batch = values
+	}
+	return batch, nil
+}
+
+// batchMethodBody generates method body for methods executing their statement once per chunk of the slice
+// of their {{values}} directive, in a transaction unless genconfig.Config.SkipMultiStatementTransaction is
+// set; the slice parameter is shadowed by the chunk in the loop
+func (m Method) batchMethodBody(values *ValuesNode) (string, error) {
+	sqlSnippet, err := m.processSQL(m.SQL.Raw)
+	if err != nil {
+		return "", err
+	}
+	chunks := fmt.Sprintf("for %[1]s := range slices.Chunk(%[1]s, %[2]d) {\n%[3]s\n%[4]s", values.Slice, values.Batch, sqlSnippet, m.traceStart("sb.String()", "params"))
+
+	if m.execResult() != execRowsAffected {
+		exec := "e"
+		if m.statementsTx {
+			exec = "q"
+		}
+		loop := fmt.Sprintf(`%sif err := %s.Exec(%s, sb.String(), params...); err != nil {
+	return err
+}
+}
+return nil`, chunks, exec, m.ctx())
+		if !m.statementsTx {
+			return loop, nil
+		}
+		return fmt.Sprintf(`return e.db.WithContext(%s).Transaction(func(tx *gorm.DB) error {
+q := gorm.G[T](tx)
+%s
+})`, m.ctx(), loop), nil
+	}
+
+	rowsAffected := "rowsAffected"
+	if m.Result[0].Type == "int" {
+		rowsAffected = "int(rowsAffected)"
+	}
+	if !m.statementsTx {
+		return fmt.Sprintf(`var rowsAffected int64
+db := e.db.WithContext(%s)
+%svar r T
+tx := db.Model(r).Exec(sb.String(), params...)
+if tx.Error != nil {
+	return %s, tx.Error
+}
+rowsAffected += tx.RowsAffected
+}
+return %[3]s, nil`, m.ctx(), chunks, rowsAffected), nil
+	}
+	return fmt.Sprintf(`var rowsAffected int64
+err := e.db.WithContext(%s).Transaction(func(db *gorm.DB) error {
+%svar r T
+tx := db.Model(r).Exec(sb.String(), params...)
+if tx.Error != nil {
+	return tx.Error
+}
+rowsAffected += tx.RowsAffected
+}
+return nil
+})
+if err != nil {
+	return 0, err
+}
+return %s, nil`, m.ctx(), chunks, rowsAffected), nil
+}
+
+// insertSQL returns the INSERT template of an insert(...) method, with the columns of the fields of its
+// struct parameter and their placeholders
+func (m Method) insertSQL() string {
+	prefix := cmp.Or(m.placeholderPrefix, defaultPlaceholderPrefix)
+	columns, values := make([]string, len(m.insertFields)), make([]string, len(m.insertFields))
+	for i, f := range m.insertFields {
+		columns[i], values[i] = f.DBName, prefix+m.insertParam+"."+f.Name
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", m.insertTable, strings.Join(columns, ", "), strings.Join(values, ", "))
+}
+
+// expandInsert resolves the struct parameter of the insert(...) annotation of the method, e.g.
+// insert(@@table, @user.*), to the fields it writes: auto-increment, read-only and association fields are skipped
+func (p *File) expandInsert(method *Method) error {
+	args := strings.Split(method.SQL.Insert, ",")
+	if len(args) != 2 || !strings.HasSuffix(strings.TrimSpace(args[1]), ".*") {
+		return fmt.Errorf("invalid insert annotation %q, expected insert(@@table, @param.*)", method.SQL.Insert)
+	}
+	table := strings.TrimSpace(args[0])
+	name := strings.TrimLeftFunc(strings.TrimSuffix(strings.TrimSpace(args[1]), ".*"), func(r rune) bool {
+		return r != '_' && !unicode.IsLetter(r)
+	})
+
+	i := slices.IndexFunc(method.Params, func(param Param) bool { return param.Name == name })
+	if i < 0 {
+		return fmt.Errorf("insert parameter %q not found", name)
+	}
+	pkgPath, typeName := p.PackagePath, strings.TrimPrefix(method.Params[i].Type, "*")
+	if pkg, n, ok := strings.Cut(typeName, "."); ok {
+		pkgPath, typeName = p.getFullImportPath(pkg), n
+	}
+	spec, src := p.loadTypeSpec(pkgPath, typeName)
+	if spec == nil {
+		return fmt.Errorf("type %s of insert parameter %s not found", method.Params[i].Type, name)
+	}
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		return fmt.Errorf("insert parameter %s must be a struct, got %s", name, method.Params[i].Type)
+	}
+
+	method.insertTable, method.insertParam, method.insertFields = table, name, nil
+	s := src.processStructType(spec, st, src.Package)
+	for _, f := range s.ColumnFields() {
+		if creatable, _ := f.permissions(); !creatable || f.autoIncrement(s) {
+			continue
+		}
+		if f.serializer() != "" {
+			return fmt.Errorf("field %s.%s uses the %s serializer, not supported by insert(...)", s.Name, f.Name, f.serializer())
+		}
+		method.insertFields = append(method.insertFields, f)
+	}
+	if len(method.insertFields) == 0 {
+		return fmt.Errorf("insert parameter %s has no column to insert", name)
+	}
+	return nil
+}
+
+// validateScanType checks the type raw SQL rows are scanned into, T, a builtin type or one declared in a
+// non-loadable package are accepted as is, other named types must be structs, e.g. DTOs, or named scalars;
+// pointers and slices are checked with their element type; rows without struct are scanned by gorm into
+// map[string]any values, other maps aren't supported
+func (p *File) validateScanType(typ string) error {
+	elem := typ
+	for {
+		if e, ok := strings.CutPrefix(elem, "*"); ok {
+			elem = e
+		} else if e, ok := strings.CutPrefix(elem, "[]"); ok {
+			elem = e
+		} else {
+			break
+		}
+	}
+	if strings.HasPrefix(elem, "map[") {
+		if m := strings.TrimPrefix(typ, "[]"); m != "map[string]any" && m != "map[string]interface{}" {
+			return fmt.Errorf("result type %s must be map[string]any or []map[string]any", typ)
+		}
+		return nil
+	}
+	elem, _, _ = strings.Cut(elem, "[")
+	if elem == "T" || types.Universe.Lookup(elem) != nil {
+		return nil
+	}
+
+	pkgPath, typeName := p.PackagePath, elem
+	if pkg, n, ok := strings.Cut(elem, "."); ok {
+		if pkgPath, typeName = p.getFullImportPath(pkg), n; pkg == p.Package {
+			pkgPath = p.PackagePath
+		}
+	}
+	spec, _ := p.loadTypeSpec(pkgPath, typeName)
+	if spec == nil {
+		return nil
+	}
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		return nil
+	case *ast.Ident:
+		if types.Universe.Lookup(t.Name) != nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("result type %s must be T, a struct, a slice of structs or a scalar", typ)
+}
+
+// integerTypeRegexp matches the Go integer types and their pointers
+var integerTypeRegexp = regexp.MustCompile(`^\*?u?int(8|16|32|64)?$`)
+
+// autoIncrement reports whether the database generates the value of the field of the struct on insert:
+// integer primary keys unless tagged autoIncrement:false, and fields tagged autoIncrement
+func (f Field) autoIncrement(s Struct) bool {
+	tags := schema.ParseTagSetting(reflect.StructTag(f.Tag).Get("gorm"), ";")
+	if v, ok := tags["AUTOINCREMENT"]; ok {
+		return !strings.EqualFold(v, "false")
+	}
+	return integerTypeRegexp.MatchString(f.GoType) && slices.ContainsFunc(s.PrimaryFields(), func(pf Field) bool { return pf.Name == f.Name })
+}
+
+// parseFieldList converts AST field list to parameter slice for method signatures
+func (p *File) parseFieldList(fields *ast.FieldList) []Param {
+	if fields == nil {
+		return nil
+	}
+
+	var params []Param
+	for _, field := range fields.List {
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{{Name: ""}}
+		}
+
+		for _, n := range names {
+			params = append(params, Param{
+				Name: n.Name,
+				Type: p.parseFieldType(field.Type, p.Package, false),
+			})
 		}
 	}
 
@@ -392,6 +1832,26 @@ var typeMap = map[string]string{
 	"bool":      "field.Bool",
 	"[]byte":    "field.Bytes",
 	"time.Time": "field.Time",
+
+	"time.Duration": "field.Duration",
+
+	"net.IP":           "field.IP[net.IP]",
+	"net/netip.Addr":   "field.IP[netip.Addr]",
+	"net/netip.Prefix": "field.CIDR",
+
+	"github.com/google/uuid.UUID":   "field.UUID[uuid.UUID]",
+	"github.com/gofrs/uuid.UUID":    "field.UUID[uuid.UUID]",
+	"github.com/gofrs/uuid/v5.UUID": "field.UUID[uuid.UUID]",
+
+	"github.com/shopspring/decimal.Decimal":     "field.Decimal[decimal.Decimal]",
+	"github.com/shopspring/decimal.NullDecimal": "field.Decimal[decimal.NullDecimal]",
+
+	"github.com/lib/pq.StringArray":  "field.Array[string]",
+	"github.com/lib/pq.Int64Array":   "field.Array[int64]",
+	"github.com/lib/pq.Int32Array":   "field.Array[int32]",
+	"github.com/lib/pq.Float64Array": "field.Array[float64]",
+	"github.com/lib/pq.Float32Array": "field.Array[float32]",
+	"github.com/lib/pq.BoolArray":    "field.Array[bool]",
 }
 
 // Type returns the field type string for template generation
@@ -407,6 +1867,21 @@ func (f Field) Type() string {
 		}
 	}
 
+	// Postgres array columns use the array helper, e.g. []string tagged `gorm:"type:text[]"`
+	if elemType, ok := strings.CutPrefix(strings.TrimPrefix(f.GoType, "*"), "[]"); ok && strings.HasSuffix(f.columnType(), "[]") {
+		return fmt.Sprintf("field.Array[%s]", filepath.Base(elemType))
+	}
+
+	// Map fields stored as JSON objects use the map helper, e.g. field.Map[string, string]
+	if key, value, ok := mapTypes(strings.TrimPrefix(f.GoType, "*")); ok && (f.serializer() == "" || strings.EqualFold(f.serializer(), "json")) {
+		return fmt.Sprintf("field.Map[%s, %s]", key, value)
+	}
+
+	// Fields serialized as JSON or tagged `gen:"json"` use the JSON helper, unless FieldNameMap maps "json"
+	if strings.EqualFold(f.serializer(), "json") || f.NamedGoType == "json" {
+		return "field.JSON"
+	}
+
 	// Check if type implements allowed interfaces
 	var (
 		goType  = strings.TrimPrefix(f.GoType, "*")
@@ -419,6 +1894,17 @@ func (f Field) Type() string {
 		pkgName, typName = goType[:pkgIdx], goType[pkgIdx+1:]
 	}
 
+	// sql.Null[T] uses the helper of T, NULL values are handled with IsNull/IsNotNull
+	if elemType, ok := sqlNullElemType(goType); ok {
+		if mapped, ok := typeMap[elemType]; ok {
+			return mapped
+		}
+		if strings.Contains(elemType, "int") || strings.Contains(elemType, "float") {
+			return fmt.Sprintf("field.Number[%s]", elemType)
+		}
+		return fmt.Sprintf("field.Field[%s]", filepath.Base(goType))
+	}
+
 	// Handle regular field types
 	if mapped, ok := typeMap[goType]; ok {
 		return mapped
@@ -428,10 +1914,21 @@ func (f Field) Type() string {
 		return fmt.Sprintf("field.Number[%s]", goType)
 	}
 
-	if typ := loadNamedType(f.file.goModDir, f.file.getFullImportPath(pkgName), typName); typ != nil {
+	if values := f.enumValues(); len(values) > 0 {
+		return fmt.Sprintf("field.Enum[%s]", filepath.Base(goType))
+	}
+
+	if typ := f.file.loader().namedType(f.file.goModDir, f.file.getFullImportPath(pkgName), typName); typ != nil {
+		if ImplementsSerializer(typ) { // Custom serializers store values as JSON
+			return "field.JSON"
+		}
 		if ImplementsAllowedInterfaces(typ) { // For interface-implementing types, use generic Field
 			return fmt.Sprintf("field.Field[%s]", filepath.Base(goType))
 		}
+		// Defined types and aliases use the helper of their underlying type, e.g. type Email string
+		if helper := underlyingHelperType(typ, filepath.Base(goType)); helper != "" {
+			return helper
+		}
 	}
 
 	// Check if this is a relation field based on its type
@@ -445,71 +1942,753 @@ func (f Field) Type() string {
 	return fmt.Sprintf("field.Field[%s]", filepath.Base(goType))
 }
 
+// StructName returns the name of the generated helper type for template generation
+func (s Struct) StructName() string {
+	return "_" + s.Name
+}
+
+// HasField reports whether the struct has a field with the given name
+func (s Struct) HasField(name string) bool {
+	return slices.ContainsFunc(s.Fields, func(f Field) bool { return f.Name == name })
+}
+
+// TableName returns the table name of the struct, it prefers the value returned by
+// the model's TableName method and falls back to GORM's NamingStrategy
+func (s Struct) TableName() string {
+	if s.file != nil && s.file.Generator != nil {
+		dir := filepath.Dir(s.file.inputPath)
+		for pth, f := range s.file.Generator.Files {
+			if filepath.Dir(pth) == dir {
+				if name, ok := f.tableNames[s.Name]; ok {
+					return name
+				}
+			}
+		}
+	}
+
+	ns := schema.NamingStrategy{IdentifierMaxLength: 64}
+	return ns.TableName(s.Name)
+}
+
+// enumValues returns the qualified constant names declared with the field's type if it's an enum type
+func (f Field) enumValues() []string {
+	goType := strings.TrimPrefix(f.GoType, "*")
+	pkgIdx := strings.LastIndex(goType, ".")
+	if pkgIdx <= 0 {
+		return nil
+	}
+
+	pkgName, typName := goType[:pkgIdx], goType[pkgIdx+1:]
+	values := f.file.loader().enumValues(f.file.goModDir, f.file.getFullImportPath(pkgName), typName)
+	for i, v := range values {
+		values[i] = path.Base(pkgName) + "." + v
+	}
+	return values
+}
+
+// updateAssigners are the helpers with assigners that only make sense in UPDATE statements, e.g. Incr,
+// create-only fields of these helpers use field.CreateOnly
+var updateAssigners = []string{"Number", "Decimal", "Array", "String", "Bytes", "Time", "Duration"}
+
+// plainHelpers are the helpers binding values as is, their read-only, nullable and create-only fields use the
+// reusable field.ReadOnly, field.Null and field.CreateOnly helpers of the value type, the others keep their helper
+var plainHelpers = []string{"Field", "Number", "Decimal", "Enum", "String", "Bytes", "Bool", "Time"}
+
+// permissions returns whether the field can be written on create and update according to its gorm tags
+func (f Field) permissions() (creatable, updatable bool) {
+	if f.view {
+		return false, false
+	}
+
+	tags := schema.ParseTagSetting(reflect.StructTag(f.Tag).Get("gorm"), ";")
+	creatable, updatable = true, true
+	if _, ok := tags["->"]; ok {
+		creatable, updatable = false, false
+	}
+	if v, ok := tags["<-"]; ok {
+		creatable, updatable = true, true
+		if v != "<-" {
+			creatable, updatable = strings.Contains(v, "create"), strings.Contains(v, "update")
+		}
+	}
+	return creatable, updatable
+}
+
+// HelperType returns the type of the field helper in the generated struct for template generation, see Field.wrapperType
+func (f Field) HelperType() string {
+	if wrapper := f.wrapperType(); wrapper != "" {
+		return wrapper
+	}
+	return f.Type()
+}
+
+// wrapperType returns the reusable helper of a read-only, version, nullable or create-only field, e.g.
+// field.Null[time.Time] of a *time.Time field, or "" if the field uses the helper of its type
+func (f Field) wrapperType() string {
+	valueType := f.valueType()
+	if f.IsAssociation() || valueType == "" || !slices.Contains(plainHelpers, embeddedName(f.Type())) {
+		return ""
+	}
+
+	creatable, updatable := f.permissions()
+	switch {
+	case !creatable && !updatable:
+		return "field.ReadOnly[" + valueType + "]"
+	case f.isVersion():
+		return "field.Version[" + valueType + "]"
+	case f.nullable():
+		return "field.Null[" + valueType + "]"
+	case !updatable && slices.Contains(updateAssigners, embeddedName(f.Type())):
+		return "field.CreateOnly[" + valueType + "]"
+	}
+	return ""
+}
+
+// isVersion reports whether the field is the optimistic lock version of its struct, tagged with
+// `gen:"version"` or an integer field named after the VersionField of the closest config declaring one
+func (f Field) isVersion() bool {
+	if f.IsAssociation() || f.view {
+		return false
+	}
+	if f.versionTagged() {
+		return true
+	}
+	if f.file != nil {
+		for _, cfg := range f.file.applicableConfigs {
+			if cfg.VersionField != "" {
+				return cfg.VersionField == f.Name && f.isInteger()
+			}
+		}
+	}
+	return false
+}
+
+// versionTagged reports whether the field is tagged `gen:"version"`, gorm itself has no version tag
+func (f Field) versionTagged() bool {
+	return f.NamedGoType == "version"
+}
+
+// isInteger reports whether the field gets an integer field.Number helper
+func (f Field) isInteger() bool {
+	typ := f.Type()
+	return strings.HasPrefix(typ, "field.Number[") && !strings.Contains(typ, "float")
+}
+
+// durationUnit returns the storage convention of a time.Duration field, "interval" for fields tagged
+// `gorm:"type:interval"`, otherwise the DurationUnit of the closest config declaring one, "ns" by default
+func (f Field) durationUnit() string {
+	if strings.EqualFold(f.columnType(), "interval") {
+		return "interval"
+	}
+	if f.file != nil {
+		for _, cfg := range f.file.applicableConfigs {
+			if cfg.DurationUnit != "" {
+				return cfg.DurationUnit
+			}
+		}
+	}
+	return "ns"
+}
+
+// DefaultValue returns the default value declared with the gorm default tag of the field as written, e.g. 'active'
+func (f Field) DefaultValue() string {
+	if f.IsAssociation() {
+		return ""
+	}
+	return schema.ParseTagSetting(reflect.StructTag(f.Tag).Get("gorm"), ";")["DEFAULT"]
+}
+
+// nullable reports whether the field is a pointer or sql.Null* column whose helper can't set NULL,
+// unlike e.g. field.Field[sql.NullInt64]
+func (f Field) nullable() bool {
+	if !strings.HasPrefix(f.GoType, "*") && !strings.HasPrefix(f.GoType, "database/sql.Null") {
+		return false
+	}
+	elem := f.valueType()
+	return !strings.HasPrefix(elem, "sql.Null") && !strings.HasPrefix(elem, "*")
+}
+
+// valueType returns the type of the values compared and set by the field helper, e.g. int of field.Number[int],
+// or "" for helpers without a single value type like associations or custom wrapper types
+func (f Field) valueType() string {
+	switch typ := f.Type(); {
+	case typ == "field.Time":
+		return "time.Time"
+	case typ == "field.String":
+		return "string"
+	case typ == "field.Bool":
+		return "bool"
+	case typ == "field.Bytes":
+		return "[]byte"
+	case typ == "field.Duration":
+		return "time.Duration"
+	case typ == "field.CIDR":
+		return "netip.Prefix"
+	case strings.HasPrefix(typ, "field.Number["), strings.HasPrefix(typ, "field.Field["), strings.HasPrefix(typ, "field.Enum["),
+		strings.HasPrefix(typ, "field.UUID["), strings.HasPrefix(typ, "field.Decimal["), strings.HasPrefix(typ, "field.IP["):
+		return typ[strings.Index(typ, "[")+1 : len(typ)-1]
+	}
+	return ""
+}
+
+// columnSchema mirrors field.ColumnSchema
+type columnSchema struct {
+	Type      string
+	Size      int
+	Precision int
+	Scale     int
+	NotNull   bool
+}
+
+// literal renders the schema as a field.ColumnSchema composite literal with the type elided, e.g. {Size: 64}
+func (c columnSchema) literal() string {
+	var values []string
+	v := reflect.ValueOf(c)
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).IsZero() {
+			values = append(values, fmt.Sprintf("%s: %#v", v.Type().Field(i).Name, v.Field(i).Interface()))
+		}
+	}
+	return "{" + strings.Join(values, ", ") + "}"
+}
+
+// columnSchema returns the column schema declared with the type, size, precision, scale and not null gorm tags of the field
+func (f Field) columnSchema() (c columnSchema) {
+	if f.IsAssociation() {
+		return c
+	}
+
+	tags := schema.ParseTagSetting(reflect.StructTag(f.Tag).Get("gorm"), ";")
+	c.Type = tags["TYPE"]
+	c.Size, _ = strconv.Atoi(tags["SIZE"])
+	c.Precision, _ = strconv.Atoi(tags["PRECISION"])
+	c.Scale, _ = strconv.Atoi(tags["SCALE"])
+	_, notNull := tags["NOT NULL"]
+	_, notNullOld := tags["NOTNULL"]
+	c.NotNull = notNull || notNullOld
+	return c
+}
+
+// Scopes returns the scopes of the struct declared in the applicable configs, sorted by name for template generation.
+// The closest config wins when a scope is declared more than once.
+func (s Struct) Scopes() (scopes []Scope) {
+	if s.file == nil || s.TypeParams != "" {
+		return nil
+	}
+
+	for _, cfg := range s.file.applicableConfigs {
+		for key, value := range cfg.Scopes {
+			model, name, ok := strings.Cut(key, ".")
+			if !ok || model != s.Name || slices.ContainsFunc(scopes, func(e Scope) bool { return e.Name == name }) {
+				continue
+			}
+
+			scope := Scope{Name: name}
+			exprs, _ := value.([]string)
+			for _, expr := range exprs {
+				scope.Exprs = append(scope.Exprs, s.Name+"."+expr)
+			}
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Slice(scopes, func(i, j int) bool { return scopes[i].Name < scopes[j].Name })
+	return scopes
+}
+
+// Conditions returns the scope expressions joined by commas for template generation
+func (s Scope) Conditions() string {
+	return strings.Join(s.Exprs, ", ")
+}
+
+// validateVersion returns an error if the struct has more than one version field or a field tagged
+// `gen:"version"` isn't an integer, fields named after VersionField are only versions if they're integers
+func (s Struct) validateVersion() error {
+	var version *Field
+	for _, f := range s.ColumnFields() {
+		if !f.isVersion() {
+			continue
+		}
+		if version != nil {
+			return fmt.Errorf("struct %s has more than one version field: field %s and field %s", s.Name, version.describe(), f.describe())
+		}
+		if !f.isInteger() {
+			return fmt.Errorf("version field %s of struct %s must be an integer, got %s", f.describe(), s.Name, f.GoType)
+		}
+		version = &f
+	}
+	return nil
+}
+
+// validateScopes returns an error if a scope of the struct is invalid or references an unknown field
+func (s Struct) validateScopes() error {
+	for _, scope := range s.Scopes() {
+		if !token.IsIdentifier(scope.Name) {
+			return fmt.Errorf("invalid scope name %q of struct %s", scope.Name, s.Name)
+		}
+		if len(scope.Exprs) == 0 {
+			return fmt.Errorf("scope %s.%s has no conditions", s.Name, scope.Name)
+		}
+		for _, expr := range scope.Exprs {
+			name, _, _ := strings.Cut(strings.TrimPrefix(expr, s.Name+"."), ".")
+			if !s.HasField(name) {
+				return fmt.Errorf("scope %s.%s references unknown field %s", s.Name, scope.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// PrimaryFields returns the primary key fields of the struct for template generation, the fields tagged
+// with primaryKey, or the field named ID following GORM's convention
+func (s Struct) PrimaryFields() (fields []Field) {
+	for _, f := range s.ColumnFields() {
+		tags := schema.ParseTagSetting(reflect.StructTag(f.Tag).Get("gorm"), ";")
+		_, primaryKey := tags["PRIMARYKEY"]
+		_, primaryKeyOld := tags["PRIMARY_KEY"]
+		if primaryKey || primaryKeyOld {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		if i := slices.IndexFunc(s.ColumnFields(), func(f Field) bool { return f.Name == "ID" }); i >= 0 {
+			fields = append(fields, s.ColumnFields()[i])
+		}
+	}
+	return fields
+}
+
+// asView declares the struct as a database view, its fields become read-only
+func (s *Struct) asView() {
+	s.view = true
+	// Fields are shared with allStructs, update a copy
+	s.Fields = slices.Clone(s.Fields)
+	for i := range s.Fields {
+		s.Fields[i].view = true
+	}
+}
+
+// IsView reports whether the struct is a database view for template generation
+func (s Struct) IsView() bool {
+	return s.view
+}
+
+// Repository reports whether a CRUD repository is generated for the struct for template generation,
+// views, generic structs and structs without a single primary key or ByID helper have none
+func (s Struct) Repository() bool {
+	return s.file != nil && s.file.crud() && !s.view && s.TypeParams == "" && s.IDType() != "" && !s.HasField("ByID")
+}
+
+// ModelType returns the qualified type of the source struct for template generation, e.g. models.User
+func (s Struct) ModelType() string {
+	return s.file.Package + "." + s.Name
+}
+
+// CreateViewSQL returns the CREATE VIEW statement of a view declared with a SELECT statement following
+// the //gorm:view marker for template generation, or "" if none
+func (s Struct) CreateViewSQL() string {
+	if !s.view || s.viewSQL == "" {
+		return ""
+	}
+	return fmt.Sprintf("CREATE VIEW %s AS %s", s.TableName(), s.viewSQL)
+}
+
+// SoftDeleteField returns the gorm.DeletedAt field of the struct, or a zero Field if it isn't soft deletable
+func (s Struct) SoftDeleteField() Field {
+	for _, f := range s.ColumnFields() {
+		if strings.TrimPrefix(f.GoType, "*") == "gorm.io/gorm.DeletedAt" {
+			return f
+		}
+	}
+	return Field{}
+}
+
+// IDField returns the primary key field of the struct if it has exactly one, see Struct.IDType
+func (s Struct) IDField() Field {
+	if fields := s.PrimaryFields(); len(fields) == 1 {
+		return fields[0]
+	}
+	return Field{}
+}
+
+// IDType returns the type of the ByID parameter for template generation, or "" if the struct has no
+// single primary key or its helper has no value type
+func (s Struct) IDType() string {
+	if f := s.IDField(); f.Name != "" {
+		return f.valueType()
+	}
+	return ""
+}
+
+// DefaultFields returns the fields with a default value declared in their gorm tag for template generation
+func (s Struct) DefaultFields() (fields []Field) {
+	for _, f := range s.Fields {
+		if f.DefaultValue() != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// SchemaFields returns the fields with column schema tags, e.g. size or not null, for template generation
+func (s Struct) SchemaFields() (fields []Field) {
+	for _, f := range s.Fields {
+		if f.columnSchema() != (columnSchema{}) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// ColumnSchema returns the column schema tags of the field as an element of a field.ColumnSchema map for template generation
+func (f Field) ColumnSchema() string {
+	return f.columnSchema().literal()
+}
+
+// serializer returns the serializer name of the field from its gorm tag
+func (f Field) serializer() string {
+	return schema.ParseTagSetting(reflect.StructTag(f.Tag).Get("gorm"), ";")["SERIALIZER"]
+}
+
+// columnType returns the column type declared with the type gorm tag of the field, e.g. text[]
+func (f Field) columnType() string {
+	return schema.ParseTagSetting(reflect.StructTag(f.Tag).Get("gorm"), ";")["TYPE"]
+}
+
+// IsAssociation reports whether the field is a relation field based on its type
+func (f Field) IsAssociation() bool {
+	fieldType := f.Type()
+	return strings.HasPrefix(fieldType, "field.Struct[") || strings.HasPrefix(fieldType, "field.Slice[")
+}
+
 // Value returns the field value string with column name for template generation
 func (f Field) Value() string {
-	fieldType := f.Type()
-	// Check if this is a relation field based on the type
-	if strings.HasPrefix(fieldType, "field.Struct[") {
-		return fmt.Sprintf("%s{}.WithName(%q)", fieldType, f.Name)
-	} else if strings.HasPrefix(fieldType, "field.Slice[") {
-		return fmt.Sprintf("%s{}.WithName(%q)", fieldType, f.Name)
+	return f.value()
+}
+
+// AliasValue returns the field helper value qualified with the table alias variable for template generation,
+// associations and custom wrapper types are left as is
+func (f Field) AliasValue(alias string) string {
+	value := f.value()
+	if !f.IsAssociation() && strings.HasPrefix(f.Type(), "field.") {
+		value += ".WithTable(" + alias + ")"
+	}
+	return value
+}
+
+// value returns the field helper value for template generation
+func (f Field) value() string {
+	if f.IsAssociation() {
+		return fmt.Sprintf("%s{}.WithName(%q).WithMetadata(%s)", f.Type(), f.Name, f.AssociationMetadata())
+	}
+	if wrapper := f.wrapperType(); wrapper != "" {
+		return fmt.Sprintf("%s{}.WithColumn(%q)", wrapper, f.DBName)
+	}
+
+	if fieldType := f.Type(); strings.HasPrefix(fieldType, "field.Enum[") {
+		return fmt.Sprintf("%s{}.WithColumn(%q).WithValues(%s)", fieldType, f.DBName, strings.Join(f.enumValues(), ", "))
+	} else if fieldType == "field.Duration" {
+		switch unit := f.durationUnit(); unit {
+		case "interval":
+			return fmt.Sprintf("%s{}.WithColumn(%q).WithInterval()", fieldType, f.DBName)
+		case "us", "ms", "s":
+			return fmt.Sprintf("%s{}.WithColumn(%q).WithUnit(%s)", fieldType, f.DBName, durationUnitValues[unit])
+		}
 	}
 
 	// Regular field
-	return fmt.Sprintf("%s{}.WithColumn(%q)", fieldType, f.DBName)
+	return fmt.Sprintf("%s{}.WithColumn(%q)", f.Type(), f.DBName)
+}
+
+// SelectableValue returns the expression of the field used in Select(...) for template generation,
+// custom wrapper types that don't implement field.Selectable fall back to field.Field[any]
+func (f Field) SelectableValue(recv string) string {
+	if strings.HasPrefix(f.Type(), "field.") {
+		return recv + "." + f.Name
+	}
+	return fmt.Sprintf("field.Field[any]{}.WithColumn(%q)", f.DBName)
+}
+
+// ColumnValue returns the clause.Column expression of the field for template generation
+func (f Field) ColumnValue(recv string) string {
+	if strings.HasPrefix(f.Type(), "field.") {
+		return recv + "." + f.Name + ".Column()"
+	}
+	return fmt.Sprintf("clause.Column{Name: %q}", f.DBName)
+}
+
+// validateColumns returns an error if two fields of the struct resolve to the same column
+func (s Struct) validateColumns() error {
+	fields := map[string]Field{}
+	for _, f := range s.ColumnFields() {
+		if prev, ok := fields[f.DBName]; ok {
+			return fmt.Errorf("duplicate column %q in struct %s: field %s and field %s", f.DBName, s.Name, prev.describe(), f.describe())
+		}
+		fields[f.DBName] = f
+	}
+	return nil
+}
+
+// describe returns the field name with its source position if known, used in error messages
+func (f Field) describe() string {
+	if f.pos.IsValid() {
+		return fmt.Sprintf("%s (%s)", f.Name, f.pos)
+	}
+	return f.Name
+}
+
+// ColumnFields returns the non-association fields of the struct for template generation
+func (s Struct) ColumnFields() (fields []Field) {
+	for _, f := range s.Fields {
+		if !f.IsAssociation() {
+			fields = append(fields, f)
+		}
+	}
+	return fields
 }
 
 // Visit implements ast.Visitor to traverse AST nodes and extract imports, interfaces, and structs
 func (p *File) Visit(n ast.Node) (w ast.Visitor) {
 	switch n := n.(type) {
 	case *ast.ImportSpec:
-		importPath, _ := strconv.Unquote(n.Path.Value)
-		importName := path.Base(importPath)
-		if n.Name != nil {
-			importName = n.Name.Name
+		p.Imports = append(p.Imports, parseImport(n))
+	case *ast.GenDecl:
+		// The doc comment of an ungrouped type declaration is attached to the GenDecl
+		if n.Tok == token.TYPE && len(n.Specs) == 1 {
+			if ts, ok := n.Specs[0].(*ast.TypeSpec); ok && ts.Doc == nil {
+				ts.Doc = n.Doc
+			}
 		}
 
-		p.Imports = append(p.Imports, Import{
-			Name: importName,
-			Path: importPath,
-		})
-	case *ast.GenDecl:
 		if n.Tok == token.VAR {
 			for _, spec := range n.Specs {
 				if vs, ok := spec.(*ast.ValueSpec); ok {
-					if cfg := p.tryParseConfig(vs); cfg != nil {
+					cfg, err := p.tryParseConfig(vs)
+					if err != nil && p.err == nil {
+						p.err = err
+					}
+					if cfg != nil {
 						p.Config = cfg
 					}
 				}
 			}
 		}
-	case *ast.TypeSpec:
-		if data, ok := n.Type.(*ast.InterfaceType); ok {
-			p.Interfaces = append(p.Interfaces, p.processInterfaceType(n, data))
-		} else if data, ok := n.Type.(*ast.StructType); ok {
-			if s := p.processStructType(n, data, ""); len(s.Fields) > 0 {
-				p.Structs = append(p.Structs, s)
+	case *ast.FuncDecl:
+		if recv, name := parseTableNameMethod(n); recv != "" {
+			if p.tableNames == nil {
+				p.tableNames = map[string]string{}
+			}
+			p.tableNames[recv] = name
+		}
+	case *ast.TypeSpec:
+		if data, ok := n.Type.(*ast.InterfaceType); ok {
+			iface, err := p.processInterfaceType(n, data)
+			if err != nil {
+				if p.err == nil {
+					p.err = err
+				}
+				return nil
+			}
+			p.Interfaces = append(p.Interfaces, iface)
+		} else if data, ok := n.Type.(*ast.StructType); ok {
+			if s := p.processStructType(n, data, ""); len(s.Fields) > 0 {
+				p.Structs = append(p.Structs, s)
+			}
+		}
+	}
+	return p
+}
+
+// tryParseConfig attempts to parse a gorm.io/cli/gorm/genconfig.Config composite literal
+// from a package-level value spec. Returns nil if not present.
+func (p *File) tryParseConfig(vs *ast.ValueSpec) (*genconfig.Config, error) {
+	isCmdConfigType := func(expr ast.Expr) bool {
+		return p.parseFieldType(expr, "", true) == "gorm.io/cli/gorm/genconfig.Config"
+	}
+
+	for _, v := range vs.Values {
+		if cl, ok := v.(*ast.CompositeLit); ok && isCmdConfigType(cl.Type) {
+			return p.parseConfigLiteral(cl)
+		}
+	}
+	return nil, nil
+}
+
+// header returns the header of the generated file from the applicable configs, defaults to codeGenHint
+func (p *File) header() (string, error) {
+	for _, cfg := range p.applicableConfigs {
+		if cfg.HeaderFile != "" {
+			content, err := os.ReadFile(cfg.HeaderFile)
+			if err != nil {
+				return "", fmt.Errorf("failed to read header file %v, got error %v", cfg.HeaderFile, err)
+			}
+			return strings.TrimRight(string(content), "\n"), nil
+		}
+		if cfg.Header != "" {
+			return strings.TrimRight(cfg.Header, "\n"), nil
+		}
+	}
+	return codeGenHint, nil
+}
+
+// requireMarker reports whether only types annotated with the //gorm:generate marker are generated
+func (p *File) requireMarker() bool {
+	if p.Generator.optIn {
+		return true
+	}
+	for _, cfg := range p.applicableConfigs {
+		if cfg.RequireMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeOutput reports whether the file is generated into a single file together with the rest of its package
+func (p *File) mergeOutput() bool {
+	if p.Generator.singleFile {
+		return true
+	}
+	for _, cfg := range p.applicableConfigs {
+		if cfg.MergeOutput {
+			return true
+		}
+	}
+	return false
+}
+
+// OutPackage returns the package name of the generated file, defaults to the source package name
+func (p *File) OutPackage() string {
+	if p.Generator.outPackage != "" {
+		return p.Generator.outPackage
+	}
+	for _, cfg := range p.applicableConfigs {
+		if cfg.OutPackage != "" {
+			return cfg.OutPackage
+		}
+	}
+	return p.Package
+}
+
+// templateDir returns the directory of the templates overriding the defaults for the file, or "" if none
+// crud reports whether CRUD repositories are generated for the structs of the file, according to
+// the --crud flag and the CRUD option of the applicable configs
+func (p *File) crud() bool {
+	return p.Generator.crud || slices.ContainsFunc(p.applicableConfigs, func(cfg *genconfig.Config) bool { return cfg.CRUD })
+}
+
+// factories reports whether fixture factories are generated for the structs of the file, according to
+// the --factories flag and the Factories option of the applicable configs
+func (p *File) factories() bool {
+	return p.Generator.factories || slices.ContainsFunc(p.applicableConfigs, func(cfg *genconfig.Config) bool { return cfg.Factories })
+}
+
+func (p *File) templateDir() string {
+	if p.Generator.templateDir != "" {
+		return p.Generator.templateDir
+	}
+	for _, cfg := range p.applicableConfigs {
+		if cfg.TemplateDir != "" {
+			return cfg.TemplateDir
+		}
+	}
+	return ""
+}
+
+// contextParam returns the ctx parameter policy of the file's query methods, defaults to contextInject
+func (p *File) contextParam() string {
+	if p.Generator.contextParam != "" {
+		return p.Generator.contextParam
+	}
+	for _, cfg := range p.applicableConfigs {
+		if cfg.ContextParam != "" {
+			return cfg.ContextParam
+		}
+	}
+	return contextInject
+}
+
+// sqlFragments returns the SQL fragments declared in the applicable configs, keyed by name; the closest
+// config wins when a name is declared more than once
+func (p *File) sqlFragments() map[string]string {
+	fragments := map[string]string{}
+	for _, cfg := range p.applicableConfigs {
+		for name, fragment := range cfg.SQLFragments {
+			if _, ok := fragments[name]; !ok {
+				fragments[name] = fragment
+			}
+		}
+	}
+	return fragments
+}
+
+// templateVars returns the SQL snippets of ${name} template vars declared in the applicable configs,
+// keyed by name; the closest config wins when a name is declared more than once
+func (p *File) templateVars() map[string]string {
+	vars := map[string]string{}
+	for _, cfg := range p.applicableConfigs {
+		for name, value := range cfg.TemplateVars {
+			if _, ok := vars[name]; !ok {
+				vars[name] = value
 			}
 		}
 	}
-	return p
+	return vars
 }
 
-// tryParseConfig attempts to parse a gorm.io/cli/gorm/genconfig.Config composite literal
-// from a package-level value spec. Returns nil if not present.
-func (p *File) tryParseConfig(vs *ast.ValueSpec) *genconfig.Config {
-	isCmdConfigType := func(expr ast.Expr) bool {
-		return p.parseFieldType(expr, "", true) == "gorm.io/cli/gorm/genconfig.Config"
+// queryHook returns the qualified variable of the query hook declared in the applicable configs, the
+// closest config wins
+func (p *File) queryHook() string {
+	for _, cfg := range p.applicableConfigs {
+		if hook, ok := cfg.QueryHook.(string); ok && hook != "" {
+			return hook
+		}
 	}
+	return ""
+}
 
-	for _, v := range vs.Values {
-		if cl, ok := v.(*ast.CompositeLit); ok && isCmdConfigType(cl.Type) {
-			if cfg := p.parseConfigLiteral(cl); cfg != nil {
-				return cfg
+// sqlFuncs returns the qualified functions declared in the applicable configs, keyed by name; the closest
+// config wins when a name is declared more than once
+func (p *File) sqlFuncs() map[string]string {
+	funcs := map[string]string{}
+	for _, cfg := range p.applicableConfigs {
+		for name, fn := range cfg.SQLFuncs {
+			if _, ok := funcs[name]; !ok {
+				funcs[name] = fmt.Sprint(fn)
 			}
 		}
 	}
-	return nil
+	return funcs
+}
+
+// placeholderPrefix returns the prefix of the placeholders of SQL templates, the closest config wins
+func (p *File) placeholderPrefix() string {
+	for _, cfg := range p.applicableConfigs {
+		if cfg.PlaceholderPrefix != "" {
+			return cfg.PlaceholderPrefix
+		}
+	}
+	return defaultPlaceholderPrefix
+}
+
+// dialect returns the dialect SQL templates are written for, see genconfig.Config.Dialect
+func (p *File) dialect() string {
+	for _, cfg := range p.applicableConfigs {
+		if cfg.Dialect != "" {
+			return cfg.Dialect
+		}
+	}
+	return ""
+}
+
+// strictPlaceholders reports whether ambiguous placeholders of SQL templates are rejected
+func (p *File) strictPlaceholders() bool {
+	return slices.ContainsFunc(p.applicableConfigs, func(cfg *genconfig.Config) bool { return cfg.StrictPlaceholders })
+}
+
+// stripComments reports whether the comments of SQL templates are dropped, according to the
+// --strip-sql-comments flag and the StripSQLComments option of the applicable configs
+func (p *File) stripComments() bool {
+	return p.Generator.stripComments || slices.ContainsFunc(p.applicableConfigs, func(cfg *genconfig.Config) bool { return cfg.StripSQLComments })
 }
 
 func (p File) UsedTypedAPI() bool {
@@ -517,16 +2696,24 @@ func (p File) UsedTypedAPI() bool {
 }
 
 // parseConfigLiteral parses a cmd.Config composite literal into a Config value.
-func (p *File) parseConfigLiteral(cl *ast.CompositeLit) *genconfig.Config {
+func (p *File) parseConfigLiteral(cl *ast.CompositeLit) (*genconfig.Config, error) {
 	cfg := &genconfig.Config{
 		FieldTypeMap: map[any]any{},
 		FieldNameMap: map[string]any{},
+		Scopes:       map[string]any{},
+
+		TemplateFuncs: map[string]any{},
+		SQLFragments:  map[string]string{},
+		SQLFuncs:      map[string]any{},
+		TemplateVars:  map[string]string{},
+		QueryModels:   map[string]any{},
 	}
 
 	// Helper to collect filter values from a composite literal list (e.g., []any{...})
 	collect := func(val ast.Expr) (results []any) {
-		if m, ok := val.(*ast.CompositeLit); ok {
+		if m, ok := p.resolveValue(val).(*ast.CompositeLit); ok {
 			for _, el := range m.Elts {
+				el = p.resolveValue(el)
 				if s := strLit(el); s != "" {
 					results = append(results, s)
 				} else {
@@ -539,17 +2726,87 @@ func (p *File) parseConfigLiteral(cl *ast.CompositeLit) *genconfig.Config {
 
 	for _, elt := range cl.Elts {
 		kv, _ := elt.(*ast.KeyValueExpr)
+		if kv == nil {
+			return nil, fmt.Errorf("%s: genconfig.Config literal must use keyed fields", p.position(elt.Pos()))
+		}
 		keyIdent, _ := kv.Key.(*ast.Ident)
+		value := p.resolveValue(kv.Value)
 
 		switch keyIdent.Name {
 		case "OutPath":
-			cfg.OutPath = strLit(kv.Value)
+			cfg.OutPath = strLit(value)
+		case "OutPackage":
+			cfg.OutPackage = strLit(value)
+		case "Header":
+			cfg.Header = strLit(value)
+		case "HeaderFile":
+			cfg.HeaderFile = strLit(value)
+		case "TemplateDir":
+			cfg.TemplateDir = strLit(value)
+		case "ContextParam":
+			cfg.ContextParam = strLit(value)
+		case "PlaceholderPrefix":
+			if cfg.PlaceholderPrefix = strLit(value); cfg.PlaceholderPrefix != "" {
+				if err := validatePlaceholderPrefix(cfg.PlaceholderPrefix); err != nil {
+					return nil, fmt.Errorf("%s: %w", p.position(kv.Value.Pos()), err)
+				}
+			}
+		case "Dialect":
+			if cfg.Dialect = strLit(value); cfg.Dialect != "" && !slices.Contains(sqlDialects, cfg.Dialect) {
+				return nil, fmt.Errorf("%s: invalid dialect %q, must be one of %s", p.position(kv.Value.Pos()), cfg.Dialect, strings.Join(sqlDialects, ", "))
+			}
+		case "StrictPlaceholders":
+			if ident, ok := value.(*ast.Ident); ok {
+				cfg.StrictPlaceholders = ident.Name == "true"
+			}
+		case "SkipMultiStatementTransaction":
+			if ident, ok := value.(*ast.Ident); ok {
+				cfg.SkipMultiStatementTransaction = ident.Name == "true"
+			}
+		case "StripSQLComments":
+			if ident, ok := value.(*ast.Ident); ok {
+				cfg.StripSQLComments = ident.Name == "true"
+			}
+		case "VersionField":
+			cfg.VersionField = strLit(value)
+		case "DurationUnit":
+			if cfg.DurationUnit = strLit(value); cfg.DurationUnit != "" && !slices.Contains(durationUnits, cfg.DurationUnit) {
+				return nil, fmt.Errorf("%s: invalid genconfig.Config DurationUnit %q, must be one of %s", p.position(kv.Value.Pos()), cfg.DurationUnit, strings.Join(durationUnits, ", "))
+			}
 		case "FileLevel":
-			if ident, ok := kv.Value.(*ast.Ident); ok {
+			if ident, ok := value.(*ast.Ident); ok {
 				cfg.FileLevel = ident.Name == "true"
 			}
+		case "MergeOutput":
+			if ident, ok := value.(*ast.Ident); ok {
+				cfg.MergeOutput = ident.Name == "true"
+			}
+		case "Scope":
+			if cfg.Scope = strLit(value); cfg.Scope != configScopePackage && cfg.Scope != configScopeSubtree {
+				return nil, fmt.Errorf("%s: invalid genconfig.Config Scope %q, must be %q or %q", p.position(kv.Value.Pos()), cfg.Scope, configScopePackage, configScopeSubtree)
+			}
+		case "NoInherit":
+			if ident, ok := value.(*ast.Ident); ok {
+				cfg.NoInherit = ident.Name == "true"
+			}
+		case "RequireMarker":
+			if ident, ok := value.(*ast.Ident); ok {
+				cfg.RequireMarker = ident.Name == "true"
+			}
+		case "IncludeTests":
+			if ident, ok := value.(*ast.Ident); ok {
+				cfg.IncludeTests = ident.Name == "true"
+			}
+		case "CRUD":
+			if ident, ok := value.(*ast.Ident); ok {
+				cfg.CRUD = ident.Name == "true"
+			}
+		case "Factories":
+			if ident, ok := value.(*ast.Ident); ok {
+				cfg.Factories = ident.Name == "true"
+			}
 		case "FieldTypeMap", "FieldNameMap":
-			if m, ok := kv.Value.(*ast.CompositeLit); ok {
+			if m, ok := value.(*ast.CompositeLit); ok {
 				for _, me := range m.Elts {
 					if pair, ok := me.(*ast.KeyValueExpr); ok {
 						// Values are wrapper type instances like JSON{} or field.Time{}
@@ -566,63 +2823,541 @@ func (p *File) parseConfigLiteral(cl *ast.CompositeLit) *genconfig.Config {
 					}
 				}
 			}
+		case "Scopes":
+			if m, ok := value.(*ast.CompositeLit); ok {
+				for _, me := range m.Elts {
+					if pair, ok := me.(*ast.KeyValueExpr); ok {
+						if key := strLit(pair.Key); key != "" {
+							exprs := []string{strLit(p.resolveValue(pair.Value))}
+							if _, ok := p.resolveValue(pair.Value).(*ast.CompositeLit); ok {
+								exprs = nil
+								for _, v := range collect(pair.Value) {
+									exprs = append(exprs, fmt.Sprint(v))
+								}
+							}
+							cfg.Scopes[key] = exprs
+						}
+					}
+				}
+			}
+		case "TemplateFuncs":
+			if m, ok := value.(*ast.CompositeLit); ok {
+				for _, me := range m.Elts {
+					pair, ok := me.(*ast.KeyValueExpr)
+					if !ok {
+						continue
+					}
+					name := strLit(p.resolveValue(pair.Key))
+					if !token.IsIdentifier(name) {
+						return nil, fmt.Errorf("%s: invalid template func name %q", p.position(pair.Key.Pos()), name)
+					}
+					if table, ok := p.resolveValue(pair.Value).(*ast.CompositeLit); ok {
+						lookup := map[string]string{}
+						for _, te := range table.Elts {
+							if kv, ok := te.(*ast.KeyValueExpr); ok {
+								lookup[strLit(p.resolveValue(kv.Key))] = strLit(p.resolveValue(kv.Value))
+							}
+						}
+						cfg.TemplateFuncs[name] = lookup
+					} else {
+						cfg.TemplateFuncs[name] = strLit(p.resolveValue(pair.Value))
+					}
+				}
+			}
+		case "SQLFragments":
+			if m, ok := value.(*ast.CompositeLit); ok {
+				for _, me := range m.Elts {
+					if pair, ok := me.(*ast.KeyValueExpr); ok {
+						if name := strLit(p.resolveValue(pair.Key)); name != "" {
+							cfg.SQLFragments[name] = strLit(p.resolveValue(pair.Value))
+						}
+					}
+				}
+			}
+		case "TemplateVars":
+			if m, ok := value.(*ast.CompositeLit); ok {
+				for _, me := range m.Elts {
+					pair, ok := me.(*ast.KeyValueExpr)
+					if !ok {
+						continue
+					}
+					name := strLit(p.resolveValue(pair.Key))
+					if !token.IsIdentifier(name) {
+						return nil, fmt.Errorf("%s: invalid template var name %q", p.position(pair.Key.Pos()), name)
+					}
+					cfg.TemplateVars[name] = strLit(p.resolveValue(pair.Value))
+				}
+			}
+		case "SQLFuncs":
+			if m, ok := value.(*ast.CompositeLit); ok {
+				for _, me := range m.Elts {
+					pair, ok := me.(*ast.KeyValueExpr)
+					if !ok {
+						continue
+					}
+					name := strLit(p.resolveValue(pair.Key))
+					if !token.IsIdentifier(name) {
+						return nil, fmt.Errorf("%s: invalid SQL func name %q", p.position(pair.Key.Pos()), name)
+					}
+					fn := p.parseFieldType(pair.Value, p.Package, false)
+					if dot := strings.LastIndex(fn, "."); dot < 0 || !token.IsExported(fn[dot+1:]) {
+						return nil, fmt.Errorf("%s: invalid SQL func %s, must be an exported function like helpers.NotEmpty", p.position(pair.Value.Pos()), name)
+					}
+					cfg.SQLFuncs[name] = fn
+				}
+			}
+		case "QueryHook":
+			// the variable itself is referenced by the generated code, not its value
+			hook := p.parseFieldType(kv.Value, p.Package, false)
+			if dot := strings.LastIndex(hook, "."); dot < 0 || !token.IsExported(hook[dot+1:]) {
+				return nil, fmt.Errorf("%s: invalid query hook %s, must be an exported variable like tracing.Hook", p.position(kv.Value.Pos()), hook)
+			}
+			cfg.QueryHook = hook
+		case "QueryModels":
+			if m, ok := value.(*ast.CompositeLit); ok {
+				for _, me := range m.Elts {
+					if pair, ok := me.(*ast.KeyValueExpr); ok {
+						if name := strLit(p.resolveValue(pair.Key)); name != "" {
+							cfg.QueryModels[name] = p.parseFieldType(pair.Value, p.Package, true)
+						}
+					}
+				}
+			}
 		case "IncludeInterfaces":
-			cfg.IncludeInterfaces = append(cfg.IncludeInterfaces, collect(kv.Value)...)
+			cfg.IncludeInterfaces = append(cfg.IncludeInterfaces, collect(value)...)
 		case "ExcludeInterfaces":
-			cfg.ExcludeInterfaces = append(cfg.ExcludeInterfaces, collect(kv.Value)...)
+			cfg.ExcludeInterfaces = append(cfg.ExcludeInterfaces, collect(value)...)
 		case "IncludeStructs":
-			cfg.IncludeStructs = append(cfg.IncludeStructs, collect(kv.Value)...)
+			cfg.IncludeStructs = append(cfg.IncludeStructs, collect(value)...)
 		case "ExcludeStructs":
-			cfg.ExcludeStructs = append(cfg.ExcludeStructs, collect(kv.Value)...)
+			cfg.ExcludeStructs = append(cfg.ExcludeStructs, collect(value)...)
+		case "Views":
+			cfg.Views = append(cfg.Views, collect(value)...)
+		case "IncludeFields":
+			cfg.IncludeFields = append(cfg.IncludeFields, collect(value)...)
+		case "ExcludeFields":
+			cfg.ExcludeFields = append(cfg.ExcludeFields, collect(value)...)
+		default:
+			return nil, fmt.Errorf("%s: unknown genconfig.Config field %s", p.position(kv.Key.Pos()), types.ExprString(kv.Key))
+		}
+	}
+	return cfg, nil
+}
+
+// resolveValue resolves references to package-level constants and variables in config literals, e.g.
+// `OutPath: outDir`, to their values. Constants are evaluated with go/types, variables are resolved to
+// their initializer expression; unresolved expressions are returned as is.
+func (p *File) resolveValue(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return p.resolveValue(e.X)
+	case *ast.Ident:
+		if e.Name == "true" || e.Name == "false" || e.Name == "nil" {
+			return e
+		}
+		// declared in the same file
+		if e.Obj != nil {
+			if vs, ok := e.Obj.Decl.(*ast.ValueSpec); ok {
+				for i, name := range vs.Names {
+					if name.Name == e.Name && i < len(vs.Values) {
+						return p.resolveValue(vs.Values[i])
+					}
+				}
+			}
+		}
+		if v := p.packageValue(p.PackagePath, e.Name); v != nil {
+			return v
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := e.X.(*ast.Ident); ok && pkg.Obj == nil {
+			if v := p.packageValue(p.getFullImportPath(pkg.Name), e.Sel.Name); v != nil {
+				return v
+			}
+		}
+	case *ast.BinaryExpr:
+		// string concatenation, e.g. baseDir + "/query"
+		if e.Op == token.ADD {
+			x, y := strLitValue(p.resolveValue(e.X)), strLitValue(p.resolveValue(e.Y))
+			if x != nil && y != nil {
+				return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(*x + *y)}
+			}
+		}
+	}
+	return expr
+}
+
+// packageValue returns the value of the package-level constant or variable name in pkgPath, or nil
+func (p *File) packageValue(pkgPath, name string) ast.Expr {
+	if pkgPath == "" {
+		return nil
+	}
+
+	if tp := p.loader().typesPackage(p.goModDir, pkgPath); tp != nil {
+		if c, ok := tp.Scope().Lookup(name).(*types.Const); ok {
+			return constLit(c.Val())
+		}
+	}
+
+	if pkg := p.loader().pkg(p.goModDir, pkgPath); pkg != nil {
+		for _, syntax := range pkg.Syntax {
+			for _, decl := range syntax.Decls {
+				if gen, ok := decl.(*ast.GenDecl); ok && (gen.Tok == token.VAR || gen.Tok == token.CONST) {
+					for _, spec := range gen.Specs {
+						vs := spec.(*ast.ValueSpec)
+						for i, n := range vs.Names {
+							if n.Name == name && i < len(vs.Values) {
+								return p.resolveValue(vs.Values[i])
+							}
+						}
+					}
+				}
+			}
 		}
 	}
-	return cfg
+	return nil
 }
 
 // processInterfaceType processes an interface type AST node and extracts interface metadata and methods
-func (p *File) processInterfaceType(n *ast.TypeSpec, data *ast.InterfaceType) Interface {
+func (p *File) processInterfaceType(n *ast.TypeSpec, data *ast.InterfaceType) (Interface, error) {
 	r := Interface{
 		Name:      n.Name.Name,
 		IfaceName: "_" + n.Name.Name,
 		Doc:       n.Doc.Text(),
+		marked:    hasMarker(n.Doc),
+	}
+
+	fragments, err := parseSQLFragments(r.Doc)
+	if err != nil {
+		return r, fmt.Errorf("%s: interface %s: %w", p.position(n.Pos()), n.Name.Name, err)
 	}
+	r.fragments = fragments
+	r.model = p.parseModelAnnotation(r.Doc)
 
 	methods := data.Methods.List
 	for _, m := range methods {
+		// Flatten embedded interfaces, e.g. BaseQuery[T] or base.Query[T]
+		if len(m.Names) == 0 {
+			embedded, err := p.embeddedMethods(m.Type)
+			if err != nil {
+				return r, fmt.Errorf("%s: interface %s: %w", p.position(m.Pos()), n.Name.Name, err)
+			}
+			for _, method := range embedded {
+				if !slices.ContainsFunc(r.Methods, func(e *Method) bool { return e.Name == method.Name }) {
+					method.Interface = r
+					r.Methods = append(r.Methods, method)
+				}
+			}
+			continue
+		}
+
 		for _, name := range m.Names {
 			method := &Method{
 				Name:      name.Name,
 				Doc:       m.Doc.Text(),
 				SQL:       extractSQL(m.Doc.Text(), name.Name),
 				Interface: r,
+				docLines:  p.docLines(m.Doc),
+			}
+			if sqlFile, ok := strings.CutPrefix(strings.TrimSpace(method.SQL.Raw), "sqlfile:"); ok {
+				sql, pth, err := p.loadSQLFile(strings.TrimSpace(sqlFile), name.Pos())
+				if err != nil {
+					return r, fmt.Errorf("%s: method %s.%s: %w", p.position(name.Pos()), n.Name.Name, method.Name, err)
+				}
+				method.SQL, method.sqlFile = ExtractedSQL{Raw: sql, Timeout: method.SQL.Timeout}, pth
+			}
+			if constName, ok := strings.CutPrefix(strings.TrimSpace(method.SQL.Raw), "sqlconst:"); ok {
+				sql, err := p.loadSQLConst(strings.TrimSpace(constName))
+				if err != nil {
+					return r, fmt.Errorf("%s: method %s.%s: %w", p.position(name.Pos()), n.Name.Name, method.Name, err)
+				}
+				method.SQL = ExtractedSQL{Raw: sql, Timeout: method.SQL.Timeout}
+			}
+			if sql, ok, err := dialectVariants(method.SQL.Raw); err != nil {
+				return r, fmt.Errorf("%s: method %s.%s: %w", p.position(name.Pos()), n.Name.Name, method.Name, err)
+			} else if ok {
+				method.SQL.Raw = sql
+			}
+			if method.SQL.Timeout != "" {
+				timeout, err := time.ParseDuration(method.SQL.Timeout)
+				if err != nil || timeout <= 0 {
+					return r, fmt.Errorf("%s: method %s.%s: invalid timeout %q, expected a positive duration, e.g. timeout: 3s", p.position(name.Pos()), n.Name.Name, method.Name, method.SQL.Timeout)
+				}
+				if !method.SQL.finish() {
+					return r, fmt.Errorf("%s: method %s.%s: timeout requires a method running its query", p.position(name.Pos()), n.Name.Name, method.Name)
+				}
+				method.timeout = timeout
+			}
+			// Explicitly declared methods take precedence over embedded ones
+			if i := slices.IndexFunc(r.Methods, func(e *Method) bool { return e.Name == method.Name }); i >= 0 {
+				r.Methods[i] = method
+			} else {
+				r.Methods = append(r.Methods, method)
 			}
-			r.Methods = append(r.Methods, method)
 
 			method.Params = p.parseFieldList(m.Type.(*ast.FuncType).Params)
 			method.Result = p.parseFieldList(m.Type.(*ast.FuncType).Results)
 
-			if len(method.Result) == 0 {
+			if method.SQL.Expr != "" {
+				if !slices.Equal(resultTypes(method.Result), []string{"clause.Expression"}) {
+					return r, fmt.Errorf("%s: method %s.%s: expr method must return a clause.Expression", p.position(name.Pos()), n.Name.Name, method.Name)
+				}
+			} else if method.SQL.Update != "" || method.SQL.Delete != "" {
+				kind := "update"
+				if method.SQL.Delete != "" {
+					kind = "delete"
+				} else if _, where, ok := splitUpdateSQL(method.SQL.Update); !ok || where == "" {
+					return r, fmt.Errorf("%s: method %s.%s: update method requires a WHERE condition, e.g. update(\"name=@name WHERE id=@id\")", p.position(name.Pos()), n.Name.Name, method.Name)
+				}
+				if !slices.Equal(resultTypes(method.Result), []string{"error"}) && !slices.Equal(resultTypes(method.Result), []string{"int", "error"}) {
+					return r, fmt.Errorf("%s: method %s.%s: %s method must return error or (int, error), the rows affected", p.position(name.Pos()), n.Name.Name, method.Name, kind)
+				}
+			} else if method.SQL.Count != "" {
+				if !slices.Equal(resultTypes(method.Result), []string{"int64", "error"}) {
+					return r, fmt.Errorf("%s: method %s.%s: count method must return (int64, error)", p.position(name.Pos()), n.Name.Name, method.Name)
+				}
+			} else if method.SQL.Insert != "" {
+				if !slices.Equal(resultTypes(method.Result), []string{"error"}) && method.execResult() == "" {
+					return r, fmt.Errorf("%s: method %s.%s: insert method must return error, (rowsAffected int64, err error), (lastInsertId int64, err error) or (sql.Result, error)", p.position(name.Pos()), n.Name.Name, method.Name)
+				}
+				if err := p.expandInsert(method); err != nil {
+					return r, fmt.Errorf("%s: method %s.%s: %w", p.position(name.Pos()), n.Name.Name, method.Name, err)
+				}
+			} else if len(method.Result) == 0 {
 				if method.SQL.Where == "" && method.SQL.Select == "" || method.SQL.Raw != "" {
-					panic(fmt.Sprintf("Method %s.%s: finish method must return at least one value (last return value must be error)", n.Name.Name, method.Name))
+					return r, fmt.Errorf("%s: method %s.%s: finish method must return at least one value (last return value must be error)", p.position(name.Pos()), n.Name.Name, method.Name)
 				}
 			} else if len(method.Result) > 2 {
-				panic(fmt.Sprintf("Method %s.%s: maximum number of return values allowed is 2 (first as data, second as error)", n.Name.Name, method.Name))
-			} else if strings.ToLower(method.Result[len(method.Result)-1].Type) != "error" {
+				return r, fmt.Errorf("%s: method %s.%s: maximum number of return values allowed is 2 (first as data, second as error)", p.position(name.Pos()), n.Name.Name, method.Name)
+			} else if strings.ToLower(method.Result[len(method.Result)-1].Type) != "error" && !(len(method.Result) == 1 && streamRegexp.MatchString(method.Result[0].Type)) {
 				if len(method.Result) == 1 {
-					panic(fmt.Sprintf("Method %s.%s: when only one return value is defined, its type must be error", n.Name.Name, method.Name))
+					return r, fmt.Errorf("%s: method %s.%s: when only one return value is defined, its type must be error or iter.Seq2[T, error]", p.position(name.Pos()), n.Name.Name, method.Name)
+				}
+				return r, fmt.Errorf("%s: method %s.%s: when two return values are defined, the second must be error", p.position(name.Pos()), n.Name.Name, method.Name)
+			} else if elem, _ := method.stream(); elem != "" || len(method.Result) == 2 && method.SQL.Raw != "" && method.execResult() == "" {
+				if elem == "" {
+					elem = method.Result[0].Type
+				}
+				if err := p.validateScanType(elem); err != nil {
+					return r, fmt.Errorf("%s: method %s.%s: %w", p.position(name.Pos()), n.Name.Name, method.Name, err)
+				}
+			}
+
+			if values, err := method.valuesBatch(); err != nil {
+				return r, fmt.Errorf("%s: method %s.%s: %w", p.position(name.Pos()), n.Name.Name, method.Name, err)
+			} else if values != nil {
+				if elem, _ := method.stream(); method.statements() != nil || elem != "" ||
+					!slices.Equal(resultTypes(method.Result), []string{"error"}) && method.execResult() != execRowsAffected {
+					return r, fmt.Errorf("%s: method %s.%s: batch of {{values}} requires a single statement method returning error or (rowsAffected int64, err error)", p.position(name.Pos()), n.Name.Name, method.Name)
+				}
+				if !slices.ContainsFunc(method.Params, func(param Param) bool { return param.Name == values.Slice && strings.HasPrefix(param.Type, "[]") }) {
+					return r, fmt.Errorf("%s: method %s.%s: batch of {{values}} requires a slice parameter, got %s", p.position(name.Pos()), n.Name.Name, method.Name, values.Slice)
+				}
+			}
+		}
+	}
+	return r, nil
+}
+
+// resultTypes returns the types of the results of a method
+func resultTypes(results []Param) (types []string) {
+	for _, r := range results {
+		types = append(types, r.Type)
+	}
+	return types
+}
+
+// loadSQLConst returns the SQL template of a `sqlconst: name` annotation, the value of a string constant
+// declared in the package of the file, or in an imported package with a qualified name, e.g. queries.GetByID
+func (p *File) loadSQLConst(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("sqlconst annotation requires a constant name, e.g. sqlconst: getUserByIDSQL")
+	}
+	pkgPath, constName := p.PackagePath, name
+	if pkg, n, ok := strings.Cut(name, "."); ok {
+		pkgPath, constName = p.getFullImportPath(pkg), n
+	}
+	if !token.IsIdentifier(constName) {
+		return "", fmt.Errorf("invalid sqlconst %q, expected the name of a string constant", name)
+	}
+
+	var sql *string
+	if tp := p.loader().typesPackage(p.goModDir, pkgPath); tp != nil {
+		if c, ok := tp.Scope().Lookup(constName).(*types.Const); ok {
+			sql = strLitValue(constLit(c.Val()))
+		}
+	} else if pkg := p.loader().pkg(p.goModDir, pkgPath); pkg != nil {
+		// the package doesn't type check, e.g. its generated code is outdated, look the constant up in its syntax
+		for _, syntax := range pkg.Syntax {
+			for _, decl := range syntax.Decls {
+				if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.CONST {
+					for _, spec := range gen.Specs {
+						vs := spec.(*ast.ValueSpec)
+						for i, n := range vs.Names {
+							if n.Name == constName && i < len(vs.Values) {
+								sql = strLitValue(p.resolveValue(vs.Values[i]))
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	if sql == nil {
+		return "", fmt.Errorf("sqlconst %s not found, it must be a string constant", name)
+	}
+	if strings.TrimSpace(*sql) == "" {
+		return "", fmt.Errorf("sqlconst %s is empty", name)
+	}
+	return strings.TrimSpace(*sql), nil
+}
+
+// loadSQLFile returns the SQL template of a `sqlfile: path` annotation and the path of the file, relative
+// paths are resolved from the directory of the source file declaring the method at pos
+func (p *File) loadSQLFile(sqlFile string, pos token.Pos) (string, string, error) {
+	if sqlFile == "" {
+		return "", "", errors.New("sqlfile annotation requires a path, e.g. sqlfile: queries/get_by_id.sql")
+	}
+
+	pth := filepath.FromSlash(sqlFile)
+	if !filepath.IsAbs(pth) {
+		dir := filepath.Dir(p.inputPath)
+		if filename := p.position(pos).Filename; filename != "" {
+			dir = filepath.Dir(filename)
+		}
+		pth = filepath.Join(dir, pth)
+	}
+
+	content, err := os.ReadFile(pth)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read sqlfile %v, got error %v", sqlFile, err)
+	}
+	sql := strings.TrimRight(strings.ReplaceAll(string(content), "\r\n", "\n"), " \t\n")
+	if strings.TrimSpace(sql) == "" {
+		return "", "", fmt.Errorf("sqlfile %v is empty", sqlFile)
+	}
+	return sql, pth, nil
+}
+
+// sqlFiles returns the paths of the SQL files the query methods of the file were loaded from
+func (p *File) sqlFiles() (files []string) {
+	for _, iface := range p.Interfaces {
+		for _, m := range iface.Methods {
+			if m.sqlFile != "" && !slices.Contains(files, m.sqlFile) {
+				files = append(files, m.sqlFile)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// embeddedMethods returns the methods of an interface embedded in a query interface, the embedded
+// interface may be declared in the same file, another file of the package or an imported package
+func (p *File) embeddedMethods(expr ast.Expr) ([]*Method, error) {
+	var args []ast.Expr
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr, args = t.X, []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		expr, args = t.X, t.Indices
+	}
+
+	var (
+		spec *ast.TypeSpec
+		src  = p
+	)
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if t.Obj != nil {
+			spec, _ = t.Obj.Decl.(*ast.TypeSpec)
+		}
+		if spec == nil {
+			spec, src = p.loadTypeSpec(p.PackagePath, t.Name)
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			spec, src = p.loadTypeSpec(p.getFullImportPath(pkg.Name), t.Sel.Name)
+		}
+	}
+	if spec == nil {
+		return nil, fmt.Errorf("embedded interface %s not found", types.ExprString(expr))
+	}
+	data, ok := spec.Type.(*ast.InterfaceType)
+	if !ok {
+		return nil, fmt.Errorf("embedded type %s is not an interface", types.ExprString(expr))
+	}
+
+	// Substitute the type parameters of the embedded interface with the type arguments
+	var replacer []string
+	if spec.TypeParams != nil {
+		var i int
+		for _, field := range spec.TypeParams.List {
+			for _, name := range field.Names {
+				if i < len(args) {
+					replacer = append(replacer, name.Name, p.parseFieldType(args[i], p.Package, false))
 				}
-				panic(fmt.Sprintf("Method %s.%s: when two return values are defined, the second must be error", n.Name.Name, method.Name))
+				i++
+			}
+		}
+	}
+	substitute := func(params []Param) []Param {
+		params = slices.Clone(params)
+		for i := range params {
+			for j := 0; j < len(replacer); j += 2 {
+				re := regexp.MustCompile(`\b` + regexp.QuoteMeta(replacer[j]) + `\b`)
+				params[i].Type = re.ReplaceAllLiteralString(params[i].Type, replacer[j+1])
 			}
 		}
+		return params
 	}
-	return r
+
+	iface, err := src.processInterfaceType(spec, data)
+	if err != nil {
+		return nil, err
+	}
+	methods := iface.Methods
+	for i, m := range methods {
+		method := *m
+		method.Params, method.Result = substitute(m.Params), substitute(m.Result)
+		methods[i] = &method
+	}
+	if src != p {
+		mergeImports(&p.Imports, src.Imports)
+	}
+	return methods, nil
+}
+
+// loadTypeSpec returns the type declaration name in pkgPath, with a file carrying the package and
+// imports of its source file to resolve its types
+func (p *File) loadTypeSpec(pkgPath, name string) (*ast.TypeSpec, *File) {
+	spec, syntax, pkg := p.loader().typeSpec(p.goModDir, pkgPath, name)
+	if spec == nil {
+		return nil, p
+	}
+
+	src := &File{Package: pkg.Name, PackagePath: pkg.PkgPath, Generator: p.Generator, goModDir: p.goModDir, fset: pkg.Fset}
+	for _, imp := range syntax.Imports {
+		src.Imports = append(src.Imports, parseImport(imp))
+	}
+	if pkg.PkgPath != p.PackagePath {
+		src.Imports = append(src.Imports, Import{Name: pkg.Name, Path: pkg.PkgPath})
+	}
+	return spec, src
 }
 
 // processStructType processes a struct type AST node and extracts struct metadata and fields
 func (p *File) processStructType(typeSpec *ast.TypeSpec, data *ast.StructType, pkgName string) Struct {
 	s := Struct{
-		Name: typeSpec.Name.Name,
+		Name:   typeSpec.Name.Name,
+		Doc:    typeSpec.Doc.Text(),
+		file:   p,
+		marked: hasMarker(typeSpec.Doc),
 	}
+	s.view, s.viewSQL = viewDirective(typeSpec.Doc)
+	s.TypeParams, s.TypeArgs = typeParams(typeSpec.TypeParams)
+
+	p.embedding = append(p.embedding, p.PackagePath+"."+s.Name)
+	defer func() { p.embedding = p.embedding[:len(p.embedding)-1] }()
 
 	for _, field := range data.Fields.List {
 		// Handle anonymous embedding first
@@ -640,19 +3375,35 @@ func (p *File) processStructType(typeSpec *ast.TypeSpec, data *ast.StructType, p
 					fieldTag, _ = strconv.Unquote(field.Tag.Value)
 				}
 
+				// Fields ignored by gorm have no column
+				if ignored := schema.ParseTagSetting(reflect.StructTag(fieldTag).Get("gorm"), ";")["-"]; ignored == "-" || ignored == "all" {
+					continue
+				}
+
+				doc := field.Doc.Text()
+				if doc == "" {
+					doc = field.Comment.Text()
+				}
+
 				s.Fields = append(s.Fields, Field{
 					Name:        n.Name,
+					Doc:         doc,
 					DBName:      generateDBName(n.Name, fieldTag),
 					GoType:      p.parseFieldType(field.Type, pkgName, true),
 					NamedGoType: reflect.StructTag(fieldTag).Get("gen"),
 					Tag:         fieldTag,
 					file:        p,
 					field:       field,
+					pos:         p.position(field.Pos()),
 				})
 			}
 		}
 	}
 
+	for i := range s.Fields {
+		s.Fields[i].owner = s.Name
+		s.Fields[i].view = s.view
+	}
 	return s
 }
 
@@ -699,12 +3450,39 @@ func (p *File) parseFieldType(expr ast.Expr, pkgName string, fullMode bool) stri
 			return ""
 		}
 		return base + "[" + idx + "]"
+	case *ast.IndexListExpr:
+		indices := make([]string, len(t.Indices))
+		for i, index := range t.Indices {
+			indices[i] = p.parseFieldType(index, pkgName, fullMode)
+		}
+		return p.parseFieldType(t.X, pkgName, fullMode) + "[" + strings.Join(indices, ", ") + "]"
+	case *ast.FuncType:
+		// Callback parameters, e.g. func(T) error
+		var params, results []string
+		for _, param := range p.parseFieldList(t.Params) {
+			params = append(params, param.Type)
+		}
+		for _, result := range p.parseFieldList(t.Results) {
+			results = append(results, result.Type)
+		}
+		typ := "func(" + strings.Join(params, ", ") + ")"
+		if len(results) == 1 {
+			return typ + " " + results[0]
+		} else if len(results) > 1 {
+			return typ + " (" + strings.Join(results, ", ") + ")"
+		}
+		return typ
 	case *ast.StarExpr:
 		innerType := p.parseFieldType(t.X, pkgName, fullMode)
 		return "*" + innerType
 	case *ast.ArrayType:
 		elementType := p.parseFieldType(t.Elt, pkgName, fullMode)
+		if t.Len != nil {
+			return "[" + types.ExprString(t.Len) + "]" + elementType
+		}
 		return "[]" + elementType
+	case *ast.MapType:
+		return "map[" + p.parseFieldType(t.Key, pkgName, fullMode) + "]" + p.parseFieldType(t.Value, pkgName, fullMode)
 	case *ast.UnaryExpr:
 		// Dereference address-of composite literals: &Type{}
 		if t.Op == token.AND {
@@ -721,6 +3499,60 @@ func (p *File) parseFieldType(expr ast.Expr, pkgName string, fullMode bool) stri
 	return "any"
 }
 
+// parseImport converts an import spec to an Import, named after the last path element by default
+func parseImport(spec *ast.ImportSpec) Import {
+	importPath, _ := strconv.Unquote(spec.Path.Value)
+	importName := path.Base(importPath)
+	if spec.Name != nil {
+		importName = spec.Name.Name
+	}
+	return Import{Name: importName, Path: importPath}
+}
+
+// docLines returns the lines of the doc comment with the positions of their text, after the comment markers
+func (p *File) docLines(doc *ast.CommentGroup) (lines []docLine) {
+	if doc == nil || p.fset == nil {
+		return nil
+	}
+	for _, c := range doc.List {
+		if text, ok := strings.CutPrefix(c.Text, "//"); ok {
+			offset := 2
+			if strings.HasPrefix(text, " ") {
+				text, offset = text[1:], 3
+			}
+			lines = append(lines, docLine{text: text, pos: p.position(c.Pos() + token.Pos(offset))})
+			continue
+		}
+
+		// the lines of /* */ comments after the first one start at column 1
+		pos := p.position(c.Pos() + 2)
+		for i, text := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(c.Text, "/*"), "*/"), "\n") {
+			if i > 0 {
+				pos.Offset += len(lines[len(lines)-1].text) + 1
+				pos.Line, pos.Column = pos.Line+1, 1
+			}
+			lines = append(lines, docLine{text: text, pos: pos})
+		}
+	}
+	return lines
+}
+
+// position returns the source position of pos in the file
+func (p *File) position(pos token.Pos) token.Position {
+	if p.fset == nil {
+		return token.Position{}
+	}
+	return p.fset.Position(pos)
+}
+
+// loader returns the package loader shared by the generator
+func (p *File) loader() *packageLoader {
+	if p.Generator != nil && p.Generator.loader != nil {
+		return p.Generator.loader
+	}
+	return defaultLoader
+}
+
 func (p *File) getFullImportPath(shortName string) string {
 	for _, i := range p.Imports {
 		if i.Name == shortName {
@@ -730,22 +3562,40 @@ func (p *File) getFullImportPath(shortName string) string {
 	return shortName
 }
 
-// handleAnonymousEmbedding processes anonymous embedded fields and returns true if handled
+// handleAnonymousEmbedding processes anonymous embedded fields and returns true if handled. Embedded
+// structs are expanded recursively, structs of other packages with the imports of their own file, and
+// a struct embedding itself, directly or not, is skipped.
 func (p *File) handleAnonymousEmbedding(field *ast.Field, pkgName string, s *Struct) bool {
-	// Helper function to add fields from embedded struct
-	addEmbeddedFields := func(structType *ast.StructType, typeName, embeddedPkgName string) bool {
-		sub := p.processStructType(&ast.TypeSpec{Name: &ast.Ident{Name: typeName}}, structType, embeddedPkgName)
+	// Helper function to add fields from embedded struct, processed by the file declaring it
+	addEmbeddedFields := func(src *File, structType *ast.StructType, typeName, embeddedPkgName string) bool {
+		if slices.Contains(p.embedding, src.PackagePath+"."+typeName) {
+			return true
+		}
+		if src != p {
+			src.embedding = slices.Clone(p.embedding)
+		}
+		sub := src.processStructType(&ast.TypeSpec{Name: &ast.Ident{Name: typeName}}, structType, embeddedPkgName)
+		if src != p {
+			for i := range sub.Fields {
+				sub.Fields[i].file = p
+			}
+			mergeImports(&p.Imports, src.Imports)
+		}
 		s.Fields = append(s.Fields, sub.Fields...)
 		return true
 	}
 
-	// Helper function to load and process external struct type
-	loadAndProcessExternalStruct := func(pkgName, typeName string) bool {
-		st, err := loadNamedStructType(p.goModDir, p.getFullImportPath(pkgName), typeName)
-		if err != nil || st == nil {
+	// Helper function to load and process struct type declared in another file or package
+	loadAndProcessExternalStruct := func(pkgPath, typeName string) bool {
+		spec, src := p.loadTypeSpec(pkgPath, typeName)
+		if spec == nil {
+			return false
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
 			return false
 		}
-		return addEmbeddedFields(st, typeName, pkgName)
+		return addEmbeddedFields(src, st, typeName, src.Package)
 	}
 
 	// Unwrap pointer types to get the underlying type
@@ -760,20 +3610,23 @@ func (p *File) handleAnonymousEmbedding(field *ast.Field, pkgName string, s *Str
 		if t.Obj != nil {
 			if ts, ok := t.Obj.Decl.(*ast.TypeSpec); ok {
 				if st, ok := ts.Type.(*ast.StructType); ok {
-					return addEmbeddedFields(st, t.Name, pkgName)
+					return addEmbeddedFields(p, st, t.Name, pkgName)
 				}
 			}
+		} else if p.PackagePath != "" {
+			// declared in another file of the package
+			return loadAndProcessExternalStruct(p.PackagePath, t.Name)
 		}
 
 	case *ast.SelectorExpr:
 		// External package type embedding (e.g., pkg.BaseStruct or *pkg.BaseStruct)
 		if pkgIdent, ok := t.X.(*ast.Ident); ok {
-			return loadAndProcessExternalStruct(pkgIdent.Name, t.Sel.Name)
+			return loadAndProcessExternalStruct(p.getFullImportPath(pkgIdent.Name), t.Sel.Name)
 		}
 
 	case *ast.StructType:
 		// Anonymous inline struct embedding (e.g., struct{...})
-		return addEmbeddedFields(t, "AnonymousStruct", pkgName)
+		return addEmbeddedFields(p, t, "AnonymousStruct", pkgName)
 	}
 
 	return false
@@ -1,24 +1,38 @@
 package gen
 
 import (
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 	"text/template"
+	"time"
+
+	"gorm.io/cli/gorm/field"
+	"gorm.io/cli/gorm/genconfig"
+	"gorm.io/cli/gorm/internal/drivers"
+	"gorm.io/gorm/clause"
 )
 
 func TestParseTemplate(t *testing.T) {
 	if _, err := template.New("").Parse(pkgTmpl); err != nil {
 		t.Errorf("failed to parse template, got %v", err)
 	}
+	if _, err := parseTemplates("", builtinFuncs); err != nil {
+		t.Errorf("failed to parse default templates, got %v", err)
+	}
 }
 
 func TestLoadNamedTypes(t *testing.T) {
-	for _, i := range allowedInterfaces {
+	for _, i := range allowedInterfaces() {
 		if i == nil {
 			t.Fatalf("failed to load named type, got nil")
 		}
@@ -61,7 +75,7 @@ func TestGeneratorWithQueryInterface(t *testing.T) {
 	}
 	goldenStr := string(goldenBytes)
 
-	generatedFile := filepath.Join(outputDir, files[0].Name())
+	generatedFile := filepath.Join(outputDir, "query.go")
 	genBytes, err := os.ReadFile(generatedFile)
 	if err != nil {
 		t.Fatalf("failed to read generated file %s: %v", generatedFile, err)
@@ -122,7 +136,7 @@ func TestProcessStructType(t *testing.T) {
 			{Name: "Team", DBName: "team", GoType: "[]User"},
 			{Name: "Languages", DBName: "languages", GoType: "[]Language"},
 			{Name: "Friends", DBName: "friends", GoType: "[]*User"},
-			{Name: "Role", DBName: "role", GoType: "string"},
+			{Name: "Role", DBName: "role", GoType: "Role"},
 			{Name: "IsAdult", DBName: "is_adult", GoType: "bool"},
 			{Name: "Profile", DBName: "profile", GoType: "string", NamedGoType: "json"},
 		},
@@ -144,3 +158,3624 @@ func TestProcessStructType(t *testing.T) {
 		t.Errorf("Expected %+v, got %+v", expected, trimmed)
 	}
 }
+
+// generateFromSource generates code from a single source file and returns the generated content
+func generateFromSource(t *testing.T, src string) string {
+	t.Helper()
+	outputDir := generateFromFiles(t, &Generator{}, map[string]string{"models.go": src})
+	return readFileMust(t, filepath.Join(outputDir, "models.go"))
+}
+
+// generateFromFiles writes the files into a new input directory, in the example.com/models module
+// unless they have a go.mod, generates code from them with g into its output path, a new directory
+// by default, and returns the output path
+func generateFromFiles(t *testing.T, g *Generator, files map[string]string) string {
+	t.Helper()
+	if g.Files == nil {
+		g.Files = map[string]*File{}
+	}
+	if g.outPath == "" {
+		g.outPath = t.TempDir()
+	}
+	if _, ok := files["go.mod"]; !ok {
+		files = maps.Clone(files)
+		files["go.mod"] = "module example.com/models\n"
+	}
+
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, files)
+	if err := g.Process(inputDir); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+	if err := g.Gen(); err != nil {
+		t.Fatalf("Gen error: %v", err)
+	}
+	return g.outPath
+}
+
+// writeFiles writes the files by slash-separated path relative to dir, creating their directories
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		pth := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(pth), 0o755); err != nil {
+			t.Fatalf("failed to create directory of %s: %v", name, err)
+		}
+		if err := os.WriteFile(pth, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestStructTableName(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type User struct {
+	Name string
+}
+
+type Pet struct {
+	Name string
+}
+
+func (Pet) TableName() string { return "animals" }
+`)
+
+	for _, expected := range []string{
+		"func (_User) TableName() string {\n\treturn \"users\"\n}",
+		"func (_Pet) TableName() string {\n\treturn \"animals\"\n}",
+		"func (_Pet) Table() clause.Table {\n\treturn clause.Table{Name: \"animals\"}\n}",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected generated code to contain %q, got\n%s", expected, content)
+		}
+	}
+}
+
+func TestStructColumns(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type User struct {
+	ID      uint
+	Name    string `+"`gorm:\"column:user_name\"`"+`
+	Pets    []Pet
+}
+
+type Pet struct {
+	Name string
+}
+`)
+
+	for _, expected := range []string{
+		"var UserColumns = struct {",
+		"ID:   \"id\",",
+		"Name: \"user_name\",",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected generated code to contain %q, got\n%s", expected, content)
+		}
+	}
+	if strings.Contains(content, "Pets: \"pets\"") {
+		t.Errorf("association fields should not be generated as columns, got\n%s", content)
+	}
+}
+
+func TestGeneratorCache(t *testing.T) {
+	moduleDir := t.TempDir()
+	inputDir := filepath.Join(moduleDir, "models")
+	// module paths of applications often have no dot, their packages are hashed like any main module's
+	writeFiles(t, moduleDir, map[string]string{
+		"go.mod":           "module app\n",
+		"shared/shared.go": "package shared\n\ntype Model struct {\n\tID uint\n}\n",
+		"models/models.go": "package models\n\nimport \"app/shared\"\n\n//gorm:generate\ntype User struct {\n\tshared.Model\n\tName string\n}\n",
+	})
+
+	outputDir, cacheDir := t.TempDir(), t.TempDir()
+	outputFile := filepath.Join(outputDir, "models.go")
+	generate := func(opts ...func(*Generator)) (skipped bool) {
+		t.Helper()
+		g := &Generator{Files: map[string]*File{}, outPath: outputDir, cacheDir: cacheDir}
+		for _, opt := range opts {
+			opt(g)
+		}
+		upToDate, err := g.upToDate(inputDir)
+		if err != nil {
+			t.Fatalf("upToDate error: %v", err)
+		}
+		if upToDate {
+			return true
+		}
+		if err := g.Process(inputDir); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		if err := g.Gen(); err != nil {
+			t.Fatalf("Gen error: %v", err)
+		}
+		return false
+	}
+
+	if generate() {
+		t.Fatalf("expected the first run not to be skipped")
+	}
+	if !strings.Contains(readFileMust(t, outputFile), "var User = _User{") {
+		t.Fatalf("expected User helper to be generated")
+	}
+
+	// Unchanged inputs should skip the run before loading them
+	if err := os.WriteFile(outputFile, []byte("stale"), 0o644); err != nil {
+		t.Fatalf("failed to write output: %v", err)
+	}
+	if !generate() {
+		t.Fatalf("expected unchanged inputs to skip the run")
+	}
+
+	// Options of the run are part of the hashes of the run and of the files
+	if generate(func(g *Generator) { g.optIn = true }) {
+		t.Fatalf("expected changed options not to skip the run")
+	}
+	if content := readFileMust(t, outputFile); !strings.Contains(content, "var User = _User{") {
+		t.Fatalf("expected the file to be regenerated with --opt-in, got %s", content)
+	}
+	generate()
+
+	// Changed packages imported by the inputs should regenerate the files depending on them
+	writeFiles(t, moduleDir, map[string]string{"shared/shared.go": "package shared\n\nimport \"time\"\n\ntype Model struct {\n\tID        uint\n\tCreatedAt time.Time\n}\n"})
+	if generate() {
+		t.Fatalf("expected a changed shared package not to skip the run")
+	}
+	if !strings.Contains(readFileMust(t, outputFile), "CreatedAt field.Time") {
+		t.Fatalf("expected the embedded fields of the changed shared package to be regenerated")
+	}
+
+	// Changed inputs should regenerate the file
+	writeFiles(t, moduleDir, map[string]string{"models/models.go": "package models\n\ntype Pet struct {\n\tName string\n}\n"})
+	if generate() {
+		t.Fatalf("expected changed inputs not to skip the run")
+	}
+	if !strings.Contains(readFileMust(t, outputFile), "var Pet = _Pet{") {
+		t.Fatalf("expected changed inputs to be regenerated")
+	}
+	if !generate() {
+		t.Fatalf("expected the run after regenerating to be skipped")
+	}
+}
+
+func TestGeneratorManifest(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod":  "module example.com/models\n",
+		"user.go": "package models\n\ntype User struct {\n\tName string\n}\n",
+		"pet.go":  "package models\n\ntype Pet struct {\n\tName string\n}\n",
+	})
+
+	outputDir := t.TempDir()
+	generate := func(check, prune bool) error {
+		t.Helper()
+		g := &Generator{Files: map[string]*File{}, outPath: outputDir, check: check, prune: prune}
+		if err := g.Process(inputDir); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		return g.Gen()
+	}
+
+	if err := generate(false, false); err != nil {
+		t.Fatalf("Gen error: %v", err)
+	}
+	manifest := loadManifest(outputDir)
+	if got := manifest.sortedFiles(); !reflect.DeepEqual(got, []string{"pet.go", "user.go"}) {
+		t.Fatalf("expected pet.go and user.go in the manifest, got %v", got)
+	}
+	userContent := readFileMust(t, filepath.Join(outputDir, "user.go"))
+	userInput, _ := filepath.Rel(outputDir, filepath.Join(inputDir, "user.go"))
+	if entry := manifest.Files["user.go"]; entry.Hash != contentHash([]byte(userContent)) ||
+		!reflect.DeepEqual(entry.Inputs, []string{filepath.ToSlash(userInput)}) {
+		t.Errorf("unexpected manifest entry of user.go: %+v", entry)
+	}
+
+	// Check mode reports outdated, missing and stale files without writing them
+	if err := generate(true, false); err != nil {
+		t.Fatalf("expected up to date outputs, got %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "user.go"), []byte("// Code generated by 'gorm.io/cli/gorm'. DO NOT EDIT.\n"), 0o644); err != nil {
+		t.Fatalf("failed to write output: %v", err)
+	}
+	if err := os.Remove(filepath.Join(inputDir, "pet.go")); err != nil {
+		t.Fatalf("failed to remove input: %v", err)
+	}
+	writeFiles(t, inputDir, map[string]string{"tag.go": "package models\n\ntype Tag struct {\n\tName string\n}\n"})
+	err := generate(true, false)
+	for _, expected := range []string{
+		"found 3 generated files out of date",
+		filepath.Join(outputDir, "user.go") + ": outdated",
+		filepath.Join(outputDir, "tag.go") + ": missing",
+		filepath.Join(outputDir, "pet.go") + ": stale",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in check error, got %v", expected, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "tag.go")); !os.IsNotExist(err) {
+		t.Errorf("expected check mode not to write tag.go, got %v", err)
+	}
+
+	// Stale files are kept without prune, and removed with prune
+	if err := generate(false, false); err != nil {
+		t.Fatalf("Gen error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "pet.go")); err != nil {
+		t.Errorf("expected pet.go to be kept without prune, got %v", err)
+	}
+	if err := generate(false, true); err != nil {
+		t.Fatalf("Gen error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "pet.go")); !os.IsNotExist(err) {
+		t.Errorf("expected pet.go to be pruned, got %v", err)
+	}
+	if got := loadManifest(outputDir).sortedFiles(); !reflect.DeepEqual(got, []string{"tag.go", "user.go"}) {
+		t.Errorf("expected tag.go and user.go in the manifest, got %v", got)
+	}
+
+	// Files the generator didn't create aren't overwritten
+	writeFiles(t, inputDir, map[string]string{"toy.go": "package models\n\ntype Toy struct {\n\tName string\n}\n"})
+	writeFiles(t, outputDir, map[string]string{"toy.go": "package models\n\n// hand written\n"})
+	if err := generate(false, false); err == nil || !strings.Contains(err.Error(), "refusing to overwrite") {
+		t.Errorf("expected hand written toy.go not to be overwritten, got %v", err)
+	}
+	if err := os.Remove(filepath.Join(inputDir, "toy.go")); err != nil {
+		t.Fatalf("failed to remove input: %v", err)
+	}
+
+	// Clean removes the generated files and the manifest, but not the other files
+	if err := cleanOutputs(outputDir); err != nil {
+		t.Fatalf("clean error: %v", err)
+	}
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatalf("failed to read output dir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "toy.go" {
+		t.Errorf("expected only the hand written toy.go to be left, got %v", entries)
+	}
+}
+
+func TestGeneratorMergeOutput(t *testing.T) {
+	sources := map[string]string{
+		"user.go": "package models\n\ntype User struct {\n\tName string\n}\n",
+		"pet.go":  "package models\n\ntype Pet struct {\n\tName string\n}\n",
+		"query.go": `package models
+
+type Query[T any] interface {
+	// SELECT * FROM @@table WHERE id=@id
+	GetByID(id int) (T, error)
+}
+`,
+	}
+
+	tests := []struct {
+		name       string
+		config     string
+		singleFile bool
+	}{
+		{name: "flag", singleFile: true},
+		{name: "config", config: "package models\n\nimport \"gorm.io/cli/gorm/genconfig\"\n\nvar _ = genconfig.Config{MergeOutput: true}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := maps.Clone(sources)
+			if tt.config != "" {
+				files["config.go"] = tt.config
+			}
+			outputDir := generateFromFiles(t, &Generator{singleFile: tt.singleFile}, files)
+
+			entries, err := os.ReadDir(outputDir)
+			if err != nil {
+				t.Fatalf("failed to read output dir: %v", err)
+			}
+			if len(entries) != 2 || entries[0].Name() != manifestFileName || entries[1].Name() != "models_gen.go" {
+				t.Fatalf("expected a single models_gen.go and the manifest, got %v", entries)
+			}
+
+			content := readFileMust(t, filepath.Join(outputDir, "models_gen.go"))
+			for _, expected := range []string{"var Pet = _Pet{", "var User = _User{", "func Query[T any](db *gorm.DB"} {
+				if !strings.Contains(content, expected) {
+					t.Errorf("expected merged output to contain %q", expected)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratorMergeOutputImports(t *testing.T) {
+	generate := func(files map[string]string) (string, error) {
+		inputDir, outputDir := t.TempDir(), t.TempDir()
+		files["go.mod"] = "module example.com/models\n"
+		writeFiles(t, inputDir, files)
+		g := &Generator{Files: map[string]*File{}, outPath: outputDir, singleFile: true}
+		if err := g.Process(inputDir); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		if err := g.Gen(); err != nil {
+			return "", err
+		}
+		return readFileMust(t, filepath.Join(outputDir, "models_gen.go")), nil
+	}
+
+	// a package imported under different names is kept under each of them
+	content, err := generate(map[string]string{
+		"user.go": "package models\n\nimport dbsql \"database/sql\"\n\ntype UserQuery[T any] interface {\n\t// SELECT * FROM @@table WHERE score=@score\n\tByScore(score dbsql.NullInt64) ([]T, error)\n}\n",
+		"pet.go":  "package models\n\nimport \"database/sql\"\n\ntype PetQuery[T any] interface {\n\t// SELECT * FROM @@table WHERE age=@age\n\tByAge(age sql.NullInt64) ([]T, error)\n}\n",
+	})
+	if err != nil {
+		t.Fatalf("Gen error: %v", err)
+	}
+	for _, expected := range []string{`dbsql "database/sql"`, "\t\"database/sql\"", "score dbsql.NullInt64", "age sql.NullInt64"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected merged output to contain %q, got:\n%s", expected, content)
+		}
+	}
+
+	// different packages imported under the same name are reported
+	_, err = generate(map[string]string{
+		"user.go": "package models\n\nimport x \"time\"\n\ntype UserQuery[T any] interface {\n\t// SELECT * FROM @@table WHERE month=@month\n\tByMonth(month x.Month) ([]T, error)\n}\n",
+		"pet.go":  "package models\n\nimport x \"os\"\n\ntype PetQuery[T any] interface {\n\t// SELECT * FROM @@table WHERE mode=@mode\n\tByMode(mode x.FileMode) ([]T, error)\n}\n",
+	})
+	if err == nil || !strings.Contains(err.Error(), `"time" and "os" are both imported as x`) && !strings.Contains(err.Error(), `"os" and "time" are both imported as x`) {
+		t.Errorf("expected the conflicting imports reported, got %v", err)
+	}
+}
+
+func TestGeneratorOutPackage(t *testing.T) {
+	files := map[string]string{
+		"models.go": "package models\n\ntype User struct {\n\tName string\n}\n",
+		"config.go": "package models\n\nimport \"gorm.io/cli/gorm/genconfig\"\n\nvar _ = genconfig.Config{OutPackage: \"query\"}\n",
+	}
+
+	for outPackage, expected := range map[string]string{"": "package query\n", "dao": "package dao\n"} {
+		outputDir := generateFromFiles(t, &Generator{outPackage: outPackage}, files)
+		content := readFileMust(t, filepath.Join(outputDir, "models.go"))
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestGeneratorHeader(t *testing.T) {
+	header := "// SPDX-License-Identifier: MIT\n// Code generated by example-tool v1.0. DO NOT EDIT."
+	outputDir := generateFromFiles(t, &Generator{}, map[string]string{
+		"models.go": "package models\n\ntype User struct {\n\tName string\n}\n",
+		"config.go": "package models\n\nimport \"gorm.io/cli/gorm/genconfig\"\n\nvar _ = genconfig.Config{Header: " + strconv.Quote(header) + "}\n",
+		// output of a previous run, it should be skipped
+		"user_gen.go": header + "\n\npackage models\n\ntype Pet struct {\n\tName string\n}\n",
+	})
+
+	content := readFileMust(t, filepath.Join(outputDir, "models.go"))
+	if !strings.HasPrefix(content, header+"\n\npackage models") {
+		t.Errorf("expected generated code to start with the configured header, got:\n%s", content)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "user_gen.go")); !os.IsNotExist(err) {
+		t.Errorf("expected file generated with the configured header to be skipped, got %v", err)
+	}
+}
+
+func TestGeneratorSkipsUnchangedFiles(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod":    "module example.com/models\n",
+		"models.go": "package models\n\ntype User struct {\n\tName string\n}\n",
+	})
+
+	outputDir := t.TempDir()
+	outputFile := filepath.Join(outputDir, "models.go")
+	generate := func() time.Time {
+		t.Helper()
+		g := &Generator{Files: map[string]*File{}, outPath: outputDir}
+		if err := g.Process(inputDir); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		if err := g.Gen(); err != nil {
+			t.Fatalf("Gen error: %v", err)
+		}
+		info, err := os.Stat(outputFile)
+		if err != nil {
+			t.Fatalf("failed to stat output: %v", err)
+		}
+		return info.ModTime()
+	}
+
+	generate()
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(outputFile, past, past); err != nil {
+		t.Fatalf("failed to change mtime: %v", err)
+	}
+
+	if mtime := generate(); !mtime.Equal(past) {
+		t.Fatalf("expected unchanged output not to be rewritten, mtime changed to %v", mtime)
+	}
+
+	writeFiles(t, inputDir, map[string]string{"models.go": "package models\n\ntype User struct {\n\tName string\n\tAge  int\n}\n"})
+	if mtime := generate(); mtime.Equal(past) {
+		t.Fatalf("expected changed output to be rewritten")
+	}
+}
+
+func TestStructSQLNullType(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type User struct {
+	Age       sql.Null[int64]
+	Score     sql.Null[float64]
+	Name      sql.Null[string]
+	Active    sql.Null[bool]
+	LastLogin sql.Null[time.Time]
+}
+`)
+
+	for _, expected := range []string{
+		"Age       field.Null[int64]",
+		"Score     field.Null[float64]",
+		"Name      field.Null[string]",
+		"Active    field.Null[bool]",
+		"LastLogin field.Null[time.Time]",
+		`Age:       field.Null[int64]{}.WithColumn("age"),`,
+		`Name:      field.Null[string]{}.WithColumn("name"),`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestStructUUIDType(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import "github.com/google/uuid"
+
+type User struct {
+	Key       uuid.UUID
+	ParentKey *uuid.UUID
+	Checksum  [16]byte
+}
+`)
+
+	for _, expected := range []string{
+		"Key       field.UUID[uuid.UUID]",
+		`Key:       field.UUID[uuid.UUID]{}.WithColumn("key"),`,
+		"ParentKey field.UUID[uuid.UUID]",
+		"Checksum  field.Field[[16]byte]",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestStructDecimalType(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import (
+	"database/sql/driver"
+
+	"github.com/shopspring/decimal"
+)
+
+type Money struct{ value string }
+
+func (m *Money) Scan(src any) error          { return nil }
+func (m Money) Value() (driver.Value, error) { return m.value, nil }
+
+type Order struct {
+	Total    decimal.Decimal
+	Discount *decimal.Decimal
+	Refund   decimal.NullDecimal
+	Price    Money
+}
+`)
+
+	for _, expected := range []string{
+		"Total    field.Decimal[decimal.Decimal]",
+		`Total:    field.Decimal[decimal.Decimal]{}.WithColumn("total"),`,
+		"Discount field.Null[decimal.Decimal]",
+		`Discount: field.Null[decimal.Decimal]{}.WithColumn("discount"),`,
+		"Refund   field.Decimal[decimal.NullDecimal]",
+		"Price    field.Field[models.Money]",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestStructArrayType(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type Post struct {
+	Tags   []string `+"`gorm:\"type:text[]\"`"+`
+	Scores []int64  `+"`gorm:\"type:bigint[]\"`"+`
+}
+`)
+
+	for _, expected := range []string{
+		"Tags   field.Array[string]",
+		"Scores field.Array[int64]",
+		`Tags:   field.Array[string]{}.WithColumn("tags"),`,
+		`Scores: field.Array[int64]{}.WithColumn("scores"),`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestStructEnumType(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+)
+
+type User struct {
+	Role     Role
+	Previous Role `+"`gorm:\"->\"`"+`
+}
+`)
+
+	for _, expected := range []string{
+		`Role:     field.Enum[models.Role]{}.WithColumn("role").WithValues(models.RoleAdmin, models.RoleUser),`,
+		"Previous field.ReadOnly[models.Role]",
+		`Previous: field.ReadOnly[models.Role]{}.WithColumn("previous"),`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+
+	// module paths of applications often have no dot, their types aren't mistaken for the standard library's
+	outputDir := generateFromFiles(t, &Generator{}, map[string]string{
+		"go.mod":           "module myapp\n",
+		"models/models.go": "package models\n\ntype Role string\n\nconst RoleAdmin Role = \"admin\"\n\ntype User struct {\n\tRole Role\n}\n",
+	})
+	if content := readFileMust(t, filepath.Join(outputDir, "models", "models.go")); !strings.Contains(content, `Role: field.Enum[models.Role]{}.WithColumn("role").WithValues(models.RoleAdmin),`) {
+		t.Errorf("expected the enum of a module without dot, got:\n%s", content)
+	}
+}
+
+func TestStructDurationType(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import (
+	"time"
+
+	"gorm.io/cli/gorm/genconfig"
+)
+
+var _ = genconfig.Config{DurationUnit: "s"}
+
+type Job struct {
+	Timeout time.Duration
+	Elapsed *time.Duration
+	TTL     time.Duration `+"`gorm:\"type:interval\"`"+`
+}
+`)
+
+	for _, expected := range []string{
+		"Timeout field.Duration",
+		`Timeout: field.Duration{}.WithColumn("timeout").WithUnit(time.Second),`,
+		`Elapsed: field.Duration{}.WithColumn("elapsed").WithUnit(time.Second),`,
+		`TTL:     field.Duration{}.WithColumn("ttl").WithInterval(),`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+
+	content = generateFromSource(t, `package models
+
+import "time"
+
+type Job struct {
+	Timeout time.Duration
+}
+`)
+	if expected := `Timeout: field.Duration{}.WithColumn("timeout"),`; !strings.Contains(content, expected) {
+		t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+	}
+}
+
+func TestStructNetworkTypes(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import (
+	"net"
+	"net/netip"
+)
+
+type Session struct {
+	ClientIP   net.IP
+	RemoteAddr netip.Addr
+	Network    netip.Prefix
+}
+`)
+
+	for _, expected := range []string{
+		"ClientIP   field.IP[net.IP]",
+		"RemoteAddr field.IP[netip.Addr]",
+		"Network    field.CIDR",
+		`ClientIP:   field.IP[net.IP]{}.WithColumn("client_ip"),`,
+		`RemoteAddr: field.IP[netip.Addr]{}.WithColumn("remote_addr"),`,
+		`Network:    field.CIDR{}.WithColumn("network"),`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestStructDuplicateColumns(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod": "module example.com/models\n",
+		"models.go": `package models
+
+type User struct {
+	Name     string
+	Nickname string ` + "`gorm:\"column:name\"`" + `
+}
+`,
+	})
+
+	g := &Generator{Files: map[string]*File{}, outPath: t.TempDir()}
+	if err := g.Process(inputDir); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+
+	err := g.Gen()
+	if err == nil {
+		t.Fatalf("expected duplicate column error")
+	}
+	for _, expected := range []string{`duplicate column "name" in struct User`, "field Name (", "models.go:4:2)", "field Nickname (", "models.go:5:2)"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q, got %v", expected, err)
+		}
+	}
+}
+
+func TestInterfaceErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "embedded interface not found",
+			src:  "type Query[T any] interface {\n\tMissing[T]\n}",
+			want: "models.go:4:2: interface Query: embedded interface Missing not found",
+		},
+		{
+			name: "expr method without result",
+			src:  "type Query[T any] interface {\n\t// expr(\"age > @age\")\n\tAdults(age int)\n}",
+			want: "models.go:5:2: method Query.Adults: expr method must return a clause.Expression",
+		},
+		{
+			name: "update method without WHERE",
+			src:  "type Query[T any] interface {\n\t// update(\"name=@name\")\n\tRename(name string) error\n}",
+			want: "models.go:5:2: method Query.Rename: update method requires a WHERE condition",
+		},
+		{
+			name: "count method result",
+			src:  "type Query[T any] interface {\n\t// count(\"role=@role\")\n\tCountByRole(role string) (int, error)\n}",
+			want: "models.go:5:2: method Query.CountByRole: count method must return (int64, error)",
+		},
+		{
+			name: "invalid timeout",
+			src:  "type Query[T any] interface {\n\t// SELECT * FROM @@table\n\t// timeout: soon\n\tAll() ([]T, error)\n}",
+			want: `models.go:6:2: method Query.All: invalid timeout "soon"`,
+		},
+		{
+			name: "values batch without slice",
+			src:  "type Query[T any] interface {\n\t// INSERT INTO @@table (name) VALUES {{values @name batch=10}}\n\tInsert(name string) error\n}",
+			want: "models.go:5:2: method Query.Insert: batch of {{values}} requires a slice parameter, got name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputDir := t.TempDir()
+			writeFiles(t, inputDir, map[string]string{
+				"go.mod":    "module example.com/models\n",
+				"models.go": "package models\n\n" + tt.src + "\n",
+			})
+
+			g := &Generator{Files: map[string]*File{}, outPath: t.TempDir()}
+			err := g.Process(inputDir)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestDocComments(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+// User is a user of the system
+type User struct {
+	// Name is the display name
+	Name string
+	Age  int // Age in years
+}
+
+// Query queries users
+type Query[T any] interface {
+	// ByName finds a user by name
+	//
+	// SELECT * FROM @@table WHERE name=@name
+	ByName(name string) (T, error)
+
+	// SELECT * FROM @@table WHERE age=@age
+	ByAge(age int) (T, error)
+}
+`)
+
+	for _, expected := range []string{
+		"// User is a user of the system\nvar User = _User{",
+		"\t// Name is the display name\n\tName field.String\n",
+		"\t// Age in years\n\tAge field.Number[int]\n",
+		"// Query queries users\nfunc Query[T any](",
+		"\t// ByName finds a user by name\n\tByName(ctx context.Context, name string) (T, error)\n",
+		"// ByName finds a user by name\nfunc (e _QueryImpl[T]) ByName(",
+		"(T, error)\n\tByAge(ctx context.Context, age int) (T, error)",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(content, "// SELECT") {
+		t.Errorf("expected SQL templates to be left out of doc comments, got:\n%s", content)
+	}
+}
+
+func TestStructSerializerType(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type User struct {
+	Tags     []string          `+"`gorm:\"serializer:json\"`"+`
+	Settings map[string]string `+"`gorm:\"type:json;serializer:json\"`"+`
+	Scores   map[string]*int   `+"`gorm:\"serializer:json\"`"+`
+	Backup   map[string]string `+"`gorm:\"serializer:gob\"`"+`
+	Profile  string            `+"`gen:\"json\"`"+`
+}
+`)
+
+	for _, expected := range []string{
+		"Tags     field.JSON",
+		"Settings field.Map[string, string]",
+		"Scores   field.Map[string, *int]",
+		"Backup   field.Field[map[string]string]",
+		`Tags:     field.JSON{}.WithColumn("tags"),`,
+		"Profile  field.JSON",
+		`Profile:  field.JSON{}.WithColumn("profile"),`,
+		`Settings: field.Map[string, string]{}.WithColumn("settings"),`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestStructAssociationMetadata(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type User struct {
+	ID        uint
+	CompanyID int
+	Company   Company
+	Pets      []Pet `+"`gorm:\"foreignKey:OwnerID\"`"+`
+	Languages []Language `+"`gorm:\"many2many:user_languages\"`"+`
+}
+
+type Company struct {
+	ID int
+}
+
+type Pet struct {
+	ID      uint
+	OwnerID uint
+}
+
+type Language struct {
+	Code string `+"`gorm:\"primaryKey\"`"+`
+}
+`)
+
+	for _, expected := range []string{
+		`WithName("Company").WithMetadata(field.AssociationMetadata{Kind: field.BelongsTo, ForeignKey: "company_id", References: "id"})`,
+		`WithName("Pets").WithMetadata(field.AssociationMetadata{Kind: field.HasMany, ForeignKey: "owner_id", References: "id"})`,
+		`WithName("Languages").WithMetadata(field.AssociationMetadata{Kind: field.Many2Many, ForeignKey: "id", References: "code", JoinTable: "user_languages", JoinForeignKey: "user_id", JoinReferences: "language_code"})`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestStructPermissionTags(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type User struct {
+	Name      string
+	Age       int    `+"`gorm:\"->\"`"+`
+	CreatedBy string `+"`gorm:\"<-:create\"`"+`
+	UpdatedBy string `+"`gorm:\"<-:update\"`"+`
+	Ignored   string `+"`gorm:\"-\"`"+`
+	Computed  string `+"`gorm:\"-:migration\"`"+`
+}
+`)
+
+	for _, expected := range []string{
+		"\tName      field.String\n",
+		"\tAge       field.ReadOnly[int]\n",
+		"\tCreatedBy field.CreateOnly[string]\n",
+		"\tUpdatedBy field.String\n",
+		"\tComputed  field.String\n",
+		`Age:       field.ReadOnly[int]{}.WithColumn("age"),`,
+		`CreatedBy: field.CreateOnly[string]{}.WithColumn("created_by"),`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(content, "Ignored") {
+		t.Errorf("expected fields ignored by gorm to be skipped, got:\n%s", content)
+	}
+}
+
+func TestStructGeneric(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type Audited[T any, K comparable] struct {
+	ID   K
+	Data T
+	Name string
+}
+`)
+
+	for _, expected := range []string{
+		"func Audited[T any, K comparable]() _Audited[T, K] {\n\treturn _Audited[T, K]{",
+		`ID:   field.Field[K]{}.WithColumn("id"),`,
+		`Data: field.Field[T]{}.WithColumn("data"),`,
+		"type _Audited[T any, K comparable] struct {",
+		"func (s _Audited[T, K]) AllFields() []field.Selectable {",
+		"func (_Audited[T, K]) TableName() string {",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(content, "var Audited =") {
+		t.Errorf("expected no helper variable for generic struct, got:\n%s", content)
+	}
+}
+
+func TestQueryInterfaceEmbedding(t *testing.T) {
+	outputDir := generateFromFiles(t, &Generator{}, map[string]string{
+		"base/base.go": `package base
+
+import "time"
+
+type Query[T any] interface {
+	// SELECT * FROM @@table WHERE id=@id
+	GetByID(id int) (T, error)
+	// SELECT * FROM @@table WHERE created_at > @since
+	CreatedAfter(since time.Time) ([]T, error)
+}
+`,
+		"models/shared.go": `package models
+
+type Shared[T any] interface {
+	// SELECT * FROM @@table WHERE name=@name
+	ByName(name string) ([]T, error)
+}
+`,
+		"models/order.go": `package models
+
+import "example.com/models/base"
+
+type Order struct {
+	Number string
+}
+
+type Local[T any] interface {
+	// SELECT * FROM @@table WHERE number=@no
+	ByNumber(no string) (T, error)
+}
+
+type OrderQuery[T any] interface {
+	base.Query[T]
+	Local[T]
+	Shared[T]
+	// SELECT * FROM @@table WHERE number=@no LIMIT 1
+	ByNumber(no string) (T, error)
+}
+`,
+	})
+
+	content := readFileMust(t, filepath.Join(outputDir, "models", "order.go"))
+	for _, expected := range []string{
+		"func (e _OrderQueryImpl[T]) GetByID(ctx context.Context, id int) (T, error) {",
+		"func (e _OrderQueryImpl[T]) CreatedAfter(ctx context.Context, since time.Time) ([]T, error) {",
+		"func (e _OrderQueryImpl[T]) ByName(ctx context.Context, name string) ([]T, error) {",
+		"func (e _OrderQueryImpl[T]) ByNumber(ctx context.Context, no string) (T, error) {",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	if n := strings.Count(content, ") ByNumber("); n != 2 {
+		t.Errorf("expected ByNumber once per interface, got %d", n)
+	}
+}
+
+func TestContextParam(t *testing.T) {
+	const query = `
+type Query[T any] interface {
+	// SELECT * FROM @@table WHERE id=@id
+	GetByID(id int) (T, error)
+	// SELECT * FROM @@table WHERE name=@name
+	ByName(ctx context.Context, name string) ([]T, error)
+}
+`
+
+	t.Run("inject", func(t *testing.T) {
+		content := generateFromSource(t, "package models\n\nimport \"context\"\n"+query)
+		for _, expected := range []string{
+			"GetByID(ctx context.Context, id int) (T, error)",
+			"ByName(ctx context.Context, name string) ([]T, error)",
+		} {
+			if !strings.Contains(content, expected) {
+				t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+			}
+		}
+	})
+
+	t.Run("omit", func(t *testing.T) {
+		content := generateFromSource(t, "package models\n\nimport (\n\t\"context\"\n\n\t\"gorm.io/cli/gorm/genconfig\"\n)\n\nvar _ = genconfig.Config{ContextParam: \"omit\"}\n"+query)
+		for _, expected := range []string{
+			"GetByID(id int) (T, error)",
+			"Scan(context.Background(), &result)",
+			"ByName(ctx context.Context, name string) ([]T, error)",
+			"Scan(ctx, &result)",
+		} {
+			if !strings.Contains(content, expected) {
+				t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+			}
+		}
+	})
+
+	t.Run("require", func(t *testing.T) {
+		inputDir := t.TempDir()
+		writeFiles(t, inputDir, map[string]string{
+			"go.mod":    "module example.com/models\n",
+			"models.go": "package models\n\nimport \"context\"\n" + query,
+		})
+
+		g := &Generator{Files: map[string]*File{}, outPath: t.TempDir(), contextParam: "require"}
+		if err := g.Process(inputDir); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		err := g.Gen()
+		if err == nil || !strings.Contains(err.Error(), "method Query.GetByID must declare a ctx context.Context parameter") {
+			t.Fatalf("expected missing ctx error, got %v", err)
+		}
+	})
+}
+
+func TestStructAlias(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type Employee struct {
+	ID        uint
+	Name      string
+	Code      string `+"`gorm:\"->\"`"+`
+	ManagerID *uint
+	Manager   *Employee
+}
+
+type Audited[T any] struct {
+	Data T
+}
+`)
+
+	for _, expected := range []string{
+		"func EmployeeAs(alias string) _EmployeeAlias {",
+		`Name:      field.String{}.WithColumn("name").WithTable(alias),`,
+		`Code:      field.ReadOnly[string]{}.WithColumn("code").WithTable(alias),`,
+		`ManagerID: field.Null[uint]{}.WithColumn("manager_id").WithTable(alias),`,
+		`Manager:   field.Struct[models.Employee]{}.WithName("Manager").WithMetadata(`,
+		"type _EmployeeAlias struct {\n\t_Employee",
+		"return clause.Table{Name: \"employees\", Alias: a.Alias}",
+		"func AuditedAs[T any](alias string) _AuditedAlias[T] {",
+		"type _AuditedAlias[T any] struct {\n\t_Audited[T]",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestStructScopes(t *testing.T) {
+	const config = "package models\n\nimport \"gorm.io/cli/gorm/genconfig\"\n\nvar _ = genconfig.Config{Scopes: map[string]any{%s}}\n\ntype User struct {\n\tName string\n\tAge  int\n}\n"
+
+	content := generateFromSource(t, fmt.Sprintf(config, "\"User.Adults\": []string{`Age.Gte(18)`}, \"User.Named\": []string{`Name.Eq(\"jinzhu\")`, `Age.Gt(0)`}, \"Pet.Young\": []string{`Age.Lt(1)`}"))
+	for _, expected := range []string{
+		"var UserScopes = _UserScopes{}",
+		"func (_UserScopes) Adults() func(*gorm.Statement) {",
+		"\t\t\tUser.Age.Gte(18),\n\t\t}})",
+		"func (_UserScopes) Named() func(*gorm.Statement) {",
+		"\t\t\tUser.Name.Eq(\"jinzhu\"),\n\t\t\tUser.Age.Gt(0),\n",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(content, "Young") {
+		t.Errorf("expected no scopes of other models, got:\n%s", content)
+	}
+
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod":    "module example.com/models\n",
+		"models.go": fmt.Sprintf(config, "\"User.Old\": []string{`Birthday.Lt(time.Now())`}"),
+	})
+
+	g := &Generator{Files: map[string]*File{}, outPath: t.TempDir()}
+	if err := g.Process(inputDir); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+	if err := g.Gen(); err == nil || !strings.Contains(err.Error(), "scope User.Old references unknown field Birthday") {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestStructFieldFilters(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import (
+	"time"
+
+	"gorm.io/cli/gorm/genconfig"
+)
+
+var _ = genconfig.Config{
+	IncludeFields: []any{"User.ID", "User.Name", "User.DeletedAt"},
+	ExcludeFields: []any{"*.DeletedAt", "Pet.Secret"},
+}
+
+type User struct {
+	ID        uint
+	Name      string
+	Profile   string
+	DeletedAt *time.Time
+}
+
+type Pet struct {
+	ID        uint
+	Name      string
+	Secret    string
+	DeletedAt *time.Time
+}
+`)
+
+	for _, expected := range []string{
+		"type _User struct {\n\tID   field.Number[uint]\n\tName field.String\n}",
+		"type _Pet struct {\n\tID   field.Number[uint]\n\tName field.String\n}",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestRequireMarker(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{RequireMarker: true}
+
+// User is a generated model
+//
+//gorm:generate
+type User struct {
+	Name string
+}
+
+// UserDTO is never generated
+type UserDTO struct {
+	Name string
+}
+
+type (
+	//gorm:generate
+	Pet struct {
+		Name string
+	}
+
+	Toy struct {
+		Name string
+	}
+)
+
+//gorm:generate
+type Query[T any] interface {
+	// SELECT * FROM @@table WHERE name=@name
+	ByName(name string) ([]T, error)
+}
+
+type Skipped[T any] interface {
+	// SELECT * FROM @@table WHERE name=@name
+	ByName(name string) ([]T, error)
+}
+`)
+
+	for _, expected := range []string{"// User is a generated model\nvar User = _User{", "var Pet = _Pet{", "func Query[T any]("} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	for _, unexpected := range []string{"UserDTO", "Toy", "Skipped", "gorm:generate"} {
+		if strings.Contains(content, unexpected) {
+			t.Errorf("expected no %q in generated code, got:\n%s", unexpected, content)
+		}
+	}
+}
+
+func TestGeneratorConfigFile(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod": "module example.com/models\n",
+		"gorm.gen.go": `//go:build gorm
+
+package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{ExcludeStructs: []any{"UserDTO"}}
+`,
+		"models.go": "package models\n\ntype User struct {\n\tName string\n}\n\ntype UserDTO struct {\n\tName string\n}\n",
+	})
+
+	// The config file applies whether the package or a single file is the input
+	for _, input := range []string{inputDir, filepath.Join(inputDir, "models.go")} {
+		outputDir := t.TempDir()
+		g := &Generator{Files: map[string]*File{}, outPath: outputDir}
+		if err := g.Process(input); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		if err := g.Gen(); err != nil {
+			t.Fatalf("Gen error: %v", err)
+		}
+
+		content := readFileMust(t, filepath.Join(outputDir, "models.go"))
+		if !strings.Contains(content, "var User = _User{") || strings.Contains(content, "UserDTO") {
+			t.Errorf("expected config of gorm.gen.go applied for input %s, got:\n%s", input, content)
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, "gorm.gen.go")); !os.IsNotExist(err) {
+			t.Errorf("expected no output for the config file, got %v", err)
+		}
+	}
+}
+
+func TestConfigResolvesConstants(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod":           "module example.com/models\n",
+		"shared/shared.go": "package shared\n\nconst Prefix = \"Admin\"\n",
+		"models/consts.go": "package models\n\nconst mergeOutput = true\n\nvar excludes = []any{\"Tmp*\"}\n",
+		"models/models.go": `package models
+
+import (
+	"example.com/models/shared"
+	"gorm.io/cli/gorm/genconfig"
+)
+
+const outPackage = "query"
+
+var header = "// Code generated by " + tool + ". DO NOT EDIT."
+
+const tool = "gorm"
+
+var _ = genconfig.Config{
+	OutPackage:     outPackage,
+	Header:         header,
+	MergeOutput:    mergeOutput,
+	ExcludeStructs: excludes,
+	IncludeStructs: []any{"User", shared.Prefix + "*", "Tmp*"},
+}
+
+type User struct{ Name string }
+
+type AdminUser struct{ Name string }
+
+type TmpUser struct{ Name string }
+
+type Pet struct{ Name string }
+`,
+	})
+
+	outputDir := t.TempDir()
+	g := &Generator{Files: map[string]*File{}, outPath: outputDir}
+	if err := g.Process(filepath.Join(inputDir, "models")); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+
+	var cfg *genconfig.Config
+	for _, f := range g.Files {
+		if f.Config != nil {
+			cfg = f.Config
+		}
+	}
+	if cfg == nil {
+		t.Fatalf("expected config to be parsed")
+	}
+
+	if cfg.OutPackage != "query" || cfg.Header != "// Code generated by gorm. DO NOT EDIT." || !cfg.MergeOutput {
+		t.Errorf("unexpected resolved config: %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.ExcludeStructs, []any{"Tmp*"}) {
+		t.Errorf("expected ExcludeStructs resolved from a variable, got %v", cfg.ExcludeStructs)
+	}
+	if !reflect.DeepEqual(cfg.IncludeStructs, []any{"User", "Admin*", "Tmp*"}) {
+		t.Errorf("expected IncludeStructs resolved from an imported constant, got %v", cfg.IncludeStructs)
+	}
+}
+
+func TestConfigUnknownField(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod":    "module example.com/models\n",
+		"models.go": "package models\n\nimport \"gorm.io/cli/gorm/genconfig\"\n\nvar _ = genconfig.Config{\n\tOutPaths: \"query\",\n}\n\ntype User struct{ Name string }\n",
+	})
+
+	g := &Generator{Files: map[string]*File{}, outPath: t.TempDir()}
+	err := g.Process(inputDir)
+	if err == nil || !strings.Contains(err.Error(), "models.go:6:2: unknown genconfig.Config field OutPaths") {
+		t.Fatalf("expected unknown field error with position, got %v", err)
+	}
+}
+
+func TestConfigScope(t *testing.T) {
+	const models = "package %s\n\ntype User struct{ Name string }\n\ntype Pet struct{ Name string }\n\ntype Toy struct{ Name string }\n"
+	const config = "package %s\n\nimport \"gorm.io/cli/gorm/genconfig\"\n\nvar _ = genconfig.Config{%s}\n"
+
+	outputDir := generateFromFiles(t, &Generator{}, map[string]string{
+		"models.go":        fmt.Sprintf(models, "models"),
+		"config.go":        fmt.Sprintf(config, "models", `ExcludeStructs: []any{"Toy"}`),
+		"pkgonly.go":       fmt.Sprintf(config, "models", `ExcludeStructs: []any{"Pet"}, Scope: "package"`),
+		"sub/models.go":    fmt.Sprintf(models, "sub"),
+		"sub/config.go":    fmt.Sprintf(config, "sub", `ExcludeStructs: []any{"User"}, NoInherit: true`),
+		"nested/models.go": fmt.Sprintf(models, "nested"),
+	})
+
+	for file, expected := range map[string][]string{
+		"models.go":        {"User"},
+		"sub/models.go":    {"Pet", "Toy"},
+		"nested/models.go": {"User", "Pet"},
+	} {
+		content := readFileMust(t, filepath.Join(outputDir, file))
+		for _, name := range []string{"User", "Pet", "Toy"} {
+			if generated := strings.Contains(content, "var "+name+" = "); generated != slices.Contains(expected, name) {
+				t.Errorf("%s: expected %s generated to be %v, got:\n%s", file, name, !generated, content)
+			}
+		}
+	}
+}
+
+func TestStructNullable(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import "time"
+
+type User struct {
+	Birthday *time.Time
+	Code     *string `+"`gorm:\"->\"`"+`
+	Score    *int    `+"`gorm:\"<-:create\"`"+`
+}
+
+type Box[T any] struct {
+	Value *T
+}
+`)
+
+	for _, expected := range []string{
+		"Birthday field.Null[time.Time]",
+		`Birthday: field.Null[time.Time]{}.WithColumn("birthday"),`,
+		"Code     field.ReadOnly[string]",
+		"Score    field.Null[int]",
+		"Value field.Null[T]",
+		`Value: field.Null[T]{}.WithColumn("value"),`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(content, "type _User_") {
+		t.Errorf("expected no dedicated helper types of nullable fields, got:\n%s", content)
+	}
+}
+
+func TestStructDefaults(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type User struct {
+	ID     uint
+	Role   string `+"`gorm:\"default:'active'\"`"+`
+	Score  int    `+"`gorm:\"column:points;default:10\"`"+`
+	Code   *int   `+"`gorm:\"default:0\"`"+`
+	Name   string
+}
+
+type Pet struct {
+	Name string
+}
+`)
+
+	for _, expected := range []string{
+		"Role:  field.String{}.WithColumn(\"role\"),",
+		"Score: field.Number[int]{}.WithColumn(\"points\"),",
+		"Code:  field.Null[int]{}.WithColumn(\"code\"),",
+		"func (_User) Defaults() map[string]string {\n\treturn map[string]string{\n\t\t\"role\":   \"'active'\",\n\t\t\"points\": \"10\",\n\t\t\"code\":   \"0\",\n\t}\n}",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	for _, unexpected := range []string{"type _User_", "func (_Pet) Defaults()"} {
+		if strings.Contains(content, unexpected) {
+			t.Errorf("unexpected %q in generated code, got:\n%s", unexpected, content)
+		}
+	}
+}
+
+func TestStructPrimaryKey(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type User struct {
+	ID   uint
+	Name string
+}
+
+type Language struct {
+	Code string `+"`gorm:\"primaryKey\"`"+`
+	Name string
+}
+
+type Membership struct {
+	UserID  uint `+"`gorm:\"primaryKey\"`"+`
+	GroupID uint `+"`gorm:\"primaryKey\"`"+`
+}
+
+type Log struct {
+	Message string
+}
+`)
+
+	for _, expected := range []string{
+		"func (s _User) PrimaryKey() []clause.Column {\n\treturn []clause.Column{\n\t\ts.ID.Column(),\n\t}\n}",
+		"func (s _User) ByID(id uint) clause.Expression {\n\treturn s.ID.Eq(id)\n}",
+		"func (s _Language) ByID(id string) clause.Expression {\n\treturn s.Code.Eq(id)\n}",
+		"func (s _Membership) PrimaryKey() []clause.Column {\n\treturn []clause.Column{\n\t\ts.UserID.Column(),\n\t\ts.GroupID.Column(),\n\t}\n}",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	for _, unexpected := range []string{"func (s _Membership) ByID", "func (s _Log) PrimaryKey", "func (s _Log) ByID"} {
+		if strings.Contains(content, unexpected) {
+			t.Errorf("unexpected %q in generated code, got:\n%s", unexpected, content)
+		}
+	}
+}
+
+func TestStructUniqueIndexes(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type User struct {
+	ID        uint
+	Email     string `+"`gorm:\"uniqueIndex\"`"+`
+	Name      string `+"`gorm:\"uniqueIndex:idx_name_tenant,priority:2\"`"+`
+	TenantID  uint   `+"`gorm:\"uniqueIndex:idx_name_tenant,priority:1;index\"`"+`
+	Code      string `+"`gorm:\"unique\"`"+`
+	Slug      string `+"`gorm:\"index:idx_slug,unique\"`"+`
+	Nickname  string `+"`gorm:\"index\"`"+`
+}
+
+type Log struct {
+	Message string `+"`gorm:\"index\"`"+`
+}
+`)
+
+	for _, expected := range []string{
+		"UniqueIndexes: _UserUniqueIndexes{Email: field.UniqueIndex{}.WithName(\"idx_users_email\").WithColumns(\"email\"), TenantIDName: field.UniqueIndex{}.WithName(\"idx_name_tenant\").WithColumns(\"tenant_id\", \"name\"), Code: field.UniqueIndex{}.WithName(\"uni_users_code\").WithColumns(\"code\"), Slug: field.UniqueIndex{}.WithName(\"idx_slug\").WithColumns(\"slug\")},",
+		"\tUniqueIndexes _UserUniqueIndexes\n}",
+		"type _UserUniqueIndexes struct {\n\t// Email is the unique index idx_users_email\n\tEmail field.UniqueIndex",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	for _, unexpected := range []string{"Nickname: field.UniqueIndex", "_LogUniqueIndexes"} {
+		if strings.Contains(content, unexpected) {
+			t.Errorf("unexpected %q in generated code, got:\n%s", unexpected, content)
+		}
+	}
+}
+
+func TestStructColumnSchema(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type Product struct {
+	ID    uint
+	Code  string  `+"`gorm:\"type:varchar(32);not null\"`"+`
+	Name  string  `+"`gorm:\"size:100\"`"+`
+	Price float64 `+"`gorm:\"precision:10;scale:2;default:0\"`"+`
+	Note  string
+}
+`)
+
+	for _, expected := range []string{
+		"Code  field.String\n",
+		"func (_Product) Schemas() map[string]field.ColumnSchema {\n\treturn map[string]field.ColumnSchema{\n\t\t\"code\":  {Type: \"varchar(32)\", NotNull: true},\n\t\t\"name\":  {Size: 100},\n\t\t\"price\": {Precision: 10, Scale: 2},\n\t}\n}",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(content, "\"note\":") {
+		t.Errorf("expected no schema of fields without schema tags, got:\n%s", content)
+	}
+}
+
+func TestStructVersionField(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+type Order struct {
+	ID       uint
+	Revision int64 `+"`gen:\"version\"`"+`
+}
+
+type Invoice struct {
+	ID      uint
+	Version uint
+}
+
+type Log struct {
+	Version string
+}
+`)
+
+	for _, expected := range []string{
+		"Revision field.Version[int64]",
+		`Revision: field.Version[int64]{}.WithColumn("revision"),`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(content, "field.Version[uint]") {
+		t.Errorf("expected no version helper without tag or VersionField config, got:\n%s", content)
+	}
+
+	t.Run("config", func(t *testing.T) {
+		content := generateFromSource(t, `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{VersionField: "Version"}
+
+type Invoice struct {
+	ID      uint
+	Version uint
+}
+`)
+		if expected := `Version: field.Version[uint]{}.WithColumn("version"),`; !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	})
+
+	t.Run("config skips non-integer names", func(t *testing.T) {
+		content := generateFromSource(t, `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{VersionField: "Version"}
+
+type Release struct {
+	ID      uint
+	Version string
+}
+`)
+		if strings.Contains(content, "field.Version[") || !strings.Contains(content, `Version: field.String{}.WithColumn("version"),`) {
+			t.Errorf("expected a plain string helper for a non-integer Version, got:\n%s", content)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"go.mod":    "module example.com/models\n\ngo 1.24\n",
+			"models.go": "package models\n\ntype Log struct {\n\tVersion string `gen:\"version\"`\n}\n",
+		})
+
+		g := &Generator{Files: map[string]*File{}, outPath: filepath.Join(dir, "out")}
+		if err := g.Process(dir); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		if err := g.Gen(); err == nil || !strings.Contains(err.Error(), "version field Version") {
+			t.Fatalf("expected version field type error, got %v", err)
+		}
+	})
+}
+
+func TestStructSoftDelete(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import "gorm.io/gorm"
+
+type User struct {
+	ID      uint
+	Removed gorm.DeletedAt `+"`gorm:\"column:removed_at\"`"+`
+}
+
+type Log struct {
+	ID uint
+}
+`)
+
+	for _, expected := range []string{
+		"func (_User) WithDeleted() func(*gorm.Statement) {\n\treturn func(stmt *gorm.Statement) {\n\t\tstmt.Unscoped = true\n\t}\n}",
+		"func (s _User) OnlyDeleted() func(*gorm.Statement) {",
+		"clause.Expr{SQL: \"? IS NOT NULL\", Vars: []any{s.Removed.Column()}},",
+		"func (s _User) NotDeleted() clause.Expression {\n\treturn clause.Expr{SQL: \"? IS NULL\", Vars: []any{s.Removed.Column()}}\n}",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(content, "func (_Log) WithDeleted") {
+		t.Errorf("expected no soft delete helpers without gorm.DeletedAt, got:\n%s", content)
+	}
+}
+
+func TestStructRepository(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{CRUD: true}
+
+type User struct {
+	ID   int64
+	Name string
+}
+
+type Membership struct {
+	UserID  uint `+"`gorm:\"primaryKey\"`"+`
+	GroupID uint `+"`gorm:\"primaryKey\"`"+`
+}
+
+//gorm:view
+type ActiveUser struct {
+	ID uint
+}
+`)
+
+	for _, expected := range []string{
+		"type UserRepository interface {",
+		"GetByID(ctx context.Context, id int64) (models.User, error)",
+		"List(ctx context.Context, conds ...clause.Expression) ([]models.User, error)",
+		"Update(ctx context.Context, id int64, assignments ...clause.Assigner) (int, error)",
+		"func NewUserRepository(db *gorm.DB) UserRepository {\n\treturn _UserRepository{db: db}\n}",
+		"return gorm.G[models.User](r.db).Where(User.ByID(id)).First(ctx)",
+		"return gorm.G[models.User](r.db).Create(ctx, record)",
+		"return gorm.G[models.User](r.db).Where(User.ByID(id)).Set(assignments...).Update(ctx)",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	for _, unexpected := range []string{"MembershipRepository", "ActiveUserRepository"} {
+		if strings.Contains(content, unexpected) {
+			t.Errorf("unexpected %q in generated code, got:\n%s", unexpected, content)
+		}
+	}
+
+	if content := generateFromSource(t, "package models\n\ntype User struct {\n\tID uint\n}\n"); strings.Contains(content, "UserRepository") {
+		t.Errorf("expected no repository without CRUD, got:\n%s", content)
+	}
+}
+
+func TestStructFixture(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import (
+	"time"
+
+	"gorm.io/cli/gorm/genconfig"
+	"gorm.io/gorm"
+)
+
+var _ = genconfig.Config{Factories: true}
+
+type Status string
+
+const (
+	StatusActive  Status = "active"
+	StatusBlocked Status = "blocked"
+)
+
+type User struct {
+	gorm.Model
+	Name      string
+	Email     string `+"`gorm:\"uniqueIndex\"`"+`
+	Status    Status
+	Role      string `+"`gorm:\"default:member\"`"+`
+	Nickname  *string
+	Age       int
+	LastLogin time.Time
+	Version   int    `+"`gen:\"version\"`"+`
+	Slug      string `+"`gorm:\"->\"`"+`
+}
+
+type Tag struct {
+	ID uint
+}
+`)
+
+	for _, expected := range []string{
+		"var _UserFixtureSeq atomic.Int64",
+		"func NewUserFixture(overrides ...func(*models.User)) models.User {\n\tseq := _UserFixtureSeq.Add(1)\n\trecord := models.User{}\n" +
+			"\trecord.Name = fmt.Sprintf(\"name-%d\", seq)\n\trecord.Email = fmt.Sprintf(\"email-%d\", seq)\n" +
+			"\trecord.Status = models.StatusActive\n\trecord.LastLogin = time.Now()\n" +
+			"\tfor _, override := range overrides {\n\t\toverride(&record)\n\t}\n\treturn record\n}",
+		"func NewTagFixture(overrides ...func(*models.Tag)) models.Tag {\n\trecord := models.Tag{}\n",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(content, "_TagFixtureSeq") {
+		t.Errorf("expected no fixture sequence without default strings, got:\n%s", content)
+	}
+}
+
+func TestStructDefinedTypes(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import "time"
+
+type Email string
+
+type Money = int64
+
+type Level uint8
+
+type Active bool
+
+type Stamp = time.Time
+
+type Payload []byte
+
+type User struct {
+	ID      uint
+	Email   Email
+	Backup  *Email
+	Balance Money
+	Level   Level
+	Active  Active
+	Joined  Stamp
+	Payload Payload
+}
+`)
+
+	for _, expected := range []string{
+		"Email   field.String\n",
+		"Balance field.Number[models.Money]\n",
+		"Level   field.Number[models.Level]\n",
+		"Active  field.Bool\n",
+		"Joined  field.Time\n",
+		"Payload field.Bytes\n",
+		"Backup  field.Null[string]\n",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestSQLTemplateErrorPosition(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod": "module example.com/models\n",
+		"query.go": `package models
+
+type Query[T any] interface {
+	// FindByName finds users by name
+	//
+	// SELECT * FROM @@table
+	// {{where}}
+	//   {{if name != ""}} name=@name
+	// {{end}}
+	FindByName(name string) ([]T, error)
+
+	// where("age > @age {{if max > 0}} AND age < @max")
+	FilterByAge(age, max int)
+}
+`,
+	})
+
+	g := &Generator{Files: map[string]*File{}, outPath: t.TempDir()}
+	if err := g.Process(inputDir); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+	err := g.Gen()
+	if want := filepath.Join(inputDir, "query.go") + ":7:5: unclosed {{where}} block"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+
+	method := g.Files[filepath.Join(inputDir, "query.go")].Interfaces[0].Methods[1]
+	if pos := method.sqlPosition(method.SQL.Where, 1, 12); pos.Line != 12 || pos.Column != 23 {
+		t.Errorf("expected the {{if}} of FilterByAge at 12:23, got %v", pos)
+	}
+
+	// the error is returned by the body of the method, not raised as a panic
+	if _, err := method.Body(); err == nil || !strings.Contains(err.Error(), ":12:23: unclosed {{if}} block") {
+		t.Errorf("expected the unclosed {{if}} of FilterByAge returned by Body, got %v", err)
+	}
+}
+
+func TestSQLFragments(t *testing.T) {
+	outDir := generateFromFiles(t, &Generator{}, map[string]string{
+		"config.go": `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{
+	SQLFragments: map[string]string{
+		"activeFilter": "role = 'active' AND deleted_at IS NULL",
+		"byName":       "{{if name != \"\"}} AND name=@name {{end}}",
+	},
+}
+`,
+		"query.go": `package models
+
+// Query of users
+//
+// {{define "activeFilter"}}status = 'active' AND deleted_at IS NULL{{end}}
+// {{define "adults"}}
+//   {{if adults}} AND age >= 18 {{end}}
+// {{end}}
+type Query[T any] interface {
+	// SELECT * FROM @@table WHERE {{include "activeFilter"}} {{include "byName"}} {{include "adults"}}
+	FindActive(name string, adults bool) ([]T, error)
+}
+`,
+	})
+
+	content := readFileMust(t, filepath.Join(outDir, "query.go"))
+	for _, want := range []string{
+		`sb.WriteString(" status = 'active' AND deleted_at IS NULL")`, // the interface's fragment wins
+		`if name != "" {`,
+		`sb.WriteString(" AND name=?")`,
+		`if adults {`,
+		`sb.WriteString(" AND age >= 18")`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	tests := []struct {
+		fragments map[string]string
+		tmpl      string
+		want      string
+	}{
+		{tmpl: `SELECT * FROM users WHERE {{include "missing"}}`, want: `1:27: unknown fragment: "missing"`},
+		{fragments: map[string]string{"a": `id=@id {{include "b"}}`, "b": `{{include "a"}}`}, tmpl: `SELECT * FROM users WHERE {{include "a"}}`, want: `1:27: in fragment "a" at 1:8: in fragment "b" at 1:1: fragment "a" includes itself`},
+		{fragments: map[string]string{"open": `{{if id > 0}} id=@id`}, tmpl: `SELECT * FROM users WHERE {{include "open"}} {{end}}`, want: `1:27: unbalanced blocks in fragment "open"`},
+	}
+	for _, tt := range tests {
+		if _, err := renderSQLTemplate(tt.tmpl, sqlTemplateOptions{fragments: tt.fragments}); err == nil || err.Error() != tt.want {
+			t.Errorf("renderSQLTemplate(%q) error = %v, want %q", tt.tmpl, err, tt.want)
+		}
+	}
+
+	if _, err := parseSQLFragments(`{{define "a"}} id=@id {{end}} {{define "a"}} name=@name {{end}}`); err == nil || err.Error() != `fragment "a" defined more than once` {
+		t.Errorf("expected duplicated fragment error, got %v", err)
+	}
+}
+
+func TestSQLFile(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod": "module example.com/models\n",
+		"query.go": `package models
+
+type Query[T any] interface {
+	// FindByName finds users by name
+	//
+	// sqlfile: queries/find_by_name.sql
+	FindByName(name string) ([]T, error)
+}
+`,
+		"queries/find_by_name.sql": "SELECT *\nFROM @@table\n{{where}}\n  {{if name != \"\"}} name=@name {{end}}\n{{end}}\n",
+	})
+
+	outDir := t.TempDir()
+	g := &Generator{Files: map[string]*File{}, outPath: outDir}
+	if err := g.Process(inputDir); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+	if err := g.Gen(); err != nil {
+		t.Fatalf("Gen error: %v", err)
+	}
+
+	content := readFileMust(t, filepath.Join(outDir, "query.go"))
+	for _, want := range []string{`sb.WriteString(" FROM ?")`, `if name != "" {`, `tmp.WriteString(" name=?")`, `sb.WriteString(" WHERE ")`} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, content)
+		}
+	}
+	if manifest := readFileMust(t, filepath.Join(outDir, manifestFileName)); !strings.Contains(manifest, "queries/find_by_name.sql") {
+		t.Errorf("expected the sqlfile in the inputs of the manifest, got:\n%s", manifest)
+	}
+
+	sqlFile := filepath.Join(inputDir, "queries", "find_by_name.sql")
+	if err := os.WriteFile(sqlFile, []byte("SELECT * FROM @@table\n{{where}}\n  {{if name != \"\"}} name=@name\n"), 0o644); err != nil {
+		t.Fatalf("failed to write sqlfile: %v", err)
+	}
+	g = &Generator{Files: map[string]*File{}, outPath: t.TempDir()}
+	if err := g.Process(inputDir); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+	if err, want := g.Gen(), sqlFile+":3:3: unclosed {{if}} block"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+}
+
+func TestExecResult(t *testing.T) {
+	tests := []struct {
+		sql    ExtractedSQL
+		result []Param
+		want   string
+	}{
+		{sql: ExtractedSQL{Raw: "UPDATE users SET age=@age"}, result: []Param{{Name: "rowsAffected", Type: "int64"}, {Name: "err", Type: "error"}}, want: execRowsAffected},
+		{sql: ExtractedSQL{Raw: "UPDATE users SET age=@age"}, result: []Param{{Name: "rowsAffected", Type: "int"}, {Name: "err", Type: "error"}}, want: execRowsAffected},
+		{sql: ExtractedSQL{Insert: "@@table, @user.*"}, result: []Param{{Name: "lastInsertId", Type: "int64"}, {Name: "err", Type: "error"}}, want: execLastInsertID},
+		{sql: ExtractedSQL{Raw: "DELETE FROM users"}, result: []Param{{Type: "sql.Result"}, {Type: "error"}}, want: execSQLResult},
+		{sql: ExtractedSQL{Raw: "SELECT count(*) FROM users"}, result: []Param{{Type: "int64"}, {Type: "error"}}},
+		{sql: ExtractedSQL{Raw: "SELECT age FROM users"}, result: []Param{{Name: "rowsAffected", Type: "string"}, {Name: "err", Type: "error"}}},
+		{sql: ExtractedSQL{Where: "age > @age"}, result: []Param{{Name: "rowsAffected", Type: "int64"}, {Name: "err", Type: "error"}}},
+	}
+	for _, tt := range tests {
+		if got := (Method{SQL: tt.sql, Result: tt.result}).execResult(); got != tt.want {
+			t.Errorf("execResult of %+v returning %v = %q, want %q", tt.sql, tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestInsertAnnotation(t *testing.T) {
+	outDir := generateFromFiles(t, &Generator{}, map[string]string{
+		"post.go": `package models
+
+type Author struct {
+	ID   uint
+	Name string
+}
+
+type Post struct {
+	Code     string ` + "`gorm:\"primaryKey\"`" + `
+	Seq      int    ` + "`gorm:\"autoIncrement\"`" + `
+	Title    string
+	Views    int ` + "`gorm:\"->\"`" + `
+	Author   Author
+	AuthorID uint
+	Draft    bool ` + "`gorm:\"-\"`" + `
+}
+
+type Query[T any] interface {
+	// insert(@@table, @post.*)
+	InsertPost(post *Post) error
+}
+`,
+	})
+
+	content := readFileMust(t, filepath.Join(outDir, "post.go"))
+	for _, want := range []string{
+		`sb.WriteString("INSERT INTO ? (code, title, author_id) VALUES (?, ?, ?)")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, post.Code, post.Title, post.AuthorID)",
+		"return e.Exec(ctx, sb.String(), params...)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestMethodTimeout(t *testing.T) {
+	outDir := generateFromFiles(t, &Generator{}, map[string]string{
+		"query.go": `package models
+
+import "iter"
+
+type Query[T any] interface {
+	// GetByID returns the record of id
+	//
+	// timeout: 3s
+	// SELECT * FROM @@table WHERE id=@id
+	GetByID(id int) (T, error)
+
+	// SELECT * FROM @@table ORDER BY id
+	// timeout: 1m30s
+	StreamAll() iter.Seq2[T, error]
+
+	// delete("id=@id")
+	// timeout: 250ms
+	DeleteByID(id int) error
+}
+`,
+	})
+
+	content := readFileMust(t, filepath.Join(outDir, "query.go"))
+	for _, want := range []string{
+		"GetByID(ctx context.Context, id int) (T, error) {\n\tctx, cancel := context.WithTimeout(ctx, 3*time.Second)\n\tdefer cancel()\n",
+		`sb.WriteString("SELECT * FROM ? WHERE id=?")`,
+		"return func(yield func(T, error) bool) {\n\t\tctx, cancel := context.WithTimeout(ctx, 90*time.Second)\n\t\tdefer cancel()\n",
+		`sb.WriteString("SELECT * FROM ? ORDER BY id")`,
+		"DeleteByID(ctx context.Context, id int) error {\n\tctx, cancel := context.WithTimeout(ctx, 250*time.Millisecond)\n\tdefer cancel()\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	for d, want := range map[time.Duration]string{
+		2 * time.Hour:           "2 * time.Hour",
+		1500 * time.Millisecond: "1500 * time.Millisecond",
+		time.Nanosecond:         "time.Duration(1)",
+	} {
+		if got := durationLiteral(d); got != want {
+			t.Errorf("durationLiteral(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestTemplateColumns(t *testing.T) {
+	gen := func(sql string) error {
+		inputDir := t.TempDir()
+		writeFiles(t, inputDir, map[string]string{
+			"go.mod": "module example.com/models\n",
+			"models/user.go": `package models
+
+import "gorm.io/gorm"
+
+type User struct {
+	gorm.Model
+	Name string
+	Role string
+}
+`,
+			"query/query.go": `package query
+
+import "example.com/models/models"
+
+// UserQuery queries users
+//
+// model: models.User
+type UserQuery[T any] interface {
+	// ` + sql + `
+	Recent(role string) ([]T, error)
+}
+`,
+		})
+
+		g := &Generator{Files: map[string]*File{}, outPath: t.TempDir()}
+		if err := g.Process(inputDir); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		return g.Gen()
+	}
+
+	if err := gen("SELECT * FROM @@table WHERE role = @role AND deleted_at IS NULL ORDER BY created_at DESC"); err != nil {
+		t.Errorf("expected known columns to be accepted, got %v", err)
+	}
+	err := gen("SELECT * FROM @@table WHERE role = @role ORDER BY created_time DESC")
+	if err == nil || !strings.Contains(err.Error(), "query.go:9:55: unknown column created_time of model User in UserQuery.Recent") {
+		t.Errorf("expected an unknown column error, got %v", err)
+	}
+}
+
+func TestTemplateParams(t *testing.T) {
+	gen := func(method string) error {
+		inputDir := t.TempDir()
+		content := `package models
+
+const nameColumn = "name"
+
+type Query[T any] interface {
+` + method + `
+}
+`
+		writeFiles(t, inputDir, map[string]string{"go.mod": "module example.com/models\n", "query.go": content})
+
+		g := &Generator{Files: map[string]*File{}, outPath: t.TempDir()}
+		if err := g.Process(inputDir); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		return g.Gen()
+	}
+
+	for _, method := range []string{
+		"\t// SELECT * FROM @@table WHERE @@nameColumn = @user.Name\n\tByUser(user struct{ Name string }) ([]T, error)",
+		"\t// SELECT * FROM @@table WHERE {{if name != \"\"}}name = @name AND{{end}} id IN ({{for i, id := range ids}}{{if i > 0}},{{end}}@id{{end}})\n\tFilter(name string, ids []int) ([]T, error)",
+		"\t// SELECT * FROM @@table {{limit @size}}\n\tFirst(size int) ([]T, error)",
+		"\t// SELECT * FROM @@table WHERE id IN @ids LIMIT @{len(ids)}\n\tByIDs(ids []int) ([]T, error)",
+	} {
+		if err := gen(method); err != nil {
+			t.Errorf("expected %q to be accepted, got %v", method, err)
+		}
+	}
+
+	if err := gen("\t// SELECT * FROM @@table WHERE name = @nmae\n\tByName(name string) ([]T, error)"); err == nil ||
+		!strings.Contains(err.Error(), "query.go:6:40: placeholder @nmae of Query.ByName references undefined parameter nmae") {
+		t.Errorf("expected an undefined parameter error, got %v", err)
+	}
+	if err := gen("\t// SELECT * FROM @@table WHERE name = @{name +}\n\tByName(name string) ([]T, error)"); err == nil ||
+		!strings.Contains(err.Error(), "query.go:6:40: placeholder @{name +} of Query.ByName is an invalid Go expression") {
+		t.Errorf("expected an invalid expression error, got %v", err)
+	}
+	if err := gen("\t// SELECT * FROM @@table WHERE name = @name\n\tByName(name string, age int) ([]T, error)"); err == nil ||
+		!strings.Contains(err.Error(), "parameter age of Query.ByName is never used by its SQL template") {
+		t.Errorf("expected an unused parameter error, got %v", err)
+	}
+}
+
+func TestMultiStatementMethods(t *testing.T) {
+	gen := func(config string) string {
+		outDir := generateFromFiles(t, &Generator{}, map[string]string{
+			"query.go": `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{` + config + `}
+
+type Query[T any] interface {
+	// DELETE FROM pets WHERE owner_id = @id;
+	// DELETE FROM @@table WHERE id = @id
+	DeleteWithPets(id int) error
+}
+`,
+		})
+		return readFileMust(t, filepath.Join(outDir, "query.go"))
+	}
+
+	content := gen("")
+	for _, want := range []string{
+		"return e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {\n\t\tq := gorm.G[T](tx)",
+		`sb.WriteString("DELETE FROM pets WHERE owner_id = ?")`,
+		`sb.WriteString("DELETE FROM ? WHERE id = ?")`,
+		"if err := q.Exec(ctx, sb.String(), params...); err != nil {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	content = gen("SkipMultiStatementTransaction: true")
+	if !strings.Contains(content, "if err := e.Exec(ctx, sb.String(), params...); err != nil {") || strings.Contains(content, "Transaction(") {
+		t.Errorf("expected statements executed without a transaction, got:\n%s", content)
+	}
+}
+
+func TestSQLComments(t *testing.T) {
+	gen := func(config string, stripComments bool) string {
+		outDir := generateFromFiles(t, &Generator{stripComments: stripComments}, map[string]string{
+			"query.go": `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{` + config + `}
+
+type Query[T any] interface {
+	// SELECT /*+ INDEX(users idx_name) */ * FROM @@table
+	// WHERE name = @name -- not by @email, {{if}} isn't a directive here
+	FindByName(name string) ([]T, error)
+}
+`,
+		})
+		return readFileMust(t, filepath.Join(outDir, "query.go"))
+	}
+
+	hint := `sb.WriteString(" /*+ INDEX(users idx_name) */")`
+	comment := `params = append(params, clause.Expr{SQL: "/* not by @email, {{if}} isn't a directive here */"})`
+	if content := gen("", false); !strings.Contains(content, hint) || !strings.Contains(content, comment) {
+		t.Errorf("expected the comments kept, got:\n%s", content)
+	}
+	for _, content := range []string{gen("StripSQLComments: true", false), gen("", true)} {
+		if !strings.Contains(content, hint) || strings.Contains(content, "not by") {
+			t.Errorf("expected the comments stripped but the hint, got:\n%s", content)
+		}
+	}
+}
+
+func TestValuesBatch(t *testing.T) {
+	outDir := generateFromFiles(t, &Generator{}, map[string]string{
+		"query.go": `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{SkipMultiStatementTransaction: true}
+
+type Tag struct {
+	Name string
+}
+
+type Query[T any] interface {
+	// INSERT INTO tags (name) VALUES {{values @tags (Name) batch=50}}
+	InsertTags(tags []Tag) error
+
+	// INSERT INTO tags (name) VALUES {{values @tags (Name) batch=50}}
+	CountInsertedTags(tags []Tag) (rowsAffected int, err error)
+}
+`,
+	})
+
+	content := readFileMust(t, filepath.Join(outDir, "query.go"))
+	for _, want := range []string{
+		"InsertTags(ctx context.Context, tags []models.Tag) error {\n\tfor tags := range slices.Chunk(tags, 50) {",
+		"if err := e.Exec(ctx, sb.String(), params...); err != nil {",
+		"params = append(params, value.Name)",
+		"db := e.db.WithContext(ctx)\n\tfor tags := range slices.Chunk(tags, 50) {",
+		"return int(rowsAffected), tx.Error",
+		"rowsAffected += tx.RowsAffected",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "Transaction(") {
+		t.Errorf("expected batches executed without a transaction, got:\n%s", content)
+	}
+}
+
+func TestScalarQueries(t *testing.T) {
+	outDir := generateFromFiles(t, &Generator{}, map[string]string{
+		"query.go": `package models
+
+type Query[T any] interface {
+	// select count(id) FROM @@table WHERE age > @age
+	CountOlder(age int) (int, error)
+
+	// SELECT EXISTS (SELECT 1 FROM @@table WHERE email = @email)
+	EmailTaken(email string) (bool, error)
+
+	// SELECT max(age) FROM @@table
+	MaxAge() (int, error)
+
+	// postgres: SELECT COUNT(*) FROM @@table WHERE name ILIKE @name
+	// default: SELECT COUNT(*) FROM @@table WHERE LOWER(name) = LOWER(@name)
+	CountByName(name string) (int64, error)
+}
+`,
+	})
+
+	content := readFileMust(t, filepath.Join(outDir, "query.go"))
+	for _, want := range []string{
+		"var result int\n\terr := e.db.WithContext(ctx).Model(r).Raw(sb.String(), params...).Row().Scan(&result)",
+		"var result bool\n\terr := e.db.WithContext(ctx).Model(r).Raw(sb.String(), params...).Row().Scan(&result)",
+		"var result int\n\terr := e.Raw(sb.String(), params...).Scan(ctx, &result)",
+		"var result int64\n\terr := e.db.WithContext(ctx).Model(r).Raw(sb.String(), params...).Row().Scan(&result)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestQueryHook(t *testing.T) {
+	outDir := generateFromFiles(t, &Generator{}, map[string]string{
+		"query.go": `package models
+
+import (
+	"iter"
+
+	"gorm.io/cli/gorm/field"
+	"gorm.io/cli/gorm/internal/drivers"
+	"gorm.io/cli/gorm/genconfig"
+)
+
+var Tracer field.QueryHook
+
+var _ = genconfig.Config{QueryHook: Tracer}
+
+type Query[T any] interface {
+	// SELECT * FROM @@table WHERE id=@id
+	GetByID(id int) (T, error)
+
+	// delete("id=@id")
+	DeleteByID(id int) error
+
+	// SELECT * FROM @@table
+	StreamAll() iter.Seq2[T, error]
+
+	// where("name=@name")
+	FilterByName(name string)
+}
+`,
+	})
+
+	content := readFileMust(t, filepath.Join(outDir, "query.go"))
+	for _, want := range []string{
+		"GetByID(ctx context.Context, id int) (T, error) {\n\ttrace := field.NewQueryTrace(models.Tracer, \"Query.GetByID\")\n\tresult, err := func() (T, error) {",
+		"\tctx = trace.Start(ctx, sb.String(), params)\n\n\t\tvar result T",
+		"}()\n\ttrace.End(err)\n\treturn result, err\n}",
+		"trace := field.NewQueryTrace(models.Tracer, \"Query.DeleteByID\")\n\terr := func() error {",
+		"}()\n\ttrace.End(err)\n\treturn err\n}",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, content)
+		}
+	}
+	if strings.Count(content, "field.NewQueryTrace(") != 2 {
+		t.Errorf("expected iterators and chain methods not to be traced, got:\n%s", content)
+	}
+}
+
+func TestSQLConst(t *testing.T) {
+	inputDir, outDir := t.TempDir(), t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod": "module example.com/models\n",
+		"queries/queries.go": `package queries
+
+const Base = "SELECT * FROM @@table"
+
+const ByName = Base + " WHERE name = @name"
+`,
+		"query.go": `package models
+
+import "example.com/models/queries"
+
+const byID = "SELECT * FROM @@table WHERE id = @id"
+
+var byAge = "SELECT * FROM @@table WHERE age = @age"
+
+const limit = 10
+
+var _ = queries.Base
+
+type Query[T any] interface {
+	// GetByID returns the record of id
+	//
+	// sqlconst: byID
+	GetByID(id int) (T, error)
+
+	// timeout: 2s
+	// sqlconst: queries.ByName
+	FindByName(name string) ([]T, error)
+}
+`,
+	})
+
+	g := &Generator{Files: map[string]*File{}, outPath: outDir}
+	if err := g.Process(inputDir); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+	if err := g.Gen(); err != nil {
+		t.Fatalf("Gen error: %v", err)
+	}
+
+	content := readFileMust(t, filepath.Join(outDir, "query.go"))
+	for _, want := range []string{
+		`sb.WriteString("SELECT * FROM ? WHERE id = ?")`,
+		"ctx, cancel := context.WithTimeout(ctx, 2*time.Second)",
+		`sb.WriteString("SELECT * FROM ? WHERE name = ?")`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	file := g.Files[filepath.Join(inputDir, "query.go")]
+	for name, want := range map[string]string{
+		"":             "sqlconst annotation requires a constant name",
+		"byAge":        "sqlconst byAge not found, it must be a string constant",
+		"limit":        "sqlconst limit not found, it must be a string constant",
+		"queries.Nope": "sqlconst queries.Nope not found",
+		"by-id":        `invalid sqlconst "by-id"`,
+	} {
+		if _, err := file.loadSQLConst(name); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("loadSQLConst(%q) error = %v, want %q", name, err, want)
+		}
+	}
+}
+
+func TestScanResultTypes(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod": "module example.com/models\n",
+		"query.go": `package models
+
+type Stats struct {
+	Role  string
+	Total int64
+}
+
+type Status string
+
+type Handler func()
+
+type Query[T any] interface {
+	// SELECT role, count(*) AS total FROM @@table GROUP BY role
+	CountPerRole() ([]Stats, error)
+}
+`,
+	})
+
+	g := &Generator{Files: map[string]*File{}, outPath: t.TempDir()}
+	if err := g.Process(inputDir); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+	file := g.Files[filepath.Join(inputDir, "query.go")]
+	if file == nil {
+		t.Fatalf("expected query.go to be processed, got %v", g.Files)
+	}
+
+	for _, typ := range []string{"T", "[]T", "[]models.Stats", "*Stats", "[]*Stats", "Status", "int64", "[]time.Time", "map[string]any", "[]map[string]interface{}"} {
+		if err := file.validateScanType(typ); err != nil {
+			t.Errorf("expected %s to be accepted, got %v", typ, err)
+		}
+	}
+	for _, typ := range []string{"Handler", "[]models.Handler"} {
+		if err := file.validateScanType(typ); err == nil || !strings.Contains(err.Error(), "must be T, a struct, a slice of structs or a scalar") {
+			t.Errorf("expected %s to be rejected, got %v", typ, err)
+		}
+	}
+	for _, typ := range []string{"map[string]string", "*map[string]any", "[][]map[string]any"} {
+		if err := file.validateScanType(typ); err == nil || !strings.Contains(err.Error(), "must be map[string]any or []map[string]any") {
+			t.Errorf("expected %s to be rejected, got %v", typ, err)
+		}
+	}
+}
+
+func TestStructNestedEmbedding(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod": "module example.com/models\n",
+		"shared/base.go": `package shared
+
+import "gorm.io/gorm"
+
+type BaseEntity struct {
+	gorm.Model
+	Audit
+}
+
+type Audit struct {
+	CreatedBy string
+	*Revision
+}
+`,
+		"shared/revision.go": `package shared
+
+type Revision struct {
+	Revision int
+	*Audit
+}
+`,
+		"models/models.go": `package models
+
+import "example.com/models/shared"
+
+type User struct {
+	shared.BaseEntity
+	Name string
+}
+`,
+	})
+
+	outputDir := t.TempDir()
+	g := &Generator{Files: map[string]*File{}, outPath: outputDir}
+	if err := g.Process(filepath.Join(inputDir, "models")); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+	if err := g.Gen(); err != nil {
+		t.Fatalf("Gen error: %v", err)
+	}
+
+	content := readFileMust(t, filepath.Join(outputDir, "models.go"))
+	for _, expected := range []string{
+		"field.Number[uint]{}.WithColumn(\"id\")",
+		"field.Field[gorm.DeletedAt]{}.WithColumn(\"deleted_at\")",
+		"field.String{}.WithColumn(\"created_by\")",
+		"field.Number[int]{}.WithColumn(\"revision\")",
+		"field.String{}.WithColumn(\"name\")",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	if n := strings.Count(content, "CreatedBy field.String\n"); n != 1 {
+		t.Errorf("expected embedding cycle to be expanded once, got %d CreatedBy helpers:\n%s", n, content)
+	}
+}
+
+func TestStructView(t *testing.T) {
+	content := generateFromSource(t, `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{Views: []any{"*Report"}}
+
+// ActiveUser lists active users
+//
+//gorm:view SELECT id, name FROM users WHERE active
+type ActiveUser struct {
+	ID   uint
+	Name string
+}
+
+type SalesReport struct {
+	Total int
+}
+
+type User struct {
+	ID   uint
+	Name string
+}
+`)
+
+	for _, expected := range []string{
+		"// ActiveUser lists active users\nvar ActiveUser",
+		"\tName field.ReadOnly[string]\n",
+		"func (_ActiveUser) ViewName() string {\n\treturn \"active_users\"\n}",
+		"func (_ActiveUser) CreateViewSQL() string {\n\treturn \"CREATE VIEW active_users AS SELECT id, name FROM users WHERE active\"\n}",
+		"\tTotal field.ReadOnly[int]\n",
+		"var User = _User{\n\tID:   field.Number[uint]{}.WithColumn(\"id\"),\n\tName: field.String{}.WithColumn(\"name\"),",
+		"func (_SalesReport) ViewName() string {\n\treturn \"sales_reports\"\n}",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, content)
+		}
+	}
+	for _, unexpected := range []string{"func (_SalesReport) CreateViewSQL", "func (_User) ViewName", "gorm:view"} {
+		if strings.Contains(content, unexpected) {
+			t.Errorf("unexpected %q in generated code, got:\n%s", unexpected, content)
+		}
+	}
+}
+
+func TestSkipTests(t *testing.T) {
+	newInput := func(t *testing.T, config string) string {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"go.mod":                "module example.com/models\n",
+			"models/models.go":      "package models\n\n" + config + "type User struct {\n\tName string\n}\n",
+			"models/models_test.go": "package models\n\ntype fixture struct {\n\tName string\n}\n\ntype Fixture struct {\n\tName string\n}\n",
+			"other/broken_test.go":  "package other\n\nfunc broken( {\n",
+		})
+		return dir
+	}
+
+	t.Run("default", func(t *testing.T) {
+		g := &Generator{Files: map[string]*File{}, outPath: t.TempDir()}
+		if err := g.Process(newInput(t, "")); err != nil {
+			t.Fatalf("expected test files to be skipped, got %v", err)
+		}
+		for pth := range g.Files {
+			if strings.HasSuffix(pth, "_test.go") {
+				t.Errorf("expected test file %s to be skipped", pth)
+			}
+		}
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		g := &Generator{Files: map[string]*File{}, outPath: t.TempDir(), includeTests: true}
+		if err := g.Process(newInput(t, "")); err == nil || !strings.Contains(err.Error(), "broken_test.go") {
+			t.Fatalf("expected the broken test file to be parsed, got %v", err)
+		}
+	})
+
+	t.Run("config", func(t *testing.T) {
+		outputDir := t.TempDir()
+		g := &Generator{Files: map[string]*File{}, outPath: outputDir}
+		input := newInput(t, "import \"gorm.io/cli/gorm/genconfig\"\n\nvar _ = genconfig.Config{IncludeTests: true}\n\n")
+		if err := g.Process(input); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		if err := g.Gen(); err != nil {
+			t.Fatalf("Gen error: %v", err)
+		}
+		if content := readFileMust(t, filepath.Join(outputDir, "models", "models_test.go")); !strings.Contains(content, "var Fixture = _Fixture{") {
+			t.Errorf("expected helpers of the included test file, got:\n%s", content)
+		}
+	})
+}
+
+func TestFollowSymlinks(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":                  "module example.com/app\n",
+		"shared/models/models.go": "package models\n\ntype User struct {\n\tName string\n}\n",
+		"service/query.go":        "package service\n\ntype Order struct {\n\tID uint\n}\n",
+	})
+	if err := os.Symlink(filepath.Join(root, "shared", "models"), filepath.Join(root, "service", "models")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	// A cycle back to the input directory is visited once
+	if err := os.Symlink(filepath.Join(root, "service"), filepath.Join(root, "service", "models", "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, follow := range []bool{false, true} {
+		outputDir := t.TempDir()
+		g := &Generator{Files: map[string]*File{}, outPath: outputDir, followSymlinks: follow}
+		if err := g.Process(filepath.Join(root, "service")); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		if err := g.Gen(); err != nil {
+			t.Fatalf("Gen error: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(outputDir, "models", "models.go"))
+		if follow && (err != nil || !strings.Contains(string(content), "var User = _User{")) {
+			t.Errorf("expected output of the symlinked package, got %v:\n%s", err, content)
+		} else if !follow && !os.IsNotExist(err) {
+			t.Errorf("expected symlinked package to be skipped without followSymlinks, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, "query.go")); err != nil {
+			t.Errorf("expected output of the input package, got %v", err)
+		}
+	}
+}
+
+func TestTemplateDir(t *testing.T) {
+	inputDir, templateDir := t.TempDir(), t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod":    "module example.com/models\n",
+		"models.go": "package models\n\ntype User struct {\n\tName string\n}\n",
+	})
+	writeFiles(t, templateDir, map[string]string{
+		"pkg.tmpl": `{{.Header}}
+
+package {{.OutPackage}}
+{{range .Structs}}
+{{template "names.tmpl" .}}
+{{end}}`,
+		"names.tmpl": `// {{.Name}}Fields lists the fields of {{.Name}}
+var {{.Name}}Fields = []string{ {{range .Fields}}{{printf "%q" .Name}}, {{end}} }`,
+	})
+
+	render := func(g *Generator) (string, error) {
+		outputDir := t.TempDir()
+		g.Files, g.outPath = map[string]*File{}, outputDir
+		if err := g.Process(inputDir); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		if err := g.Gen(); err != nil {
+			return "", err
+		}
+		return readFileMust(t, filepath.Join(outputDir, "models.go")), nil
+	}
+
+	content, err := render(&Generator{templateDir: templateDir})
+	if err != nil {
+		t.Fatalf("Gen error: %v", err)
+	}
+	if expected := "var UserFields = []string{\"Name\"}"; !strings.Contains(content, expected) || strings.Contains(content, "_User") {
+		t.Errorf("expected output of the overriding template, got:\n%s", content)
+	}
+
+	writeFiles(t, inputDir, map[string]string{"config.go": fmt.Sprintf("package models\n\nimport \"gorm.io/cli/gorm/genconfig\"\n\nvar _ = genconfig.Config{TemplateDir: %q}\n", templateDir)})
+	if content, err := render(&Generator{}); err != nil || !strings.Contains(content, "var UserFields") {
+		t.Errorf("expected TemplateDir of the config to apply, got %v:\n%s", err, content)
+	}
+
+	if _, err := render(&Generator{templateDir: filepath.Join(templateDir, "missing")}); err == nil || !strings.Contains(err.Error(), "invalid template directory") {
+		t.Errorf("expected invalid template directory error, got %v", err)
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	templateDir := t.TempDir()
+	writeFiles(t, templateDir, map[string]string{
+		"pkg.tmpl": `{{.Header}}
+
+package {{.OutPackage}}
+{{range .Structs}}
+// {{snake .Name}} {{plural (snake .Name)}} {{lowerCamel (snake .Name)}} {{fkName .Name}}
+{{range .Fields}}// {{.Name}} {{sqlType .GoType}}
+{{end}}{{end}}`,
+	})
+
+	outputDir := generateFromFiles(t, &Generator{templateDir: templateDir}, map[string]string{
+		"models.go": `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{
+	TemplateFuncs: map[string]any{
+		"fkName": "fk_{{snake .}}_id",
+		"sqlType": map[string]string{"string": "text", "int64": "bigint"},
+	},
+}
+
+type UserProfile struct {
+	AccountID int64
+	Name      string
+}
+`,
+	})
+
+	content := readFileMust(t, filepath.Join(outputDir, "models.go"))
+	for _, expected := range []string{
+		"// user_profile user_profiles userProfile fk_user_profile_id",
+		"// AccountID bigint",
+		"// Name text",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in output, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestWithTests(t *testing.T) {
+	inputDir, outputDir := t.TempDir(), t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod": "module example.com/models\n",
+		"query.go": `package models
+
+import (
+	"context"
+	"iter"
+)
+
+type Query[T any] interface {
+	// SELECT * FROM @@table WHERE id=@id
+	GetByID(id int) (T, error)
+
+	// SELECT * FROM @@table WHERE name=@name
+	StreamByName(name string) iter.Seq2[T, error]
+
+	// UPDATE @@table SET name=@name WHERE id IN @ids
+	Rename(ctx context.Context, name string, ids []int) error
+
+	// where("name=@name")
+	FilterByName(name string)
+}
+`,
+		"user.go": `package models
+
+type User struct {
+	ID   uint
+	Name string
+}
+
+type Pet struct {
+	ID   uint
+	Name string
+}
+
+// model: User
+type UserQuery[T any] interface {
+	// SELECT * FROM @@table WHERE name=@name
+	GetByName(name string) (T, error)
+}
+`,
+	})
+
+	gen := func(withTests bool) {
+		g := &Generator{Files: map[string]*File{}, outPath: outputDir, withTests: withTests, prune: true}
+		if err := g.Process(inputDir); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		if err := g.Gen(); err != nil {
+			t.Fatalf("Gen error: %v", err)
+		}
+	}
+
+	testPath := filepath.Join(outputDir, "query_test.go")
+	if gen(false); !func() bool { _, err := os.Stat(testPath); return os.IsNotExist(err) }() {
+		t.Fatalf("expected no test skeleton without --with-tests")
+	}
+
+	gen(true)
+	content := readFileMust(t, testPath)
+	for _, expected := range []string{
+		"type queryModel = any",
+		"func setupQueryDB(t *testing.T) *gorm.DB {",
+		"func TestQuery_GetByID(t *testing.T) {\n\ttype T = queryModel\n\ttype args struct {\n\t\tid int\n\t}",
+		`t.Skip("TODO: add test cases of Query.GetByID")`,
+		"got, err := Query[T](db).GetByID(context.Background(), tt.args.id)",
+		"ids  []int",
+		"err := Query[T](db).Rename(context.Background(), tt.args.name, tt.args.ids)",
+		"got, err := Query[T](db).FilterByName(context.Background(), tt.args.name).Find(context.Background())",
+		"for row, rowErr := range Query[T](db).StreamByName(context.Background(), tt.args.name) {",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in test skeleton, got:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(content, "DO NOT EDIT") {
+		t.Errorf("expected an editable test skeleton, got:\n%s", content)
+	}
+
+	edited := "package models\n\n// edited by hand\n"
+	if err := os.WriteFile(testPath, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if gen(true); readFileMust(t, testPath) != edited {
+		t.Errorf("expected the existing test skeleton to be kept")
+	}
+
+	userTestPath := filepath.Join(outputDir, "user_test.go")
+	content = readFileMust(t, userTestPath)
+	for _, expected := range []string{
+		"type userQueryModel = models.User",
+		"if err := db.AutoMigrate(new(userQueryModel)); err != nil {",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in test skeleton, got:\n%s", expected, content)
+		}
+	}
+	if manifest := readFileMust(t, filepath.Join(outputDir, manifestFileName)); !strings.Contains(manifest, `"user_test.go"`) {
+		t.Errorf("expected the test skeleton in the manifest, got:\n%s", manifest)
+	}
+
+	if err := os.Remove(filepath.Join(inputDir, "user.go")); err != nil {
+		t.Fatal(err)
+	}
+	gen(true)
+	if _, err := os.Stat(userTestPath); !os.IsNotExist(err) {
+		t.Errorf("expected the test skeleton of the removed interface to be pruned, got %v", err)
+	}
+	if readFileMust(t, testPath) != edited {
+		t.Errorf("expected the edited test skeleton to be kept")
+	}
+}
+
+func TestDocs(t *testing.T) {
+	outputDir := generateFromFiles(t, &Generator{docs: docsMarkdown}, map[string]string{
+		"models.go": `package models
+
+import "database/sql"
+
+// User is a registered user
+type User struct {
+	ID       uint
+	Email    string ` + "`gorm:\"type:varchar;size:128;uniqueIndex\"`" + `
+	Nickname *string
+	Score    sql.NullInt64
+	Role     string ` + "`gorm:\"default:member\"`" + `
+	Pets     []Pet
+}
+
+type Pet struct {
+	ID     uint
+	UserID uint
+}
+
+type Query[T any] interface {
+	// GetByEmail returns the user with the email
+	//
+	// SELECT * FROM @@table WHERE email=@email
+	GetByEmail(email string) (T, error)
+
+	// where("role=@role")
+	FilterByRole(role string)
+}
+`,
+	})
+
+	content := readFileMust(t, filepath.Join(outputDir, "models.md"))
+	for _, expected := range []string{
+		"<!-- Code generated by 'gorm.io/cli/gorm'. DO NOT EDIT. -->\n\n# Package models\n",
+		"## User\n\nUser is a registered user\n\nTable: `users`\n",
+		"| `id` | ID | `uint` |  | no | primary key |  |",
+		"| `email` | Email | `string` | `varchar(128)` | no | unique (idx_users_email) |  |",
+		"| `nickname` | Nickname | `*string` |  | yes |  |  |",
+		"| `score` | Score | `sql.NullInt64` |  | yes |  |  |",
+		"| `role` | Role | `string` |  | no |  | `member` |",
+		"### Relations of User",
+		"| Pets | has many | `models.Pet` | `user_id` | `id` |  |",
+		"### GetByEmail(ctx context.Context, email string) (T, error)\n\nGetByEmail returns the user with the email\n\n```sql\nSELECT * FROM @@table WHERE email=@email\n```",
+		"### FilterByRole(ctx context.Context, role string) _QueryInterface[T]\n\n```sql\nWHERE role=@role\n```",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in docs, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestDiagram(t *testing.T) {
+	files := map[string]string{
+		"models.go": `package models
+
+type User struct {
+	ID        uint
+	Email     string ` + "`gorm:\"uniqueIndex\"`" + `
+	CompanyID int
+	Company   Company
+	Pets      []*Pet
+	Languages []Language ` + "`gorm:\"many2many:user_languages\"`" + `
+}
+
+type Company struct {
+	ID   int
+	Name string
+}
+
+type Pet struct {
+	ID     uint
+	UserID uint
+	Tags   []string ` + "`gorm:\"serializer:json\"`" + `
+}
+
+type Language struct {
+	Code string ` + "`gorm:\"primaryKey\"`" + `
+}
+`,
+	}
+
+	tests := []struct {
+		format   string
+		expected []string
+	}{
+		{format: diagramMermaid, expected: []string{
+			"%% Code generated by 'gorm.io/cli/gorm'. DO NOT EDIT.\nerDiagram\n",
+			"    User {\n        uint id PK\n        string email UK\n        int company_id FK\n    }",
+			"    Pet {\n        uint id PK\n        uint user_id FK\n        string[] tags\n    }",
+			`    User }o--|| Company : "Company"`,
+			`    User ||--o{ Pet : "Pets"`,
+			`    User }o--o{ Language : "Languages"`,
+		}},
+		{format: diagramDot, expected: []string{
+			"// Code generated by 'gorm.io/cli/gorm'. DO NOT EDIT.\ndigraph \"models\" {\n",
+			`    "User" [label="{User (users)|id : uint PK\lemail : string UK\lcompany_id : int FK\l}"];`,
+			`    "Language" [label="{Language (languages)|code : string PK\l}"];`,
+			`    "User" -> "Company" [label="Company (belongs to)"];`,
+			`    "User" -> "Pet" [label="Pets (has many)"];`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			outputDir := generateFromFiles(t, &Generator{diagram: tt.format}, files)
+
+			content := readFileMust(t, filepath.Join(outputDir, "models."+diagramExtensions[tt.format]))
+			for _, expected := range tt.expected {
+				if !strings.Contains(content, expected) {
+					t.Errorf("expected %q in diagram, got:\n%s", expected, content)
+				}
+			}
+		})
+	}
+}
+
+// The drivers are registered by main, see main.go
+func init() {
+	SchemaDrivers = drivers.Dialectors
+}
+
+// skipWithoutSQLite skips the test in builds without cgo, which leave the SQLite driver out
+func skipWithoutSQLite(t *testing.T) {
+	t.Helper()
+	if _, ok := SchemaDrivers["sqlite"]; !ok {
+		t.Skip("the SQLite driver requires cgo")
+	}
+}
+
+func TestSchemaDriver(t *testing.T) {
+	for _, driver := range []string{"mysql", "postgres"} {
+		if err := validateSchemaDriver(driver); err != nil {
+			t.Errorf("expected driver %s to be valid, got %v", driver, err)
+		}
+	}
+	if err := validateSchemaDriver("oracle"); err == nil || !strings.Contains(err.Error(), `invalid driver "oracle"`) {
+		t.Errorf("expected invalid driver error, got %v", err)
+	}
+	if _, ok := SchemaDrivers["sqlite"]; !ok {
+		if err := validateSchemaDriver("sqlite"); err == nil || !strings.Contains(err.Error(), "requires a cgo build") {
+			t.Errorf("expected cgo error of sqlite, got %v", err)
+		}
+	}
+}
+
+func TestSchemaDiff(t *testing.T) {
+	skipWithoutSQLite(t)
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod": "module example.com/models\n",
+		"models.go": `package models
+
+import "time"
+
+type User struct {
+	ID        uint
+	Name      string
+	Age       int
+	Email     string
+	Score     float64 ` + "`gorm:\"type:decimal(10,2)\"`" + `
+	Languages []Language ` + "`gorm:\"many2many:user_languages\"`" + `
+	CreatedAt time.Time
+}
+
+type Language struct {
+	Code string ` + "`gorm:\"primaryKey;type:varchar(8)\"`" + `
+}
+
+type Order struct {
+	ID uint
+}
+
+// UserSummary has no primary key, it's not compared
+type UserSummary struct {
+	Name  string
+	Count int
+}
+`,
+	})
+
+	db, err := openSchemaDB("sqlite", filepath.Join(t.TempDir(), "schema.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	for _, stmt := range []string{
+		"CREATE TABLE users (id integer PRIMARY KEY AUTOINCREMENT, name text, age varchar(8), score text, created_at datetime)",
+		"CREATE TABLE languages (code text PRIMARY KEY)",
+		"CREATE TABLE user_languages (user_id integer, language_code text)",
+		"CREATE TABLE audit_logs (id integer)",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+	}
+
+	g := &Generator{Files: map[string]*File{}}
+	if err := g.Process(inputDir); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+	diffs, err := g.diffSchema(db)
+	if err != nil {
+		t.Fatalf("diffSchema error: %v", err)
+	}
+
+	var got []string
+	for _, diff := range diffs {
+		got = append(got, diff.String())
+	}
+	expected := []string{
+		"users.age: type mismatch (User.Age), model int, database varchar",
+		"users.email: missing column (User.Email)",
+		"users.score: type mismatch (User.Score), model decimal, database text",
+		"orders: missing table (Order)",
+		"audit_logs: extra table",
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected diffs:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestVerifyColumns(t *testing.T) {
+	skipWithoutSQLite(t)
+	g := &Generator{}
+	generateFromFiles(t, g, map[string]string{
+		"models.go": `package models
+
+type User struct {
+	ID    uint
+	Name  string
+	Email string ` + "`gorm:\"column:emial\"`" + `
+}
+
+type Pet struct {
+	ID uint
+}
+
+const (
+	nameColumn  = "name"
+	scoreColumn = "score"
+)
+
+type Query[T any] interface {
+	// SELECT * FROM @@table WHERE @@nameColumn=@name AND @@scoreColumn>0 AND @@column=@value
+	Filter(name, column, value string) ([]T, error)
+}
+`,
+	})
+
+	db, err := openSchemaDB("sqlite", filepath.Join(t.TempDir(), "verify.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE users (id integer PRIMARY KEY, name text, email text)").Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	err = g.verifyColumns(db)
+	if err == nil {
+		t.Fatalf("expected verification error")
+	}
+	expected := "found 3 columns or tables missing in the database:\n" +
+		"  users.emial: missing column (User.Email)\n" +
+		"  pets: missing table (Pet)\n" +
+		"  score: unknown column (Query.Filter)"
+	if err.Error() != expected {
+		t.Errorf("expected error:\n%s\ngot:\n%s", expected, err)
+	}
+
+	if err := db.Exec("CREATE TABLE pets (id integer PRIMARY KEY, score integer)").Error; err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	if err := db.Exec("ALTER TABLE users RENAME COLUMN email TO emial").Error; err != nil {
+		t.Fatalf("failed to rename column: %v", err)
+	}
+	if err := g.verifyColumns(db); err != nil {
+		t.Errorf("expected no verification error, got %v", err)
+	}
+}
+
+func TestProtoModels(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		content  string
+		typeMap  map[string]string
+		expected []string
+	}{
+		{
+			name:   "proto",
+			source: "shop.proto",
+			content: `syntax = "proto3";
+
+package shop.v1;
+
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+// Status of an order
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_PENDING = 1; // waiting for payment
+}
+
+// User is a customer
+message User {
+  uint64 id = 1;
+  // name shown in the shop
+  string name = 2;
+  optional int32 age = 3;
+  google.protobuf.StringValue nickname = 4;
+  google.protobuf.Timestamp created_at = 5;
+  repeated string tags = 6;
+  map<string, string> labels = 7;
+  string emailAddress = 8 [deprecated = true];
+  Address address = 9;
+}
+
+message Address {
+  string city = 1;
+}
+
+message Order {
+  uint64 id = 1;
+  shop.v1.Status status = 2;
+  repeated Item items = 3;
+  oneof payment {
+    string card = 4;
+  }
+  reserved 5, 6;
+
+  message Item {
+    string sku = 1;
+  }
+}
+
+service Shop {
+  rpc GetUser(User) returns (User) {}
+}
+`,
+			typeMap: map[string]string{"google.protobuf.Timestamp": "example.com/types.Time"},
+			expected: []string{
+				"// Code generated by 'gorm proto gen' from shop.proto. DO NOT EDIT.\n\npackage models\n",
+				"\"example.com/types\"",
+				"// Status of an order\ntype Status int32\n\nconst (\n\tStatusUnspecified Status = 0\n\tStatusPending     Status = 1\n)",
+				"// User is a customer\ntype User struct {\n\tID uint64\n\t// name shown in the shop\n\tName         string\n",
+				"\tAge          *int32\n",
+				"\tNickname     sql.NullString\n",
+				"\tCreatedAt    types.Time\n",
+				"\tTags         []string          `gorm:\"serializer:json\"`\n",
+				"\tLabels       map[string]string `gorm:\"serializer:json\"`\n",
+				"\tEmailAddress string            `gorm:\"column:emailAddress\"`\n",
+				"\tAddress      *Address          `gorm:\"serializer:json\"`\n",
+				"type OrderItem struct {\n\tSku string\n}",
+				"type Order struct {\n\tID     uint64\n\tStatus Status\n\tItems  []OrderItem `gorm:\"serializer:json\"`\n\tCard   *string\n}",
+			},
+		},
+		{
+			name:   "pb.go",
+			source: "shop.pb.go",
+			content: `package shopv1
+
+import (
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Order_Kind int32
+
+const (
+	Order_KIND_UNSPECIFIED Order_Kind = 0
+	Order_KIND_GIFT        Order_Kind = 1
+)
+
+// User is a customer
+type User struct {
+	state     protoimpl.MessageState ` + "`protogen:\"open.v1\"`" + `
+	Id        uint64                 ` + "`protobuf:\"varint,1,opt,name=id,proto3\" json:\"id,omitempty\"`" + `
+	Age       *int32                 ` + "`protobuf:\"varint,2,opt,name=age,proto3,oneof\" json:\"age,omitempty\"`" + `
+	CreatedAt *timestamppb.Timestamp ` + "`protobuf:\"bytes,3,opt,name=created_at,json=createdAt,proto3\" json:\"created_at,omitempty\"`" + `
+	Avatar    []byte                 ` + "`protobuf:\"bytes,4,opt,name=avatar,proto3\" json:\"avatar,omitempty\"`" + `
+	Kind      Order_Kind             ` + "`protobuf:\"varint,5,opt,name=kind,proto3,enum=shop.v1.Order_Kind\" json:\"kind,omitempty\"`" + `
+	Items     []*Order_Item          ` + "`protobuf:\"bytes,6,rep,name=items,proto3\" json:\"items,omitempty\"`" + `
+	sizeCache protoimpl.SizeCache
+}
+
+type Order_Item struct {
+	state protoimpl.MessageState ` + "`protogen:\"open.v1\"`" + `
+	Sku   string                 ` + "`protobuf:\"bytes,1,opt,name=sku,proto3\" json:\"sku,omitempty\"`" + `
+}
+`,
+			expected: []string{
+				"// Code generated by 'gorm proto gen' from shop.pb.go. DO NOT EDIT.\n\npackage models\n",
+				"type OrderKind int32\n\nconst (\n\tOrderKindUnspecified OrderKind = 0\n\tOrderKindGift        OrderKind = 1\n)",
+				"// User is a customer\ntype User struct {\n\tID        uint64\n\tAge       *int32\n\tCreatedAt time.Time\n\tAvatar    []byte\n\tKind      OrderKind\n\tItems     []OrderItem `gorm:\"serializer:json\"`\n}",
+				"type OrderItem struct {\n\tSku string\n}",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputDir, outputDir := t.TempDir(), t.TempDir()
+			writeFiles(t, inputDir, map[string]string{tt.source: tt.content})
+			if err := genProto(inputDir, outputDir, "models", tt.typeMap); err != nil {
+				t.Fatalf("genProto error: %v", err)
+			}
+
+			content := readFileMust(t, filepath.Join(outputDir, "shop_models.go"))
+			for _, expected := range tt.expected {
+				if !strings.Contains(content, expected) {
+					t.Errorf("expected %q in models, got:\n%s", expected, content)
+				}
+			}
+		})
+	}
+
+	t.Run("unknown type", func(t *testing.T) {
+		inputDir := t.TempDir()
+		writeFiles(t, inputDir, map[string]string{"user.proto": "syntax = \"proto3\";\nmessage User {\n  google.protobuf.Any extra = 1;\n}\n"})
+		err := genProto(inputDir, t.TempDir(), "models", nil)
+		if err == nil || !strings.Contains(err.Error(), "unknown type google.protobuf.Any, map it to a Go type with --type-map") {
+			t.Errorf("expected unknown type error, got %v", err)
+		}
+	})
+
+	for name, tt := range map[string]struct{ content, want string }{
+		"truncated message": {"message User {\n  string name", `user.proto:2: unexpected end of file, expected "="`},
+		"truncated map":     {"message User {\n  map<string,", "user.proto:2: unexpected end of file"},
+		"truncated oneof":   {"message User {\n  oneof", "user.proto:2: unexpected end of file"},
+		"missing number":    {"message User {\n  string name = 1;\n  int64 age;\n}\n", `user.proto:3: expected "=", got ";"`},
+		"missing name":      {"enum Role {\n  ADMIN = ;\n}\n", `user.proto:2: expected a name, got ";"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseProtoFile("user.proto", tt.content)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("expected error %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestModelSchemas(t *testing.T) {
+	files := map[string]string{
+		"models.go": `package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+)
+
+// User is a registered user
+type User struct {
+	ID        uint          ` + "`json:\"id\"`" + `
+	// Name is the display name
+	Name      string        ` + "`json:\"name\"`" + `
+	Nickname  *string       ` + "`json:\"nickname,omitempty\"`" + `
+	Score     sql.NullInt64 ` + "`json:\"score\"`" + `
+	Role      Role          ` + "`json:\"role\"`" + `
+	Password  string        ` + "`json:\"-\"`" + `
+	CreatedAt time.Time
+	Pets      []Pet         ` + "`json:\"pets,omitempty\"`" + `
+	Manager   *User         ` + "`json:\"manager\"`" + `
+	ManagerID *uint         ` + "`json:\"manager_id\"`" + `
+}
+
+type Pet struct {
+	ID     uint
+	UserID uint
+}
+`,
+	}
+
+	tests := []struct {
+		format, file string
+		expected     []string
+	}{
+		{format: schemaOpenAPI, file: "models.openapi.json", expected: []string{
+			"{\n  \"openapi\": \"3.1.0\",\n  \"info\": {\n    \"title\": \"models\",\n    \"version\": \"1.0.0\"\n  },\n  \"components\": {\n    \"schemas\": {\n      \"User\": {\n        \"type\": \"object\",\n        \"description\": \"User is a registered user\",\n",
+			`"pets": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/Pet"
+            }
+          },
+          "manager": {
+            "anyOf": [
+              {
+                "$ref": "#/components/schemas/User"
+              },
+              {
+                "type": "null"
+              }
+            ]
+          },`,
+		}},
+		{format: schemaJSONSchema, file: "models.schema.json", expected: []string{
+			"{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$defs\": {\n    \"User\": {\n",
+			`"id": {
+          "type": "integer",
+          "format": "int64",
+          "minimum": 0
+        },
+        "name": {
+          "type": "string",
+          "description": "Name is the display name"
+        },
+        "nickname": {
+          "type": [
+            "string",
+            "null"
+          ]
+        },
+        "score": {
+          "type": [
+            "integer",
+            "null"
+          ],
+          "format": "int64"
+        },
+        "role": {
+          "type": "string",
+          "enum": [
+            "admin",
+            "member"
+          ]
+        },
+        "CreatedAt": {
+          "type": "string",
+          "format": "date-time"
+        },`,
+			`"required": [
+        "id",
+        "name",
+        "score",
+        "role",
+        "CreatedAt",
+        "manager",
+        "manager_id"
+      ]`,
+			`"$ref": "#/$defs/Pet"`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			outputDir := generateFromFiles(t, &Generator{apiSchema: tt.format}, files)
+
+			content := readFileMust(t, filepath.Join(outputDir, tt.file))
+			if strings.Contains(content, "password") || strings.Contains(content, "Password") {
+				t.Errorf("expected the Password field to be skipped, got:\n%s", content)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(content, expected) {
+					t.Errorf("expected %q in schema, got:\n%s", expected, content)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSQL(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod": "module example.com/models\n",
+		"query.go": `package models
+
+type Query[T any] interface {
+	// SELECT * FROM @@table WHERE name = @name
+	FindByName(name string) ([]T, error)
+
+	// UPDATE @@table SET name=@name age=@age WHERE id=@id
+	UpdateInfo(name string, age int, id int) error
+}
+`,
+	})
+
+	for _, dialect := range []string{sqlCheckGeneric, "mysql", "sqlite"} {
+		if dialect == "sqlite" {
+			skipWithoutSQLite(t)
+		}
+		g := &Generator{Files: map[string]*File{}, outPath: t.TempDir(), validateSQL: dialect}
+		if err := g.Process(inputDir); err != nil {
+			t.Fatalf("Process error: %v", err)
+		}
+		err := g.Gen()
+		if err == nil || !strings.Contains(err.Error(), "query.go:7") || !strings.Contains(err.Error(), "invalid SQL of Query.UpdateInfo for "+dialect) {
+			t.Errorf("expected the invalid SQL of UpdateInfo reported for %s, got %v", dialect, err)
+		}
+		if dialect == "mysql" && !strings.Contains(err.Error(), "for mysql (common checks only") {
+			t.Errorf("expected the mysql check labeled as the common checks, got %v", err)
+		}
+	}
+
+	g := &Generator{Files: map[string]*File{}, outPath: t.TempDir(), validateSQL: "db2"}
+	if err := g.Process(inputDir); err != nil {
+		t.Fatalf("Process error: %v", err)
+	}
+	if err := g.Gen(); err == nil || !strings.Contains(err.Error(), "invalid SQL dialect") {
+		t.Errorf("expected an invalid dialect error, got %v", err)
+	}
+}
+
+func TestDialectOrdinalPlaceholders(t *testing.T) {
+	gen := func(dialect, sql string) (string, error) {
+		inputDir, outDir := t.TempDir(), t.TempDir()
+		writeFiles(t, inputDir, map[string]string{
+			"go.mod": "module example.com/models\n",
+			"query.go": `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{Dialect: "` + dialect + `"}
+
+type Query[T any] interface {
+	// ` + sql + `
+	FindByName(ctx context.Context, name string, age int) ([]T, error)
+}
+`,
+		})
+
+		g := &Generator{Files: map[string]*File{}, outPath: outDir}
+		if err := g.Process(inputDir); err != nil {
+			return "", err
+		}
+		if err := g.Gen(); err != nil {
+			return "", err
+		}
+		return readFileMust(t, filepath.Join(outDir, "query.go")), nil
+	}
+
+	content, err := gen("postgres", "SELECT * FROM @@table WHERE name = $1 AND age > $2 AND note <> '$1'")
+	if err != nil {
+		t.Fatalf("generation error: %v", err)
+	}
+	if want := `sb.WriteString("SELECT * FROM ? WHERE name = ? AND age > ? AND note <> '$1'")`; !strings.Contains(content, want) || !strings.Contains(content, "params = append(params, clause.Table{Name: clause.CurrentTable}, name, age)") {
+		t.Errorf("expected the ordinal placeholders bound to the params, got:\n%s", content)
+	}
+
+	if _, err := gen("postgres", "SELECT * FROM @@table WHERE name = $3"); err == nil || !strings.Contains(err.Error(), "query.go:8:40: placeholder $3 is out of the 2 parameters of the method") {
+		t.Errorf("expected an out of range placeholder error, got %v", err)
+	}
+	if _, err := gen("db2", "SELECT * FROM @@table WHERE name = ?"); err == nil || !strings.Contains(err.Error(), `invalid dialect "db2"`) {
+		t.Errorf("expected an invalid dialect error, got %v", err)
+	}
+}
+
+func TestPreviewSQL(t *testing.T) {
+	inputDir := t.TempDir()
+	writeFiles(t, inputDir, map[string]string{
+		"go.mod": "module example.com/models\n",
+		"query.go": `package models
+
+import "gorm.io/cli/gorm/genconfig"
+
+var _ = genconfig.Config{TemplateVars: map[string]string{"active": "deleted_at IS NULL"}}
+
+type Query[T any] interface {
+	// SELECT * FROM @@table
+	// {{where}}
+	//   {{if name != ""}} name = @name {{end}}
+	//   {{if age > 0}} AND age > @{age - 1} {{end}}
+	//   AND ${active}
+	// {{end}}
+	Filter(name string, age int) ([]T, error)
+
+	// SELECT * FROM @@table WHERE {{for _, u := range users}}{{join " OR "}}(name = @u.Name AND age = @u.Age){{end}}
+	FindUsers(users []User) ([]T, error)
+
+	// update("name = @name WHERE id = @id")
+	Rename(name string, id int) error
+
+	// SELECT * FROM @@table WHERE {{in "role" roles}} {{group}}{{if age > 0}} OR age > @age{{end}}{{end}}
+	// {{if dialect "postgres"}} ORDER BY name NULLS LAST {{else}} ORDER BY name {{end}}
+	// {{orderBy @column allow="name,age"}} {{limit @size}}
+	Search(roles []string, age int, column string, size int) ([]T, error)
+
+	// INSERT INTO @@table (name, age) VALUES {{values @users (Name, Age)}}
+	// ON CONFLICT DO UPDATE SET {{for i := 0; i < len(fields); i++}}{{join ", "}}@{fields[i]} = @{$index}{{end}}
+	Upsert(users []User, fields []string) error
+}
+`,
+	})
+
+	type User struct {
+		Name string
+		Age  int
+	}
+	table := clause.Table{Name: clause.CurrentTable}
+	for _, tc := range []struct {
+		method     string
+		args       map[string]any
+		wantSQL    string
+		wantParams []any
+	}{
+		{"Filter", map[string]any{"name": "jinzhu", "age": 18}, "SELECT * FROM ? WHERE name = ? AND age > ? AND deleted_at IS NULL", []any{table, "jinzhu", 17}},
+		{"Filter", map[string]any{"name": "", "age": 0}, "SELECT * FROM ? WHERE deleted_at IS NULL", []any{table}},
+		{"FindUsers", map[string]any{"users": []User{{Name: "a", Age: 1}, {Name: "b", Age: 2}}}, "SELECT * FROM ? WHERE (name = ? AND age = ?) OR (name = ? AND age = ?)", []any{table, "a", 1, "b", 2}},
+		{"Rename", map[string]any{"name": "jinzhu", "id": 1}, "name = ? WHERE id = ?", []any{"jinzhu", 1}},
+		{"Search", map[string]any{"roles": []string{"admin"}, "age": 18, "column": "age", "size": 10}, "SELECT * FROM ? WHERE role IN ? (age > ?) ? ? ?", []any{
+			table, []string{"admin"}, 18,
+			field.Dialect(field.DialectSQL{Dialects: []string{"postgres"}, SQL: "ORDER BY name NULLS LAST", Vars: []any{}}, field.DialectSQL{SQL: "ORDER BY name", Vars: []any{}}),
+			field.OrderBy("age", "", "name", "age"), field.Paginate(10, 0),
+		}},
+		{"Search", map[string]any{"roles": []string(nil), "age": 0, "column": "name", "size": 1}, "SELECT * FROM ? WHERE 1 = 0 ? ? ?", []any{
+			table,
+			field.Dialect(field.DialectSQL{Dialects: []string{"postgres"}, SQL: "ORDER BY name NULLS LAST", Vars: []any{}}, field.DialectSQL{SQL: "ORDER BY name", Vars: []any{}}),
+			field.OrderBy("name", "", "name", "age"), field.Paginate(1, 0),
+		}},
+		{"Upsert", map[string]any{"users": []User{{Name: "a", Age: 1}, {Name: "b", Age: 2}}, "fields": []string{"name", "age"}}, "INSERT INTO ? (name, age) VALUES (?,?),(?,?) ON CONFLICT DO UPDATE SET ? = ?, ? = ?", []any{
+			table, "a", 1, "b", 2, "name", 0, "age", 1,
+		}},
+	} {
+		sql, params, err := PreviewSQL(inputDir, "Query", tc.method, tc.args)
+		if err != nil {
+			t.Fatalf("PreviewSQL %s error: %v", tc.method, err)
+		}
+		if sql != tc.wantSQL || !reflect.DeepEqual(params, tc.wantParams) {
+			t.Errorf("PreviewSQL %s with %v, expected %q %v, got %q %v", tc.method, tc.args, tc.wantSQL, tc.wantParams, sql, params)
+		}
+	}
+
+	if _, _, err := PreviewSQL(inputDir, "Query", "Filter", map[string]any{"name": "jinzhu"}); err == nil || !strings.Contains(err.Error(), "undefined: age") {
+		t.Errorf("expected an undefined arg error, got %v", err)
+	}
+	if _, _, err := PreviewSQL(inputDir, "Query", "Missing", nil); err == nil || !strings.Contains(err.Error(), "method Query.Missing not found") {
+		t.Errorf("expected a missing method error, got %v", err)
+	}
+}
+
+// TestProcessConcurrent generates packages whose files are processed in parallel and resolve types,
+// embedded interfaces and SQL constants of a shared package through the loader, run it with -race
+func TestProcessConcurrent(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/app\n",
+		"shared/shared.go": `package shared
+
+import "time"
+
+type Status string
+
+const (
+	StatusActive Status = "active"
+	StatusBanned Status = "banned"
+)
+
+type Model struct {
+	ID        uint
+	CreatedAt time.Time
+}
+
+type BaseQuery[T any] interface {
+	// SELECT * FROM @@table WHERE id=@id
+	GetByID(id int) (T, error)
+}
+
+const ByStatusSQL = "SELECT * FROM @@table WHERE status=@status"
+`,
+	}
+	for _, pkg := range []string{"users", "orders", "invoices", "payments"} {
+		for i := range 4 {
+			files[fmt.Sprintf("%s/model%d.go", pkg, i)] = fmt.Sprintf(`package %[1]s
+
+import "example.com/app/shared"
+
+type Model%[2]d struct {
+	shared.Model
+	Name   string
+	Status shared.Status
+}
+
+type Query%[2]d[T any] interface {
+	shared.BaseQuery[T]
+
+	// sqlconst: shared.ByStatusSQL
+	FindByStatus(status shared.Status) ([]T, error)
+}
+`, pkg, i)
+		}
+	}
+
+	generate := func() map[string]string {
+		outputDir := generateFromFiles(t, &Generator{quiet: true}, files)
+		outputs := map[string]string{}
+		filepath.Walk(outputDir, func(pth string, info os.FileInfo, err error) error {
+			if err == nil && strings.HasSuffix(pth, ".go") {
+				rel, _ := filepath.Rel(outputDir, pth)
+				outputs[rel] = readFileMust(t, pth)
+			}
+			return err
+		})
+		return outputs
+	}
+
+	outputs := generate()
+	for _, pkg := range []string{"users", "orders", "invoices", "payments"} {
+		for i := range 4 {
+			content, ok := outputs[filepath.Join(pkg, fmt.Sprintf("model%d.go", i))]
+			if !ok {
+				t.Fatalf("expected %s/model%d.go to be generated, got %v", pkg, i, slices.Collect(maps.Keys(outputs)))
+			}
+			for _, expected := range []string{
+				fmt.Sprintf("func (e %sImpl[T]) GetByID(", fmt.Sprintf("_Query%d", i)),
+				fmt.Sprintf("func (e %sImpl[T]) FindByStatus(", fmt.Sprintf("_Query%d", i)),
+				"WHERE status=?",
+				`CreatedAt: field.Time{}.WithColumn("created_at"),`,
+				"WithValues(shared.StatusActive, shared.StatusBanned)",
+			} {
+				if !strings.Contains(content, expected) {
+					t.Errorf("expected %q in %s/model%d.go, got:\n%s", expected, pkg, i, content)
+				}
+			}
+		}
+	}
+
+	// The parallel processing doesn't change the output between runs
+	if again := generate(); !maps.Equal(outputs, again) {
+		t.Errorf("expected the same outputs of every run")
+	}
+}
@@ -0,0 +1,108 @@
+package gen
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// UniqueIndex is a unique index or unique constraint of a struct, rendered as a field.UniqueIndex
+type UniqueIndex struct {
+	// Name is the name of the index in the generated UniqueIndexes struct, the names of its fields joined
+	Name string
+	// IndexName is the index or constraint name in the database
+	IndexName string
+	Columns   []string
+	fields    []indexField
+}
+
+// indexField is a field of an index with its priority, fields are ordered by priority like GORM does
+type indexField struct {
+	Field
+	priority int
+}
+
+// Value returns the field.UniqueIndex literal of the index for template generation
+func (i UniqueIndex) Value() string {
+	columns := make([]string, len(i.Columns))
+	for idx, column := range i.Columns {
+		columns[idx] = strconv.Quote(column)
+	}
+	return fmt.Sprintf("field.UniqueIndex{}.WithName(%q).WithColumns(%s)", i.IndexName, strings.Join(columns, ", "))
+}
+
+// UniqueIndexes returns the unique indexes and constraints declared with the `uniqueIndex`, `index:,unique`
+// and `unique` gorm tags of the struct for template generation. Index names follow GORM's NamingStrategy.
+func (s Struct) UniqueIndexes() (indexes []UniqueIndex) {
+	if s.HasField("UniqueIndexes") {
+		return nil
+	}
+
+	var (
+		ns    = schema.NamingStrategy{IdentifierMaxLength: 64}
+		table = s.TableName()
+		named = map[string]int{}
+	)
+
+	add := func(name string, f Field, priority int) {
+		idx, ok := named[name]
+		if !ok {
+			idx = len(indexes)
+			named[name] = idx
+			indexes = append(indexes, UniqueIndex{IndexName: name})
+		}
+		indexes[idx].fields = append(indexes[idx].fields, indexField{Field: f, priority: priority})
+	}
+
+	for _, f := range s.ColumnFields() {
+		for _, value := range strings.Split(reflect.StructTag(f.Tag).Get("gorm"), ";") {
+			key, tag, _ := strings.Cut(value, ":")
+			switch key = strings.TrimSpace(strings.ToUpper(key)); key {
+			case "UNIQUE":
+				add(ns.UniqueName(table, f.DBName), f, 10)
+			case "INDEX", "UNIQUEINDEX":
+				name, setting, _ := strings.Cut(tag, ",")
+				settings := schema.ParseTagSetting(setting, ",")
+				if key != "UNIQUEINDEX" && settings["UNIQUE"] == "" && !strings.EqualFold(settings["CLASS"], "UNIQUE") {
+					continue
+				}
+				if name == "" {
+					name = ns.IndexName(table, cmp.Or(settings["COMPOSITE"], f.Name))
+				}
+				priority, err := strconv.Atoi(settings["PRIORITY"])
+				if err != nil {
+					priority = 10
+				}
+				add(name, f, priority)
+			}
+		}
+	}
+
+	result := indexes[:0]
+	for _, index := range indexes {
+		sort.SliceStable(index.fields, func(i, j int) bool { return index.fields[i].priority < index.fields[j].priority })
+		for _, f := range index.fields {
+			index.Name += f.Name
+			index.Columns = append(index.Columns, f.DBName)
+		}
+		if !slices.ContainsFunc(result, func(i UniqueIndex) bool { return i.Name == index.Name }) {
+			result = append(result, index)
+		}
+	}
+	return result
+}
+
+// UniqueIndexesValue returns the literal of the generated UniqueIndexes struct for template generation
+func (s Struct) UniqueIndexesValue() string {
+	var values []string
+	for _, index := range s.UniqueIndexes() {
+		values = append(values, index.Name+": "+index.Value())
+	}
+	return fmt.Sprintf("%sUniqueIndexes{%s}", s.StructName(), strings.Join(values, ", "))
+}
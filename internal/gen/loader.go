@@ -0,0 +1,195 @@
+package gen
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// loadMode loads the syntax and types of the packages with their dependencies, which are type-checked
+// with them instead of being read from export data
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps | packages.NeedModule
+
+// packageLoader resolves packages, named types and struct declarations for the generator.
+//
+// The packages under the input are loaded once with types and syntax, types of their
+// dependencies are indexed from the type-checked imports, so most lookups don't need
+// to invoke go/packages again. Packages outside the initial load are loaded on demand
+// and cached.
+type packageLoader struct {
+	mu    sync.Mutex
+	pkgs  map[string]*packages.Package // loaded packages by import path
+	types map[string]*types.Package    // type-checked packages by import path, including dependencies
+	dirs  map[string]string            // import paths by package directory
+	mods  map[string]string            // module root directories by directory
+}
+
+// defaultLoader is used when no Generator is associated, e.g. for package-level lookups
+var defaultLoader = newPackageLoader()
+
+func newPackageLoader() *packageLoader {
+	return &packageLoader{
+		pkgs:  map[string]*packages.Package{},
+		types: map[string]*types.Package{},
+		dirs:  map[string]string{},
+		mods:  map[string]string{},
+	}
+}
+
+// goModDir returns the module root directory of dir, `go env GOMOD` is invoked once per directory
+func (l *packageLoader) goModDir(dir string) string {
+	l.mu.Lock()
+	modDir, ok := l.mods[dir]
+	l.mu.Unlock()
+	if ok {
+		return modDir
+	}
+
+	modDir = findGoModDir(filepath.Join(dir, "_"))
+
+	l.mu.Lock()
+	l.mods[dir] = modDir
+	l.mu.Unlock()
+	return modDir
+}
+
+// load loads the packages matching patterns from dir in a single packages.Load call
+func (l *packageLoader) load(dir string, patterns ...string) error {
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode, Dir: dir}, patterns...)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, pkg := range pkgs {
+		l.add(pkg)
+	}
+	return nil
+}
+
+// add indexes a loaded package, it must be called with l.mu held
+func (l *packageLoader) add(pkg *packages.Package) {
+	if pkg.PkgPath == "" {
+		return
+	}
+
+	l.pkgs[pkg.PkgPath] = pkg
+	for _, f := range pkg.GoFiles {
+		l.dirs[filepath.Dir(f)] = pkg.PkgPath
+	}
+
+	var index func(tp *types.Package)
+	index = func(tp *types.Package) {
+		if _, ok := l.types[tp.Path()]; ok {
+			return
+		}
+		l.types[tp.Path()] = tp
+		for _, imp := range tp.Imports() {
+			index(imp)
+		}
+	}
+	if pkg.Types != nil && pkg.Types.Complete() {
+		index(pkg.Types)
+	}
+}
+
+// pkg returns the loaded package for pkgPath, loading it from modRoot if necessary
+func (l *packageLoader) pkg(modRoot, pkgPath string) *packages.Package {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if pkg, ok := l.pkgs[pkgPath]; ok {
+		return pkg
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode, Dir: modRoot}, pkgPath)
+	if err != nil || len(pkgs) == 0 {
+		l.pkgs[pkgPath] = nil
+		return nil
+	}
+	l.add(pkgs[0])
+	l.pkgs[pkgPath] = pkgs[0]
+	return pkgs[0]
+}
+
+// typesPackage returns the type-checked package for pkgPath
+func (l *packageLoader) typesPackage(modRoot, pkgPath string) *types.Package {
+	l.mu.Lock()
+	tp := l.types[pkgPath]
+	l.mu.Unlock()
+
+	if tp == nil {
+		if pkg := l.pkg(modRoot, pkgPath); pkg != nil && pkg.Types != nil && len(pkg.Errors) == 0 {
+			tp = pkg.Types
+		}
+	}
+	return tp
+}
+
+// packagePath returns the import path of the package in dir
+func (l *packageLoader) packagePath(modRoot, dir string) string {
+	l.mu.Lock()
+	pkgPath, ok := l.dirs[dir]
+	l.mu.Unlock()
+	if ok {
+		return pkgPath
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName, Dir: modRoot}, dir)
+	if err == nil && len(pkgs) > 0 {
+		pkgPath = pkgs[0].PkgPath
+	}
+
+	l.mu.Lock()
+	l.dirs[dir] = pkgPath
+	l.mu.Unlock()
+	return pkgPath
+}
+
+// namedType returns the type of the package-level type declaration name in pkgPath
+func (l *packageLoader) namedType(modRoot, pkgPath, name string) types.Type {
+	if tp := l.typesPackage(modRoot, pkgPath); tp != nil {
+		if obj, ok := tp.Scope().Lookup(name).(*types.TypeName); ok {
+			return obj.Type()
+		}
+	}
+	return nil
+}
+
+// structType returns the AST of the struct type declaration name in pkgPath, and the file set of its positions
+func (l *packageLoader) structType(modRoot, pkgPath, name string) (*ast.StructType, *token.FileSet) {
+	if ts, _, pkg := l.typeSpec(modRoot, pkgPath, name); ts != nil {
+		if st, ok := ts.Type.(*ast.StructType); ok {
+			return st, pkg.Fset
+		}
+	}
+	return nil, nil
+}
+
+// typeSpec returns the AST of the type declaration name in pkgPath, with the file and package declaring it
+func (l *packageLoader) typeSpec(modRoot, pkgPath, name string) (*ast.TypeSpec, *ast.File, *packages.Package) {
+	pkg := l.pkg(modRoot, pkgPath)
+	if pkg == nil {
+		return nil, nil, nil
+	}
+
+	for _, syntax := range pkg.Syntax {
+		for _, decl := range syntax.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == name {
+					return ts, syntax, pkg
+				}
+			}
+		}
+	}
+	return nil, nil, nil
+}
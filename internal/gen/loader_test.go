@@ -0,0 +1,43 @@
+package gen
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPackageLoader(t *testing.T) {
+	modelsDir, err := filepath.Abs("../../examples/models")
+	if err != nil {
+		t.Fatalf("failed to get absolute path: %v", err)
+	}
+
+	l := newPackageLoader()
+	modRoot := l.goModDir(modelsDir)
+	if err := l.load(modRoot, modelsDir); err != nil {
+		t.Fatalf("failed to load packages: %v", err)
+	}
+
+	if _, ok := l.mods[modelsDir]; !ok {
+		t.Errorf("expected module root of %v to be cached", modelsDir)
+	}
+
+	if pkgPath := l.packagePath(modRoot, modelsDir); pkgPath != "gorm.io/cli/gorm/examples/models" {
+		t.Errorf("expected package path of models, got %q", pkgPath)
+	}
+
+	// Dependencies are resolved from the initial load
+	if _, ok := l.types["gorm.io/gorm"]; !ok {
+		t.Errorf("expected gorm.io/gorm to be indexed from imports")
+	}
+	if typ := l.namedType(modRoot, "gorm.io/gorm", "DeletedAt"); typ == nil {
+		t.Errorf("failed to load gorm.DeletedAt")
+	}
+
+	if st, _ := l.structType(modRoot, "gorm.io/gorm", "Model"); st == nil || len(st.Fields.List) != 4 {
+		t.Errorf("failed to load gorm.Model struct, got %v", st)
+	}
+
+	if values := l.enumValues(modRoot, "gorm.io/cli/gorm/examples/models", "Role"); len(values) != 2 || values[0] != "RoleActive" {
+		t.Errorf("unexpected enum values of models.Role, got %v", values)
+	}
+}
@@ -0,0 +1,248 @@
+package gen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const manifestFileName = "gorm_gen_manifest.json"
+
+// genManifest lists the files generated into an output root with their inputs and content hashes,
+// so files of previous runs can be pruned and files the generator didn't create aren't overwritten
+type genManifest struct {
+	root  string
+	Files map[string]manifestEntry `json:"files"` // by slash-separated path relative to the root
+}
+
+type manifestEntry struct {
+	Inputs []string `json:"inputs"` // slash-separated paths relative to the root
+	Hash   string   `json:"hash"`   // sha256 of the generated content
+}
+
+// loadManifest loads the manifest of the output root, a missing or corrupted manifest starts empty
+func loadManifest(root string) *genManifest {
+	m := &genManifest{root: root, Files: map[string]manifestEntry{}}
+	if content, err := os.ReadFile(filepath.Join(root, manifestFileName)); err == nil {
+		if err := json.Unmarshal(content, m); err != nil || m.Files == nil {
+			m.Files = map[string]manifestEntry{}
+		}
+	}
+	return m
+}
+
+// save writes the manifest into its output root, or removes it once no generated file is left
+func (m *genManifest) save() error {
+	manifestPath := filepath.Join(m.root, manifestFileName)
+	if len(m.Files) == 0 {
+		if err := os.Remove(manifestPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove manifest %v, got error %v", manifestPath, err)
+		}
+		return nil
+	}
+
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if existing, err := os.ReadFile(manifestPath); err == nil && bytes.Equal(existing, append(content, '\n')) {
+		return nil
+	}
+	if err := os.MkdirAll(m.root, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %v, got error %v", m.root, err)
+	}
+	return os.WriteFile(manifestPath, append(content, '\n'), 0o640)
+}
+
+// sortedFiles returns the paths of the generated files relative to the root in order
+func (m *genManifest) sortedFiles() []string {
+	files := make([]string, 0, len(m.Files))
+	for rel := range m.Files {
+		files = append(files, rel)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// remove deletes the generated file rel and the directories it leaves empty, unless it was
+// modified since it was generated, and drops it from the manifest
+func (m *genManifest) remove(rel string) error {
+	filePath := filepath.Join(m.root, filepath.FromSlash(rel))
+	entry := m.Files[rel]
+	delete(m.Files, rel)
+
+	content, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if contentHash(content) != entry.Hash {
+		fmt.Printf("Keeping file %s, it was modified since it was generated\n", filePath)
+		return nil
+	}
+
+	fmt.Printf("Removing file %s...\n", filePath)
+	if err := os.Remove(filePath); err != nil {
+		return fmt.Errorf("failed to remove file %v, got error %v", filePath, err)
+	}
+	for dir := filepath.Dir(filePath); dir != filepath.Clean(m.root) && strings.HasPrefix(dir, filepath.Clean(m.root)); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil { // not empty
+			break
+		}
+	}
+	return nil
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// outputWriter writes the generated files of a run and records them in the manifests of their output
+// roots. In check mode nothing is written, the files that are missing, outdated or stale are reported.
+type outputWriter struct {
+	check     bool
+	manifests map[string]*genManifest    // by output root
+	generated map[string]map[string]bool // files generated by the run, by output root
+	outdated  []string                   // files out of date in check mode
+}
+
+func newOutputWriter(check bool) *outputWriter {
+	return &outputWriter{check: check, manifests: map[string]*genManifest{}, generated: map[string]map[string]bool{}}
+}
+
+// manifest returns the manifest of the output root, loaded on first use
+func (w *outputWriter) manifest(root string) *genManifest {
+	if m, ok := w.manifests[root]; ok {
+		return m
+	}
+	m := loadManifest(root)
+	w.manifests[root] = m
+	w.generated[root] = map[string]bool{}
+	return m
+}
+
+// relPath returns the path of a generated or input file relative to the output root, slash-separated
+func (w *outputWriter) relPath(root, filePath string) string {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return filepath.ToSlash(filePath)
+	}
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return filepath.ToSlash(filePath)
+	}
+	if rel, err := filepath.Rel(absRoot, absPath); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(filePath)
+}
+
+// keep records the file of the output root as generated by the run without rendering it, e.g. cached outputs
+func (w *outputWriter) keep(root, filePath string) {
+	w.manifest(root)
+	w.generated[root][w.relPath(root, filePath)] = true
+}
+
+// write writes the generated content to filePath, unless it's unchanged, and records it in the manifest
+// of the output root. Existing files neither listed in the manifest nor containing hint, the header of
+// the generated files, weren't created by the generator and aren't overwritten.
+func (w *outputWriter) write(root, filePath string, content []byte, inputs []string, hint, message string) error {
+	m, rel := w.manifest(root), w.relPath(root, filePath)
+	w.generated[root][rel] = true
+
+	existing, err := os.ReadFile(filePath)
+	if err == nil && !bytes.Equal(existing, content) {
+		if _, owned := m.Files[rel]; !owned && hint != "" && !bytes.Contains(existing, []byte(hint)) {
+			return fmt.Errorf("refusing to overwrite %v, it wasn't generated by gorm", filePath)
+		}
+	}
+
+	if err != nil || !bytes.Equal(existing, content) {
+		if w.check {
+			state := "outdated"
+			if err != nil {
+				state = "missing"
+			}
+			w.outdated = append(w.outdated, filePath+": "+state)
+			return nil
+		}
+
+		fmt.Print(message)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for %v, got error %v", filePath, err)
+		}
+		if err := os.WriteFile(filePath, content, 0o640); err != nil {
+			return fmt.Errorf("failed to write file %v, got error %v", filePath, err)
+		}
+	}
+
+	sources := make([]string, len(inputs))
+	for i, input := range inputs {
+		sources[i] = w.relPath(root, input)
+	}
+	m.Files[rel] = manifestEntry{Inputs: sources, Hash: contentHash(content)}
+	return nil
+}
+
+// finish handles the files of previous runs the run didn't generate: they're reported as stale in
+// check mode, removed with prune and kept in the manifest otherwise. It saves the manifests, or
+// returns an error listing the files out of date in check mode.
+func (w *outputWriter) finish(prune bool) error {
+	roots := make([]string, 0, len(w.manifests))
+	for root := range w.manifests {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+
+	for _, root := range roots {
+		m := w.manifests[root]
+		for _, rel := range m.sortedFiles() {
+			if w.generated[root][rel] {
+				continue
+			}
+			switch {
+			case w.check:
+				w.outdated = append(w.outdated, filepath.Join(root, filepath.FromSlash(rel))+": stale")
+			case prune:
+				if err := m.remove(rel); err != nil {
+					return err
+				}
+			}
+		}
+		if !w.check {
+			if err := m.save(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(w.outdated) > 0 {
+		return fmt.Errorf("found %d generated files out of date:\n  %s", len(w.outdated), strings.Join(w.outdated, "\n  "))
+	}
+	return nil
+}
+
+// cleanOutputs removes the files generated into the output root listed in its manifest, and the
+// manifest; files modified since they were generated are kept
+func cleanOutputs(root string) error {
+	m := loadManifest(root)
+	if len(m.Files) == 0 {
+		fmt.Printf("No generated files found in %s\n", root)
+		return nil
+	}
+	for _, rel := range m.sortedFiles() {
+		if err := m.remove(rel); err != nil {
+			return err
+		}
+	}
+	return m.save()
+}
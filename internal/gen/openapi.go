@@ -0,0 +1,242 @@
+package gen
+
+import (
+	"encoding/json"
+	"go/constant"
+	"go/types"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Formats of the generated model schemas, see the --schema flag
+const (
+	schemaOpenAPI    = "openapi"
+	schemaJSONSchema = "jsonschema"
+)
+
+// schemaExtensions are the file extensions of the model schema formats
+var schemaExtensions = map[string]string{schemaOpenAPI: "openapi.json", schemaJSONSchema: "schema.json"}
+
+// jsonSchema is a JSON Schema of a model or field, as used by OpenAPI 3.1 component schemas
+type jsonSchema struct {
+	Ref                  string         `json:"$ref,omitempty"`
+	Type                 any            `json:"type,omitempty"` // a type name, or the type name and null of nullable fields
+	Format               string         `json:"format,omitempty"`
+	Description          string         `json:"description,omitempty"`
+	Enum                 []any          `json:"enum,omitempty"`
+	Minimum              *int           `json:"minimum,omitempty"`
+	Items                *jsonSchema    `json:"items,omitempty"`
+	AdditionalProperties *jsonSchema    `json:"additionalProperties,omitempty"`
+	Properties           jsonSchemaList `json:"properties,omitempty"`
+	Required             []string       `json:"required,omitempty"`
+	AnyOf                []*jsonSchema  `json:"anyOf,omitempty"`
+}
+
+// jsonSchemaList is a list of named schemas marshaled as a JSON object, keeping the order of the fields
+type jsonSchemaList []namedJSONSchema
+
+type namedJSONSchema struct {
+	Name   string
+	Schema *jsonSchema
+}
+
+func (l jsonSchemaList) MarshalJSON() ([]byte, error) {
+	b := []byte{'{'}
+	for i, s := range l {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		name, err := json.Marshal(s.Name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(s.Schema)
+		if err != nil {
+			return nil, err
+		}
+		b = append(append(append(b, name...), ':'), schema...)
+	}
+	return append(b, '}'), nil
+}
+
+// goJSONSchemas are the schemas of the Go types with a fixed JSON representation, nullable types are
+// marked by schemaOf, e.g. sql.NullString is a string or null
+var goJSONSchemas = map[string]jsonSchema{
+	"string":                   {Type: "string"},
+	"bool":                     {Type: "boolean"},
+	"int":                      {Type: "integer", Format: "int64"},
+	"int8":                     {Type: "integer", Format: "int32"},
+	"int16":                    {Type: "integer", Format: "int32"},
+	"int32":                    {Type: "integer", Format: "int32"},
+	"int64":                    {Type: "integer", Format: "int64"},
+	"float32":                  {Type: "number", Format: "float"},
+	"float64":                  {Type: "number", Format: "double"},
+	"[]byte":                   {Type: "string", Format: "byte"},
+	"time.Time":                {Type: "string", Format: "date-time"},
+	"time.Duration":            {Type: "integer", Format: "int64"},
+	"gorm.io/gorm.DeletedAt":   {Type: "string", Format: "date-time"},
+	"database/sql.NullString":  {Type: "string"},
+	"database/sql.NullBool":    {Type: "boolean"},
+	"database/sql.NullTime":    {Type: "string", Format: "date-time"},
+	"database/sql.NullByte":    {Type: "integer", Format: "int32"},
+	"database/sql.NullInt16":   {Type: "integer", Format: "int32"},
+	"database/sql.NullInt32":   {Type: "integer", Format: "int32"},
+	"database/sql.NullInt64":   {Type: "integer", Format: "int64"},
+	"database/sql.NullFloat64": {Type: "number", Format: "double"},
+}
+
+// modelSchemas returns the schemas of the structs of the package, generic structs are skipped.
+// Associations refer to the schemas of their models with refPrefix, e.g. #/components/schemas/.
+func (p docPackage) modelSchemas(refPrefix string) (schemas jsonSchemaList) {
+	models := map[string]bool{}
+	for _, s := range p.Structs {
+		models[s.file.Package+"."+s.Name] = true
+	}
+
+	for _, s := range p.Structs {
+		if s.TypeParams != "" {
+			continue
+		}
+		schema := &jsonSchema{Type: "object", Description: s.Description()}
+		for _, f := range s.Fields {
+			name, opts, _ := strings.Cut(reflect.StructTag(f.Tag).Get("json"), ",")
+			if name == "-" && opts == "" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+
+			fieldSchema := f.schemaOf(strings.TrimPrefix(f.GoType, "*"), refPrefix, models)
+			if f.Nullable() {
+				fieldSchema = fieldSchema.orNull()
+			}
+			fieldSchema.Description = strings.TrimSpace(f.Doc)
+			schema.Properties = append(schema.Properties, namedJSONSchema{Name: name, Schema: fieldSchema})
+
+			// fields are always marshaled, unless omitted when empty
+			if !strings.Contains(","+opts+",", ",omitempty,") && !strings.Contains(","+opts+",", ",omitzero,") {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+		schemas = append(schemas, namedJSONSchema{Name: s.Name, Schema: schema})
+	}
+	return schemas
+}
+
+// schemaOf returns the schema of goType, the type of the field or of its elements. Enums list their
+// values, models of the package are referenced, and types of unknown representation accept any value.
+func (f Field) schemaOf(goType, refPrefix string, models map[string]bool) *jsonSchema {
+	if elemType, ok := sqlNullElemType(goType); ok {
+		return f.schemaOf(elemType, refPrefix, models)
+	}
+	if schema, ok := goJSONSchemas[goType]; ok {
+		return &schema
+	}
+	if goType == "uint" || goType == "uint8" || goType == "uint16" || goType == "uint32" || goType == "uint64" {
+		format, minimum := "int64", 0
+		if goType != "uint" && goType != "uint64" {
+			format = "int32"
+		}
+		return &jsonSchema{Type: "integer", Format: format, Minimum: &minimum}
+	}
+	if elemType, ok := strings.CutPrefix(goType, "[]"); ok {
+		return &jsonSchema{Type: "array", Items: f.schemaOf(strings.TrimPrefix(elemType, "*"), refPrefix, models)}
+	}
+	if strings.HasPrefix(goType, "map[") {
+		if _, valueType, ok := strings.Cut(goType, "]"); ok {
+			return &jsonSchema{Type: "object", AdditionalProperties: f.schemaOf(strings.TrimPrefix(valueType, "*"), refPrefix, models)}
+		}
+	}
+	if models[path.Base(goType)] {
+		return &jsonSchema{Ref: refPrefix + path.Ext(goType)[1:]}
+	}
+	if schema := f.enumSchema(goType); schema != nil {
+		return schema
+	}
+	return &jsonSchema{}
+}
+
+// enumSchema returns the schema of goType if it's an enum type, with the values of its constants
+func (f Field) enumSchema(goType string) *jsonSchema {
+	idx := strings.LastIndex(goType, ".")
+	if idx <= 0 {
+		return nil
+	}
+	pkgPath, name := f.file.getFullImportPath(goType[:idx]), goType[idx+1:]
+	names := f.file.loader().enumValues(f.file.goModDir, pkgPath, name)
+	if len(names) == 0 {
+		return nil
+	}
+
+	schema := &jsonSchema{Type: "string"}
+	scope := f.file.loader().typesPackage(f.file.goModDir, pkgPath).Scope()
+	for _, n := range names {
+		c, ok := scope.Lookup(n).(*types.Const)
+		if !ok {
+			continue
+		}
+		switch v := constant.Val(c.Val()).(type) {
+		case string:
+			schema.Enum = append(schema.Enum, v)
+		default:
+			schema.Type = "integer"
+			schema.Enum = append(schema.Enum, v)
+		}
+	}
+	return schema
+}
+
+// orNull returns the schema accepting null too, references are wrapped as they can't have siblings
+func (s *jsonSchema) orNull() *jsonSchema {
+	switch typ := s.Type.(type) {
+	case string:
+		s.Type = []string{typ, "null"}
+		return s
+	case nil:
+		if s.Ref != "" {
+			return &jsonSchema{AnyOf: []*jsonSchema{s, {Type: "null"}}}
+		}
+	}
+	return s
+}
+
+// writeSchemas writes the schemas of the models of each output directory into <package>.<ext> files of
+// the directory in the format: an OpenAPI document with component schemas, or a JSON Schema with $defs
+func writeSchemas(w *outputWriter, outputs []*output, format string) error {
+	for _, pkg := range docPackages(outputs) {
+		var doc any
+		switch format {
+		case schemaOpenAPI:
+			schemas := pkg.modelSchemas("#/components/schemas/")
+			if len(schemas) == 0 {
+				continue
+			}
+			doc = struct {
+				OpenAPI    string            `json:"openapi"`
+				Info       map[string]string `json:"info"`
+				Components map[string]any    `json:"components"`
+			}{"3.1.0", map[string]string{"title": pkg.Name, "version": "1.0.0"}, map[string]any{"schemas": schemas}}
+		case schemaJSONSchema:
+			schemas := pkg.modelSchemas("#/$defs/")
+			if len(schemas) == 0 {
+				continue
+			}
+			doc = struct {
+				Schema string         `json:"$schema"`
+				Defs   jsonSchemaList `json:"$defs"`
+			}{"https://json-schema.org/draft/2020-12/schema", schemas}
+		}
+
+		content, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := w.writeDoc(pkg, filepath.Join(pkg.dir, pkg.Name+"."+schemaExtensions[format]), append(content, '\n'), ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
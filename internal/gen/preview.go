@@ -0,0 +1,908 @@
+package gen
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/cli/gorm/field"
+	"gorm.io/gorm/clause"
+)
+
+// PreviewSQL renders the SQL template of the method with the args and runs the Go code it's rendered into,
+// returning the SQL and params the generated method would pass to gorm, see PreviewSQL of the gen package
+func PreviewSQL(input, interfaceName, methodName string, args map[string]any) (sql string, params []any, err error) {
+	g := Generator{Files: map[string]*File{}, quiet: true}
+	if err := g.Process(input); err != nil {
+		return "", nil, err
+	}
+	m, err := g.previewMethod(interfaceName, methodName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	vars := map[string]reflect.Value{}
+	for name, arg := range args {
+		vars[name] = reflect.ValueOf(arg)
+	}
+	p := &previewer{method: m, scope: &previewScope{vars: vars}}
+
+	if m.SQL.Update != "" {
+		set, where, _ := splitUpdateSQL(m.SQL.Update)
+		whereSQL, whereParams, err := p.run(where)
+		if err != nil {
+			return "", nil, err
+		}
+		setSQL, setParams, err := p.run(set)
+		if err != nil {
+			return "", nil, err
+		}
+		return setSQL + " WHERE " + whereSQL, slices.Concat(setParams, whereParams), nil
+	}
+
+	tmpl := cmp.Or(m.SQL.Raw, m.SQL.Where, m.SQL.Select, m.SQL.Expr, m.SQL.Delete, m.SQL.Count)
+	if m.SQL.Insert != "" {
+		tmpl = m.insertSQL()
+	}
+	if tmpl == "" {
+		return "", nil, fmt.Errorf("method %s.%s has no SQL template", interfaceName, methodName)
+	}
+	return p.run(tmpl)
+}
+
+// previewMethod returns the method of the interface with the options of the configs applying to its file,
+// the interface is looked up in the processed files in the order of their paths
+func (g *Generator) previewMethod(interfaceName, methodName string) (*Method, error) {
+	inputPaths := make([]string, 0, len(g.Files))
+	for pth := range g.Files {
+		inputPaths = append(inputPaths, pth)
+	}
+	sort.Strings(inputPaths)
+
+	filesWithCfg := g.filesWithConfig()
+	for _, inputPath := range inputPaths {
+		file := g.Files[inputPath]
+		i := slices.IndexFunc(file.Interfaces, func(iface Interface) bool { return iface.Name == interfaceName })
+		if i < 0 {
+			continue
+		}
+
+		g.applyConfigs(file, filesWithCfg)
+		if err := file.prepareMethods(); err != nil {
+			return nil, err
+		}
+		for _, m := range file.Interfaces[i].Methods {
+			if m.Name == methodName {
+				return m, nil
+			}
+		}
+		return nil, fmt.Errorf("method %s.%s not found", interfaceName, methodName)
+	}
+	return nil, fmt.Errorf("interface %s not found", interfaceName)
+}
+
+// previewer runs the Go code rendered from the SQL templates of a method, see renderSQLTemplate; it
+// supports the statements emitted by Node.Emit and the Go expressions of the templates
+type previewer struct {
+	method *Method
+	scope  *previewScope
+}
+
+// previewScope holds the Go variables of the running code: the args of the method, and the variables
+// declared by the blocks being run
+type previewScope struct {
+	vars   map[string]reflect.Value
+	parent *previewScope
+}
+
+func (s *previewScope) lookup(name string) (reflect.Value, bool) {
+	for ; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// previewPackages are the package-level functions, values and types used by the rendered code
+var previewPackages = map[string]any{
+	"strings.TrimSpace":   strings.TrimSpace,
+	"strings.Trim":        strings.Trim,
+	"regexp.MustCompile":  regexp.MustCompile,
+	"clause.CurrentTable": clause.CurrentTable,
+	"field.AllowedColumn": field.AllowedColumn,
+	"field.Dialect":       field.Dialect,
+	"field.OrderBy":       field.OrderBy,
+	"field.Paginate":      field.Paginate,
+	"strings.Builder":     reflect.TypeFor[strings.Builder](),
+	"clause.Column":       reflect.TypeFor[clause.Column](),
+	"clause.Expr":         reflect.TypeFor[clause.Expr](),
+	"clause.Table":        reflect.TypeFor[clause.Table](),
+	"field.DialectSQL":    reflect.TypeFor[field.DialectSQL](),
+}
+
+// run renders the SQL template into the Go code of the generated method and runs it, returning its SQL
+// and params; the functions of the template aren't qualified, they're called from the args
+func (p *previewer) run(tmpl string) (string, []any, error) {
+	m := p.method
+	code, err := m.renderSQL(tmpl, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\nfunc _() {\n"+code+"\n}\n", 0)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid SQL template of %s.%s: %v", m.Interface.Name, m.Name, err)
+	}
+
+	outer := p.scope
+	defer func() { p.scope = outer }()
+	p.scope = &previewScope{vars: map[string]reflect.Value{}, parent: outer}
+	for _, stmt := range file.Decls[0].(*ast.FuncDecl).Body.List {
+		if err := p.exec(stmt); err != nil {
+			return "", nil, fmt.Errorf("failed to render SQL template of %s.%s: %v", m.Interface.Name, m.Name, err)
+		}
+	}
+
+	sb, _ := p.scope.lookup("sb")
+	params, _ := p.scope.lookup("params")
+	return sb.Addr().Interface().(*strings.Builder).String(), params.Interface().([]any), nil
+}
+
+// execList runs the statements in a scope of their own
+func (p *previewer) execList(stmts []ast.Stmt) error {
+	outer := p.scope
+	defer func() { p.scope = outer }()
+	p.scope = &previewScope{vars: map[string]reflect.Value{}, parent: outer}
+
+	for _, stmt := range stmts {
+		if err := p.exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *previewer) exec(stmt ast.Stmt) error {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		return p.execList(s.List)
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		if !ok {
+			return fmt.Errorf("unsupported statement %T", s.X)
+		}
+		_, err := p.call(call, true)
+		return err
+	case *ast.DeclStmt:
+		spec := s.Decl.(*ast.GenDecl).Specs[0].(*ast.ValueSpec)
+		typ, err := p.evalType(spec.Type)
+		if err != nil {
+			return err
+		}
+		p.declare(spec.Names[0].Name, reflect.Zero(typ))
+	case *ast.AssignStmt:
+		return p.assign(s.Lhs, s.Rhs, s.Tok)
+	case *ast.IncDecStmt:
+		v, err := p.evalExpr(s.X)
+		if err != nil {
+			return err
+		} else if !v.CanSet() || !isNumber(v) {
+			return fmt.Errorf("invalid operation %s on %s", s.Tok, describeValue(v))
+		}
+		op := token.ADD
+		if s.Tok == token.DEC {
+			op = token.SUB
+		}
+		r, err := arithmetic(op, v, reflect.ValueOf(1), v.Type())
+		if err != nil {
+			return err
+		}
+		v.Set(r)
+	case *ast.IfStmt:
+		outer := p.scope
+		defer func() { p.scope = outer }()
+		p.scope = &previewScope{vars: map[string]reflect.Value{}, parent: outer}
+		if s.Init != nil {
+			if err := p.exec(s.Init); err != nil {
+				return err
+			}
+		}
+		if ok, err := p.evalBool(s.Cond); err != nil {
+			return err
+		} else if ok {
+			return p.execList(s.Body.List)
+		}
+		if s.Else != nil {
+			return p.exec(s.Else)
+		}
+	case *ast.ForStmt:
+		return p.execFor(s)
+	case *ast.RangeStmt:
+		return p.execRange(s)
+	default:
+		return fmt.Errorf("unsupported statement %T", stmt)
+	}
+	return nil
+}
+
+// declare declares the variable in the current scope, addressable so it can be assigned and its pointer
+// methods called, e.g. WriteString of a strings.Builder
+func (p *previewer) declare(name string, v reflect.Value) {
+	if name == "_" {
+		return
+	}
+	if !v.IsValid() {
+		v = reflect.ValueOf((*any)(nil)).Elem()
+	}
+	variable := reflect.New(v.Type()).Elem()
+	variable.Set(v)
+	p.scope.vars[name] = variable
+}
+
+// assign runs the := or = assignment of the values to the variables
+func (p *previewer) assign(lhs, rhs []ast.Expr, tok token.Token) error {
+	if len(lhs) != 1 || len(rhs) != 1 {
+		return errors.New("unsupported assignment of multiple values")
+	}
+	v, err := p.evalExpr(rhs[0])
+	if err != nil {
+		return err
+	}
+	if tok == token.DEFINE {
+		p.declare(lhs[0].(*ast.Ident).Name, v)
+		return nil
+	}
+
+	variable, err := p.evalExpr(lhs[0])
+	if err != nil {
+		return err
+	} else if !variable.CanSet() {
+		return fmt.Errorf("cannot assign to %s", describeValue(variable))
+	}
+	if v, err = assignValue(v, variable.Type()); err != nil {
+		return err
+	}
+	variable.Set(v)
+	return nil
+}
+
+func (p *previewer) execFor(s *ast.ForStmt) error {
+	outer := p.scope
+	defer func() { p.scope = outer }()
+	p.scope = &previewScope{vars: map[string]reflect.Value{}, parent: outer}
+
+	if s.Init != nil {
+		if err := p.exec(s.Init); err != nil {
+			return err
+		}
+	}
+	for {
+		if s.Cond != nil {
+			if ok, err := p.evalBool(s.Cond); err != nil || !ok {
+				return err
+			}
+		}
+		if err := p.execList(s.Body.List); err != nil {
+			return err
+		}
+		if s.Post != nil {
+			if err := p.exec(s.Post); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *previewer) execRange(s *ast.RangeStmt) error {
+	collection, err := p.evalExpr(s.X)
+	if err != nil {
+		return err
+	}
+	elems, err := rangeValues(collection)
+	if err != nil {
+		return err
+	}
+
+	outer := p.scope
+	defer func() { p.scope = outer }()
+	for _, elem := range elems {
+		p.scope = &previewScope{vars: map[string]reflect.Value{}, parent: outer}
+		for i, expr := range []ast.Expr{s.Key, s.Value} {
+			if ident, ok := expr.(*ast.Ident); ok {
+				p.declare(ident.Name, elem[i])
+			}
+		}
+		if err := p.execList(s.Body.List); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rangeValues returns the key and value of each iteration of a range loop over the value, maps are ranged
+// over in the order of their formatted keys
+func rangeValues(v reflect.Value) (elems [][2]reflect.Value, err error) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			elems = append(elems, [2]reflect.Value{reflect.ValueOf(i), concrete(v.Index(i))})
+		}
+	case reflect.String:
+		for i, r := range v.String() {
+			elems = append(elems, [2]reflect.Value{reflect.ValueOf(i), reflect.ValueOf(r)})
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			elems = append(elems, [2]reflect.Value{key, concrete(v.MapIndex(key))})
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		for i := range v.Int() {
+			elems = append(elems, [2]reflect.Value{reflect.ValueOf(i).Convert(v.Type()), {}})
+		}
+	default:
+		return nil, fmt.Errorf("cannot range over %s", v.Type())
+	}
+	return elems, nil
+}
+
+// valueLen returns the length of the value, 0 for nil
+func valueLen(v reflect.Value) (int, error) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return 0, nil
+	case reflect.Slice, reflect.Array, reflect.String, reflect.Map, reflect.Chan:
+		return v.Len(), nil
+	case reflect.Pointer:
+		if v.Type().Elem().Kind() == reflect.Array {
+			return v.Type().Elem().Len(), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid argument for len: %s", v.Type())
+}
+
+// concrete returns the dynamic value of interface values, the invalid value for nil interfaces
+func concrete(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	return v
+}
+
+func (p *previewer) evalBool(e ast.Expr) (bool, error) {
+	v, err := p.evalExpr(e)
+	if err != nil {
+		return false, err
+	}
+	if v.Kind() != reflect.Bool {
+		return false, fmt.Errorf("non-boolean condition %s", describeValue(v))
+	}
+	return v.Bool(), nil
+}
+
+// basicTypes are the types of the conversions evaluated in expressions, e.g. string(status)
+var basicTypes = map[string]reflect.Type{
+	"bool": reflect.TypeFor[bool](), "string": reflect.TypeFor[string](),
+	"int": reflect.TypeFor[int](), "int8": reflect.TypeFor[int8](), "int16": reflect.TypeFor[int16](),
+	"int32": reflect.TypeFor[int32](), "int64": reflect.TypeFor[int64](), "rune": reflect.TypeFor[rune](),
+	"uint": reflect.TypeFor[uint](), "uint8": reflect.TypeFor[uint8](), "uint16": reflect.TypeFor[uint16](),
+	"uint32": reflect.TypeFor[uint32](), "uint64": reflect.TypeFor[uint64](), "byte": reflect.TypeFor[byte](),
+	"float32": reflect.TypeFor[float32](), "float64": reflect.TypeFor[float64](), "any": reflect.TypeFor[any](),
+}
+
+// evalType returns the type of the type expression of the rendered code, e.g. []any
+func (p *previewer) evalType(e ast.Expr) (reflect.Type, error) {
+	switch e := e.(type) {
+	case *ast.Ident:
+		if typ, ok := basicTypes[e.Name]; ok {
+			return typ, nil
+		}
+	case *ast.SelectorExpr:
+		if ident, ok := e.X.(*ast.Ident); ok {
+			if typ, ok := previewPackages[ident.Name+"."+e.Sel.Name].(reflect.Type); ok {
+				return typ, nil
+			}
+		}
+	case *ast.ArrayType:
+		if e.Len == nil {
+			elem, err := p.evalType(e.Elt)
+			if err != nil {
+				return nil, err
+			}
+			return reflect.SliceOf(elem), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported type %T", e)
+}
+
+func (p *previewer) evalExpr(e ast.Expr) (reflect.Value, error) {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return p.evalExpr(e.X)
+	case *ast.Ident:
+		switch e.Name {
+		case "true", "false":
+			return reflect.ValueOf(e.Name == "true"), nil
+		case "nil":
+			return reflect.Value{}, nil
+		}
+		if v, ok := p.scope.lookup(e.Name); ok {
+			if v.CanSet() {
+				return v, nil // a variable of the rendered code
+			}
+			return concrete(v), nil
+		}
+		return reflect.Value{}, fmt.Errorf("undefined: %s", e.Name)
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.INT:
+			n, err := strconv.ParseInt(e.Value, 0, 64)
+			return reflect.ValueOf(int(n)), err
+		case token.FLOAT:
+			f, err := strconv.ParseFloat(e.Value, 64)
+			return reflect.ValueOf(f), err
+		case token.STRING:
+			s, err := strconv.Unquote(e.Value)
+			return reflect.ValueOf(s), err
+		case token.CHAR:
+			s, err := strconv.Unquote(e.Value)
+			return reflect.ValueOf([]rune(s)[0]), err
+		}
+	case *ast.SelectorExpr:
+		// package-level functions and values are given by their qualified name, e.g. time.Now
+		if ident, ok := e.X.(*ast.Ident); ok {
+			if _, ok := p.scope.lookup(ident.Name); !ok {
+				name := ident.Name + "." + e.Sel.Name
+				if v, ok := p.scope.lookup(name); ok {
+					return concrete(v), nil
+				}
+				if v, ok := previewPackages[name]; ok {
+					if _, ok := v.(reflect.Type); !ok {
+						return reflect.ValueOf(v), nil
+					}
+				}
+			}
+		}
+		x, err := p.evalExpr(e.X)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return selectValue(x, e.Sel.Name)
+	case *ast.StarExpr:
+		x, err := p.evalExpr(e.X)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if x.Kind() != reflect.Pointer {
+			return reflect.Value{}, fmt.Errorf("invalid indirect of %s", describeValue(x))
+		} else if x.IsNil() {
+			return reflect.Value{}, errors.New("nil pointer dereference")
+		}
+		return x.Elem(), nil
+	case *ast.CompositeLit:
+		return p.evalCompositeLit(e)
+	case *ast.IndexExpr:
+		return p.evalIndex(e)
+	case *ast.UnaryExpr:
+		return p.evalUnary(e)
+	case *ast.BinaryExpr:
+		return p.evalBinary(e)
+	case *ast.CallExpr:
+		return p.evalCall(e)
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported expression %T", e)
+}
+
+// selectValue returns the field or the method of the value, through pointers; the pointer methods of
+// variables are selected on their address
+func selectValue(x reflect.Value, name string) (reflect.Value, error) {
+	if x.CanAddr() {
+		if m := x.Addr().MethodByName(name); m.IsValid() {
+			return m, nil
+		}
+	}
+	for v := concrete(x); v.IsValid(); v = v.Elem() {
+		if m := v.MethodByName(name); m.IsValid() {
+			return m, nil
+		}
+		if v.Kind() == reflect.Struct {
+			if f, ok := v.Type().FieldByName(name); ok && f.IsExported() {
+				return concrete(v.FieldByIndex(f.Index)), nil
+			}
+			break
+		}
+		if v.Kind() != reflect.Pointer {
+			break
+		} else if v.IsNil() {
+			return reflect.Value{}, fmt.Errorf("nil pointer dereference selecting %s", name)
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("%s has no field or method %s", describeValue(x), name)
+}
+
+// evalCompositeLit returns the struct or slice of the composite literal of the rendered code, e.g.
+// clause.Column{Name: name}
+func (p *previewer) evalCompositeLit(e *ast.CompositeLit) (reflect.Value, error) {
+	typ, err := p.evalType(e.Type)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	v := reflect.New(typ).Elem()
+	for _, elt := range e.Elts {
+		var dst reflect.Value
+		switch {
+		case typ.Kind() == reflect.Slice:
+			v = reflect.Append(v, reflect.Zero(typ.Elem()))
+			dst = v.Index(v.Len() - 1)
+		case typ.Kind() == reflect.Struct:
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("unsupported %s literal without keys", typ)
+			}
+			if dst = v.FieldByName(kv.Key.(*ast.Ident).Name); !dst.IsValid() {
+				return reflect.Value{}, fmt.Errorf("unknown field %s of %s", kv.Key.(*ast.Ident).Name, typ)
+			}
+			elt = kv.Value
+		default:
+			return reflect.Value{}, fmt.Errorf("unsupported %s literal", typ)
+		}
+
+		x, err := p.evalExpr(elt)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if x, err = assignValue(x, dst.Type()); err != nil {
+			return reflect.Value{}, err
+		}
+		dst.Set(x)
+	}
+	return v, nil
+}
+
+func (p *previewer) evalIndex(e *ast.IndexExpr) (reflect.Value, error) {
+	x, err := p.evalExpr(e.X)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	x = concrete(x)
+	index, err := p.evalExpr(e.Index)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	switch x.Kind() {
+	case reflect.Map:
+		key, err := assignValue(index, x.Type().Key())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v := x.MapIndex(key); v.IsValid() {
+			return concrete(v), nil
+		}
+		return reflect.Zero(x.Type().Elem()), nil
+	case reflect.Slice, reflect.Array, reflect.String:
+		if !isNumber(index) {
+			return reflect.Value{}, fmt.Errorf("invalid index %s", describeValue(index))
+		}
+		i := int(index.Convert(reflect.TypeFor[int]()).Int())
+		if i < 0 || i >= x.Len() {
+			return reflect.Value{}, fmt.Errorf("index out of range [%d] with length %d", i, x.Len())
+		}
+		return concrete(x.Index(i)), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot index %s", describeValue(x))
+}
+
+func (p *previewer) evalUnary(e *ast.UnaryExpr) (reflect.Value, error) {
+	x, err := p.evalExpr(e.X)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	x = concrete(x)
+	switch {
+	case e.Op == token.NOT && x.Kind() == reflect.Bool:
+		return reflect.ValueOf(!x.Bool()).Convert(x.Type()), nil
+	case e.Op == token.ADD && isNumber(x):
+		return x, nil
+	case e.Op == token.SUB && isNumber(x):
+		return arithmetic(token.SUB, reflect.Zero(x.Type()), x, x.Type())
+	}
+	return reflect.Value{}, fmt.Errorf("invalid operation %s%s", e.Op, describeValue(x))
+}
+
+func (p *previewer) evalBinary(e *ast.BinaryExpr) (reflect.Value, error) {
+	x, err := p.evalExpr(e.X)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	x = concrete(x)
+	if e.Op == token.LAND || e.Op == token.LOR {
+		if x.Kind() != reflect.Bool {
+			return reflect.Value{}, fmt.Errorf("invalid operation %s on %s", e.Op, describeValue(x))
+		}
+		if x.Bool() == (e.Op == token.LOR) {
+			return x, nil
+		}
+		y, err := p.evalExpr(e.Y)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if y = concrete(y); y.Kind() != reflect.Bool {
+			return reflect.Value{}, fmt.Errorf("invalid operation %s on %s", e.Op, describeValue(y))
+		}
+		return y, nil
+	}
+
+	y, err := p.evalExpr(e.Y)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	y = concrete(y)
+	switch e.Op {
+	case token.EQL, token.NEQ:
+		eq, err := equalValues(x, y)
+		return reflect.ValueOf(eq == (e.Op == token.EQL)), err
+	case token.LSS, token.LEQ, token.GTR, token.GEQ:
+		c, err := compareValues(x, y)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		switch e.Op {
+		case token.LSS:
+			return reflect.ValueOf(c < 0), nil
+		case token.LEQ:
+			return reflect.ValueOf(c <= 0), nil
+		case token.GTR:
+			return reflect.ValueOf(c > 0), nil
+		}
+		return reflect.ValueOf(c >= 0), nil
+	case token.ADD, token.SUB, token.MUL, token.QUO, token.REM:
+		if e.Op == token.ADD && x.Kind() == reflect.String && y.Kind() == reflect.String {
+			return reflect.ValueOf(x.String() + y.String()).Convert(x.Type()), nil
+		}
+		if !isNumber(x) || !isNumber(y) {
+			return reflect.Value{}, fmt.Errorf("invalid operation %s %s %s", describeValue(x), e.Op, describeValue(y))
+		}
+		// the type of an untyped constant operand is the type of the other operand
+		typ := x.Type()
+		if _, ok := e.X.(*ast.BasicLit); ok {
+			typ = y.Type()
+		}
+		return arithmetic(e.Op, x, y, typ)
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported operator %s", e.Op)
+}
+
+func (p *previewer) evalCall(e *ast.CallExpr) (reflect.Value, error) {
+	return p.call(e, false)
+}
+
+// call calls the function, the results are discarded by call statements, e.g. sb.WriteString("?")
+func (p *previewer) call(e *ast.CallExpr, stmt bool) (reflect.Value, error) {
+	if ident, ok := e.Fun.(*ast.Ident); ok {
+		if _, ok := p.scope.lookup(ident.Name); !ok {
+			if v, ok, err := p.evalBuiltin(ident.Name, e); ok {
+				return v, err
+			}
+		}
+	}
+
+	fn, err := p.evalExpr(e.Fun)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if fn = concrete(fn); fn.Kind() != reflect.Func {
+		return reflect.Value{}, fmt.Errorf("cannot call non-function %s", describeValue(fn))
+	}
+	typ := fn.Type()
+	if typ.NumOut() != 1 && !stmt {
+		return reflect.Value{}, fmt.Errorf("function %s must return a single value", typ)
+	}
+	if len(e.Args) < typ.NumIn()-1 || !typ.IsVariadic() && len(e.Args) != typ.NumIn() {
+		return reflect.Value{}, fmt.Errorf("wrong number of arguments in call to %s", typ)
+	}
+
+	args := make([]reflect.Value, len(e.Args))
+	for i, argExpr := range e.Args {
+		arg, err := p.evalExpr(argExpr)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		in := typ.In(min(i, typ.NumIn()-1))
+		if typ.IsVariadic() && i >= typ.NumIn()-1 && !e.Ellipsis.IsValid() {
+			in = in.Elem()
+		}
+		if args[i], err = assignValue(concrete(arg), in); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	var results []reflect.Value
+	if e.Ellipsis.IsValid() {
+		results = fn.CallSlice(args)
+	} else {
+		results = fn.Call(args)
+	}
+	if len(results) == 0 {
+		return reflect.Value{}, nil
+	}
+	return concrete(results[0]), nil
+}
+
+// evalBuiltin evaluates the call of the builtin function or the conversion to the basic type, it reports
+// false for other functions
+func (p *previewer) evalBuiltin(name string, e *ast.CallExpr) (reflect.Value, bool, error) {
+	switch name {
+	case "make":
+		typ, err := p.evalType(e.Args[0])
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+		return reflect.MakeSlice(typ, 0, 0), true, nil
+	case "append":
+		slice, err := p.evalExpr(e.Args[0])
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+		slice = concrete(slice)
+		for _, argExpr := range e.Args[1:] {
+			arg, err := p.evalExpr(argExpr)
+			if err != nil {
+				return reflect.Value{}, true, err
+			}
+			if arg = concrete(arg); e.Ellipsis.IsValid() {
+				slice = reflect.AppendSlice(slice, arg)
+			} else if arg, err = assignValue(arg, slice.Type().Elem()); err != nil {
+				return reflect.Value{}, true, err
+			} else {
+				slice = reflect.Append(slice, arg)
+			}
+		}
+		return slice, true, nil
+	}
+
+	typ, conversion := basicTypes[name]
+	if name != "len" && !conversion || len(e.Args) != 1 {
+		return reflect.Value{}, false, nil
+	}
+	arg, err := p.evalExpr(e.Args[0])
+	if err != nil {
+		return reflect.Value{}, true, err
+	}
+	arg = concrete(arg)
+	if name == "len" {
+		n, err := valueLen(arg)
+		return reflect.ValueOf(n), true, err
+	}
+	if !arg.IsValid() || !arg.Type().ConvertibleTo(typ) {
+		return reflect.Value{}, true, fmt.Errorf("cannot convert %s to %s", describeValue(arg), typ)
+	}
+	return arg.Convert(typ), true, nil
+}
+
+// assignValue returns the value as a value of the type, converting nil and numbers
+func assignValue(v reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	switch {
+	case !v.IsValid():
+		return reflect.Zero(typ), nil
+	case v.Type().AssignableTo(typ):
+		return v, nil
+	case isNumber(v) && v.Type().ConvertibleTo(typ) || v.Kind() == reflect.String && typ.Kind() == reflect.String:
+		return v.Convert(typ), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot use %s as %s", describeValue(v), typ)
+}
+
+func isNumber(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isFloat(v reflect.Value) bool {
+	return v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+}
+
+// isNil reports whether the value is nil, the invalid value being the untyped nil
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Func, reflect.Interface, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
+
+// describeValue returns the type of the value for errors
+func describeValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	return v.Type().String()
+}
+
+func equalValues(x, y reflect.Value) (bool, error) {
+	switch {
+	case !x.IsValid() || !y.IsValid():
+		return isNil(x) && isNil(y), nil
+	case isNumber(x) && isNumber(y):
+		c, err := compareValues(x, y)
+		return c == 0, err
+	case x.Kind() == reflect.String && y.Kind() == reflect.String:
+		return x.String() == y.String(), nil
+	case x.Kind() == reflect.Bool && y.Kind() == reflect.Bool:
+		return x.Bool() == y.Bool(), nil
+	case x.Type() == y.Type() && x.Type().Comparable():
+		return x.Equal(y), nil
+	}
+	return false, fmt.Errorf("mismatched types %s and %s", describeValue(x), describeValue(y))
+}
+
+func compareValues(x, y reflect.Value) (int, error) {
+	switch {
+	case x.Kind() == reflect.String && y.Kind() == reflect.String:
+		return strings.Compare(x.String(), y.String()), nil
+	case !isNumber(x) || !isNumber(y):
+		return 0, fmt.Errorf("cannot compare %s and %s", describeValue(x), describeValue(y))
+	case isFloat(x) || isFloat(y):
+		return cmp.Compare(x.Convert(reflect.TypeFor[float64]()).Float(), y.Convert(reflect.TypeFor[float64]()).Float()), nil
+	}
+	return cmp.Compare(x.Convert(reflect.TypeFor[int64]()).Int(), y.Convert(reflect.TypeFor[int64]()).Int()), nil
+}
+
+// arithmetic returns the result of the operation on the numbers as a value of the type
+func arithmetic(op token.Token, x, y reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	if isFloat(x) || isFloat(y) {
+		a, b := x.Convert(reflect.TypeFor[float64]()).Float(), y.Convert(reflect.TypeFor[float64]()).Float()
+		var r float64
+		switch op {
+		case token.ADD:
+			r = a + b
+		case token.SUB:
+			r = a - b
+		case token.MUL:
+			r = a * b
+		case token.QUO:
+			r = a / b
+		default:
+			return reflect.Value{}, fmt.Errorf("invalid operation %s on floats", op)
+		}
+		return reflect.ValueOf(r).Convert(typ), nil
+	}
+
+	a, b := x.Convert(reflect.TypeFor[int64]()).Int(), y.Convert(reflect.TypeFor[int64]()).Int()
+	var r int64
+	switch op {
+	case token.ADD:
+		r = a + b
+	case token.SUB:
+		r = a - b
+	case token.MUL:
+		r = a * b
+	case token.QUO, token.REM:
+		if b == 0 {
+			return reflect.Value{}, errors.New("integer divide by zero")
+		}
+		if r = a / b; op == token.REM {
+			r = a % b
+		}
+	}
+	return reflect.ValueOf(r).Convert(typ), nil
+}
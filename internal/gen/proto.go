@@ -0,0 +1,788 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/imports"
+	"gorm.io/gorm/schema"
+)
+
+// protoGenHint is the header of the models generated from protobuf files, formatted with the source file.
+// It differs from codeGenHint, so the models are processed when generating their field helpers.
+const protoGenHint = "// Code generated by 'gorm proto gen' from %s. DO NOT EDIT."
+
+// protoTypes are the Go types of the protobuf scalar and well-known types in generated models,
+// overridden with the --type-map flag
+var protoTypes = map[string]string{
+	"double":   "float64",
+	"float":    "float32",
+	"int32":    "int32",
+	"int64":    "int64",
+	"uint32":   "uint32",
+	"uint64":   "uint64",
+	"sint32":   "int32",
+	"sint64":   "int64",
+	"fixed32":  "uint32",
+	"fixed64":  "uint64",
+	"sfixed32": "int32",
+	"sfixed64": "int64",
+	"bool":     "bool",
+	"string":   "string",
+	"bytes":    "[]byte",
+
+	"google.protobuf.Timestamp":   "time.Time",
+	"google.protobuf.Duration":    "time.Duration",
+	"google.protobuf.StringValue": "sql.NullString",
+	"google.protobuf.BoolValue":   "sql.NullBool",
+	"google.protobuf.Int32Value":  "sql.NullInt32",
+	"google.protobuf.Int64Value":  "sql.NullInt64",
+	"google.protobuf.UInt32Value": "sql.NullInt64",
+	"google.protobuf.UInt64Value": "sql.NullInt64",
+	"google.protobuf.FloatValue":  "sql.NullFloat64",
+	"google.protobuf.DoubleValue": "sql.NullFloat64",
+	"google.protobuf.BytesValue":  "[]byte",
+}
+
+// protoScalars are the protobuf types stored as plain columns when optional, others are messages
+var protoScalars = []string{"double", "float", "int32", "int64", "uint32", "uint64", "sint32", "sint64", "fixed32", "fixed64", "sfixed32", "sfixed64", "bool", "string", "bytes"}
+
+// protoWellKnownPackages are the Go packages of the well-known protobuf types, see parsePBGoFile
+var protoWellKnownPackages = []string{"timestamppb", "durationpb", "wrapperspb", "structpb", "emptypb", "anypb"}
+
+type (
+	// protoFile is a .proto file, or the messages of a generated .pb.go file, rendered into models by the proto.tmpl template
+	protoFile struct {
+		Header   string
+		Package  string
+		Imports  []string
+		Enums    []protoEnum
+		Messages []protoMessage
+
+		source       string // path of the .proto or .pb.go file
+		protoPackage string // package declared in the .proto file, e.g. shop.v1
+	}
+	protoEnum struct {
+		GoName string
+		Doc    string
+		Values []protoEnumValue
+
+		fullName string // name of the enum in the proto package, e.g. Order.Status
+	}
+	protoEnumValue struct {
+		GoName string
+		Number string
+	}
+	protoMessage struct {
+		GoName string
+		Doc    string
+		Fields []protoField
+
+		fullName string
+	}
+	protoField struct {
+		GoName string
+		Doc    string
+		GoType string
+		Tag    string
+
+		name     string // name of the field in the proto message, the column of the model
+		typ      string // proto type of the field, or of the values of maps
+		key      string // proto type of the keys of maps
+		repeated bool
+		optional bool
+		scope    string // full name of the message declaring the field, to resolve nested types
+	}
+)
+
+// DocComment returns the doc comment of the enum for template generation
+func (e protoEnum) DocComment() string {
+	return protoDocComment(e.Doc, "")
+}
+
+// DocComment returns the doc comment of the message for template generation
+func (m protoMessage) DocComment() string {
+	return protoDocComment(m.Doc, "")
+}
+
+// DocComment returns the doc comment of the field for template generation
+func (f protoField) DocComment() string {
+	return protoDocComment(f.Doc, "\t")
+}
+
+func protoDocComment(doc, indent string) string {
+	if doc = strings.TrimSpace(doc); doc == "" {
+		return ""
+	}
+	return indent + "// " + strings.ReplaceAll(doc, "\n", "\n"+indent+"// ") + "\n"
+}
+
+// protoGoName returns the Go name of a proto message, enum or field name, e.g. UserID of user_id and
+// OrderStatus of the nested enum Order.Status
+func protoGoName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, ".") {
+		if strings.ContainsRune(part, '_') || strings.ToUpper(part) == part {
+			part = strings.ToLower(part)
+		}
+		b.WriteString(camelCase(part))
+	}
+	return b.String()
+}
+
+// genProto generates the models of the .proto and .pb.go files of input into dir, with package pkg and the
+// Go types of typeMap overriding protoTypes, into a <file>_models.go per file
+func genProto(input, dir, pkg string, typeMap map[string]string) error {
+	var sources []string
+	err := filepath.WalkDir(input, func(pth string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && (strings.HasSuffix(pth, ".proto") || strings.HasSuffix(pth, ".pb.go")) {
+			sources = append(sources, pth)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("no .proto or .pb.go files found in %v", input)
+	}
+	sort.Strings(sources)
+
+	var files []*protoFile
+	for _, source := range sources {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return err
+		}
+		var file *protoFile
+		if strings.HasSuffix(source, ".proto") {
+			file, err = parseProtoFile(source, string(content))
+		} else {
+			file, err = parsePBGoFile(source, content)
+		}
+		if err != nil {
+			return err
+		}
+		file.Package = pkg
+		file.Header = fmt.Sprintf(protoGenHint, filepath.Base(source))
+		files = append(files, file)
+	}
+
+	goTypes := map[string]string{}
+	for k, v := range protoTypes {
+		goTypes[k] = v
+	}
+	for k, v := range typeMap {
+		goTypes[k] = v
+	}
+	if err := resolveProtoTypes(files, goTypes); err != nil {
+		return err
+	}
+
+	tmpl, err := parseTemplates("", builtinFuncs)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %v, got error %v", dir, err)
+	}
+
+	for _, file := range files {
+		base := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(file.source), ".proto"), ".pb.go")
+		outPath := filepath.Join(dir, base+"_models.go")
+
+		// Never overwrite hand-written files, e.g. a user.go declaring more methods of the models
+		if existing, err := os.ReadFile(outPath); err == nil && !bytes.HasPrefix(existing, []byte(file.Header)) {
+			return fmt.Errorf("file %v exists and wasn't generated from %v, remove it first", outPath, file.source)
+		}
+
+		var results bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&results, protoTmplName, file); err != nil {
+			return fmt.Errorf("failed to render models of %v, got error %v", file.source, err)
+		}
+		result, err := imports.Process(outPath, results.Bytes(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to format models of %v, got error %v", file.source, err)
+		}
+		if existing, err := os.ReadFile(outPath); err != nil || !bytes.Equal(existing, result) {
+			fmt.Printf("Generating file %s from %s...\n", outPath, file.source)
+			if err := os.WriteFile(outPath, result, 0o640); err != nil {
+				return fmt.Errorf("failed to write file %v, got error %v", outPath, err)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveProtoTypes sets the Go types and gorm tags of the fields of the files. Messages and enums may be
+// referenced across files, they are generated into the same package.
+func resolveProtoTypes(files []*protoFile, types map[string]string) error {
+	declared := map[string]string{} // full name to Go name of messages and enums
+	enums := map[string]bool{}
+	for _, file := range files {
+		for _, e := range file.Enums {
+			declared[e.fullName], enums[e.fullName] = e.GoName, true
+		}
+		for _, m := range file.Messages {
+			declared[m.fullName] = m.GoName
+		}
+	}
+
+	ns := schema.NamingStrategy{}
+	for _, file := range files {
+		// lookup resolves a proto type from the scope of a message, returning its Go type and whether it's a message
+		lookup := func(typ, scope string) (goType string, message bool, err error) {
+			if goType, ok := types[strings.TrimPrefix(typ, ".")]; ok {
+				return file.qualify(goType), !slices.Contains(protoScalars, typ), nil
+			}
+			name := strings.TrimPrefix(strings.TrimPrefix(typ, "."), file.protoPackage+".")
+			for scope := scope; ; scope = scope[:max(strings.LastIndex(scope, "."), 0)] {
+				full := strings.TrimPrefix(scope+"."+name, ".")
+				if goName, ok := declared[full]; ok {
+					return goName, !enums[full], nil
+				}
+				if scope == "" {
+					return "", false, fmt.Errorf("unknown type %s, map it to a Go type with --type-map", typ)
+				}
+			}
+		}
+
+		for i := range file.Messages {
+			m := &file.Messages[i]
+			for j := range m.Fields {
+				f := &m.Fields[j]
+				goType, message, err := lookup(f.typ, f.scope)
+				if err != nil {
+					return fmt.Errorf("field %s of message %s in %v: %v", f.name, m.fullName, file.source, err)
+				}
+
+				var tags []string
+				if column := ns.ColumnName("", f.GoName); column != f.name {
+					tags = append(tags, "column:"+f.name)
+				}
+				_, mapped := types[strings.TrimPrefix(f.typ, ".")]
+				switch {
+				case f.key != "":
+					keyType, _, err := lookup(f.key, f.scope)
+					if err != nil {
+						return fmt.Errorf("field %s of message %s in %v: %v", f.name, m.fullName, file.source, err)
+					}
+					f.GoType = "map[" + keyType + "]" + goType
+					tags = append(tags, "serializer:json")
+				case f.repeated:
+					f.GoType = "[]" + goType
+					tags = append(tags, "serializer:json")
+				case message && !mapped: // messages of the package are stored as JSON
+					f.GoType = "*" + goType
+					tags = append(tags, "serializer:json")
+				case f.optional && !message:
+					f.GoType = "*" + goType
+				default:
+					f.GoType = goType
+				}
+				if len(tags) > 0 {
+					f.Tag = `gorm:"` + strings.Join(tags, ";") + `"`
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// qualify returns the Go type as written in the models, types of other packages declared with their
+// import path are imported, e.g. github.com/google/uuid.UUID to uuid.UUID
+func (p *protoFile) qualify(goType string) string {
+	elem := strings.TrimLeft(goType, "[]*")
+	idx := strings.LastIndex(elem, ".")
+	if idx < 0 || !strings.Contains(elem[:idx], "/") {
+		return goType
+	}
+	if pkgPath := elem[:idx]; !slices.Contains(p.Imports, pkgPath) {
+		p.Imports = append(p.Imports, pkgPath)
+	}
+	return goType[:len(goType)-len(elem)] + path.Base(elem[:idx]) + elem[idx:]
+}
+
+// protoToken is a token of a .proto file with the comment lines right above it
+type protoToken struct {
+	text string
+	doc  string
+	line int
+}
+
+// tokenizeProto splits the content of a .proto file into tokens, comments at the end of a line are dropped
+func tokenizeProto(content string) (tokens []protoToken) {
+	var (
+		doc      []string
+		line     = 1
+		lastLine = 0 // line of the last token
+	)
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '\n':
+			if line++; i+1 < len(content) && content[i+1] == '\n' {
+				doc = nil // comments separated by a blank line don't document the next token
+			}
+			i++
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(content[i:], "//"):
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				end = len(content) - i
+			}
+			if line != lastLine {
+				doc = append(doc, strings.TrimSpace(strings.TrimPrefix(content[i:i+end], "//")))
+			}
+			i += end
+		case strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				end = len(content) - i - 2
+			}
+			comment := content[i+2 : i+2+end]
+			if line != lastLine {
+				for _, l := range strings.Split(comment, "\n") {
+					if l = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(l), "*")); l != "" {
+						doc = append(doc, l)
+					}
+				}
+			}
+			line += strings.Count(comment, "\n")
+			i += end + 4
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(content) && content[end] != c {
+				if content[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			tokens = append(tokens, protoToken{text: content[i:min(end+1, len(content))], line: line})
+			lastLine, doc, i = line, nil, end+1
+		case strings.ContainsRune("{}[]()<>;=,", rune(c)):
+			tokens = append(tokens, protoToken{text: string(c), doc: strings.Join(doc, "\n"), line: line})
+			lastLine, doc, i = line, nil, i+1
+		default:
+			end := i
+			for end < len(content) && !unicode.IsSpace(rune(content[end])) && !strings.ContainsRune("{}[]()<>;=,\"'/", rune(content[end])) {
+				end++
+			}
+			if end == i { // a single / not starting a comment
+				end++
+			}
+			tokens = append(tokens, protoToken{text: content[i:end], doc: strings.Join(doc, "\n"), line: line})
+			lastLine, doc, i = line, nil, end
+		}
+	}
+	return tokens
+}
+
+// protoParser parses the messages and enums of a .proto file, options, services and extensions are skipped
+type protoParser struct {
+	source string
+	tokens []protoToken
+	pos    int
+	file   *protoFile
+}
+
+// parseProtoFile parses the messages and enums of the .proto file
+func parseProtoFile(source, content string) (*protoFile, error) {
+	p := &protoParser{source: source, tokens: tokenizeProto(content), file: &protoFile{source: source}}
+	for p.pos < len(p.tokens) {
+		tok, _ := p.next()
+		var err error
+		switch tok.text {
+		case "package":
+			var pkg protoToken
+			if pkg, err = p.next(); err == nil {
+				p.file.protoPackage = pkg.text
+				err = p.expect(";")
+			}
+		case "message":
+			err = p.parseMessage("", tok.doc)
+		case "enum":
+			err = p.parseEnum("", tok.doc)
+		case ";":
+		default: // syntax, import, option, service, extend...
+			err = p.skipStatement()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p.file, nil
+}
+
+// errorf returns an error at the line of the current token, or of the last one at the end of the file
+func (p *protoParser) errorf(format string, args ...any) error {
+	var line int
+	if len(p.tokens) > 0 {
+		line = p.tokens[min(p.pos, len(p.tokens)-1)].line
+	}
+	return fmt.Errorf("%s:%d: %s", p.source, line, fmt.Sprintf(format, args...))
+}
+
+func (p *protoParser) next() (protoToken, error) {
+	if p.pos >= len(p.tokens) {
+		return protoToken{}, p.errorf("unexpected end of file")
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+func (p *protoParser) peek() protoToken {
+	if p.pos >= len(p.tokens) {
+		return protoToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *protoParser) expect(text string) error {
+	if p.pos >= len(p.tokens) {
+		return p.errorf("unexpected end of file, expected %q", text)
+	}
+	if tok := p.tokens[p.pos]; tok.text != text {
+		return p.errorf("expected %q, got %q", text, tok.text)
+	}
+	p.pos++
+	return nil
+}
+
+// ident returns the next token, a name or a type, failing on punctuation like { or ;
+func (p *protoParser) ident() (string, error) {
+	tok, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if len(tok.text) == 1 && strings.ContainsRune("{}[]()<>;=,", rune(tok.text[0])) {
+		p.pos--
+		return "", p.errorf("expected a name, got %q", tok.text)
+	}
+	return tok.text, nil
+}
+
+// skipStatement skips the tokens up to the end of the current statement, a ; or a {} block
+func (p *protoParser) skipStatement() error {
+	for depth := 0; ; {
+		tok, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch tok.text {
+		case "{":
+			depth++
+		case "}":
+			if depth--; depth <= 0 {
+				return nil
+			}
+		case ";":
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+func (p *protoParser) parseEnum(scope, doc string) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	full := strings.TrimPrefix(scope+"."+name, ".")
+	e := protoEnum{GoName: protoGoName(full), Doc: doc, fullName: full}
+	prefix := strings.ToUpper(schema.NamingStrategy{}.ColumnName("", name)) + "_"
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for p.pos < len(p.tokens) && p.peek().text != "}" {
+		tok, _ := p.next()
+		switch tok.text {
+		case "option", "reserved":
+			err = p.skipStatement()
+		case ";":
+		default:
+			var number string
+			if err = p.expect("="); err == nil {
+				if number, err = p.ident(); err == nil {
+					e.Values = append(e.Values, protoEnumValue{GoName: e.GoName + protoGoName(strings.TrimPrefix(tok.text, prefix)), Number: number})
+					err = p.skipStatement()
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := p.expect("}"); err != nil {
+		return err
+	}
+	p.file.Enums = append(p.file.Enums, e)
+	return nil
+}
+
+func (p *protoParser) parseMessage(scope, doc string) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	full := strings.TrimPrefix(scope+"."+name, ".")
+	m := protoMessage{GoName: protoGoName(full), Doc: doc, fullName: full}
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for p.pos < len(p.tokens) && p.peek().text != "}" {
+		switch tok := p.peek(); tok.text {
+		case "message":
+			p.pos++
+			err = p.parseMessage(full, tok.doc)
+		case "enum":
+			p.pos++
+			err = p.parseEnum(full, tok.doc)
+		case "oneof": // the fields of a oneof are optional columns
+			p.pos++
+			if _, err = p.ident(); err == nil {
+				err = p.parseOneof(full, &m)
+			}
+		case "option", "reserved", "extensions", "extend":
+			err = p.skipStatement()
+		case ";":
+			p.pos++
+		default:
+			var f protoField
+			if f, err = p.parseField(full); err == nil {
+				m.Fields = append(m.Fields, f)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := p.expect("}"); err != nil {
+		return err
+	}
+	p.file.Messages = append(p.file.Messages, m)
+	return nil
+}
+
+// parseOneof parses the { ... } block of a oneof, its fields are added to the message as optional fields
+func (p *protoParser) parseOneof(scope string, m *protoMessage) error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for p.pos < len(p.tokens) && p.peek().text != "}" {
+		if p.peek().text == "option" {
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+			continue
+		}
+		f, err := p.parseField(scope)
+		if err != nil {
+			return err
+		}
+		f.optional = true
+		m.Fields = append(m.Fields, f)
+	}
+	return p.expect("}")
+}
+
+// parseField parses a field declaration, e.g. repeated string tags = 3 [deprecated = true];
+func (p *protoParser) parseField(scope string) (f protoField, err error) {
+	first := p.peek()
+	f = protoField{Doc: first.doc, scope: scope}
+	switch first.text {
+	case "repeated":
+		f.repeated = true
+		p.pos++
+	case "optional":
+		f.optional = true
+		p.pos++
+	case "required":
+		p.pos++
+	}
+
+	if f.typ, err = p.ident(); err != nil {
+		return f, err
+	}
+	if f.typ == "map" {
+		if err = p.expect("<"); err != nil {
+			return f, err
+		}
+		if f.key, err = p.ident(); err != nil {
+			return f, err
+		}
+		if err = p.expect(","); err != nil {
+			return f, err
+		}
+		if f.typ, err = p.ident(); err != nil {
+			return f, err
+		}
+		if err = p.expect(">"); err != nil {
+			return f, err
+		}
+	}
+	if f.name, err = p.ident(); err != nil {
+		return f, err
+	}
+	f.GoName = protoGoName(f.name)
+	if err = p.expect("="); err != nil {
+		return f, err
+	}
+	return f, p.skipStatement()
+}
+
+// parsePBGoFile parses the messages and enums of a .pb.go file generated by protoc-gen-go, back into
+// their proto types. Oneof fields are skipped, their Go types are interfaces.
+func parsePBGoFile(source string, content []byte) (*protoFile, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), source, content, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse file %q: %s", source, err)
+	}
+	file := &protoFile{source: source}
+
+	// enums are named int32 types, their values are constants named <Enum>_<VALUE>
+	enums := map[string]*protoEnum{}
+	var enumNames []string
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ident, ok := ts.Type.(*ast.Ident); ok && ident.Name == "int32" {
+				full := strings.ReplaceAll(ts.Name.Name, "_", ".")
+				enums[ts.Name.Name] = &protoEnum{GoName: protoGoName(full), Doc: pbDoc(gen.Doc, ts.Doc), fullName: full}
+				enumNames = append(enumNames, ts.Name.Name)
+			}
+		}
+	}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			typ, ok := vs.Type.(*ast.Ident)
+			if !ok || enums[typ.Name] == nil {
+				continue
+			}
+			// values of nested enums are prefixed with the parent message, e.g. Order_PENDING of Order_Status
+			e := enums[typ.Name]
+			goPrefix := typ.Name + "_"
+			if idx := strings.LastIndex(typ.Name, "_"); idx > 0 {
+				goPrefix = typ.Name[:idx+1]
+			}
+			prefix := strings.ToUpper(schema.NamingStrategy{}.ColumnName("", path.Ext("." + e.fullName)[1:])) + "_"
+			for i, name := range vs.Names {
+				if i < len(vs.Values) {
+					value := strings.TrimPrefix(strings.TrimPrefix(name.Name, goPrefix), prefix)
+					e.Values = append(e.Values, protoEnumValue{GoName: e.GoName + protoGoName(value), Number: types.ExprString(vs.Values[i])})
+				}
+			}
+		}
+	}
+	for _, name := range enumNames {
+		file.Enums = append(file.Enums, *enums[name])
+	}
+
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			full := strings.ReplaceAll(ts.Name.Name, "_", ".")
+			m := protoMessage{GoName: protoGoName(full), Doc: pbDoc(gen.Doc, ts.Doc), fullName: full}
+			isMessage := false
+			for _, field := range st.Fields.List {
+				if field.Tag == nil || len(field.Names) == 0 {
+					continue
+				}
+				tag, _ := strconv.Unquote(field.Tag.Value)
+				protoTag, ok := reflect.StructTag(tag).Lookup("protobuf")
+				if !ok {
+					continue
+				}
+				isMessage = true
+
+				pf := protoField{Doc: strings.TrimSpace(field.Doc.Text())}
+				for _, opt := range strings.Split(protoTag, ",") {
+					if name, ok := strings.CutPrefix(opt, "name="); ok {
+						pf.name = name
+					}
+				}
+				pf.GoName = protoGoName(pf.name)
+
+				typ := field.Type
+				if mt, ok := typ.(*ast.MapType); ok {
+					pf.key, typ = pbProtoType(mt.Key), mt.Value
+				} else if at, ok := typ.(*ast.ArrayType); ok && types.ExprString(at.Elt) != "byte" {
+					pf.repeated, typ = true, at.Elt
+				}
+				if star, ok := typ.(*ast.StarExpr); ok {
+					if _, ok := star.X.(*ast.Ident); ok && slices.Contains(protoScalars, pbProtoType(star.X)) {
+						pf.optional = true // proto3 optional scalars are pointers
+					}
+					typ = star.X
+				}
+				pf.typ = pbProtoType(typ)
+				m.Fields = append(m.Fields, pf)
+			}
+			if isMessage {
+				file.Messages = append(file.Messages, m)
+			}
+		}
+	}
+	return file, nil
+}
+
+// pbDoc returns the doc comment of a type of a .pb.go file
+func pbDoc(groups ...*ast.CommentGroup) string {
+	for _, g := range groups {
+		if text := strings.TrimSpace(g.Text()); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// pbGoTypes are the proto types of the Go types of .pb.go fields, other than their names
+var pbGoTypes = map[string]string{"float64": "double", "float32": "float", "[]byte": "bytes"}
+
+// pbProtoType returns the proto type of the Go type of a .pb.go field, e.g. google.protobuf.Timestamp of
+// timestamppb.Timestamp and Order.Status of Order_Status
+func pbProtoType(expr ast.Expr) string {
+	typ := types.ExprString(expr)
+	if protoType, ok := pbGoTypes[typ]; ok {
+		return protoType
+	}
+	if pkg, name, ok := strings.Cut(typ, "."); ok && slices.Contains(protoWellKnownPackages, pkg) {
+		return "google.protobuf." + name
+	}
+	return strings.ReplaceAll(typ, "_", ".")
+}
@@ -0,0 +1,325 @@
+package gen
+
+import (
+	"fmt"
+	"go/parser"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Kinds of differences between the models and a database schema, see diffSchema
+const (
+	diffMissingTable  = "missing table"
+	diffMissingColumn = "missing column"
+	diffTypeMismatch  = "type mismatch"
+	diffExtraTable    = "extra table"
+	diffUnknownColumn = "unknown column" // a column of a SQL template that no table has
+)
+
+// SchemaDrivers open the database of the schema diff command and the --verify-db flag by driver name,
+// they're registered by main from the drivers package, which the generator doesn't import to keep the
+// drivers out of the test binaries of PreviewSQL
+var SchemaDrivers = map[string]func(dsn string) gorm.Dialector{}
+
+// validateSchemaDriver returns an error if the driver can't open the database of --verify-db or schema diff
+func validateSchemaDriver(driver string) error {
+	if _, ok := SchemaDrivers[driver]; ok {
+		return nil
+	}
+	if driver == "sqlite" {
+		return fmt.Errorf("driver sqlite requires a cgo build of gorm, e.g. CGO_ENABLED=1 go install gorm.io/cli/gorm@latest")
+	}
+	return fmt.Errorf("invalid driver %q, must be one of mysql, postgres, sqlite", driver)
+}
+
+// schemaDiff is a difference between a model and the database schema
+type schemaDiff struct {
+	Kind   string
+	Table  string
+	Column string
+	Model  string // the struct or field of the model, e.g. User.Email
+	Detail string // the model and database types of a type mismatch
+}
+
+func (d schemaDiff) String() string {
+	s := d.Table
+	if d.Column != "" && s != "" {
+		s += "." + d.Column
+	} else if d.Column != "" {
+		s = d.Column
+	}
+	s += ": " + d.Kind
+	if d.Model != "" {
+		s += " (" + d.Model + ")"
+	}
+	if d.Detail != "" {
+		s += ", " + d.Detail
+	}
+	return s
+}
+
+// openSchemaDB connects to the database compared by the schema diff command or the --verify-db flag
+func openSchemaDB(driver, dsn string) (*gorm.DB, error) {
+	if err := validateSchemaDriver(driver); err != nil {
+		return nil, err
+	}
+	return gorm.Open(SchemaDrivers[driver](dsn), &gorm.Config{Logger: logger.Discard})
+}
+
+// diffSchema compares the processed model structs against the tables of the database, it reports
+// missing tables and columns, columns whose type doesn't match the field and tables of no model.
+// Views, generic structs and structs without a primary key, e.g. DTOs, aren't compared.
+func (g *Generator) diffSchema(db *gorm.DB) ([]schemaDiff, error) {
+	var (
+		diffs      []schemaDiff
+		models     []Struct
+		knownNames = map[string]bool{} // tables of the models and their join tables
+	)
+
+	paths := make([]string, 0, len(g.Files))
+	for pth := range g.Files {
+		paths = append(paths, pth)
+	}
+	sort.Strings(paths)
+	for _, pth := range paths {
+		for _, s := range g.Files[pth].Structs {
+			if s.view || s.TypeParams != "" || len(s.PrimaryFields()) == 0 || knownNames[s.TableName()] {
+				continue
+			}
+			models = append(models, s)
+			knownNames[s.TableName()] = true
+			for _, f := range s.Fields {
+				if f.IsAssociation() && f.association().JoinTable != "" {
+					knownNames[f.association().JoinTable] = true
+				}
+			}
+		}
+	}
+
+	for _, s := range models {
+		structDiffs, err := diffStruct(db, s)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, structDiffs...)
+	}
+
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the tables of the database, got error %v", err)
+	}
+	slices.Sort(tables)
+	for _, table := range tables {
+		if !knownNames[table] && !strings.HasPrefix(table, "sqlite_") {
+			diffs = append(diffs, schemaDiff{Kind: diffExtraTable, Table: table})
+		}
+	}
+	return diffs, nil
+}
+
+// verifyColumns checks the columns of the generated models exist on their tables, and the columns of
+// @@ placeholders of SQL templates resolving to constants exist on some table, see the --verify-db flag.
+// Views and generic structs aren't verified, nor placeholders of method parameters known at runtime only.
+func (g *Generator) verifyColumns(db *gorm.DB) error {
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return fmt.Errorf("failed to load the tables of the database, got error %v", err)
+	}
+	knownColumns := map[string]bool{}
+	for _, table := range tables {
+		columnTypes, err := db.Migrator().ColumnTypes(table)
+		if err != nil {
+			return fmt.Errorf("failed to load the columns of table %v, got error %v", table, err)
+		}
+		for _, c := range columnTypes {
+			knownColumns[strings.ToLower(c.Name())] = true
+		}
+	}
+
+	paths := make([]string, 0, len(g.Files))
+	for pth := range g.Files {
+		paths = append(paths, pth)
+	}
+	sort.Strings(paths)
+
+	var diffs []schemaDiff
+	for _, pth := range paths {
+		file := g.Files[pth]
+		for _, s := range file.Structs {
+			if s.view || s.TypeParams != "" {
+				continue
+			}
+			structDiffs, err := diffStruct(db, s)
+			if err != nil {
+				return err
+			}
+			diffs = append(diffs, slices.DeleteFunc(structDiffs, func(d schemaDiff) bool { return d.Kind == diffTypeMismatch })...)
+		}
+
+		for _, iface := range file.Interfaces {
+			for _, m := range iface.Methods {
+				for _, column := range m.templateColumns(file) {
+					if !knownColumns[strings.ToLower(column)] {
+						diffs = append(diffs, schemaDiff{Kind: diffUnknownColumn, Column: column, Model: iface.Name + "." + m.Name})
+					}
+				}
+			}
+		}
+	}
+
+	if len(diffs) == 0 {
+		return nil
+	}
+	report := make([]string, len(diffs))
+	for i, d := range diffs {
+		report[i] = "  " + d.String()
+	}
+	return fmt.Errorf("found %d columns or tables missing in the database:\n%s", len(diffs), strings.Join(report, "\n"))
+}
+
+// templateColumns returns the columns of the @@ placeholders of the method's SQL template that resolve
+// to string constants, e.g. @@columnName with const columnName = "name"; placeholders of parameters are skipped
+func (m Method) templateColumns(file *File) (columns []string) {
+	for _, sql := range m.SQL.templates() {
+		_, placeholders, _ := scanPlaceholders(stripSQLComments(sql), m.placeholderPrefix)
+		for _, ph := range placeholders {
+			name := ph.Name
+			if !ph.Ident || name == "table" {
+				continue
+			}
+			root, _, _ := strings.Cut(name, ".")
+			if slices.ContainsFunc(m.Params, func(p Param) bool { return p.Name == root }) {
+				continue
+			}
+			expr, err := parser.ParseExpr(name)
+			if err != nil {
+				continue
+			}
+			if column := strLitValue(file.resolveValue(expr)); column != nil {
+				columns = append(columns, *column)
+			}
+		}
+	}
+	return columns
+}
+
+// diffStruct compares the struct against its table, it reports a missing table, missing columns and
+// columns whose type doesn't match the field
+func diffStruct(db *gorm.DB, s Struct) (diffs []schemaDiff, err error) {
+	migrator, table := db.Migrator(), s.TableName()
+	if !migrator.HasTable(table) {
+		return []schemaDiff{{Kind: diffMissingTable, Table: table, Model: s.Name}}, nil
+	}
+
+	columnTypes, err := migrator.ColumnTypes(table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the columns of table %v, got error %v", table, err)
+	}
+	columns := map[string]gorm.ColumnType{}
+	for _, c := range columnTypes {
+		columns[strings.ToLower(c.Name())] = c
+	}
+
+	for _, f := range s.ColumnFields() {
+		model := s.Name + "." + f.Name
+		column, ok := columns[strings.ToLower(f.DBName)]
+		if !ok {
+			diffs = append(diffs, schemaDiff{Kind: diffMissingColumn, Table: table, Column: f.DBName, Model: model})
+			continue
+		}
+		if expected, ok := f.matchesColumnType(column.DatabaseTypeName()); !ok {
+			diffs = append(diffs, schemaDiff{
+				Kind: diffTypeMismatch, Table: table, Column: f.DBName, Model: model,
+				Detail: fmt.Sprintf("model %s, database %s", expected, strings.ToLower(column.DatabaseTypeName())),
+			})
+		}
+	}
+	return diffs, nil
+}
+
+// goColumnKinds are the kinds of column types Go types are stored in, see Field.matchesColumnType
+var goColumnKinds = map[string]string{
+	"string":                   "string",
+	"bool":                     "bool",
+	"[]byte":                   "bytes",
+	"time.Time":                "time",
+	"gorm.io/gorm.DeletedAt":   "time",
+	"database/sql.NullString":  "string",
+	"database/sql.NullBool":    "bool",
+	"database/sql.NullTime":    "time",
+	"database/sql.NullByte":    "int",
+	"database/sql.NullInt16":   "int",
+	"database/sql.NullInt32":   "int",
+	"database/sql.NullInt64":   "int",
+	"database/sql.NullFloat64": "float",
+}
+
+// dbColumnKinds are the kinds of database column types, by type name without size
+var dbColumnKinds = map[string]string{}
+
+func init() {
+	for kind, names := range map[string][]string{
+		"string":  {"char", "varchar", "nchar", "nvarchar", "text", "tinytext", "mediumtext", "longtext", "clob", "enum", "set", "uuid", "json", "jsonb"},
+		"int":     {"int", "integer", "tinyint", "smallint", "mediumint", "bigint", "int2", "int4", "int8", "serial", "bigserial"},
+		"float":   {"float", "double", "double precision", "real", "float4", "float8"},
+		"decimal": {"decimal", "numeric"},
+		"bool":    {"bool", "boolean", "bit"},
+		"time":    {"date", "time", "datetime", "timestamp", "timestamptz", "timestamp with time zone", "timestamp without time zone"},
+		"bytes":   {"binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob", "bytea"},
+	} {
+		for _, name := range names {
+			dbColumnKinds[name] = kind
+		}
+	}
+}
+
+// compatibleColumnKinds are the database column kinds each Go kind can be stored in, booleans are
+// stored in tinyint on MySQL and numeric on SQLite
+var compatibleColumnKinds = map[string][]string{
+	"string": {"string"},
+	"int":    {"int", "decimal"},
+	"float":  {"float", "decimal"},
+	"bool":   {"bool", "int", "decimal"},
+	"time":   {"time"},
+	"bytes":  {"bytes"},
+}
+
+var columnTypeSizeRegexp = regexp.MustCompile(`\s*\(.*\)|\s+unsigned`)
+
+// matchesColumnType reports whether the database column type fits the field, with the type expected
+// by the field. Fields declaring a type in their gorm tag expect the same type name, the others a type
+// of a compatible kind. Types that can't be classified, e.g. serialized fields, always match.
+func (f Field) matchesColumnType(dbType string) (expected string, ok bool) {
+	dbType = columnTypeSizeRegexp.ReplaceAllString(strings.ToLower(dbType), "")
+	if declared := f.columnSchema().Type; declared != "" {
+		declared = columnTypeSizeRegexp.ReplaceAllString(strings.ToLower(declared), "")
+		return declared, declared == dbType || dbColumnKinds[declared] != "" && dbColumnKinds[declared] == dbColumnKinds[dbType]
+	}
+	if f.serializer() != "" {
+		return "", true
+	}
+
+	goType := strings.TrimPrefix(f.GoType, "*")
+	if elemType, ok := sqlNullElemType(goType); ok {
+		goType = elemType
+	}
+	kind := goColumnKinds[goType]
+	switch {
+	case kind != "":
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"):
+		kind = "int"
+	case strings.HasPrefix(goType, "float"):
+		kind = "float"
+	}
+
+	dbKind := dbColumnKinds[dbType]
+	if kind == "" || dbKind == "" {
+		return kind, true
+	}
+	return kind, slices.Contains(compatibleColumnKinds[kind], dbKind)
+}
@@ -0,0 +1,138 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+
+	"golang.org/x/tools/imports"
+)
+
+// TestModel returns the name of the type alias the test skeleton of the interface instantiates it with
+func (i Interface) TestModel() string {
+	return lowerFirst(i.Name) + "Model"
+}
+
+// TestModelType returns the model type the test skeleton of the interface is instantiated with, the
+// model the interface is bound to, see queryModel, or the only struct of the file, "" if unknown
+func (p *File) TestModelType(iface Interface) string {
+	if model := p.queryModel(iface); model != "" {
+		dot := strings.LastIndex(model, ".")
+		pkgPath, name := model[:dot], model[dot+1:]
+		for _, imp := range p.Imports {
+			if imp.Path == pkgPath {
+				return imp.Name + "." + name
+			}
+		}
+		return path.Base(pkgPath) + "." + name
+	}
+	if len(p.Structs) == 1 {
+		return p.Structs[0].ModelType()
+	}
+	return ""
+}
+
+// TestSetup returns the name of the database setup helper of the interface's test skeleton
+func (i Interface) TestSetup() string {
+	return "setup" + i.Name + "DB"
+}
+
+// TestFields returns the parameters of the method without ctx, the fields of a test case
+func (m Method) TestFields() (fields []Param) {
+	for _, p := range m.Params {
+		if p.Name != "ctx" && p.Type != "context.Context" {
+			fields = append(fields, p)
+		}
+	}
+	return fields
+}
+
+// TestArgs returns the arguments of the method call in its test skeleton, taken from the test case
+func (m Method) TestArgs() string {
+	var args []string
+	if m.injectContext() {
+		args = append(args, "context.Background()")
+	}
+	for _, p := range m.Params {
+		if p.Name == "ctx" || p.Type == "context.Context" {
+			args = append(args, "context.Background()")
+		} else {
+			args = append(args, "tt.args."+p.Name)
+		}
+	}
+	return strings.Join(args, ", ")
+}
+
+// TestResults returns the variables assigned with the results of the method call in its test skeleton,
+// chain methods are finished with Find
+func (m Method) TestResults() string {
+	if m.SQL.finish() && len(m.Result) == 1 && !m.TestStreams() {
+		return "err"
+	}
+	return "got, err"
+}
+
+// TestStreams reports whether the method returns an iterator, its test skeleton collects the streamed rows
+func (m Method) TestStreams() bool {
+	elem, callback := m.stream()
+	return elem != "" && callback == ""
+}
+
+// TestCall returns the method call of the test skeleton, expressions of expr methods are queried with Where
+func (m Method) TestCall() string {
+	call := fmt.Sprintf("%s[T](db).%s(%s)", m.Interface.Name, m.Name, m.TestArgs())
+	if m.SQL.Expr != "" {
+		call = fmt.Sprintf("%s[T](db).Where(%s)", m.Interface.Name, call)
+	}
+	if !m.SQL.finish() {
+		call += ".Find(context.Background())"
+	}
+	return call
+}
+
+// testSkeletonPath returns the path of the test skeleton of a generated file, e.g. query_test.go of query.go
+func testSkeletonPath(outPath string) string {
+	return strings.TrimSuffix(outPath, ".go") + "_test.go"
+}
+
+// keepTestSkeleton keeps the test skeleton of the generated file at outPath in the manifest of the
+// output root, if it was generated, and reports whether it exists
+func keepTestSkeleton(writer *outputWriter, root, outPath string) (bool, error) {
+	testPath := testSkeletonPath(outPath)
+	if _, err := os.Stat(testPath); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if _, ok := writer.manifest(root).Files[writer.relPath(root, testPath)]; ok {
+		writer.keep(root, testPath)
+	}
+	return true, nil
+}
+
+// writeTestSkeleton renders the test skeleton of the interfaces of file next to the generated file at
+// outPath and records it in the manifest of the output root, so it's pruned with the interfaces unless
+// modified. Skeletons are edited by hand, so an existing one is never overwritten.
+func writeTestSkeleton(writer *outputWriter, root string, tmpl *template.Template, file *File, outPath string, inputs []string) error {
+	testPath := testSkeletonPath(outPath)
+	if exists, err := keepTestSkeleton(writer, root, outPath); exists || err != nil || writer.check {
+		return err
+	}
+
+	var results bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&results, testTmplName, file); err != nil {
+		return fmt.Errorf("failed to render test skeleton of %v, got error %v", file.inputPath, err)
+	}
+
+	result, err := imports.Process(testPath, results.Bytes(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to format test skeleton %v, got error %v", testPath, err)
+	}
+
+	message := fmt.Sprintf("Generating test skeleton %s from %s...\n", testPath, file.inputPath)
+	return writer.write(root, testPath, result, inputs, "", message)
+}
@@ -0,0 +1,369 @@
+package gen
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// sqlCheckGeneric is the dialect of --validate-sql without a value, its templates are checked by
+// checkSQLTokens only
+const sqlCheckGeneric = "generic"
+
+// sqlChecker checks the syntax of example statements of SQL templates for a dialect, with the parser of
+// the embedded SQLite for sqlite, and with checkSQLTokens for the other dialects, which have no parser:
+// their errors are labeled as the common checks, see describe
+type sqlChecker struct {
+	dialect string
+	db      *sql.DB // in-memory SQLite database preparing the statements of the sqlite dialect
+}
+
+// newSQLChecker returns the checker of the dialect, one of sqlDialects or sqlCheckGeneric
+func newSQLChecker(dialect string) (*sqlChecker, error) {
+	if dialect != sqlCheckGeneric && !slices.Contains(sqlDialects, dialect) {
+		return nil, fmt.Errorf("invalid SQL dialect %q, must be one of %s, %s", dialect, sqlCheckGeneric, strings.Join(sqlDialects, ", "))
+	}
+	checker := &sqlChecker{dialect: dialect}
+	if dialect == "sqlite" {
+		db, err := openSchemaDB("sqlite", ":memory:")
+		if err != nil {
+			return nil, fmt.Errorf("error opening the SQLite parser: %v", err)
+		}
+		if checker.db, err = db.DB(); err != nil {
+			return nil, fmt.Errorf("error opening the SQLite parser: %v", err)
+		}
+	}
+	return checker, nil
+}
+
+func (c *sqlChecker) Close() error {
+	if c.db == nil {
+		return nil
+	}
+	return c.db.Close()
+}
+
+// describe returns the dialect of the checker for error messages, labeling the dialects checked by
+// checkSQLTokens only
+func (c *sqlChecker) describe() string {
+	if c.db == nil && c.dialect != sqlCheckGeneric {
+		return c.dialect + " (common checks only, no " + c.dialect + " parser)"
+	}
+	return c.dialect
+}
+
+// sqliteSyntaxErrorRegexp matches the errors of SQLite parsing statements, the other errors of preparing them,
+// e.g. no such table, aren't syntax errors
+var sqliteSyntaxErrorRegexp = regexp.MustCompile(`syntax error|incomplete input|unrecognized token|unterminated`)
+
+// check reports the syntax error of the statement
+func (c *sqlChecker) check(stmt string) error {
+	if c.db == nil {
+		return checkSQLTokens(stmt, c.dialect)
+	}
+	prepared, err := c.db.Prepare(stmt)
+	if err != nil {
+		if sqliteSyntaxErrorRegexp.MatchString(err.Error()) {
+			return err
+		}
+		return nil
+	}
+	return prepared.Close()
+}
+
+// clauseKeywords are the keywords starting a clause, a comma can't precede them
+var clauseKeywords = map[string]bool{
+	"FROM": true, "WHERE": true, "GROUP": true, "ORDER": true, "HAVING": true, "LIMIT": true, "OFFSET": true,
+	"UNION": true, "INTERSECT": true, "EXCEPT": true, "RETURNING": true, "VALUES": true,
+}
+
+// dollarQuoteRegexp matches the opening $$ or $tag$ of a Postgres dollar-quoted string
+var dollarQuoteRegexp = regexp.MustCompile(`^\$(?:[A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// quotedEnd returns the offset after the quoted string or identifier starting at offset i of the statement,
+// -1 if it's unterminated, or 0 if there's none. MySQL escapes quotes with backslashes, Postgres with
+// backslashes in E'...' strings only and also quotes strings with $$ or $tag$ dollar quotes; doubled
+// quotes are read as two adjacent strings by all dialects.
+func quotedEnd(stmt string, i int, dialect string) int {
+	c := stmt[i]
+	if dialect == "postgres" && c == '$' && (i == 0 || !isWordChar(stmt[i-1])) {
+		tag := dollarQuoteRegexp.FindString(stmt[i:])
+		if tag == "" {
+			return 0
+		}
+		end := strings.Index(stmt[i+len(tag):], tag)
+		if end < 0 {
+			return -1
+		}
+		return i + len(tag) + end + len(tag)
+	}
+	if c != '\'' && c != '"' && c != '`' {
+		return 0
+	}
+
+	escapes := dialect == "mysql" && c != '`' ||
+		dialect == "postgres" && c == '\'' && i > 0 && (stmt[i-1] == 'E' || stmt[i-1] == 'e') && (i == 1 || !isWordChar(stmt[i-2]))
+	for j := i + 1; j < len(stmt); j++ {
+		switch {
+		case escapes && stmt[j] == '\\':
+			j++
+		case stmt[j] == c:
+			return j + 1
+		}
+	}
+	return -1
+}
+
+// checkSQLTokens checks the statement for the syntax errors common to SQL dialects: unbalanced parentheses,
+// unterminated quotes and comments, misplaced commas and commas missing between values or assignments.
+// Quoted strings are read by the rules of the dialect, see quotedEnd, and emptied before the statement is
+// tokenized, so their content isn't checked.
+func checkSQLTokens(stmt, dialect string) error {
+	var depth int
+	var masked strings.Builder
+	for i := 0; i < len(stmt); i++ {
+		switch c, quoted := stmt[i], quotedEnd(stmt, i, dialect); {
+		case quoted < 0:
+			return fmt.Errorf("unterminated quoted string %s", stmt[i:])
+		case quoted > 0:
+			if c == '$' {
+				c = '\''
+			}
+			masked.WriteByte(c)
+			masked.WriteByte(c)
+			i = quoted - 1
+			continue
+		case strings.HasPrefix(stmt[i:], "--"):
+			end := strings.IndexByte(stmt[i:], '\n')
+			if end < 0 {
+				end = len(stmt) - i
+			}
+			masked.WriteString(stmt[i : i+end])
+			i += end - 1
+			continue
+		case strings.HasPrefix(stmt[i:], "/*"):
+			end := strings.Index(stmt[i+2:], "*/")
+			if end < 0 {
+				return errors.New("unterminated /* comment")
+			}
+			masked.WriteString(stmt[i : i+end+4])
+			i += end + 3
+			continue
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth--; depth < 0 {
+				return fmt.Errorf("unbalanced ) near %q", near(stmt, i))
+			}
+		}
+		masked.WriteByte(stmt[i])
+	}
+	if depth > 0 {
+		return errors.New("unbalanced (, missing )")
+	}
+
+	stmt = masked.String()
+	tokens := tokenizeSQL(stmt, "@")
+	isValue := func(i int) bool {
+		return i < len(tokens) && (tokens[i].kind == sqlLiteral || tokens[i].text == "?" ||
+			tokens[i].kind == sqlIdent && !sqlKeywords[strings.ToUpper(tokens[i].text)])
+	}
+	for i, token := range tokens {
+		var next string
+		if i+1 < len(tokens) {
+			next = tokens[i+1].text
+		}
+		switch {
+		case token.text == "," && (next == "" || next == "," || next == ")" || clauseKeywords[strings.ToUpper(next)]):
+			return fmt.Errorf("misplaced comma near %q", near(stmt, token.offset))
+		case next == "," && (token.text == "(" || token.kind == sqlIdent && slices.Contains([]string{"SELECT", "BY", "SET"}, strings.ToUpper(token.text))):
+			return fmt.Errorf("misplaced comma near %q", near(stmt, tokens[i+1].offset))
+		case (token.text == "?" || token.kind == sqlLiteral) && (next == "?" || i+1 < len(tokens) && tokens[i+1].kind == sqlLiteral && token.text == "?"):
+			return fmt.Errorf("missing comma near %q", near(stmt, token.offset))
+		case token.text == "=" && isValue(i+1) && i+3 < len(tokens) && isValue(i+2) && tokens[i+2].kind == sqlIdent && tokens[i+3].text == "=":
+			return fmt.Errorf("missing comma near %q", near(stmt, tokens[i+1].offset))
+		}
+	}
+	return nil
+}
+
+// near returns the text of the statement around the offset, for error messages
+func near(stmt string, offset int) string {
+	return strings.TrimSpace(stmt[max(offset-20, 0):min(offset+20, len(stmt))])
+}
+
+// connectorRegexp matches the leading and trailing AND/OR connectors trimmed by {{where}} and {{group}}
+var connectorRegexp = regexp.MustCompile(`(?i)^\s*(?:and|or)\s+|\s+(?:and|or)\s*$`)
+
+// exampleSQL renders the nodes into an example of the SQL of the template for the dialect, see Node.Example
+func exampleSQL(nodes []Node, dialect string) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		if s := strings.TrimSpace(n.Example(dialect)); s != "" {
+			if b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(s)
+		}
+	}
+	return b.String()
+}
+
+// inListRegexp matches the IN operator preceding a placeholder, the slice of which gorm renders as a list
+var inListRegexp = regexp.MustCompile(`(?i)\bIN\s*$`)
+
+func (t *TextNode) Example(dialect string) string {
+	text := strings.TrimSpace(t.Text)
+	example, _, _ := replacePlaceholders(text, t.Prefix, func(ph placeholder) string {
+		switch {
+		case ph.Quoted:
+			return "x"
+		case ph.Ident && ph.Name == "table" && ph.Alias != "":
+			return "t " + ph.Alias
+		case ph.Ident && ph.Name == "table":
+			return "t"
+		case ph.Ident:
+			return "c"
+		case inListRegexp.MatchString(text[:ph.Offset]):
+			return "(?)"
+		}
+		return "?"
+	})
+	return example
+}
+
+func (c *CommentNode) Example(dialect string) string {
+	return c.sql()
+}
+
+func (f *FuncNode) Example(dialect string) string {
+	c := exampleSQL(f.Body, dialect)
+	if pattern := trimPattern(f.Prefixes, f.Suffixes); f.Name == "trim" && pattern != "" {
+		c = strings.TrimSpace(regexp.MustCompile(pattern).ReplaceAllString(c, ""))
+	}
+	if c == "" {
+		return ""
+	}
+	switch f.Name {
+	case "where":
+		return "WHERE " + connectorRegexp.ReplaceAllString(c, "")
+	case "set":
+		return "SET " + strings.Trim(c, ", ")
+	case "group":
+		return "(" + connectorRegexp.ReplaceAllString(c, "") + ")"
+	}
+	return c
+}
+
+// Example renders the first branch of the block, or the branch of the dialect of {{if dialect}} blocks
+func (in *IfNode) Example(dialect string) string {
+	if len(in.Branches[0].Dialects) == 0 {
+		return exampleSQL(in.Branches[0].Body, dialect)
+	}
+	for _, br := range in.Branches {
+		if slices.Contains(br.Dialects, dialect) {
+			return exampleSQL(br.Body, dialect)
+		}
+	}
+	return exampleSQL(in.ElseBody, dialect)
+}
+
+// Example renders a single iteration of the loop
+func (fn *ForNode) Example(dialect string) string {
+	return exampleSQL(fn.Body, dialect)
+}
+
+func (l *LimitNode) Example(dialect string) string {
+	var parts []string
+	switch dialect {
+	case "sqlserver", "oracle":
+		if l.Offset != "" {
+			parts = append(parts, "OFFSET ? ROWS")
+		}
+		if l.Limit != "" && l.Offset != "" {
+			parts = append(parts, "FETCH NEXT ? ROWS ONLY")
+		} else if l.Limit != "" {
+			parts = append(parts, "FETCH FIRST ? ROWS ONLY")
+		}
+	default:
+		if l.Limit != "" {
+			parts = append(parts, "LIMIT ?")
+		} else if dialect != "postgres" {
+			parts = append(parts, "LIMIT -1")
+		}
+		if l.Offset != "" {
+			parts = append(parts, "OFFSET ?")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (o *OrderByNode) Example(dialect string) string {
+	if o.Direction != "" {
+		return "ORDER BY c DESC"
+	}
+	return "ORDER BY c"
+}
+
+func (v *ValuesNode) Example(dialect string) string {
+	return "(" + strings.Repeat("?, ", max(len(v.Fields), 1)-1) + "?)"
+}
+
+func (in *InNode) Example(dialect string) string {
+	return in.Column + " IN (?)"
+}
+
+// validateSQL checks the syntax of the SQL templates of the methods of the interface with the checker, the
+// templates are rendered into example statements: placeholders are replaced with ?, @@table with a table
+// t, the first branches of {{if}} blocks and a single iteration of {{for}} loops are rendered
+func (p *File) validateSQL(iface Interface, checker *sqlChecker) error {
+	for _, m := range iface.Methods {
+		type statement struct{ tmpl, prefix, suffix string }
+		var stmts []statement
+		if m.SQL.Raw != "" {
+			raws := m.statements()
+			if raws == nil {
+				raws = []string{m.SQL.Raw}
+			}
+			for _, raw := range raws {
+				stmts = append(stmts, statement{tmpl: raw})
+			}
+		}
+		for _, s := range []statement{
+			{m.SQL.Where, "SELECT * FROM t WHERE ", ""},
+			{m.SQL.Select, "SELECT ", " FROM t"},
+			{m.SQL.Expr, "SELECT * FROM t WHERE ", ""},
+			{m.SQL.Update, "UPDATE t SET ", ""},
+			{m.SQL.Delete, "DELETE FROM t WHERE ", ""},
+			{m.SQL.Count, "SELECT COUNT(*) FROM t WHERE ", ""},
+		} {
+			if s.tmpl != "" {
+				stmts = append(stmts, s)
+			}
+		}
+
+		for _, s := range stmts {
+			nodes, err := parseSQLTemplate(s.tmpl, sqlTemplateOptions{
+				fragments:     m.fragments,
+				funcs:         m.funcs,
+				prefix:        m.placeholderPrefix,
+				stripComments: m.stripComments,
+			})
+			if err != nil {
+				continue // reported when generating the method
+			}
+			example := s.prefix + exampleSQL(nodes, checker.dialect) + s.suffix
+			if err := checker.check(example); err != nil {
+				msg := fmt.Sprintf("invalid SQL of %s.%s for %s: %v\n\t%s", iface.Name, m.Name, checker.describe(), err, example)
+				if pos := m.sqlPosition(s.tmpl, 1, 1); pos.IsValid() {
+					return fmt.Errorf("%s: %s", pos, msg)
+				}
+				return errors.New(msg)
+			}
+		}
+	}
+	return nil
+}
@@ -3,21 +3,201 @@ package gen
 import (
 	"errors"
 	"fmt"
+	"go/scanner"
+	"go/token"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 // Node is the interface that all AST nodes implement.
 type Node interface {
 	Emit(indent, target string, withPrefix bool) string
+	// Example renders the node into example SQL for the dialect, for checking the syntax of templates
+	Example(dialect string) string
 }
 
 // TextNode holds plain text.
 type TextNode struct {
-	Text string
+	Text   string
+	Prefix string            // placeholder prefix, defaults to @
+	Funcs  map[string]string // qualified Go functions callable in @{expr} placeholders, by name
+	Loop   *ForNode          // innermost loop of the text, the metadata of which @{expr} placeholders can use
+}
+
+// defaultPlaceholderPrefix is the prefix of @param placeholders, doubled for @@ident placeholders
+const defaultPlaceholderPrefix = "@"
+
+// placeholder is a @param or @@ident placeholder of SQL template text
+type placeholder struct {
+	Offset int    // byte offset of the placeholder in the text
+	Name   string // parameter expression or identifier, e.g. user.Name, table
+	Ident  bool   // @@ident placeholder of a column, or of the current table
+	Expr   bool   // @{expr} placeholder of a Go expression, e.g. len(users)
+	Quoted bool   // placeholder inside a '...' string literal
+	Alias  string // alias of the current table, e.g. u of @@table(as=u)
+	// Allowed are the columns of the in (name, age) whitelist of a @@column placeholder, checked at runtime,
+	// a single "*" for the columns of the model
+	Allowed []string
+}
+
+// text returns the placeholder as written in templates
+func (ph placeholder) text(prefix string) string {
+	if ph.Alias != "" {
+		return prefix + prefix + ph.Name + "(as=" + ph.Alias + ")"
+	}
+	if ph.Ident {
+		return prefix + prefix + ph.Name
+	}
+	if ph.Expr {
+		return prefix + "{" + ph.Name + "}"
+	}
+	return prefix + ph.Name
+}
+
+// inlineExprEnd returns the end of the Go expression of a @{expr} placeholder, the offset after the
+// brace closing the one at the offset i of the text, or -1 if it isn't closed
+func inlineExprEnd(text string, i int) int {
+	var depth int
+	for ; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '"', '`', '\'':
+			for i++; i < len(text) && text[i] != c; i++ {
+				if text[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// templateVarRegexp matches the ${name} template vars of SQL templates, escaped with a backslash
+var templateVarRegexp = regexp.MustCompile(`\\?\$\{(\w*)\}`)
+
+// expandTemplateVars replaces the ${name} vars of the template with their SQL, \${name} is unescaped
+// and kept as is; it returns the offset of the unknown var on errors
+func expandTemplateVars(tmpl string, vars map[string]string) (string, int, error) {
+	var b strings.Builder
+	var last int
+	for _, loc := range templateVarRegexp.FindAllStringSubmatchIndex(tmpl, -1) {
+		b.WriteString(tmpl[last:loc[0]])
+		last = loc[1]
+		if tmpl[loc[0]] == '\\' {
+			b.WriteString(tmpl[loc[0]+1 : loc[1]])
+			continue
+		}
+		value, ok := vars[tmpl[loc[2]:loc[3]]]
+		if !ok {
+			return "", loc[0], fmt.Errorf("unknown template var %s", tmpl[loc[0]:loc[1]])
+		}
+		b.WriteString(value)
+	}
+	b.WriteString(tmpl[last:])
+	return b.String(), 0, nil
 }
 
-var rePlaceholder = regexp.MustCompile(`@@table|@@[A-Za-z0-9_.]+|@[A-Za-z0-9_.]+`)
+// tableAliasRegexp matches the alias option of @@table(as=u) placeholders
+var tableAliasRegexp = regexp.MustCompile(`^\(\s*as\s*=\s*([A-Za-z_]\w*)\s*\)`)
+
+// identWhitelistRegexp matches the in (name, age) whitelist of @@column placeholders, in (*) for the
+// columns of the model
+var identWhitelistRegexp = regexp.MustCompile(`^\s+in\s*\(\s*(\*|[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?(?:\s*,\s*[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?)*)\s*\)`)
+
+// scanPlaceholders replaces the placeholders of the text with ?, and unescapes the escaped prefixes, e.g. \@.
+// It returns the offsets of the prefixes not followed by a name, which are kept as is.
+func scanPlaceholders(text, prefix string) (sql string, placeholders []placeholder, bare []int) {
+	return replacePlaceholders(text, prefix, func(placeholder) string { return "?" })
+}
+
+// replacePlaceholders is scanPlaceholders replacing each placeholder with the result of replace
+func replacePlaceholders(text, prefix string, replace func(placeholder) string) (sql string, placeholders []placeholder, bare []int) {
+	if prefix == "" {
+		prefix = defaultPlaceholderPrefix
+	}
+	isNameChar := func(i int) bool {
+		return i < len(text) && text[i] < unicode.MaxASCII && (isWordChar(text[i]) || text[i] == '.')
+	}
+	nameEnd := func(i int) int {
+		for isNameChar(i) {
+			i++
+		}
+		return i
+	}
+
+	var b strings.Builder
+	var quoted bool
+	for i := 0; i < len(text); {
+		switch rest := text[i:]; {
+		case strings.HasPrefix(rest, `\`+prefix):
+			b.WriteString(prefix)
+			i += 1 + len(prefix)
+		case strings.HasPrefix(rest, prefix+prefix) && isNameChar(i+2*len(prefix)):
+			end := nameEnd(i + 2*len(prefix))
+			ph := placeholder{Offset: i, Name: text[i+2*len(prefix) : end], Ident: true, Quoted: quoted}
+			if m := tableAliasRegexp.FindStringSubmatch(text[end:]); m != nil && ph.Name == "table" {
+				ph.Alias = m[1]
+				end += len(m[0])
+			} else if m := identWhitelistRegexp.FindStringSubmatch(text[end:]); m != nil && ph.Name != "table" {
+				for _, column := range strings.Split(m[1], ",") {
+					ph.Allowed = append(ph.Allowed, strings.TrimSpace(column))
+				}
+				end += len(m[0])
+			}
+			placeholders = append(placeholders, ph)
+			b.WriteString(replace(ph))
+			i = end
+		case strings.HasPrefix(rest, prefix+"{") && !strings.HasPrefix(rest, prefix+"{{") && inlineExprEnd(text, i+len(prefix)) > 0:
+			end := inlineExprEnd(text, i+len(prefix))
+			ph := placeholder{Offset: i, Name: strings.TrimSpace(text[i+len(prefix)+1 : end-1]), Expr: true, Quoted: quoted}
+			placeholders = append(placeholders, ph)
+			b.WriteString(replace(ph))
+			i = end
+		case strings.HasPrefix(rest, prefix) && isNameChar(i+len(prefix)):
+			end := nameEnd(i + len(prefix))
+			ph := placeholder{Offset: i, Name: text[i+len(prefix) : end], Quoted: quoted}
+			placeholders = append(placeholders, ph)
+			b.WriteString(replace(ph))
+			i = end
+		case strings.HasPrefix(rest, prefix):
+			bare = append(bare, i)
+			b.WriteString(prefix)
+			i += len(prefix)
+		default:
+			if text[i] == '\'' {
+				quoted = !quoted
+			}
+			b.WriteByte(text[i])
+			i++
+		}
+	}
+	return b.String(), placeholders, bare
+}
+
+// validatePlaceholderPrefix reports whether the prefix can't be confused with the SQL around placeholders
+func validatePlaceholderPrefix(prefix string) error {
+	if prefix == "" || strings.ContainsFunc(prefix, func(r rune) bool {
+		return r > unicode.MaxASCII || unicode.IsSpace(r) || r == '_' || r == '.' || r == '\\' || r == '\'' || r == '"' ||
+			r == '{' || r == '}' || r == '?' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) {
+		return fmt.Errorf("invalid placeholder prefix %q, must be made of punctuation characters like @ or $", prefix)
+	}
+	// @@column placeholders double the prefix, which mustn't read as SQL, e.g. Postgres ::text casts
+	for _, op := range []string{"::", "--", "||", "&&", "<<", ">>"} {
+		if strings.Contains(prefix+prefix, op) {
+			return fmt.Errorf("invalid placeholder prefix %q, %q placeholders are confused with the SQL operator %s", prefix, prefix+prefix, op)
+		}
+	}
+	return nil
+}
 
 func (t *TextNode) Emit(indent, target string, withPrefix bool) string {
 	str := strings.TrimSpace(t.Text)
@@ -25,26 +205,31 @@ func (t *TextNode) Emit(indent, target string, withPrefix bool) string {
 		return ""
 	}
 
-	escapedToken := "___ESCAPED_AT___"
-	str = strings.ReplaceAll(str, "\\@", escapedToken)
-
+	replaced, placeholders, _ := scanPlaceholders(str, t.Prefix)
 	var params []string
-	replaced := rePlaceholder.ReplaceAllStringFunc(str, func(ph string) string {
+	for _, ph := range placeholders {
 		switch {
-		case ph == "@@table":
+		case ph.Ident && ph.Name == "table" && ph.Alias != "":
+			params = append(params, fmt.Sprintf("clause.Table{Name: clause.CurrentTable, Alias: %q}", ph.Alias))
+		case ph.Ident && ph.Name == "table":
 			params = append(params, "clause.Table{Name: clause.CurrentTable}")
-			return "?"
-		case strings.HasPrefix(ph, "@@"):
-			params = append(params, fmt.Sprintf("clause.Column{Name: %s}", ph[2:]))
-			return "?"
-		case strings.HasPrefix(ph, "@"):
-			params = append(params, ph[1:])
-			return "?"
-		}
-		return ph
-	})
-
-	replaced = strings.ReplaceAll(replaced, escapedToken, "@")
+		case ph.Ident && len(ph.Allowed) > 0:
+			args := []string{ph.Name}
+			for _, column := range ph.Allowed {
+				if column != "*" {
+					args = append(args, strconv.Quote(column))
+				}
+			}
+			params = append(params, fmt.Sprintf("field.AllowedColumn(%s)", strings.Join(args, ", ")))
+		case ph.Ident:
+			params = append(params, fmt.Sprintf("clause.Column{Name: %s}", ph.Name))
+		case ph.Expr:
+			expr, _ := bindLoopVars(ph.Name, t.Loop) // checked when parsing the template
+			params = append(params, qualifyFuncs(expr, t.Funcs))
+		default:
+			params = append(params, ph.Name)
+		}
+	}
 
 	if withPrefix {
 		replaced = " " + replaced
@@ -58,20 +243,133 @@ func (t *TextNode) Emit(indent, target string, withPrefix bool) string {
 	return out.String()
 }
 
-// FuncNode for {{where}} / {{set}} blocks.
+// CommentNode holds a SQL comment kept in the generated SQL, as a /* */ block comment since the
+// generated SQL is written on a single line.
+type CommentNode struct {
+	Text string
+}
+
+// sql returns the comment as a block comment
+func (c *CommentNode) sql() string {
+	if body, ok := strings.CutPrefix(c.Text, "--"); ok {
+		return "/* " + strings.ReplaceAll(strings.TrimSpace(body), "*/", "* /") + " */"
+	}
+	return c.Text
+}
+
+func (c *CommentNode) Emit(indent, target string, withPrefix bool) string {
+	text := c.sql()
+	prefix := ""
+	if withPrefix {
+		prefix = " "
+	}
+	// gorm would bind the ? and @name of the comment to the params, they're written by a clause.Expr instead
+	if strings.ContainsAny(text, "?@") {
+		return fmt.Sprintf("%s%s.WriteString(%q)\n%sparams = append(params, clause.Expr{SQL: %q})\n", indent, target, prefix+"?", indent, text)
+	}
+	return fmt.Sprintf("%s%s.WriteString(%q)\n", indent, target, prefix+text)
+}
+
+// sqlCommentEnd returns the end of the -- or /* */ comment starting at the offset i of the SQL template,
+// line comments end before the newline; it returns i if no comment starts at i, and -1 if the block
+// comment isn't closed
+func sqlCommentEnd(tmpl string, i int) int {
+	switch {
+	case strings.HasPrefix(tmpl[i:], "--"):
+		if end := strings.IndexByte(tmpl[i:], '\n'); end >= 0 {
+			return i + end
+		}
+		return len(tmpl)
+	case strings.HasPrefix(tmpl[i:], "/*"):
+		if end := strings.Index(tmpl[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 2
+		}
+		return -1
+	}
+	return i
+}
+
+// scanSQLComments calls fn with the offsets of the comments of the SQL template, comments are
+// recognized outside quoted strings and directives, and directives inside comments are part of them
+func scanSQLComments(tmpl string, fn func(start, end int)) {
+	var quote byte
+	for i := 0; i < len(tmpl); {
+		switch c := tmpl[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			i++
+		case strings.HasPrefix(tmpl[i:], "{{"):
+			end := strings.Index(tmpl[i:], "}}")
+			if end < 0 {
+				return
+			}
+			i += end + 2
+		case c == '\\':
+			i += 2
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			i++
+		default:
+			end := sqlCommentEnd(tmpl, i)
+			if end < 0 {
+				end = len(tmpl)
+			}
+			if end == i {
+				i++
+				continue
+			}
+			fn(i, end)
+			i = end
+		}
+	}
+}
+
+// stripSQLComments returns the SQL template with its comments blanked out, newlines and the offsets of
+// the rest of the template are kept to report positions
+func stripSQLComments(tmpl string) string {
+	b := []byte(tmpl)
+	scanSQLComments(tmpl, func(start, end int) {
+		for i := start; i < end; i++ {
+			if b[i] != '\n' {
+				b[i] = ' '
+			}
+		}
+	})
+	return string(b)
+}
+
+// sqlHintRegexp matches optimizer hints and MySQL executable comments, which aren't stripped
+var sqlHintRegexp = regexp.MustCompile(`^/\*[+!]`)
+
+// FuncNode for {{where}} / {{set}} / {{trim}} / {{group}} blocks.
 type FuncNode struct {
-	Name string
-	Body []Node
+	Name     string
+	Body     []Node
+	Prefixes []string // leading tokens trimmed by {{trim}}, e.g. AND, OR
+	Suffixes []string // trailing tokens trimmed by {{trim}}, e.g. ","
 }
 
 func (f *FuncNode) Emit(indent, target string, withPrefix bool) string {
+	tmp := nestedName("tmp", target)
+
 	var b strings.Builder
 	b.WriteString(fmt.Sprintf("%s{\n", indent))
-	b.WriteString(fmt.Sprintf("%s\tvar tmp strings.Builder\n", indent))
+	b.WriteString(fmt.Sprintf("%s\tvar %s strings.Builder\n", indent, tmp))
 	for _, c := range f.Body {
-		b.WriteString(c.Emit(indent+"\t", "tmp", true))
+		b.WriteString(c.Emit(indent+"\t", tmp, true))
+	}
+	b.WriteString(fmt.Sprintf("%s\tc := strings.TrimSpace(%s.String())\n", indent, tmp))
+	if f.Name == "trim" {
+		// Trim the configured leading and trailing tokens before checking whether anything is left
+		if pattern := trimPattern(f.Prefixes, f.Suffixes); pattern != "" {
+			b.WriteString(fmt.Sprintf("%s\treTrim := regexp.MustCompile(`%s`)\n", indent, pattern))
+			b.WriteString(fmt.Sprintf("%s\tc = strings.TrimSpace(reTrim.ReplaceAllString(c, \"\"))\n", indent))
+		}
 	}
-	b.WriteString(fmt.Sprintf("%s\tc := strings.TrimSpace(tmp.String())\n", indent))
 	b.WriteString(fmt.Sprintf("%s\tif c != \"\" {\n", indent))
 	switch f.Name {
 	case "where":
@@ -86,34 +384,541 @@ func (f *FuncNode) Emit(indent, target string, withPrefix bool) string {
 		b.WriteString(fmt.Sprintf("%s\t\tc = strings.Trim(c, \", \")\n", indent))
 		b.WriteString(fmt.Sprintf("%s\t\t%s.WriteString(\" SET \")\n", indent, target))
 		b.WriteString(fmt.Sprintf("%s\t\t%s.WriteString(c)\n", indent, target))
+	case "group":
+		// Trim connectors like {{where}}, and wrap the conditions in parentheses
+		b.WriteString(fmt.Sprintf("%s\t\treTrim := regexp.MustCompile(`(?i)^\\s*(?:and|or)\\s+|\\s+(?:and|or)\\s*$`)\n", indent))
+		b.WriteString(fmt.Sprintf("%s\t\tc = reTrim.ReplaceAllString(c, \"\")\n", indent))
+		if withPrefix {
+			b.WriteString(fmt.Sprintf("%s\t\t%s.WriteString(\" (\")\n", indent, target))
+		} else {
+			b.WriteString(fmt.Sprintf("%s\t\t%s.WriteString(\"(\")\n", indent, target))
+		}
+		b.WriteString(fmt.Sprintf("%s\t\t%s.WriteString(c)\n", indent, target))
+		b.WriteString(fmt.Sprintf("%s\t\t%s.WriteString(\")\")\n", indent, target))
 	default:
-		panic(fmt.Sprintf("unsupported func %q in sql tempalte\n", f.Name))
+		// trim, the parser rejects the other blocks as unknown directives
+		if withPrefix {
+			b.WriteString(fmt.Sprintf("%s\t\t%s.WriteString(\" \")\n", indent, target))
+		}
+		b.WriteString(fmt.Sprintf("%s\t\t%s.WriteString(c)\n", indent, target))
 	}
 	b.WriteString(fmt.Sprintf("%s\t}\n", indent))
 	b.WriteString(fmt.Sprintf("%s}\n", indent))
 	return b.String()
 }
 
+// nestedName returns the name of a builder declared by a block writing into target, numbered when
+// target has the same name so the builder of a nested block doesn't shadow the builder of its parent
+func nestedName(name, target string) string {
+	suffix, ok := strings.CutPrefix(target, name)
+	if !ok {
+		return name
+	}
+	n, err := strconv.Atoi(suffix)
+	if err != nil {
+		if suffix != "" {
+			return name
+		}
+		n = 1
+	}
+	return name + strconv.Itoa(n+1)
+}
+
+// trimPattern returns the case-insensitive regexp matching one of the prefixes at the start or one of
+// the suffixes at the end of the body of a {{trim}} block, words only match whole words
+func trimPattern(prefixes, suffixes []string) string {
+	alternation := func(tokens []string, leading bool) string {
+		alts := make([]string, len(tokens))
+		for i, token := range tokens {
+			alts[i] = regexp.QuoteMeta(token)
+			switch {
+			case leading && isWordChar(token[len(token)-1]):
+				alts[i] += `\b`
+			case !leading && isWordChar(token[0]):
+				alts[i] = `\b` + alts[i]
+			}
+		}
+		return "(?:" + strings.Join(alts, "|") + ")"
+	}
+
+	var patterns []string
+	if len(prefixes) > 0 {
+		patterns = append(patterns, `^\s*`+alternation(prefixes, true))
+	}
+	if len(suffixes) > 0 {
+		patterns = append(patterns, alternation(suffixes, false)+`\s*$`)
+	}
+	if len(patterns) == 0 {
+		return ""
+	}
+	return "(?i)" + strings.Join(patterns, "|")
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// trimTokensRegexp matches the prefix="..." and suffix="..." options of {{trim}} directives
+var trimTokensRegexp = regexp.MustCompile(`^(\w+)="([^"]*)"\s*`)
+
+// parseTrim returns the {{trim}} block of the options of the directive, tokens of an option are separated by |
+func parseTrim(options string) (*FuncNode, error) {
+	fn := &FuncNode{Name: "trim"}
+	for options = strings.TrimSpace(options); options != ""; {
+		m := trimTokensRegexp.FindStringSubmatch(options)
+		if m == nil {
+			return nil, fmt.Errorf("invalid trim options: %q, expected prefix=\"...\" or suffix=\"...\"", options)
+		}
+		options = options[len(m[0]):]
+
+		var tokens []string
+		for _, token := range strings.Split(m[2], "|") {
+			if token = strings.TrimSpace(token); token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+		switch m[1] {
+		case "prefix":
+			fn.Prefixes = append(fn.Prefixes, tokens...)
+		case "suffix":
+			fn.Suffixes = append(fn.Suffixes, tokens...)
+		default:
+			return nil, fmt.Errorf("unknown trim option: %q", m[1])
+		}
+	}
+	return fn, nil
+}
+
+// defineRegexp matches the {{define "name"}} directives declaring named SQL fragments
+var defineRegexp = regexp.MustCompile(`{{\s*define\s+"([^"]+)"\s*}}`)
+
+// parseSQLFragments returns the fragments declared with {{define "name"}} ... {{end}} blocks in a doc
+// comment, keyed by name, for {{include "name"}} directives. Blocks opened in a fragment must be closed in it.
+func parseSQLFragments(doc string) (map[string]string, error) {
+	fragments := map[string]string{}
+	for rest := doc; ; {
+		loc := defineRegexp.FindStringSubmatchIndex(rest)
+		if loc == nil {
+			return fragments, nil
+		}
+		name := rest[loc[2]:loc[3]]
+		if _, ok := fragments[name]; ok {
+			return nil, fmt.Errorf("fragment %q defined more than once", name)
+		}
+		rest = rest[loc[1]:]
+
+		// find the {{end}} closing the define, skipping the ends of the blocks opened in the fragment
+		depth, offset := 1, 0
+		for depth > 0 {
+			start := strings.Index(rest[offset:], "{{")
+			if start == -1 {
+				return nil, fmt.Errorf("unclosed {{define %q}} block", name)
+			}
+			start += offset
+			end := strings.Index(rest[start:], "}}")
+			if end == -1 {
+				return nil, fmt.Errorf("missing }} in fragment %q", name)
+			}
+			switch dir := strings.TrimSpace(rest[start+2 : start+end]); {
+			case dir == "end":
+				depth--
+			case dir == "where" || dir == "set" || dir == "trim" || strings.HasPrefix(dir, "trim ") ||
+				strings.HasPrefix(dir, "for ") || strings.HasPrefix(dir, "if "):
+				depth++
+			}
+			if depth == 0 {
+				fragments[name] = strings.TrimSpace(rest[:start])
+			}
+			offset = start + end + 2
+		}
+		rest = rest[offset:]
+	}
+}
+
 // ForNode for {{for expr}}.
 type ForNode struct {
-	Expr string
-	Body []Node
+	Expr  string
+	Body  []Node
+	Sep   string          // separator written between non-empty iterations, set by {{join "sep"}}
+	Depth int             // nesting depth of the loop, suffixing the variables of its metadata
+	Vars  map[string]bool // loop metadata used by the body: index, first or last, see bindLoopVars
+}
+
+// loopVarNames are the Go variables of the $index, $first and $last metadata of {{for}} loops, suffixed
+// by the depth of nested loops, e.g. loopIndex2
+var loopVarNames = map[string]string{"index": "loopIndex", "first": "loopFirst", "last": "loopLast", "len": "loopLen"}
+
+var (
+	loopVarRegexp   = regexp.MustCompile(`^\$(\w*)`)
+	rangeExprRegexp = regexp.MustCompile(`\brange\s+(.+)$`)
+)
+
+// varName returns the Go variable of the loop metadata
+func (fn *ForNode) varName(name string) string {
+	if fn.Depth > 1 {
+		return loopVarNames[name] + strconv.Itoa(fn.Depth)
+	}
+	return loopVarNames[name]
+}
+
+// bindLoopVars rewrites the $index, $first and $last metadata of the innermost loop in the Go expression
+// into the variables of the loop, and marks them used by its body; string literals are kept as is
+func bindLoopVars(expr string, loop *ForNode) (string, error) {
+	if !strings.Contains(expr, "$") {
+		return expr, nil
+	}
+
+	var b strings.Builder
+	var last int
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; c {
+		case '"', '`', '\'':
+			for i++; i < len(expr) && expr[i] != c; i++ {
+				if expr[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case '$':
+			m := loopVarRegexp.FindStringSubmatch(expr[i:])
+			switch {
+			case m[1] == "len" || loopVarNames[m[1]] == "":
+				return "", fmt.Errorf("unknown loop variable %s, expected $index, $first or $last", m[0])
+			case loop == nil:
+				return "", fmt.Errorf("loop variable %s outside for block", m[0])
+			case m[1] == "last" && !rangeExprRegexp.MatchString(loop.Expr):
+				return "", fmt.Errorf("loop variable %s requires a range loop", m[0])
+			}
+			if loop.Vars == nil {
+				loop.Vars = map[string]bool{}
+			}
+			loop.Vars[m[1]] = true
+			b.WriteString(expr[last:i] + loop.varName(m[1]))
+			i += len(m[0]) - 1
+			last = i + 1
+		}
+	}
+	b.WriteString(expr[last:])
+	return b.String(), nil
+}
+
+// loopStart returns the declarations of the variables of the loop metadata used by the body, before the loop
+func (fn *ForNode) loopStart(indent string) string {
+	if len(fn.Vars) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	if fn.Vars["last"] {
+		b.WriteString(fmt.Sprintf("%s%s := len(%s)\n", indent, fn.varName("len"), rangeExprRegexp.FindStringSubmatch(fn.Expr)[1]))
+	}
+	b.WriteString(fmt.Sprintf("%s%s := 0\n", indent, fn.varName("index")))
+	return b.String()
+}
+
+// iterationStart returns the flags of the loop metadata used by the body, at the start of each iteration
+func (fn *ForNode) iterationStart(indent string) string {
+	var b strings.Builder
+	if fn.Vars["first"] {
+		b.WriteString(fmt.Sprintf("%s%s := %s == 0\n", indent, fn.varName("first"), fn.varName("index")))
+	}
+	if fn.Vars["last"] {
+		b.WriteString(fmt.Sprintf("%s%s := %s == %s-1\n", indent, fn.varName("last"), fn.varName("index"), fn.varName("len")))
+	}
+	return b.String()
+}
+
+// iterationEnd returns the increment of the index of the loop metadata, at the end of each iteration
+func (fn *ForNode) iterationEnd(indent string) string {
+	if len(fn.Vars) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s%s++\n", indent, fn.varName("index"))
 }
 
 func (fn *ForNode) Emit(indent, target string, withPrefix bool) string {
+	if fn.Sep != "" {
+		return fn.emitJoin(indent, target, withPrefix)
+	}
+
+	var b strings.Builder
+	loopIndent := indent
+	if len(fn.Vars) > 0 {
+		// the variables of the metadata are scoped to the loop
+		loopIndent += "\t"
+		b.WriteString(fmt.Sprintf("%s{\n", indent))
+		b.WriteString(fn.loopStart(loopIndent))
+	}
+	b.WriteString(fmt.Sprintf("%sfor %s {\n", loopIndent, fn.Expr))
+	b.WriteString(fn.iterationStart(loopIndent + "\t"))
+	for _, c := range fn.Body {
+		b.WriteString(c.Emit(loopIndent+"\t", target, withPrefix))
+	}
+	b.WriteString(fn.iterationEnd(loopIndent + "\t"))
+	b.WriteString(fmt.Sprintf("%s}\n", loopIndent))
+	if len(fn.Vars) > 0 {
+		b.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+	return b.String()
+}
+
+// emitJoin renders each iteration into its own builder and writes the separator before every
+// non-empty iteration but the first one
+func (fn *ForNode) emitJoin(indent, target string, withPrefix bool) string {
+	part := nestedName("joinPart", target)
+
 	var b strings.Builder
-	b.WriteString(fmt.Sprintf("%sfor %s {\n", indent, fn.Expr))
+	b.WriteString(fmt.Sprintf("%s{\n", indent))
+	b.WriteString(fmt.Sprintf("%s\tjoinFirst := true\n", indent))
+	b.WriteString(fn.loopStart(indent + "\t"))
+	b.WriteString(fmt.Sprintf("%s\tfor %s {\n", indent, fn.Expr))
+	b.WriteString(fn.iterationStart(indent + "\t\t"))
+	b.WriteString(fmt.Sprintf("%s\t\tvar %s strings.Builder\n", indent, part))
 	for _, c := range fn.Body {
-		b.WriteString(c.Emit(indent+"\t", target, withPrefix))
+		b.WriteString(c.Emit(indent+"\t\t", part, true))
 	}
+	b.WriteString(fmt.Sprintf("%s\t\tif c := strings.TrimSpace(%s.String()); c != \"\" {\n", indent, part))
+	if withPrefix {
+		b.WriteString(fmt.Sprintf("%s\t\t\tif joinFirst {\n", indent))
+		b.WriteString(fmt.Sprintf("%s\t\t\t\t%s.WriteString(\" \")\n", indent, target))
+		b.WriteString(fmt.Sprintf("%s\t\t\t} else {\n", indent))
+	} else {
+		b.WriteString(fmt.Sprintf("%s\t\t\tif !joinFirst {\n", indent))
+	}
+	b.WriteString(fmt.Sprintf("%s\t\t\t\t%s.WriteString(%q)\n", indent, target, fn.Sep))
+	b.WriteString(fmt.Sprintf("%s\t\t\t}\n", indent))
+	b.WriteString(fmt.Sprintf("%s\t\t\tjoinFirst = false\n", indent))
+	b.WriteString(fmt.Sprintf("%s\t\t\t%s.WriteString(c)\n", indent, target))
+	b.WriteString(fmt.Sprintf("%s\t\t}\n", indent))
+	b.WriteString(fn.iterationEnd(indent + "\t\t"))
+	b.WriteString(fmt.Sprintf("%s\t}\n", indent))
+	b.WriteString(fmt.Sprintf("%s}\n", indent))
+	return b.String()
+}
+
+// LimitNode for {{limit @size offset @offset}} / {{offset @offset}}, rendered per dialect by field.Paginate.
+type LimitNode struct {
+	Limit  string // Go expression of the limit, "" without limit
+	Offset string // Go expression of the offset, "" without offset
+}
+
+var (
+	limitRegexp  = regexp.MustCompile(`^limit\s+@?([A-Za-z_][\w.]*)(?:\s+offset\s+@?([A-Za-z_][\w.]*))?$`)
+	offsetRegexp = regexp.MustCompile(`^offset\s+@?([A-Za-z_][\w.]*)$`)
+)
+
+func (l *LimitNode) Emit(indent, target string, withPrefix bool) string {
+	limit, offset := "-1", "0"
+	if l.Limit != "" {
+		limit = fmt.Sprintf("int(%s)", l.Limit)
+	}
+	if l.Offset != "" {
+		offset = fmt.Sprintf("int(%s)", l.Offset)
+	}
+
+	placeholder := "?"
+	if withPrefix {
+		placeholder = " ?"
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s%s.WriteString(%q)\n", indent, target, placeholder))
+	b.WriteString(fmt.Sprintf("%sparams = append(params, field.Paginate(%s, %s))\n", indent, limit, offset))
+	return b.String()
+}
+
+// OrderByNode for {{orderBy @column allow="name,age" dir=@direction}}, the column and direction are
+// validated against the allowed columns at runtime by field.OrderBy.
+type OrderByNode struct {
+	Column    string   // Go expression of the column
+	Direction string   // Go expression of the direction, "" for ascending
+	Allowed   []string // columns allowed to sort by
+}
+
+var (
+	orderByRegexp       = regexp.MustCompile(`^orderBy\s+@?([A-Za-z_][\w.]*)\s*(.*)$`)
+	orderByOptionRegexp = regexp.MustCompile(`^(\w+)=(?:"([^"]*)"|@?([A-Za-z_][\w.]*))\s*`)
+	orderByColumnRegexp = regexp.MustCompile(`^[A-Za-z_][\w]*(?:\.[A-Za-z_][\w]*)?$`)
+)
+
+// parseOrderBy returns the {{orderBy}} node of the directive
+func parseOrderBy(dir string) (*OrderByNode, error) {
+	m := orderByRegexp.FindStringSubmatch(dir)
+	if m == nil {
+		return nil, errors.New(`invalid orderBy, expected {{orderBy @column allow="name,age" dir=@direction}}`)
+	}
+
+	n := &OrderByNode{Column: m[1]}
+	for options := m[2]; options != ""; {
+		om := orderByOptionRegexp.FindStringSubmatch(options)
+		if om == nil {
+			return nil, fmt.Errorf("invalid orderBy options: %q", options)
+		}
+		options = options[len(om[0]):]
+
+		switch om[1] {
+		case "allow":
+			for _, column := range strings.Split(om[2], ",") {
+				if column = strings.TrimSpace(column); !orderByColumnRegexp.MatchString(column) {
+					return nil, fmt.Errorf("invalid orderBy allowed column: %q", column)
+				}
+				n.Allowed = append(n.Allowed, column)
+			}
+		case "dir":
+			if n.Direction = om[3]; n.Direction == "" {
+				return nil, errors.New("invalid orderBy dir, expected a parameter like dir=@direction")
+			}
+		default:
+			return nil, fmt.Errorf("unknown orderBy option: %q", om[1])
+		}
+	}
+	if len(n.Allowed) == 0 {
+		return nil, errors.New(`orderBy requires the allowed columns, e.g. allow="name,age"`)
+	}
+	return n, nil
+}
+
+func (o *OrderByNode) Emit(indent, target string, withPrefix bool) string {
+	direction := `""`
+	if o.Direction != "" {
+		direction = fmt.Sprintf("string(%s)", o.Direction)
+	}
+	args := []string{fmt.Sprintf("string(%s)", o.Column), direction}
+	for _, column := range o.Allowed {
+		args = append(args, strconv.Quote(column))
+	}
+
+	placeholder := "?"
+	if withPrefix {
+		placeholder = " ?"
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s%s.WriteString(%q)\n", indent, target, placeholder))
+	b.WriteString(fmt.Sprintf("%sparams = append(params, field.OrderBy(%s))\n", indent, strings.Join(args, ", ")))
+	return b.String()
+}
+
+// ValuesNode for {{values @users (Name, Age) batch=100}}, writing a (?,?) tuple of the fields of each
+// element of the slice, e.g. the VALUES list of a bulk INSERT; with a batch size the method executes its
+// statement once per chunk of the slice, see Method.batchMethodBody
+type ValuesNode struct {
+	Slice  string   // Go expression of the slice
+	Fields []string // Go expressions of the tuple values relative to an element, the element itself when empty
+	Batch  int      // maximum number of tuples per statement, 0 to write them all
+}
+
+var (
+	valuesRegexp      = regexp.MustCompile(`^values\s+@?([A-Za-z_][\w.]*)\s*(?:\(([^)]*)\))?\s*(?:batch=(\w+))?$`)
+	valuesFieldRegexp = regexp.MustCompile(`^[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)*$`)
+)
+
+// parseValues returns the {{values}} node of the directive
+func parseValues(dir string) (*ValuesNode, error) {
+	m := valuesRegexp.FindStringSubmatch(dir)
+	if m == nil {
+		return nil, errors.New("invalid values, expected {{values @slice (Field1, Field2)}} or {{values @slice (Field1, Field2) batch=100}}")
+	}
+
+	n := &ValuesNode{Slice: m[1]}
+	if strings.Contains(dir, "(") {
+		for _, f := range strings.Split(m[2], ",") {
+			if f = strings.TrimSpace(f); !valuesFieldRegexp.MatchString(f) {
+				return nil, fmt.Errorf("invalid values field: %q", f)
+			}
+			n.Fields = append(n.Fields, f)
+		}
+	}
+	if m[3] != "" {
+		batch, err := strconv.Atoi(m[3])
+		if err != nil || batch <= 0 {
+			return nil, fmt.Errorf("invalid values batch: %q, expected a positive number", m[3])
+		}
+		n.Batch = batch
+	}
+	return n, nil
+}
+
+func (v *ValuesNode) Emit(indent, target string, withPrefix bool) string {
+	placeholders, params := []string{"?"}, []string{"value"}
+	if len(v.Fields) > 0 {
+		placeholders, params = make([]string, len(v.Fields)), make([]string, len(v.Fields))
+		for i, f := range v.Fields {
+			placeholders[i], params[i] = "?", "value."+f
+		}
+	}
+
+	var b strings.Builder
+	if withPrefix {
+		b.WriteString(fmt.Sprintf("%s%s.WriteString(\" \")\n", indent, target))
+	}
+	b.WriteString(fmt.Sprintf("%sfor i, value := range %s {\n", indent, v.Slice))
+	b.WriteString(fmt.Sprintf("%s\tif i > 0 {\n", indent))
+	b.WriteString(fmt.Sprintf("%s\t\t%s.WriteString(\",\")\n", indent, target))
+	b.WriteString(fmt.Sprintf("%s\t}\n", indent))
+	b.WriteString(fmt.Sprintf("%s\t%s.WriteString(%q)\n", indent, target, "("+strings.Join(placeholders, ",")+")"))
+	b.WriteString(fmt.Sprintf("%s\tparams = append(params, %s)\n", indent, strings.Join(params, ", ")))
+	b.WriteString(fmt.Sprintf("%s}\n", indent))
+	return b.String()
+}
+
+// InNode for {{in "status" statuses}}, writing the status IN (?,?) condition of the elements of the slice,
+// expanded by gorm, or a false predicate when the slice is empty
+type InNode struct {
+	Column string // column compared with the elements of the slice
+	Slice  string // Go expression of the slice
+}
+
+var inRegexp = regexp.MustCompile(`^in\s+"([^"]*)"\s+@?([A-Za-z_][\w.]*)$`)
+
+// parseIn returns the {{in}} node of the directive
+func parseIn(dir string) (*InNode, error) {
+	m := inRegexp.FindStringSubmatch(dir)
+	if m == nil {
+		return nil, errors.New(`invalid in, expected {{in "column" slice}}`)
+	}
+	if !orderByColumnRegexp.MatchString(m[1]) {
+		return nil, fmt.Errorf("invalid in column: %q", m[1])
+	}
+	return &InNode{Column: m[1], Slice: m[2]}, nil
+}
+
+func (in *InNode) Emit(indent, target string, withPrefix bool) string {
+	space := ""
+	if withPrefix {
+		space = " "
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%sif len(%s) > 0 {\n", indent, in.Slice))
+	b.WriteString(fmt.Sprintf("%s\t%s.WriteString(%q)\n", indent, target, space+in.Column+" IN ?"))
+	b.WriteString(fmt.Sprintf("%s\tparams = append(params, %s)\n", indent, in.Slice))
+	b.WriteString(fmt.Sprintf("%s} else {\n", indent))
+	b.WriteString(fmt.Sprintf("%s\t%s.WriteString(%q)\n", indent, target, space+"1 = 0"))
 	b.WriteString(fmt.Sprintf("%s}\n", indent))
 	return b.String()
 }
 
 // IfBranch holds one condition + body.
 type IfBranch struct {
-	Cond string
-	Body []Node
+	Cond     string
+	Dialects []string // names of the dialects of a {{if dialect "mysql"}} condition
+	Body     []Node
+}
+
+// dialectCondRegexp matches the conditions of {{if dialect "mysql" "sqlite"}} blocks
+var dialectCondRegexp = regexp.MustCompile(`^dialect((?:\s+"[A-Za-z0-9_]+")+)$`)
+
+// parseDialects returns the dialects of a {{if dialect "name"}} condition, or nil for Go conditions
+func parseDialects(cond string) ([]string, error) {
+	if cond != "dialect" && !strings.HasPrefix(cond, "dialect ") {
+		return nil, nil
+	}
+	m := dialectCondRegexp.FindStringSubmatch(cond)
+	if m == nil {
+		return nil, errors.New(`invalid dialect condition, expected {{if dialect "mysql"}}`)
+	}
+	var dialects []string
+	for _, name := range strings.Fields(m[1]) {
+		dialects = append(dialects, strings.Trim(name, `"`))
+	}
+	return dialects, nil
 }
 
 // IfNode can have multiple branches (if, else if, else if, ...), plus an optional else.
@@ -123,6 +928,10 @@ type IfNode struct {
 }
 
 func (in *IfNode) Emit(indent, target string, withPrefix bool) string {
+	if len(in.Branches[0].Dialects) > 0 {
+		return in.emitDialects(indent, target, withPrefix)
+	}
+
 	var b strings.Builder
 	// if branches[0].Cond { ... } else if branches[1].Cond { ... } else ...
 	for i, br := range in.Branches {
@@ -145,18 +954,180 @@ func (in *IfNode) Emit(indent, target string, withPrefix bool) string {
 	return b.String()
 }
 
+// emitDialects renders each branch of a {{if dialect "name"}} block with its own params into a
+// field.DialectSQL, the branch of the statement's dialect is chosen at runtime by field.Dialect
+func (in *IfNode) emitDialects(indent, target string, withPrefix bool) string {
+	tmp := nestedName("tmp", target)
+	emitBranch := func(b *strings.Builder, dialects []string, body []Node) {
+		quoted := make([]string, len(dialects))
+		for i, dialect := range dialects {
+			quoted[i] = strconv.Quote(dialect)
+		}
+
+		b.WriteString(fmt.Sprintf("%s\t{\n", indent))
+		b.WriteString(fmt.Sprintf("%s\t\tvar %s strings.Builder\n", indent, tmp))
+		b.WriteString(fmt.Sprintf("%s\t\tparams := make([]any, 0)\n", indent))
+		for _, c := range body {
+			b.WriteString(c.Emit(indent+"\t\t", tmp, true))
+		}
+		if len(dialects) > 0 {
+			b.WriteString(fmt.Sprintf("%s\t\tdialects = append(dialects, field.DialectSQL{Dialects: []string{%s}, SQL: strings.TrimSpace(%s.String()), Vars: params})\n", indent, strings.Join(quoted, ", "), tmp))
+		} else {
+			b.WriteString(fmt.Sprintf("%s\t\tdialects = append(dialects, field.DialectSQL{SQL: strings.TrimSpace(%s.String()), Vars: params})\n", indent, tmp))
+		}
+		b.WriteString(fmt.Sprintf("%s\t}\n", indent))
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s{\n", indent))
+	count := len(in.Branches)
+	if len(in.ElseBody) > 0 {
+		count++
+	}
+	b.WriteString(fmt.Sprintf("%s\tdialects := make([]field.DialectSQL, 0, %d)\n", indent, count))
+	for _, br := range in.Branches {
+		emitBranch(&b, br.Dialects, br.Body)
+	}
+	if len(in.ElseBody) > 0 {
+		emitBranch(&b, nil, in.ElseBody)
+	}
+	placeholder := "?"
+	if withPrefix {
+		placeholder = " ?"
+	}
+	b.WriteString(fmt.Sprintf("%s\t%s.WriteString(%q)\n", indent, target, placeholder))
+	b.WriteString(fmt.Sprintf("%s\tparams = append(params, field.Dialect(dialects...))\n", indent))
+	b.WriteString(fmt.Sprintf("%s}\n", indent))
+	return b.String()
+}
+
+// qualifyFuncs replaces the calls of the functions declared in genconfig.Config.SQLFuncs in the Go
+// expression of a condition, e.g. notEmpty(name) to helpers.NotEmpty(name), keeping its formatting
+func qualifyFuncs(expr string, funcs map[string]string) string {
+	if len(funcs) == 0 {
+		return expr
+	}
+
+	var (
+		s       scanner.Scanner
+		fset    = token.NewFileSet()
+		b       strings.Builder
+		last    int
+		prevTok token.Token
+		ident   string
+		identAt = -1
+	)
+	s.Init(fset.AddFile("", -1, len(expr)), []byte(expr), nil, 0)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		offset := fset.Position(pos).Offset
+		if tok == token.LPAREN && identAt >= 0 {
+			if qualified, ok := funcs[ident]; ok {
+				b.WriteString(expr[last:identAt])
+				b.WriteString(qualified)
+				last = identAt + len(ident)
+			}
+		}
+
+		identAt = -1
+		if tok == token.IDENT && prevTok != token.PERIOD {
+			ident, identAt = lit, offset
+		}
+		prevTok = tok
+	}
+	b.WriteString(expr[last:])
+	return b.String()
+}
+
 // stackItem holds a node or ifNode under construction.
 type stackItem struct {
 	node      Node
 	ifNode    *IfNode // non-nil if it's an if
 	branchIdx int     // which branch index are we currently filling?
 	elsePart  bool
+	directive string // name of the directive opening the block, e.g. if
+	line, col int    // position of the directive opening the block
+}
+
+// SQLTemplateError is an error of a SQL template at a position of the template, lines and columns start at 1
+type SQLTemplateError struct {
+	Line, Column int
+	Msg          string
+}
+
+func (e *SQLTemplateError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
 }
 
 // RenderSQLTemplate parses the template string and returns Go code or an error.
 func RenderSQLTemplate(tmpl string) (string, error) {
+	return renderSQLTemplate(tmpl, sqlTemplateOptions{})
+}
+
+// sqlTemplateOptions are the declarations SQL templates of query methods can use
+type sqlTemplateOptions struct {
+	fragments map[string]string // fragments of {{include "name"}} directives, by name
+	funcs     map[string]string // qualified Go functions callable in conditions, by name
+	prefix    string            // prefix of placeholders, @ when empty
+	strict    bool              // reject ambiguous placeholders instead of keeping them as is
+	// stripComments drops the comments of the template, but optimizer hints like /*+ ... */
+	stripComments bool
+}
+
+// renderSQLTemplate parses the template string with the fragments and functions of opts, and returns
+// Go code or an error.
+func renderSQLTemplate(tmpl string, opts sqlTemplateOptions) (string, error) {
+	root, err := parseSQLTemplate(tmpl, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		sb          strings.Builder
+		codes       []string
+		paramsCount int
+	)
+
+	for idx, n := range root {
+		code := n.Emit("", "sb", idx != 0)
+		count, baseCount := 0, 1
+
+		for _, line := range strings.Split(code, "\n") {
+			if strings.Index(code, "\tfor ") > 0 {
+				baseCount = 4
+			}
+			if strings.Contains(line, "params = append(params") {
+				count += (strings.Count(line, ",") - strings.Count(line, ", Alias: ")) * baseCount
+			}
+		}
+
+		paramsCount += count
+		codes = append(codes, code)
+	}
+
+	sb.WriteString("var sb strings.Builder\n")
+	sb.WriteString(fmt.Sprintf("params := make([]any, 0, %d)\n\n", paramsCount))
+
+	for _, code := range codes {
+		sb.WriteString(code)
+	}
+	return sb.String(), nil
+}
+
+// parseSQLTemplate parses the template string with the fragments and functions of opts into its nodes
+func parseSQLTemplate(tmpl string, opts sqlTemplateOptions) ([]Node, error) {
+	if opts.prefix == "" {
+		opts.prefix = defaultPlaceholderPrefix
+	} else if err := validatePlaceholderPrefix(opts.prefix); err != nil {
+		return nil, err
+	}
+
 	var root []Node
 	var stack []stackItem
+	var including []string // names of the fragments being included, to detect cycles
 
 	// getBody returns the Node slice we should append text/child-block to,
 	// depending on if we're in an if branch or else part, or a for/func block
@@ -179,47 +1150,91 @@ func RenderSQLTemplate(tmpl string) (string, error) {
 		return &si.ifNode.Branches[si.branchIdx].Body
 	}
 
-	appendText := func(txt string) {
-		str := strings.TrimSpace(txt)
-		if str == "" {
-			return
-		}
-		t := &TextNode{Text: txt}
+	appendNode := func(n Node) {
 		if len(stack) == 0 {
-			root = append(root, t)
+			root = append(root, n)
 			return
 		}
 		top := &stack[len(stack)-1]
 		b := getBody(top)
-		*b = append(*b, t)
+		*b = append(*b, n)
+	}
+
+	// innerLoop returns the innermost {{for}} block being parsed, nil outside loops
+	innerLoop := func() *ForNode {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if fn, ok := stack[i].node.(*ForNode); ok {
+				return fn
+			}
+		}
+		return nil
+	}
+
+	// appendText appends the text found at the line and column, in strict mode it rejects the prefixes
+	// not followed by a name and the placeholders inside string literals
+	appendText := func(txt string, line, col int) error {
+		str := strings.TrimSpace(txt)
+		if str == "" {
+			return nil
+		}
+		if opts.strict {
+			_, placeholders, bare := scanPlaceholders(txt, opts.prefix)
+			if len(bare) > 0 {
+				return &SQLTemplateError{Line: line, Column: col + bare[0], Msg: fmt.Sprintf("ambiguous placeholder %q without a name, escape it as %q", opts.prefix, `\`+opts.prefix)}
+			}
+			for _, ph := range placeholders {
+				if ph.Quoted {
+					return &SQLTemplateError{Line: line, Column: col + ph.Offset, Msg: fmt.Sprintf("ambiguous placeholder %q inside a string literal, escape it or move it out of the quotes", ph.text(opts.prefix))}
+				}
+			}
+		}
+		loop := innerLoop()
+		_, placeholders, _ := scanPlaceholders(txt, opts.prefix)
+		for _, ph := range placeholders {
+			if !ph.Expr {
+				continue
+			}
+			if _, err := bindLoopVars(ph.Name, loop); err != nil {
+				return &SQLTemplateError{Line: line, Column: col + ph.Offset, Msg: err.Error()}
+			}
+		}
+		appendNode(&TextNode{Text: txt, Prefix: opts.prefix, Funcs: opts.funcs, Loop: loop})
+		return nil
 	}
 
-	pushBlock := func(n Node) {
+	pushBlock := func(n Node, item stackItem) {
 		// push a non-if block (for, func)
+		item.node = n
 		if len(stack) == 0 {
-			stack = append(stack, stackItem{node: n})
+			stack = append(stack, item)
 		} else {
 			top := &stack[len(stack)-1]
 			b := getBody(top)
 			*b = append(*b, n)
-			stack = append(stack, stackItem{node: n})
+			stack = append(stack, item)
 		}
 	}
 
-	handleIfStart := func(cond string) {
+	handleIfStart := func(cond string, item stackItem) error {
+		dialects, err := parseDialects(cond)
+		if err != nil {
+			return err
+		}
 		in := &IfNode{
 			Branches: []IfBranch{
-				{Cond: cond},
+				{Cond: cond, Dialects: dialects},
 			},
 		}
+		item.node, item.ifNode, item.branchIdx = in, in, 0
 		if len(stack) == 0 {
-			stack = append(stack, stackItem{node: in, ifNode: in, branchIdx: 0})
+			stack = append(stack, item)
 		} else {
 			top := &stack[len(stack)-1]
 			b := getBody(top)
 			*b = append(*b, in)
-			stack = append(stack, stackItem{node: in, ifNode: in, branchIdx: 0})
+			stack = append(stack, item)
 		}
+		return nil
 	}
 
 	handleElseIf := func(cond string) error {
@@ -235,7 +1250,13 @@ func RenderSQLTemplate(tmpl string) (string, error) {
 		}
 		// add a new branch
 		in := top.ifNode
-		in.Branches = append(in.Branches, IfBranch{Cond: cond})
+		dialects, err := parseDialects(cond)
+		if err != nil {
+			return err
+		} else if (len(dialects) > 0) != (len(in.Branches[0].Dialects) > 0) {
+			return errors.New("cannot mix dialect and Go conditions in the same if block")
+		}
+		in.Branches = append(in.Branches, IfBranch{Cond: cond, Dialects: dialects})
 		top.branchIdx = len(in.Branches) - 1
 		return nil
 	}
@@ -255,6 +1276,54 @@ func RenderSQLTemplate(tmpl string) (string, error) {
 		return nil
 	}
 
+	handleJoin := func(sep string) error {
+		if len(stack) == 0 || stack[len(stack)-1].ifNode != nil {
+			return errors.New("join outside for block")
+		}
+		fn, ok := stack[len(stack)-1].node.(*ForNode)
+		if !ok {
+			return errors.New("join outside for block")
+		} else if fn.Sep != "" {
+			return errors.New("multiple join in same for block")
+		}
+
+		sep, err := strconv.Unquote(strings.TrimSpace(sep))
+		if err != nil || sep == "" {
+			return errors.New(`invalid join separator, expected a quoted string like {{join ", "}}`)
+		}
+		fn.Sep = sep
+		return nil
+	}
+
+	var parse func(tmpl string) error
+	handleInclude := func(name string) error {
+		name, err := strconv.Unquote(strings.TrimSpace(name))
+		if err != nil {
+			return errors.New(`invalid include, expected a quoted fragment name like {{include "name"}}`)
+		}
+		fragment, ok := opts.fragments[name]
+		if !ok {
+			return fmt.Errorf("unknown fragment: %q", name)
+		} else if slices.Contains(including, name) {
+			return fmt.Errorf("fragment %q includes itself", name)
+		}
+
+		including = append(including, name)
+		depth := len(stack)
+		if err := parse(fragment); err != nil {
+			var tmplErr *SQLTemplateError
+			if errors.As(err, &tmplErr) {
+				return fmt.Errorf("in fragment %q at %v", name, tmplErr)
+			}
+			return err
+		}
+		if len(stack) != depth {
+			return fmt.Errorf("unbalanced blocks in fragment %q", name)
+		}
+		including = including[:len(including)-1]
+		return nil
+	}
+
 	handleEnd := func() error {
 		if len(stack) == 0 {
 			return errors.New("unmatched end")
@@ -267,87 +1336,162 @@ func RenderSQLTemplate(tmpl string) (string, error) {
 		return nil
 	}
 
-	handleDirective := func(dir string, lineNo int) error {
+	handleDirective := func(dir string, lineNo, col int) error {
+		block := stackItem{line: lineNo, col: col}
 		switch {
-		case dir == "where" || dir == "set":
+		case dir == "where" || dir == "set" || dir == "group":
 			fn := &FuncNode{Name: dir}
-			pushBlock(fn)
+			block.directive = dir
+			pushBlock(fn, block)
+		case dir == "trim" || strings.HasPrefix(dir, "trim "):
+			fn, err := parseTrim(strings.TrimPrefix(dir, "trim"))
+			if err != nil {
+				return err
+			}
+			block.directive = "trim"
+			pushBlock(fn, block)
 		case strings.HasPrefix(dir, "for "):
-			ex := strings.TrimSpace(dir[3:])
-			f := &ForNode{Expr: ex}
-			pushBlock(f)
+			outer := innerLoop()
+			ex, err := bindLoopVars(strings.TrimSpace(dir[3:]), outer)
+			if err != nil {
+				return err
+			}
+			f := &ForNode{Expr: qualifyFuncs(ex, opts.funcs), Depth: 1}
+			if outer != nil {
+				f.Depth = outer.Depth + 1
+			}
+			block.directive = "for"
+			pushBlock(f, block)
 		case strings.HasPrefix(dir, "if "):
-			c := strings.TrimSpace(dir[2:])
-			handleIfStart(c)
+			c, err := bindLoopVars(strings.TrimSpace(dir[2:]), innerLoop())
+			if err != nil {
+				return err
+			}
+			block.directive = "if"
+			return handleIfStart(qualifyFuncs(c, opts.funcs), block)
 		case strings.HasPrefix(dir, "else if "):
-			c := strings.TrimSpace(dir[len("else if "):])
-			return handleElseIf(c)
+			c, err := bindLoopVars(strings.TrimSpace(dir[len("else if "):]), innerLoop())
+			if err != nil {
+				return err
+			}
+			return handleElseIf(qualifyFuncs(c, opts.funcs))
+		case dir == "limit" || strings.HasPrefix(dir, "limit "):
+			m := limitRegexp.FindStringSubmatch(dir)
+			if m == nil {
+				return errors.New("invalid limit, expected {{limit @size}} or {{limit @size offset @offset}}")
+			}
+			appendNode(&LimitNode{Limit: m[1], Offset: m[2]})
+		case dir == "offset" || strings.HasPrefix(dir, "offset "):
+			m := offsetRegexp.FindStringSubmatch(dir)
+			if m == nil {
+				return errors.New("invalid offset, expected {{offset @offset}}")
+			}
+			appendNode(&LimitNode{Offset: m[1]})
+		case dir == "orderBy" || strings.HasPrefix(dir, "orderBy "):
+			n, err := parseOrderBy(dir)
+			if err != nil {
+				return err
+			}
+			appendNode(n)
+		case dir == "in" || strings.HasPrefix(dir, "in "):
+			n, err := parseIn(dir)
+			if err != nil {
+				return err
+			}
+			appendNode(n)
+		case dir == "values" || strings.HasPrefix(dir, "values "):
+			n, err := parseValues(dir)
+			if err != nil {
+				return err
+			}
+			appendNode(n)
+		case strings.HasPrefix(dir, "include "):
+			return handleInclude(dir[len("include "):])
+		case strings.HasPrefix(dir, "join "):
+			return handleJoin(dir[len("join "):])
 		case dir == "else":
 			return handleElse()
 		case dir == "end":
 			return handleEnd()
 		default:
-			return fmt.Errorf("unknown directive: %q (line %d)", dir, lineNo)
+			return fmt.Errorf("unknown directive: %q", dir)
 		}
 		return nil
 	}
 
-	lines := strings.Split(tmpl, "\n")
-	for i, line := range lines {
-		rest := line
-		for {
-			start := strings.Index(rest, "{{")
-			if start == -1 {
-				appendText(rest)
-				break
-			}
-			if start > 0 {
-				appendText(rest[:start])
-			}
-			rest = rest[start+2:]
-			end := strings.Index(rest, "}}")
-			if end == -1 {
-				return "", fmt.Errorf("line %d: missing }}", i+1)
-			}
-			dir := strings.TrimSpace(rest[:end])
-			rest = rest[end+2:]
-			if err := handleDirective(dir, i+1); err != nil {
-				return "", fmt.Errorf("line %d: %w", i+1, err)
-			}
+	// appendComment appends the comment, unless comments are stripped or it's inside a {{where}}, {{set}},
+	// {{trim}} or {{group}} block, where it would be taken for the content of the block
+	appendComment := func(comment string) {
+		if opts.stripComments && !sqlHintRegexp.MatchString(comment) {
+			return
 		}
-	}
-	if len(stack) > 0 {
-		return "", errors.New("unclosed block(s) at EOF")
+		if slices.ContainsFunc(stack, func(item stackItem) bool { _, ok := item.node.(*FuncNode); return ok }) {
+			return
+		}
+		appendNode(&CommentNode{Text: comment})
 	}
 
-	var (
-		sb          strings.Builder
-		codes       []string
-		paramsCount int
-	)
+	parse = func(tmpl string) error {
+		position := func(offset int) (line, col int) {
+			return strings.Count(tmpl[:offset], "\n") + 1, offset - strings.LastIndex(tmpl[:offset], "\n")
+		}
+		// appendLines appends the text between the offsets line by line
+		appendLines := func(start, end int) error {
+			for _, txt := range strings.SplitAfter(tmpl[start:end], "\n") {
+				line, col := position(start)
+				if err := appendText(strings.TrimSuffix(txt, "\n"), line, col); err != nil {
+					return err
+				}
+				start += len(txt)
+			}
+			return nil
+		}
 
-	for idx, n := range root {
-		code := n.Emit("", "sb", idx != 0)
-		count, baseCount := 0, 1
+		var comments [][2]int
+		scanSQLComments(tmpl, func(start, end int) { comments = append(comments, [2]int{start, end}) })
 
-		for _, line := range strings.Split(code, "\n") {
-			if strings.Index(code, "\tfor ") > 0 {
-				baseCount = 4
+		var last int
+		for i := 0; i < len(tmpl); {
+			if len(comments) > 0 && comments[0][0] == i {
+				if err := appendLines(last, i); err != nil {
+					return err
+				}
+				end := comments[0][1]
+				if tmpl[i] == '/' && (end-i < 4 || !strings.HasSuffix(tmpl[i:end], "*/")) {
+					line, col := position(i)
+					return &SQLTemplateError{Line: line, Column: col, Msg: "unclosed /* comment"}
+				}
+				appendComment(tmpl[i:end])
+				comments, last, i = comments[1:], end, end
+				continue
 			}
-			if strings.Contains(line, "params = append(params") {
-				count += strings.Count(line, ",") * baseCount
+			if !strings.HasPrefix(tmpl[i:], "{{") {
+				i++
+				continue
 			}
-		}
 
-		paramsCount += count
-		codes = append(codes, code)
+			if err := appendLines(last, i); err != nil {
+				return err
+			}
+			line, col := position(i)
+			end := strings.Index(tmpl[i+2:], "}}")
+			if end == -1 || strings.Contains(tmpl[i+2:i+2+end], "\n") {
+				return &SQLTemplateError{Line: line, Column: col, Msg: "missing }}"}
+			}
+			dir := strings.TrimSpace(tmpl[i+2 : i+2+end])
+			if err := handleDirective(dir, line, col); err != nil {
+				return &SQLTemplateError{Line: line, Column: col, Msg: err.Error()}
+			}
+			last, i = i+2+end+2, i+2+end+2
+		}
+		return appendLines(last, len(tmpl))
 	}
-
-	sb.WriteString("var sb strings.Builder\n")
-	sb.WriteString(fmt.Sprintf("params := make([]any, 0, %d)\n\n", paramsCount))
-
-	for _, code := range codes {
-		sb.WriteString(code)
+	if err := parse(tmpl); err != nil {
+		return nil, err
 	}
-	return sb.String(), nil
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		return nil, &SQLTemplateError{Line: top.line, Column: top.col, Msg: fmt.Sprintf("unclosed {{%s}} block", top.directive)}
+	}
+	return root, nil
 }
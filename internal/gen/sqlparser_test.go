@@ -1,9 +1,13 @@
 package gen
 
 import (
+	"errors"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -21,6 +25,18 @@ var methodExpectedLines = map[string][]string{
 		`sb.WriteString("SELECT * FROM ? WHERE ?=?")`,
 		`params = append(params, clause.Table{Name: clause.CurrentTable}, clause.Column{Name: column}, value)`,
 	},
+	"FilterByAllowedColumn": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 5)",
+		`sb.WriteString("SELECT * FROM ? WHERE ? = ? ORDER BY id")`,
+		`params = append(params, clause.Table{Name: clause.CurrentTable}, field.AllowedColumn(column, "name", "role"), value)`,
+	},
+	"FilterByModelColumn": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 3)",
+		`sb.WriteString("SELECT * FROM ? WHERE ? = ? ORDER BY id")`,
+		`params = append(params, clause.Table{Name: clause.CurrentTable}, field.AllowedColumn(column), value)`,
+	},
 	"QueryWith": {
 		"var sb strings.Builder",
 		"params := make([]any, 0, 2)",
@@ -116,6 +132,261 @@ var methodExpectedLines = map[string][]string{
 		"}",
 		"}",
 	},
+	"FindCreatedWithin": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 2)",
+		`sb.WriteString("SELECT * FROM ? WHERE created_at > ? ORDER BY id")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, time.Now().Add(-period))",
+	},
+	"FindAdultsByNamePrefix": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 5)",
+		`sb.WriteString("SELECT * FROM ? WHERE is_adult = true")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable})",
+		"{",
+		"loopLen := len(prefixes)",
+		"loopIndex := 0",
+		"for _, prefix := range prefixes {",
+		"loopFirst := loopIndex == 0",
+		"loopLast := loopIndex == loopLen-1",
+		"if loopFirst {",
+		`sb.WriteString(" AND (")`,
+		"}",
+		`sb.WriteString(" name LIKE ?")`,
+		`params = append(params, prefix + "%")`,
+		"if loopLast {",
+		`sb.WriteString(" )")`,
+		"} else {",
+		`sb.WriteString(" OR")`,
+		"}",
+		"loopIndex++",
+		"}",
+		"}",
+		`sb.WriteString(" ORDER BY id")`,
+	},
+	"Page": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 3)",
+		`sb.WriteString("SELECT * FROM ? ORDER BY id")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable})",
+		`sb.WriteString(" ?")`,
+		"params = append(params, field.Paginate(int(size), int(offset)))",
+	},
+	"FindByNameInsensitive": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 5)",
+		`sb.WriteString("SELECT * FROM ? WHERE")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable})",
+		"{",
+		"dialects := make([]field.DialectSQL, 0, 3)",
+		"{",
+		"var tmp strings.Builder",
+		"params := make([]any, 0)",
+		`tmp.WriteString(" name ILIKE ?")`,
+		"params = append(params, name)",
+		`dialects = append(dialects, field.DialectSQL{Dialects: []string{"postgres"}, SQL: strings.TrimSpace(tmp.String()), Vars: params})`,
+		"}",
+		"{",
+		"var tmp strings.Builder",
+		"params := make([]any, 0)",
+		`tmp.WriteString(" name = ? COLLATE utf8mb4_general_ci")`,
+		"params = append(params, name)",
+		`dialects = append(dialects, field.DialectSQL{Dialects: []string{"mysql"}, SQL: strings.TrimSpace(tmp.String()), Vars: params})`,
+		"}",
+		"{",
+		"var tmp strings.Builder",
+		"params := make([]any, 0)",
+		`tmp.WriteString(" LOWER(name) = LOWER(?)")`,
+		"params = append(params, name)",
+		"dialects = append(dialects, field.DialectSQL{SQL: strings.TrimSpace(tmp.String()), Vars: params})",
+		"}",
+		`sb.WriteString(" ?")`,
+		"params = append(params, field.Dialect(dialects...))",
+		"}",
+	},
+	"OldestOfRole": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 7)",
+		"{",
+		"dialects := make([]field.DialectSQL, 0, 3)",
+		"{",
+		"var tmp strings.Builder",
+		"params := make([]any, 0)",
+		`tmp.WriteString(" SELECT * FROM ? WHERE role = ? ORDER BY age DESC NULLS LAST, id LIMIT 1")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, role)",
+		`dialects = append(dialects, field.DialectSQL{Dialects: []string{"postgres"}, SQL: strings.TrimSpace(tmp.String()), Vars: params})`,
+		"}",
+		"{",
+		"var tmp strings.Builder",
+		"params := make([]any, 0)",
+		`tmp.WriteString(" SELECT * FROM ? WHERE role = ?")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, role)",
+		`tmp.WriteString(" ORDER BY age IS NULL, age DESC, id LIMIT 1")`,
+		`dialects = append(dialects, field.DialectSQL{Dialects: []string{"mysql", "sqlite"}, SQL: strings.TrimSpace(tmp.String()), Vars: params})`,
+		"}",
+		"{",
+		"var tmp strings.Builder",
+		"params := make([]any, 0)",
+		`tmp.WriteString(" SELECT * FROM ? WHERE role = ? ORDER BY age DESC, id LIMIT 1")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, role)",
+		"dialects = append(dialects, field.DialectSQL{SQL: strings.TrimSpace(tmp.String()), Vars: params})",
+		"}",
+		`sb.WriteString("?")`,
+		"params = append(params, field.Dialect(dialects...))",
+		"}",
+	},
+	"FindByNameLike": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 2)",
+		`sb.WriteString("SELECT * FROM ? WHERE name LIKE ? ORDER BY id")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, pattern)",
+	},
+	"AdultsByRole": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 2)",
+		`sb.WriteString("SELECT * FROM ? WHERE is_adult = true AND role = ? ORDER BY id")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, role)",
+	},
+	"FindByRoles": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 2)",
+		`sb.WriteString("SELECT * FROM ?")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable})",
+		"{",
+		"var tmp strings.Builder",
+		"if len(roles) > 0 {",
+		`tmp.WriteString(" role IN ?")`,
+		"params = append(params, roles)",
+		"} else {",
+		`tmp.WriteString(" 1 = 0")`,
+		"}",
+		"c := strings.TrimSpace(tmp.String())",
+		`if c != "" {`,
+		"reTrim := regexp.MustCompile(`(?i)^\\s*(?:and|or)\\s+|\\s+(?:and|or)\\s*$`)",
+		`c = reTrim.ReplaceAllString(c, "")`,
+		`sb.WriteString(" WHERE ")`,
+		"sb.WriteString(c)",
+		"}",
+		"}",
+		`sb.WriteString(" ORDER BY id")`,
+	},
+	"SearchByNameAndAge": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 3)",
+		`sb.WriteString("SELECT * FROM ?")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable})",
+		"{",
+		"var tmp strings.Builder",
+		"if notEmpty(name) {",
+		`tmp.WriteString(" name=?")`,
+		"params = append(params, name)",
+		"}",
+		"if inRange(age, 1, 150) {",
+		`tmp.WriteString(" AND age=?")`,
+		"params = append(params, age)",
+		"}",
+		"c := strings.TrimSpace(tmp.String())",
+		"if c != \"\" {",
+		"reTrim := regexp.MustCompile(`(?i)^\\s*(?:and|or)\\s+|\\s+(?:and|or)\\s*$`)",
+		"c = reTrim.ReplaceAllString(c, \"\")",
+		`sb.WriteString(" WHERE ")`,
+		"sb.WriteString(c)",
+		"}",
+		"}",
+	},
+	"MarkMinorsPending": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 2)",
+		`sb.WriteString("UPDATE ? SET role = 'pending' WHERE age < ?")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, age)",
+	},
+	"InsertBatch": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 5)",
+		`sb.WriteString("INSERT INTO ? (name, age, role, is_adult) VALUES")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable})",
+		`sb.WriteString(" ")`,
+		"for i, value := range users {",
+		"if i > 0 {",
+		`sb.WriteString(",")`,
+		"}",
+		`sb.WriteString("(?,?,?,?)")`,
+		"params = append(params, value.Name, value.Age, value.Role, value.IsAdult)",
+		"}",
+	},
+	"TouchByID": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 3)",
+		`sb.WriteString("UPDATE ? SET updated_at = ? WHERE id = ?")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, at, id)",
+	},
+	"StreamByRole": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 2)",
+		`sb.WriteString("SELECT * FROM ? WHERE role = ? ORDER BY id")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, role)",
+	},
+	"EachAdult": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 1)",
+		`sb.WriteString("SELECT * FROM ? WHERE is_adult = true ORDER BY id")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable})",
+	},
+	"CountPerRole": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 1)",
+		`sb.WriteString("SELECT role, count(*) AS total FROM ? GROUP BY role ORDER BY role")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable})",
+	},
+	"RoleSummary": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 1)",
+		`sb.WriteString("SELECT role, count(*) AS total, MAX(age) AS max_age FROM ? GROUP BY role ORDER BY role")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable})",
+	},
+	"StatsByRole": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 2)",
+		`sb.WriteString("SELECT count(*) AS total, MAX(age) AS max_age FROM ? WHERE role = ?")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, role)",
+	},
+	"OlderThanRoleAverage": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 2)",
+		`sb.WriteString("SELECT u.* FROM ? WHERE u.age > (SELECT AVG(r.age) FROM ? WHERE r.role = u.role) ORDER BY u.id")`,
+		`params = append(params, clause.Table{Name: clause.CurrentTable, Alias: "u"}, clause.Table{Name: clause.CurrentTable, Alias: "r"})`,
+	},
+	"CountAdults": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 1)",
+		`sb.WriteString("SELECT COUNT(*) FROM ? WHERE is_adult = true")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable})",
+		`sb.WriteString(" /* soft deleted records too */")`,
+	},
+	"ExistsByName": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 2)",
+		`sb.WriteString("SELECT EXISTS(SELECT 1 FROM ? WHERE name = ?)")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, name)",
+	},
+	"SwapRoles": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 7)",
+		`sb.WriteString("UPDATE ? SET role = '~swap' WHERE role = ?;")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, a)",
+		`sb.WriteString(" UPDATE ? SET role = ? WHERE role = ?;")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, a, b)",
+		`sb.WriteString(" UPDATE ? SET role = ? WHERE role = '~swap'")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable}, b)",
+	},
+	"SortBy": {
+		"var sb strings.Builder",
+		"params := make([]any, 0, 6)",
+		`sb.WriteString("SELECT * FROM ?")`,
+		"params = append(params, clause.Table{Name: clause.CurrentTable})",
+		`sb.WriteString(" ?")`,
+		`params = append(params, field.OrderBy(string(column), string(direction), "name", "age", "created_at"))`,
+	},
 }
 
 // TestRenderSQLTemplate
@@ -156,7 +427,7 @@ func TestRenderSQLTemplate(t *testing.T) {
 		name := method.Names[0].Name
 
 		doc := extractSQL(method.Doc.Text(), name)
-		if doc.Raw == "" && doc.Where == "" && doc.Select == "" {
+		if !slices.ContainsFunc(doc.templates(), func(sql string) bool { return sql != "" }) {
 			t.Fatalf("[SKIP] method %s has no doc", name)
 			continue
 		}
@@ -164,6 +435,14 @@ func TestRenderSQLTemplate(t *testing.T) {
 		if doc.Raw == "" {
 			continue
 		}
+		if constName, ok := strings.CutPrefix(strings.TrimSpace(doc.Raw), "sqlconst:"); ok {
+			doc.Raw = stringConst(t, parsedFile, strings.TrimSpace(constName))
+		}
+		if tmpl, ok, err := dialectVariants(doc.Raw); err != nil {
+			t.Fatalf("dialectVariants error for method %s: %v", name, err)
+		} else if ok {
+			doc.Raw = tmpl
+		}
 
 		got, err := RenderSQLTemplate(doc.Raw)
 		t.Run(name, func(t *testing.T) {
@@ -192,6 +471,657 @@ func TestRenderSQLTemplate(t *testing.T) {
 	}
 }
 
+func TestRenderSQLTemplateErrors(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{tmpl: "SELECT * FROM users\n{{where}}\n  {{if name != \"\"}} name=@name\n{{end}}", want: "2:1: unclosed {{where}} block"},
+		{tmpl: "SELECT * FROM users {{if id > 0}} WHERE id=@id", want: "1:21: unclosed {{if}} block"},
+		{tmpl: "SELECT * FROM users\n  WHERE {{unknown}}", want: "2:9: unknown directive: \"unknown\""},
+		{tmpl: "SELECT * FROM users {{if id > 0", want: "1:21: missing }}"},
+		{tmpl: "SELECT * FROM users {{end}}", want: "1:21: unmatched end"},
+		{tmpl: "SELECT * FROM users {{trim prefix=AND}} id=@id {{end}}", want: "1:21: invalid trim options: \"prefix=AND\", expected prefix=\"...\" or suffix=\"...\""},
+		{tmpl: "SELECT * FROM users {{trim infix=\"AND\"}} id=@id {{end}}", want: "1:21: unknown trim option: \"infix\""},
+		{tmpl: "SELECT * FROM users {{limit @size offset}}", want: "1:21: invalid limit, expected {{limit @size}} or {{limit @size offset @offset}}"},
+		{tmpl: "SELECT * FROM users {{orderBy @sort}}", want: "1:21: orderBy requires the allowed columns, e.g. allow=\"name,age\""},
+		{tmpl: "SELECT * FROM users {{orderBy @sort allow=\"name;DROP TABLE users\"}}", want: "1:21: invalid orderBy allowed column: \"name;DROP TABLE users\""},
+		{tmpl: "INSERT INTO users (name) VALUES {{values @users (Name,)}}", want: "1:33: invalid values field: \"\""},
+		{tmpl: "INSERT INTO users (name) VALUES {{values @users (Name) batch=0}}", want: "1:33: invalid values batch: \"0\", expected a positive number"},
+		{tmpl: "SELECT * FROM users WHERE {{in status statuses}}", want: "1:27: invalid in, expected {{in \"column\" slice}}"},
+		{tmpl: "SELECT * FROM users WHERE {{in \"status;--\" statuses}}", want: "1:27: invalid in column: \"status;--\""},
+		{tmpl: "SELECT * FROM users WHERE {{if dialect mysql}} id=@id {{end}}", want: "1:27: invalid dialect condition, expected {{if dialect \"mysql\"}}"},
+		{tmpl: "SELECT * FROM users WHERE {{if dialect \"mysql\"}} id=@id {{else if id > 0}} id=@id {{end}}", want: "1:57: cannot mix dialect and Go conditions in the same if block"},
+		{tmpl: "SELECT * FROM users WHERE {{join \" OR \"}}", want: "1:27: join outside for block"},
+		{tmpl: "SELECT * FROM users WHERE {{for _, n := range names}}{{if n != \"\"}}{{join \" OR \"}} name=@n {{end}}{{end}}", want: "1:68: join outside for block"},
+		{tmpl: "SELECT * FROM users WHERE {{for _, n := range names}}{{join OR}} name=@n {{end}}", want: "1:54: invalid join separator, expected a quoted string like {{join \", \"}}"},
+	}
+
+	for _, tt := range tests {
+		_, err := RenderSQLTemplate(tt.tmpl)
+		var tmplErr *SQLTemplateError
+		if !errors.As(err, &tmplErr) || err.Error() != tt.want {
+			t.Errorf("RenderSQLTemplate(%q) error = %v, want %q", tt.tmpl, err, tt.want)
+		}
+	}
+}
+
+func TestRenderSQLTemplateTrim(t *testing.T) {
+	got, err := RenderSQLTemplate(`SELECT * FROM users WHERE {{trim prefix="AND|OR" suffix=","}}{{if name != ""}} AND name=@name{{end}}{{end}}`)
+	if err != nil {
+		t.Fatalf("RenderSQLTemplate error: %v", err)
+	}
+	for _, want := range []string{"reTrim := regexp.MustCompile(`(?i)^\\s*(?:AND\\b|OR\\b)|(?:,)\\s*$`)", `sb.WriteString(" ")`, "sb.WriteString(c)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	tests := []struct {
+		prefixes, suffixes []string
+		body, want         string
+	}{
+		{prefixes: []string{"AND", "OR"}, body: "and name = ? AND age > ?", want: "name = ? AND age > ?"},
+		{prefixes: []string{"AND", "OR"}, body: "ORDER BY id", want: "ORDER BY id"},
+		{suffixes: []string{","}, body: "name = ?, age = ?,", want: "name = ?, age = ?"},
+		{prefixes: []string{"UNION ALL"}, suffixes: []string{"AND"}, body: "UNION ALL SELECT 1 AND", want: "SELECT 1"},
+		{suffixes: []string{"AND"}, body: "brand", want: "brand"},
+	}
+	for _, tt := range tests {
+		re := regexp.MustCompile(trimPattern(tt.prefixes, tt.suffixes))
+		if got := strings.TrimSpace(re.ReplaceAllString(tt.body, "")); got != tt.want {
+			t.Errorf("trim %q with prefixes %v and suffixes %v = %q, want %q", tt.body, tt.prefixes, tt.suffixes, got, tt.want)
+		}
+	}
+}
+
+func TestRenderSQLTemplateJoin(t *testing.T) {
+	got, err := RenderSQLTemplate(`SELECT * FROM users WHERE {{for _, n := range names}}{{join " OR "}}{{if n != ""}} name=@n {{end}}{{end}}`)
+	if err != nil {
+		t.Fatalf("RenderSQLTemplate error: %v", err)
+	}
+
+	want := []string{
+		`sb.WriteString("SELECT * FROM users WHERE")`,
+		"{",
+		"joinFirst := true",
+		"for _, n := range names {",
+		"var joinPart strings.Builder",
+		`if n != "" {`,
+		`joinPart.WriteString(" name=?")`,
+		"params = append(params, n)",
+		"}",
+		`if c := strings.TrimSpace(joinPart.String()); c != "" {`,
+		"if joinFirst {",
+		`sb.WriteString(" ")`,
+		"} else {",
+		`sb.WriteString(" OR ")`,
+		"}",
+		"joinFirst = false",
+		"sb.WriteString(c)",
+		"}",
+		"}",
+		"}",
+	}
+	gotLines := splitNonEmptyLines(got)[2:] // skip the builder and params declarations
+	if strings.Join(gotLines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected generated code:\n%s\nwant:\n%s", strings.Join(gotLines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRenderSQLTemplateValues(t *testing.T) {
+	got, err := RenderSQLTemplate(`INSERT INTO users (name, age) VALUES {{values @users (Name, Profile.Age) batch=100}}`)
+	if err != nil {
+		t.Fatalf("RenderSQLTemplate error: %v", err)
+	}
+
+	want := []string{
+		`sb.WriteString("INSERT INTO users (name, age) VALUES")`,
+		`sb.WriteString(" ")`,
+		"for i, value := range users {",
+		"if i > 0 {",
+		`sb.WriteString(",")`,
+		"}",
+		`sb.WriteString("(?,?)")`,
+		"params = append(params, value.Name, value.Profile.Age)",
+		"}",
+	}
+	gotLines := splitNonEmptyLines(got)[2:] // skip the builder and params declarations
+	if strings.Join(gotLines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected generated code:\n%s\nwant:\n%s", strings.Join(gotLines, "\n"), strings.Join(want, "\n"))
+	}
+
+	got, err = RenderSQLTemplate(`DELETE FROM users WHERE id IN ({{values @ids}})`)
+	if err != nil {
+		t.Fatalf("RenderSQLTemplate error: %v", err)
+	}
+	for _, want := range []string{`sb.WriteString("(?)")`, "params = append(params, value)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderSQLTemplateIn(t *testing.T) {
+	got, err := RenderSQLTemplate(`SELECT * FROM users {{where}} {{in "status" @statuses}} {{end}}`)
+	if err != nil {
+		t.Fatalf("RenderSQLTemplate error: %v", err)
+	}
+
+	want := []string{
+		`sb.WriteString("SELECT * FROM users")`,
+		"{",
+		"var tmp strings.Builder",
+		"if len(statuses) > 0 {",
+		`tmp.WriteString(" status IN ?")`,
+		"params = append(params, statuses)",
+		"} else {",
+		`tmp.WriteString(" 1 = 0")`,
+		"}",
+	}
+	gotLines := splitNonEmptyLines(got)[2:] // skip the builder and params declarations
+	if strings.Join(gotLines[:len(want)], "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected generated code:\n%s\nwant:\n%s", strings.Join(gotLines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRenderSQLTemplateColumnWhitelist(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want []string
+	}{
+		{
+			tmpl: "SELECT * FROM @@table WHERE @@column in (name, u.age) = @value",
+			want: []string{
+				`sb.WriteString("SELECT * FROM ? WHERE ? = ?")`,
+				`params = append(params, clause.Table{Name: clause.CurrentTable}, field.AllowedColumn(column, "name", "u.age"), value)`,
+			},
+		},
+		{
+			tmpl: "SELECT * FROM @@table ORDER BY @@column in ( * )",
+			want: []string{
+				`sb.WriteString("SELECT * FROM ? ORDER BY ?")`,
+				`params = append(params, clause.Table{Name: clause.CurrentTable}, field.AllowedColumn(column))`,
+			},
+		},
+		{
+			// an IN list of values isn't a whitelist
+			tmpl: "SELECT * FROM @@table WHERE @@column in (1, 2)",
+			want: []string{
+				`sb.WriteString("SELECT * FROM ? WHERE ? in (1, 2)")`,
+				`params = append(params, clause.Table{Name: clause.CurrentTable}, clause.Column{Name: column})`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := RenderSQLTemplate(tt.tmpl)
+		if err != nil {
+			t.Fatalf("RenderSQLTemplate(%q) error: %v", tt.tmpl, err)
+		}
+		if gotLines := splitNonEmptyLines(got)[2:]; strings.Join(gotLines, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("RenderSQLTemplate(%q) =\n%s\nwant:\n%s", tt.tmpl, strings.Join(gotLines, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestRenderSQLTemplateInlineExprs(t *testing.T) {
+	tests := []struct {
+		tmpl  string
+		funcs map[string]string
+		want  []string
+	}{
+		{
+			tmpl: "SELECT * FROM @@table WHERE id IN @ids LIMIT @{len(ids)} OFFSET @{ map[string]int{\"}\": 1}[key] }",
+			want: []string{
+				`sb.WriteString("SELECT * FROM ? WHERE id IN ? LIMIT ? OFFSET ?")`,
+				`params = append(params, clause.Table{Name: clause.CurrentTable}, ids, len(ids), map[string]int{"}": 1}[key])`,
+			},
+		},
+		{
+			tmpl:  "UPDATE @@table SET name = @{trim(name)}, note = '@{x}' WHERE id = @id",
+			funcs: map[string]string{"trim": "strings.TrimSpace"},
+			want: []string{
+				`sb.WriteString("UPDATE ? SET name = ?, note = '?' WHERE id = ?")`,
+				`params = append(params, clause.Table{Name: clause.CurrentTable}, strings.TrimSpace(name), x, id)`,
+			},
+		},
+		{
+			// an unclosed brace isn't an expression
+			tmpl: "SELECT * FROM users WHERE name = @{name",
+			want: []string{`sb.WriteString("SELECT * FROM users WHERE name = @{name")`},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := renderSQLTemplate(tt.tmpl, sqlTemplateOptions{funcs: tt.funcs})
+		if err != nil {
+			t.Fatalf("renderSQLTemplate(%q) error: %v", tt.tmpl, err)
+		}
+		if gotLines := splitNonEmptyLines(got)[2:]; strings.Join(gotLines, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("renderSQLTemplate(%q) =\n%s\nwant:\n%s", tt.tmpl, strings.Join(gotLines, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestRenderSQLTemplateLoopVars(t *testing.T) {
+	got, err := RenderSQLTemplate(`{{for i := 0; i < n; i++}}{{if $index > 0 && name != "$last"}},{{end}}@{$index}{{end}}`)
+	if err != nil {
+		t.Fatalf("RenderSQLTemplate error: %v", err)
+	}
+	want := []string{
+		"{",
+		"loopIndex := 0",
+		"for i := 0; i < n; i++ {",
+		`if loopIndex > 0 && name != "$last" {`,
+		`sb.WriteString(",")`,
+		"}",
+		`sb.WriteString("?")`,
+		"params = append(params, loopIndex)",
+		"loopIndex++",
+		"}",
+		"}",
+	}
+	if gotLines := splitNonEmptyLines(got)[2:]; strings.Join(gotLines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("RenderSQLTemplate =\n%s\nwant:\n%s", strings.Join(gotLines, "\n"), strings.Join(want, "\n"))
+	}
+
+	for tmpl, msg := range map[string]string{
+		"SELECT * FROM users {{if $first}}WHERE 1 = 1{{end}}":                "1:21: loop variable $first outside for block",
+		"{{for _, id := range ids}}@id{{if !$end}},{{end}}{{end}}":           "1:30: unknown loop variable $end, expected $index, $first or $last",
+		"{{for i := 0; i < n; i++}}{{if !$last}},{{end}}{{end}}":             "1:27: loop variable $last requires a range loop",
+		"SELECT * FROM users WHERE {{for _, id := range ids}}@{$len}{{end}}": "1:53: unknown loop variable $len, expected $index, $first or $last",
+	} {
+		if _, err := RenderSQLTemplate(tmpl); err == nil || err.Error() != msg {
+			t.Errorf("RenderSQLTemplate(%q) error = %v, want %q", tmpl, err, msg)
+		}
+	}
+}
+
+func TestRenderSQLTemplateComments(t *testing.T) {
+	tests := []struct {
+		name  string
+		tmpl  string
+		strip bool
+		want  []string
+	}{
+		{
+			name: "line comments are kept as block comments",
+			tmpl: "SELECT * FROM users -- active users, see */ below\nWHERE id = @id /* by id\n */",
+			want: []string{
+				`sb.WriteString("SELECT * FROM users")`,
+				`sb.WriteString(" /* active users, see * / below */")`,
+				`sb.WriteString(" WHERE id = ?")`,
+				"params = append(params, id)",
+				`sb.WriteString(" /* by id\n */")`,
+			},
+		},
+		{
+			name: "directives and placeholders of comments are ignored",
+			tmpl: "SELECT * FROM users -- {{if name != \"\"}} name = @name?\nWHERE note <> '-- not a comment'",
+			want: []string{
+				`sb.WriteString("SELECT * FROM users")`,
+				`sb.WriteString(" ?")`,
+				`params = append(params, clause.Expr{SQL: "/* {{if name != \"\"}} name = @name? */"})`,
+				`sb.WriteString(" WHERE note <> '-- not a comment'")`,
+			},
+		},
+		{
+			name: "comments are dropped in blocks",
+			tmpl: "SELECT * FROM users {{where}} -- by name\n{{if name != \"\"}} name = @name {{end}} {{end}}",
+			want: []string{
+				`sb.WriteString("SELECT * FROM users")`,
+				"{",
+				"var tmp strings.Builder",
+				`if name != "" {`,
+			},
+		},
+		{
+			name:  "stripped comments but hints",
+			tmpl:  "SELECT /*+ MAX_EXECUTION_TIME(1000) */ * FROM users -- all users\n/* ordered */ ORDER BY id",
+			strip: true,
+			want: []string{
+				`sb.WriteString("SELECT")`,
+				`sb.WriteString(" /*+ MAX_EXECUTION_TIME(1000) */")`,
+				`sb.WriteString(" * FROM users")`,
+				`sb.WriteString(" ORDER BY id")`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderSQLTemplate(tt.tmpl, sqlTemplateOptions{stripComments: tt.strip})
+			if err != nil {
+				t.Fatalf("renderSQLTemplate error: %v", err)
+			}
+			gotLines := splitNonEmptyLines(got)[2:]
+			if len(gotLines) > len(tt.want) {
+				gotLines = gotLines[:len(tt.want)]
+			}
+			if strings.Join(gotLines, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("unexpected generated code:\n%s\nwant:\n%s", got, strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	if _, err := RenderSQLTemplate("SELECT * FROM users\n  /* unclosed {{end}}"); err == nil || err.Error() != "2:3: unclosed /* comment" {
+		t.Errorf("expected an unclosed comment error, got: %v", err)
+	}
+}
+
+func TestRenderSQLTemplateNested(t *testing.T) {
+	got, err := RenderSQLTemplate(`SELECT * FROM users
+{{where}}
+  {{for _, f := range filters}}{{join " OR "}}
+    {{group}} {{if f.Name != ""}} AND name=@f.Name {{end}} {{if f.Age > 0}} AND age=@f.Age {{end}} {{end}}
+  {{end}}
+  AND id IN (SELECT user_id FROM orders {{where}} {{if min > 0}} amount > @min {{end}} {{end}})
+{{end}}`)
+	if err != nil {
+		t.Fatalf("RenderSQLTemplate error: %v", err)
+	}
+
+	for _, want := range []string{
+		"var tmp strings.Builder",
+		"var joinPart strings.Builder",
+		`joinPart.WriteString(" (")`, // the group writes into the iteration of the loop
+		`joinPart.WriteString(")")`,
+		"var tmp2 strings.Builder", // the nested where doesn't shadow the builder of its parent
+		`tmp2.WriteString(" amount > ?")`,
+		"c := strings.TrimSpace(tmp2.String())",
+		`tmp.WriteString(" WHERE ")`,
+		`sb.WriteString(" WHERE ")`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderSQLTemplatePlaceholders(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl string
+		opts sqlTemplateOptions
+		want []string
+	}{
+		{
+			name: "escaped prefixes",
+			tmpl: `SELECT @@col FROM @@table WHERE name=@name AND email LIKE '%\@gmail.com' AND note <> '\@\@x'`,
+			want: []string{
+				`sb.WriteString("SELECT ? FROM ? WHERE name=? AND email LIKE '%@gmail.com' AND note <> '@@x'")`,
+				"params = append(params, clause.Column{Name: col}, clause.Table{Name: clause.CurrentTable}, name)",
+			},
+		},
+		{
+			name: "custom prefix",
+			tmpl: `DECLARE @total INT; SELECT $$col FROM $$table WHERE id=$user.ID AND tag = '\$x' AND note::text <> ''`,
+			opts: sqlTemplateOptions{prefix: "$"},
+			want: []string{
+				`sb.WriteString("DECLARE @total INT; SELECT ? FROM ? WHERE id=? AND tag = '$x' AND note::text <> ''")`,
+				"params = append(params, clause.Column{Name: col}, clause.Table{Name: clause.CurrentTable}, user.ID)",
+			},
+		},
+		{
+			name: "table aliases",
+			tmpl: `SELECT a.name FROM @@table(as=a) JOIN @@table( as = b ) ON b.id = a.manager_id WHERE a.id = @id AND a.tag <> '@@table(x)'`,
+			want: []string{
+				`sb.WriteString("SELECT a.name FROM ? JOIN ? ON b.id = a.manager_id WHERE a.id = ? AND a.tag <> '?(x)'")`,
+				`params = append(params, clause.Table{Name: clause.CurrentTable, Alias: "a"}, clause.Table{Name: clause.CurrentTable, Alias: "b"}, id, clause.Table{Name: clause.CurrentTable})`,
+			},
+		},
+		{
+			name: "ambiguous placeholders kept as is",
+			tmpl: `SELECT * FROM users WHERE email = 'a@b' AND @ = 1`,
+			want: []string{
+				`sb.WriteString("SELECT * FROM users WHERE email = 'a?' AND @ = 1")`,
+				"params = append(params, b)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderSQLTemplate(tt.tmpl, tt.opts)
+			if err != nil {
+				t.Fatalf("renderSQLTemplate error: %v", err)
+			}
+			gotLines := splitNonEmptyLines(got)[2:] // skip the builder and params declarations
+			if strings.Join(gotLines, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("unexpected generated code:\n%s\nwant:\n%s", strings.Join(gotLines, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestRenderSQLTemplateStrictPlaceholders(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		opts sqlTemplateOptions
+		want string
+	}{
+		{tmpl: "SELECT * FROM users\nWHERE email = 'a@b'", want: `2:17: ambiguous placeholder "@b" inside a string literal, escape it or move it out of the quotes`},
+		{tmpl: "SELECT * FROM users WHERE {{if id > 0}}id = @ {{end}}", want: `1:45: ambiguous placeholder "@" without a name, escape it as "\\@"`},
+		{tmpl: "SELECT * FROM users WHERE name = '$$$name'", opts: sqlTemplateOptions{prefix: "$"}, want: `1:35: ambiguous placeholder "$" without a name, escape it as "\\$"`},
+	}
+
+	for _, tt := range tests {
+		tt.opts.strict = true
+		_, err := renderSQLTemplate(tt.tmpl, tt.opts)
+		var tmplErr *SQLTemplateError
+		if !errors.As(err, &tmplErr) || err.Error() != tt.want {
+			t.Errorf("renderSQLTemplate(%q) error = %v, want %q", tt.tmpl, err, tt.want)
+		}
+	}
+
+	if _, err := renderSQLTemplate(`SELECT * FROM users WHERE email LIKE '%\@gmail.com' AND name = @name`, sqlTemplateOptions{strict: true}); err != nil {
+		t.Errorf("renderSQLTemplate error: %v", err)
+	}
+	for _, prefix := range []string{"a", ":", "-"} {
+		if _, err := renderSQLTemplate("SELECT * FROM users WHERE id = #id", sqlTemplateOptions{prefix: prefix}); err == nil {
+			t.Errorf("expected an error of the invalid placeholder prefix %q", prefix)
+		}
+	}
+}
+
+func TestExtractSQLAnnotation(t *testing.T) {
+	tests := []struct {
+		doc         string
+		want        ExtractedSQL
+		description string
+	}{
+		{
+			doc:         "GetByID returns the user.\n\nsql(\"SELECT * FROM @@table WHERE id=@id\")\n\nIt returns gorm.ErrRecordNotFound when missing.",
+			want:        ExtractedSQL{Raw: "SELECT * FROM @@table WHERE id=@id"},
+			description: "GetByID returns the user.\n\nIt returns gorm.ErrRecordNotFound when missing.",
+		},
+		{
+			doc:         "Recent returns the latest users\ntimeout: 2s\nsql(\"SELECT * FROM users WHERE name = \"jinzhu\" ORDER BY id DESC\")",
+			want:        ExtractedSQL{Raw: `SELECT * FROM users WHERE name = "jinzhu" ORDER BY id DESC`, Timeout: "2s"},
+			description: "Recent returns the latest users",
+		},
+		{
+			doc:         "Count uses sql(...) in its documentation\n\nSELECT count(*) FROM users",
+			want:        ExtractedSQL{Raw: "SELECT count(*) FROM users"},
+			description: "Count uses sql(...) in its documentation",
+		},
+	}
+	for _, tt := range tests {
+		if got := extractSQL(tt.doc, "Method"); got != tt.want {
+			t.Errorf("extractSQL(%q) = %+v, want %+v", tt.doc, got, tt.want)
+		}
+		if got := extractDescription(tt.doc, "Method"); got != tt.description {
+			t.Errorf("extractDescription(%q) = %q, want %q", tt.doc, got, tt.description)
+		}
+	}
+}
+
+func TestSplitUpdateSQL(t *testing.T) {
+	tests := []struct {
+		tmpl, set, where string
+		ok               bool
+	}{
+		{tmpl: "name=@name WHERE id=@id", set: "name=@name", where: "id=@id", ok: true},
+		{tmpl: "name=(SELECT name FROM users WHERE id=@from), note='where' where id=@id", set: "name=(SELECT name FROM users WHERE id=@from), note='where'", where: "id=@id", ok: true},
+		{tmpl: `{{set}} {{if nowhere}} name=@name, {{end}} {{end}} WHERE id IN (@ids)`, set: `{{set}} {{if nowhere}} name=@name, {{end}} {{end}}`, where: "id IN (@ids)", ok: true},
+		{tmpl: "name=@name, somewhere=@somewhere", set: "name=@name, somewhere=@somewhere"},
+	}
+	for _, tt := range tests {
+		set, where, ok := splitUpdateSQL(tt.tmpl)
+		if set != tt.set || where != tt.where || ok != tt.ok {
+			t.Errorf("splitUpdateSQL(%q) = %q, %q, %v, want %q, %q, %v", tt.tmpl, set, where, ok, tt.set, tt.where, tt.ok)
+		}
+	}
+}
+
+func TestExpandTemplateVars(t *testing.T) {
+	vars := map[string]string{"softDelete": "deleted_at IS NULL", "adult": "age >= 18"}
+	got, _, err := expandTemplateVars("SELECT * FROM @@table WHERE ${softDelete} AND ${adult} AND note <> '\\${adult}'", vars)
+	if want := "SELECT * FROM @@table WHERE deleted_at IS NULL AND age >= 18 AND note <> '${adult}'"; err != nil || got != want {
+		t.Errorf("expandTemplateVars = %q, %v, want %q", got, err, want)
+	}
+
+	if _, offset, err := expandTemplateVars("SELECT *\nFROM @@table WHERE ${deleted}", vars); err == nil || err.Error() != "unknown template var ${deleted}" || offset != 28 {
+		t.Errorf("expected an unknown template var error at 28, got %v at %d", err, offset)
+	}
+}
+
+func TestDialectVariants(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+		ok   bool
+		err  string
+	}{
+		{sql: "SELECT * FROM @@table WHERE name = @name", want: "SELECT * FROM @@table WHERE name = @name"},
+		{sql: "SELECT x::text:\nFROM @@table", want: "SELECT x::text:\nFROM @@table"},
+		{
+			sql:  "default: SELECT * FROM @@table\npostgres: SELECT * FROM @@table\n  WHERE name ILIKE @name\nmysql, sqlite: SELECT 1",
+			want: "{{if dialect \"postgres\"}}\nSELECT * FROM @@table\n  WHERE name ILIKE @name\n{{else if dialect \"mysql\" \"sqlite\"}}\nSELECT 1\n{{else}}\nSELECT * FROM @@table\n{{end}}",
+			ok:   true,
+		},
+		{sql: "mysql: SELECT 1", want: "{{if dialect \"mysql\"}}\nSELECT 1\n{{end}}", ok: true},
+		{sql: "default: SELECT 1", want: "SELECT 1", ok: true},
+		{sql: "mysql: SELECT 1\nmysql: SELECT 2", ok: true, err: "duplicate SQL variant of dialect mysql"},
+		{sql: "mysql: SELECT 1\ndefault, sqlite: SELECT 2", ok: true, err: "the default SQL variant can't be declared with other dialects"},
+		{sql: "mysql:\npostgres: SELECT 2", ok: true, err: "empty SQL variant of dialect mysql"},
+	}
+	for _, tt := range tests {
+		got, ok, err := dialectVariants(tt.sql)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err || ok != tt.ok {
+				t.Errorf("dialectVariants(%q) error = %v, %v, want %q", tt.sql, err, ok, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want || ok != tt.ok {
+			t.Errorf("dialectVariants(%q) = %q, %v, %v, want %q, %v", tt.sql, got, ok, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBindOrdinals(t *testing.T) {
+	params := []string{"name", "age"}
+	tests := []struct {
+		tmpl    string
+		dialect string
+		prefix  string
+		want    string
+		err     string
+	}{
+		{tmpl: "SELECT * FROM users WHERE name = $1 AND (age > $2 OR age IS NULL) AND note = $1", dialect: "postgres", want: "SELECT * FROM users WHERE name = @name AND (age > @age OR age IS NULL) AND note = @name"},
+		{tmpl: "SELECT * FROM users WHERE name = ? AND age > ?", dialect: "mysql", want: "SELECT * FROM users WHERE name = @name AND age > @age"},
+		{tmpl: "SELECT * FROM users WHERE name = ?2 AND age > ?", dialect: "sqlite", want: "SELECT * FROM users WHERE name = @age AND age > @name"},
+		{tmpl: "SELECT * FROM users WHERE name = :1 AND age::text = :2", dialect: "oracle", prefix: "#", want: "SELECT * FROM users WHERE name = #name AND age::text = #age"},
+		{tmpl: "SELECT * FROM users WHERE name = @p1 AND age > @age", dialect: "sqlserver", want: "SELECT * FROM users WHERE name = @name AND age > @age"},
+		{
+			tmpl:    "SELECT data->>'$1' FROM users /* $2 */ WHERE name = $1 {{if age > 0}}AND age > $2{{end}} -- by $1",
+			dialect: "postgres",
+			want:    "SELECT data->>'$1' FROM users /* $2 */ WHERE name = @name {{if age > 0}}AND age > @age{{end}} -- by $1",
+		},
+		{tmpl: "SELECT * FROM users WHERE name = $1", dialect: "mysql", want: "SELECT * FROM users WHERE name = $1"},
+		{tmpl: "SELECT * FROM users WHERE name = $3", dialect: "postgres", err: "placeholder $3 is out of the 2 parameters of the method"},
+		{tmpl: "SELECT * FROM users WHERE name = ? AND age > ? AND role = ?", dialect: "mysql", err: "placeholder ? is out of the 2 parameters of the method"},
+	}
+	for _, tt := range tests {
+		got, _, err := bindOrdinals(tt.tmpl, tt.dialect, tt.prefix, params)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("bindOrdinals(%q, %s) error = %v, want %q", tt.tmpl, tt.dialect, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("bindOrdinals(%q, %s) = %q, %v, want %q", tt.tmpl, tt.dialect, got, err, tt.want)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want []string
+	}{
+		{tmpl: "DELETE FROM pets WHERE user_id = @id; DELETE FROM @@table WHERE id = @id;", want: []string{"DELETE FROM pets WHERE user_id = @id", "DELETE FROM @@table WHERE id = @id"}},
+		{tmpl: "UPDATE @@table SET note = 'a;b' {{if name != \";\"}}, name = @name{{end}}", want: []string{"UPDATE @@table SET note = 'a;b' {{if name != \";\"}}, name = @name{{end}}"}},
+		{tmpl: " ; ", want: nil},
+		{tmpl: "DELETE FROM pets; -- then the users; of the pets\nDELETE FROM @@table /* ; */ -- done", want: []string{"DELETE FROM pets", "-- then the users; of the pets\nDELETE FROM @@table /* ; */ -- done"}},
+	}
+	for _, tt := range tests {
+		if got := splitStatements(tt.tmpl); !slices.Equal(got, tt.want) {
+			t.Errorf("splitStatements(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestQualifyFuncs(t *testing.T) {
+	funcs := map[string]string{"notEmpty": "helpers.NotEmpty", "inRange": "helpers.InRange"}
+	tests := []struct {
+		expr, want string
+	}{
+		{expr: "notEmpty(user.Name)", want: "helpers.NotEmpty(user.Name)"},
+		{expr: `notEmpty(name) && inRange(age, 1,  150)`, want: `helpers.NotEmpty(name) && helpers.InRange(age, 1,  150)`},
+		{expr: `user.notEmpty(name) || notEmpty != nil`, want: `user.notEmpty(name) || notEmpty != nil`},
+		{expr: `name != "notEmpty(x)" && len(name) > 0`, want: `name != "notEmpty(x)" && len(name) > 0`},
+		{expr: "_, v := range filter(values)", want: "_, v := range filter(values)"},
+	}
+	for _, tt := range tests {
+		if got := qualifyFuncs(tt.expr, funcs); got != tt.want {
+			t.Errorf("qualifyFuncs(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestSQLColumnRefs(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want []string
+	}{
+		{tmpl: `SELECT * FROM @@table WHERE id=@id AND name = "\@name"`, want: []string{"id", "name"}},
+		{tmpl: "SELECT * FROM @@table WHERE {{if role != \"\"}}role IN @roles AND{{end}} age NOT BETWEEN @min AND @max", want: []string{"role", "age"}},
+		{tmpl: "SELECT role, count(*) AS total FROM @@table GROUP BY role ORDER BY total DESC, created_at LIMIT 10", want: []string{"role", "created_at"}},
+		{tmpl: "UPDATE @@table SET name=@name, age = age + 1 WHERE deleted_at IS NULL", want: []string{"name", "age", "deleted_at"}},
+		{tmpl: "SELECT u.* FROM users u WHERE u.name LIKE @name AND lower(email) = @email -- note = 1", want: []string{"u.name"}},
+		{tmpl: "SELECT * FROM @@table JOIN pets ON users.id = pets.user_id WHERE pets.name = @name AND age > 18", want: []string{"users.id"}},
+		{tmpl: "SELECT * FROM @@table WHERE id IN (SELECT user_id FROM pets) AND @@column = @value", want: nil},
+		{tmpl: "SELECT * FROM @@table {{where}} {{in \"role\" @roles}} AND {{in \"pets.name\" names}} {{end}}", want: []string{"role"}},
+		{tmpl: "SELECT u.* FROM @@table(as=u) WHERE u.age > (SELECT AVG(r.age) FROM @@table(as=r) WHERE r.role = u.role) AND m.id = 1", want: []string{"u.age", "r.role"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, ref := range sqlColumnRefs(tt.tmpl, defaultPlaceholderPrefix, "users") {
+			if ref.Qualifier != "" {
+				ref.Name = ref.Qualifier + "." + ref.Name
+			}
+			got = append(got, ref.Name)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("sqlColumnRefs(%q) = %v, want %v", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
 func splitNonEmptyLines(s string) []string {
 	var out []string
 	for _, l := range strings.Split(s, "\n") {
@@ -202,3 +1132,94 @@ func splitNonEmptyLines(s string) []string {
 	}
 	return out
 }
+
+// stringConst returns the value of the string constant name declared in file
+func stringConst(t *testing.T, file *ast.File, name string) string {
+	t.Helper()
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, ident := range vs.Names {
+				if ident.Name != name || i >= len(vs.Values) {
+					continue
+				}
+				if lit, ok := vs.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					value, err := strconv.Unquote(lit.Value)
+					if err != nil {
+						t.Fatalf("invalid string constant %s: %v", name, err)
+					}
+					return value
+				}
+			}
+		}
+	}
+	t.Fatalf("string constant %s not found", name)
+	return ""
+}
+
+func TestCheckSQLTemplates(t *testing.T) {
+	tests := []struct {
+		tmpl    string
+		dialect string
+		example string
+		err     string
+	}{
+		{
+			tmpl:    "SELECT * FROM @@table u WHERE @@column = @name AND id IN @ids {{if age > 0}}AND age > @age{{end}}",
+			dialect: sqlCheckGeneric,
+			example: "SELECT * FROM t u WHERE c = ? AND id IN (?) AND age > ?",
+		},
+		{
+			tmpl:    "UPDATE @@table {{set}}{{if name != \"\"}}name=@name,{{end}}{{end}} {{where}}{{for _, id := range ids}}OR id=@id{{end}}{{end}}",
+			dialect: sqlCheckGeneric,
+			example: "UPDATE t SET name=? WHERE id=?",
+		},
+		{
+			tmpl:    "SELECT * FROM users {{if dialect \"postgres\"}}ORDER BY id NULLS LAST{{else}}ORDER BY id IS NULL, id{{end}} {{limit @limit offset @offset}}",
+			dialect: "sqlserver",
+			example: "SELECT * FROM users ORDER BY id IS NULL, id OFFSET ? ROWS FETCH NEXT ? ROWS ONLY",
+		},
+		{tmpl: "SELECT * FROM users WHERE (id = @id", dialect: sqlCheckGeneric, err: "unbalanced ("},
+		{tmpl: "SELECT name, FROM users", dialect: sqlCheckGeneric, err: "misplaced comma"},
+		{tmpl: "UPDATE users SET name=@name age=@age", dialect: sqlCheckGeneric, err: "missing comma"},
+		{tmpl: "SELECT * FROM users WHERE name = 'x", dialect: sqlCheckGeneric, err: "unterminated"},
+		{tmpl: `SELECT * FROM users WHERE name = 'it\'s, (' AND id = @id`, dialect: "mysql"},
+		{tmpl: `SELECT * FROM users WHERE name = 'it\'s, (' AND id = @id`, dialect: sqlCheckGeneric, err: "unterminated"},
+		{tmpl: `SELECT * FROM users WHERE name = E'it\'s' AND note = 'C:\' AND id = @id`, dialect: "postgres"},
+		{tmpl: "SELECT $body$a, (b$body$, id FROM users WHERE id = @id", dialect: "postgres"},
+		{tmpl: "SELECT $$a, (b FROM users WHERE id = @id", dialect: "postgres", err: "unterminated"},
+		{tmpl: "SELECT * FROM users WHERE name = @name AND", dialect: "sqlite", err: "incomplete input"},
+		{tmpl: "SELECT * FROM missing_table WHERE name = @name", dialect: "sqlite"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			nodes, err := parseSQLTemplate(tt.tmpl, sqlTemplateOptions{})
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			example := exampleSQL(nodes, tt.dialect)
+			if tt.example != "" && example != tt.example {
+				t.Errorf("expected example %q, got %q", tt.example, example)
+			}
+
+			if tt.dialect == "sqlite" {
+				skipWithoutSQLite(t)
+			}
+			checker, err := newSQLChecker(tt.dialect)
+			if err != nil {
+				t.Fatalf("checker error: %v", err)
+			}
+			defer checker.Close()
+			if err := checker.check(example); tt.err == "" && err != nil {
+				t.Errorf("expected %q valid, got %v", example, err)
+			} else if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("expected %q error of %q, got %v", tt.err, example, err)
+			}
+		})
+	}
+}
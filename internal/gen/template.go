@@ -1,59 +1,71 @@
 package gen
 
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
 var (
 	codeGenHint = "// Code generated by 'gorm.io/cli/gorm'. DO NOT EDIT."
-	pkgTmpl     = codeGenHint + `
 
-package {{.Package}}
+	// defaultTemplates are the templates of generated files, *.tmpl files of a template
+	// directory override the templates with the same name, see genconfig.Config.TemplateDir
+	//
+	//go:embed templates/*.tmpl
+	defaultTemplates embed.FS
 
-import (
-    "gorm.io/gorm"
-    "gorm.io/gorm/clause"
-    "gorm.io/cli/gorm/field"
-    {{- if .UsedTypedAPI }}
-    "gorm.io/cli/gorm/typed"
-    {{- end }}
-    {{range .Imports -}}
-    {{.ImportPath}}
-    {{end -}}
+	//go:embed templates/pkg.tmpl
+	pkgTmpl string
 )
 
-{{range .Interfaces}}
-{{$IfaceName := .IfaceName}}
-func {{.Name}}[T any](db *gorm.DB, opts ...clause.Expression) {{$IfaceName}}Interface[T] {
-    return {{$IfaceName}}Impl[T]{
-        Interface: {{if $.UsedTypedAPI}}typed{{else}}gorm{{end}}.G[T](db, opts...),
-    }
-}
+const (
+	// pkgTmplName is the name of the template rendering a generated file
+	pkgTmplName = "pkg.tmpl"
+	// testTmplName is the name of the template rendering the test skeleton of a generated file
+	testTmplName = "test.tmpl"
+	// docsTmplName is the name of the template rendering the Markdown documentation of a generated package
+	docsTmplName = "docs.tmpl"
+	// protoTmplName is the name of the template rendering the models of a protobuf file, see genProto
+	protoTmplName = "proto.tmpl"
+)
 
-type {{$IfaceName}}Interface[T any] interface {
-    {{if $.UsedTypedAPI}}typed{{else}}gorm{{end}}.Interface[T]
-    {{range .Methods -}}
-    {{.Name}}({{.ParamsString}}) ({{.ResultString}})
-    {{end}}
-}
+// parseTemplates parses the default templates with funcs, overridden by the *.tmpl files of dir if set.
+// Additional files of dir are available to the overrides with {{template "<name>.tmpl" .}}.
+func parseTemplates(dir string, funcs template.FuncMap) (*template.Template, error) {
+	tmpl, err := template.New(pkgTmplName).Funcs(funcs).ParseFS(defaultTemplates, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return tmpl, nil
+	}
 
-type {{$IfaceName}}Impl[T any] struct {
-    {{if $.UsedTypedAPI}}typed{{else}}gorm{{end}}.Interface[T]
+	files, err := templateFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no *.tmpl files found in template directory %v", dir)
+	}
+	for _, pth := range files {
+		content, err := os.ReadFile(pth)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tmpl.New(filepath.Base(pth)).Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("failed to parse template %v, got error %v", pth, err)
+		}
+	}
+	return tmpl, nil
 }
 
-{{range .Methods}}
-func (e {{$IfaceName}}Impl[T]) {{.Name}}({{.ParamsString}}) ({{.ResultString}}) {
-	{{.Body}}
+// templateFiles returns the *.tmpl files of the template directory
+func templateFiles(dir string) ([]string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("invalid template directory %v, got error %v", dir, err)
+	}
+	return filepath.Glob(filepath.Join(dir, "*.tmpl"))
 }
-{{end}}
-{{end}}
-
-{{range .Structs}}
-var {{.Name}} = struct {
-	{{range .Fields -}}
-	{{.Name}} {{.Type}}
-	{{end}}
-}{
-	{{range .Fields -}}
-	{{.Name}}: {{.Value}},
-	{{end -}}
-}
-{{end}}
-`
-)
@@ -4,38 +4,67 @@ import (
 	"bytes"
 	_ "database/sql"
 	_ "database/sql/driver"
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
+	"go/parser"
 	"go/token"
 	"go/types"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"golang.org/x/tools/go/packages"
 	_ "gorm.io/gorm"
 	"gorm.io/gorm/schema"
 )
 
-var allowedInterfaces = []types.Type{
-	loadNamedType("", "database/sql", "Scanner"),
-	loadNamedType("", "database/sql/driver", "Valuer"),
-	loadNamedType("", "gorm.io/gorm", "Valuer"),
-	loadNamedType("", "gorm.io/gorm/schema", "SerializerInterface"),
-}
+// serializerInterface and allowedInterfaces are loaded on first use, so commands that don't resolve
+// field types, e.g. --help, don't load packages
+var (
+	serializerInterface = sync.OnceValue(func() types.Type {
+		return loadNamedType("", "gorm.io/gorm/schema", "SerializerInterface")
+	})
+	allowedInterfaces = sync.OnceValue(func() []types.Type {
+		return []types.Type{
+			loadNamedType("", "database/sql", "Scanner"),
+			loadNamedType("", "database/sql/driver", "Valuer"),
+			loadNamedType("", "gorm.io/gorm", "Valuer"),
+			serializerInterface(),
+		}
+	})
+)
 
 type ExtractedSQL struct {
 	Raw    string
 	Where  string
 	Select string
+	Expr   string // template of a method returning a clause.Expression, see Method.exprMethodBody
+	Update string // SET list and WHERE condition of an update method, e.g. name=@name WHERE id=@id
+	Delete string // WHERE condition of a delete method
+	Count  string // WHERE condition of a count method
+	Insert string // table and struct parameter of an insert method, e.g. @@table, @user.*
+
+	Timeout string // duration of a `timeout: 3s` line before or after the SQL, see Method.timeout
 }
 
 func extractSQL(comment string, methodName string) ExtractedSQL {
 	comment = strings.TrimSpace(comment)
 
+	if sql, rest, ok := cutSQLAnnotation(comment); ok {
+		_, timeout := cutTimeoutLine(rest)
+		return ExtractedSQL{Raw: sql, Timeout: timeout}
+	}
+
 	if index := strings.Index(comment, "\n\n"); index != -1 {
 		if strings.Contains(comment[index+2:], methodName) {
 			comment = comment[:index]
@@ -44,19 +73,292 @@ func extractSQL(comment string, methodName string) ExtractedSQL {
 		}
 	}
 
-	sql := strings.TrimPrefix(comment, methodName)
-	if strings.HasPrefix(sql, "where(") && strings.HasSuffix(sql, ")") {
-		content := strings.TrimSuffix(strings.TrimPrefix(sql, "where("), ")")
-		content = strings.Trim(content, "\"")
-		content = strings.TrimSpace(content)
-		return ExtractedSQL{Where: content}
-	} else if strings.HasPrefix(sql, "select(") && strings.HasSuffix(sql, ")") {
-		content := strings.TrimSuffix(strings.TrimPrefix(sql, "select("), ")")
-		content = strings.Trim(content, "\"")
-		content = strings.TrimSpace(content)
-		return ExtractedSQL{Select: content}
+	sql, timeout := cutTimeout(strings.TrimPrefix(comment, methodName))
+
+	extracted := ExtractedSQL{Timeout: timeout}
+	for _, annotation := range []struct {
+		name string
+		dst  *string
+	}{
+		{"where", &extracted.Where},
+		{"select", &extracted.Select},
+		{"expr", &extracted.Expr},
+		{"update", &extracted.Update},
+		{"delete", &extracted.Delete},
+		{"count", &extracted.Count},
+		{"insert", &extracted.Insert},
+	} {
+		if strings.HasPrefix(sql, annotation.name+"(") && strings.HasSuffix(sql, ")") {
+			content := strings.TrimSuffix(strings.TrimPrefix(sql, annotation.name+"("), ")")
+			content = strings.Trim(content, "\"")
+			*annotation.dst = strings.TrimSpace(content)
+			return extracted
+		}
+	}
+	return ExtractedSQL{Raw: sql, Timeout: timeout}
+}
+
+// cutTimeout cuts the `timeout: <duration>` line of the SQL, its first or last line, and returns the SQL
+// without it and the duration
+func cutTimeout(sql string) (string, string) {
+	lines := strings.Split(strings.TrimSpace(sql), "\n")
+	for _, i := range []int{0, len(lines) - 1} {
+		if timeout, ok := strings.CutPrefix(strings.TrimSpace(lines[i]), "timeout:"); ok {
+			lines = slices.Delete(lines, i, i+1)
+			return strings.TrimSpace(strings.Join(lines, "\n")), strings.TrimSpace(timeout)
+		}
+	}
+	return sql, ""
+}
+
+// sqlAnnotationRegexp matches the `sql("SELECT ...")` line of a method comment declaring its SQL template
+var sqlAnnotationRegexp = regexp.MustCompile(`^sql\("(.*)"\)$`)
+
+// cutSQLAnnotation cuts the `sql("...")` line of the comment, and returns its SQL template and the rest of
+// the comment, the documentation of the method around it
+func cutSQLAnnotation(comment string) (sql, rest string, ok bool) {
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		if m := sqlAnnotationRegexp.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			return strings.TrimSpace(m[1]), strings.TrimSpace(strings.Join(deleteLine(lines, i), "\n")), true
+		}
+	}
+	return "", comment, false
+}
+
+// cutTimeoutLine cuts the `timeout: <duration>` line of the comment of a sql("...") annotation, any of its
+// lines, and returns the comment without it and the duration
+func cutTimeoutLine(comment string) (string, string) {
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		if timeout, ok := strings.CutPrefix(strings.TrimSpace(line), "timeout:"); ok {
+			return strings.TrimSpace(strings.Join(deleteLine(lines, i), "\n")), strings.TrimSpace(timeout)
+		}
+	}
+	return comment, ""
+}
+
+// deleteLine deletes the line i, and the blank line following it when it separated two paragraphs
+func deleteLine(lines []string, i int) []string {
+	lines = slices.Delete(lines, i, i+1)
+	if i > 0 && i < len(lines) && strings.TrimSpace(lines[i-1]) == "" && strings.TrimSpace(lines[i]) == "" {
+		lines = slices.Delete(lines, i, i+1)
 	}
-	return ExtractedSQL{Raw: sql}
+	return lines
+}
+
+// sqlDialects are the names of the dialects of `mysql: ...` variants of SQL templates, see dialectVariants
+var sqlDialects = []string{"mysql", "postgres", "sqlite", "sqlserver", "oracle", "clickhouse", "gaussdb"}
+
+// durationUnits are the storage conventions of time.Duration fields, see genconfig.Config.DurationUnit
+var durationUnits = []string{"ns", "us", "ms", "s", "interval"}
+
+// durationUnitValues are the time.Duration units passed to field.Duration.WithUnit by storage convention
+var durationUnitValues = map[string]string{"us": "time.Microsecond", "ms": "time.Millisecond", "s": "time.Second"}
+
+// dialectLabelRegexp matches the `mysql: SELECT ...`, `mysql, sqlite: SELECT ...` or `default: SELECT ...`
+// line starting a dialect variant of a SQL template
+var dialectLabelRegexp = regexp.MustCompile(`^([a-z]+(?:\s*,\s*[a-z]+)*)\s*:(?:\s+(.*))?$`)
+
+// dialectVariants rewrites the dialect variants of the SQL template, starting with lines like `mysql: ...`,
+// `postgres: ...` or `default: ...`, into a {{if dialect}} block, so the variant of the runtime dialect, or
+// the default one, is chosen by field.Dialect; ok is false when the template doesn't start with a variant
+func dialectVariants(sql string) (tmpl string, ok bool, err error) {
+	type variant struct {
+		dialects []string
+		lines    []string
+	}
+	label := func(line string) (dialects []string, rest string, ok bool) {
+		m := dialectLabelRegexp.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			return nil, "", false
+		}
+		for _, name := range strings.Split(m[1], ",") {
+			if name = strings.TrimSpace(name); name != "default" && !slices.Contains(sqlDialects, name) {
+				return nil, "", false
+			}
+			dialects = append(dialects, name)
+		}
+		return dialects, m[2], true
+	}
+
+	lines := strings.Split(strings.TrimSpace(sql), "\n")
+	if _, _, ok := label(lines[0]); !ok {
+		return sql, false, nil
+	}
+
+	var variants []*variant
+	var fallback *variant
+	seen := map[string]bool{}
+	for _, line := range lines {
+		dialects, rest, ok := label(line)
+		if !ok {
+			variants[len(variants)-1].lines = append(variants[len(variants)-1].lines, line)
+			continue
+		}
+		for _, dialect := range dialects {
+			if seen[dialect] {
+				return "", true, fmt.Errorf("duplicate SQL variant of dialect %s", dialect)
+			}
+			seen[dialect] = true
+		}
+		if slices.Contains(dialects, "default") && len(dialects) > 1 {
+			return "", true, errors.New("the default SQL variant can't be declared with other dialects")
+		}
+		variants = append(variants, &variant{dialects: dialects, lines: []string{rest}})
+	}
+
+	var b strings.Builder
+	for _, v := range variants {
+		body := strings.TrimSpace(strings.Join(v.lines, "\n"))
+		if body == "" {
+			return "", true, fmt.Errorf("empty SQL variant of dialect %s", strings.Join(v.dialects, ", "))
+		}
+		if v.dialects[0] == "default" {
+			fallback = v
+			continue
+		}
+
+		directive := "{{else if dialect"
+		if b.Len() == 0 {
+			directive = "{{if dialect"
+		}
+		for _, dialect := range v.dialects {
+			directive += " " + strconv.Quote(dialect)
+		}
+		b.WriteString(directive + "}}\n" + body + "\n")
+	}
+	switch {
+	case b.Len() == 0:
+		return strings.TrimSpace(strings.Join(fallback.lines, "\n")), true, nil
+	case fallback != nil:
+		b.WriteString("{{else}}\n" + strings.TrimSpace(strings.Join(fallback.lines, "\n")) + "\n")
+	}
+	b.WriteString("{{end}}")
+	return b.String(), true, nil
+}
+
+// ordinalPlaceholderRegexps match the hand-written ordinal placeholders of SQL templates in the
+// convention of each dialect, see bindOrdinals; the submatch is the position of the parameter,
+// placeholders without one are numbered in order
+var ordinalPlaceholderRegexps = map[string]*regexp.Regexp{
+	"mysql":      regexp.MustCompile(`^\?()`),
+	"sqlite":     regexp.MustCompile(`^\?(\d*)`),
+	"clickhouse": regexp.MustCompile(`^\?()`),
+	"postgres":   regexp.MustCompile(`^\$(\d+)`),
+	"gaussdb":    regexp.MustCompile(`^\$(\d+)`),
+	"oracle":     regexp.MustCompile(`^:(\d+)`),
+	"sqlserver":  regexp.MustCompile(`^@p(\d+)\b`),
+}
+
+// bindOrdinals rewrites the hand-written ordinal placeholders of the SQL template in the convention of the
+// dialect, e.g. $1 of postgres or ? of mysql, into the placeholders of the params at their positions, so
+// gorm binds them along with the other params in the convention of the dialect it runs on. Placeholders in
+// quoted strings, comments and directives are kept; offset is the offset of the invalid placeholder
+func bindOrdinals(tmpl, dialect, prefix string, params []string) (sql string, offset int, err error) {
+	re := ordinalPlaceholderRegexps[dialect]
+	if re == nil || tmpl == "" {
+		return tmpl, 0, nil
+	}
+	if prefix == "" {
+		prefix = defaultPlaceholderPrefix
+	}
+
+	stripped := stripSQLComments(tmpl)
+	var b strings.Builder
+	var quote byte
+	var next, last int // next position of unnumbered placeholders, end of the last rewritten one
+	for i := 0; i < len(stripped); {
+		switch c := stripped[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			i++
+		case strings.HasPrefix(stripped[i:], "{{"):
+			end := strings.Index(stripped[i:], "}}")
+			if end < 0 {
+				i = len(stripped)
+				continue
+			}
+			i += end + 2
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			i++
+		default:
+			m := re.FindStringSubmatch(stripped[i:])
+			if m == nil || i > 0 && (isWordChar(stripped[i-1]) || strings.ContainsRune(`\:@$?`, rune(stripped[i-1]))) {
+				i++
+				continue
+			}
+			position := next + 1
+			if m[1] != "" {
+				position, _ = strconv.Atoi(m[1])
+			} else {
+				next++
+			}
+			if position < 1 || position > len(params) {
+				return "", i, fmt.Errorf("placeholder %s is out of the %d parameters of the method", m[0], len(params))
+			}
+			if dialect == "sqlserver" && slices.Contains(params, m[0][1:]) {
+				i += len(m[0]) // @p1 is the placeholder of the p1 parameter
+				continue
+			}
+			b.WriteString(tmpl[last:i] + prefix + params[position-1])
+			i += len(m[0])
+			last = i
+		}
+	}
+	b.WriteString(tmpl[last:])
+	return b.String(), 0, nil
+}
+
+// finish reports whether the SQL runs the query, its method returns the results instead of the chain
+func (s ExtractedSQL) finish() bool {
+	return s.Raw != "" || s.Update != "" || s.Delete != "" || s.Count != "" || s.Insert != ""
+}
+
+// templates returns the SQL templates of the annotation
+func (s ExtractedSQL) templates() []string {
+	return []string{s.Raw, s.Where, s.Select, s.Expr, s.Update, s.Delete, s.Count, s.Insert}
+}
+
+// extractDescription returns the part of a method comment that isn't its SQL template, see extractSQL
+func extractDescription(comment string, methodName string) string {
+	comment = strings.TrimSpace(comment)
+
+	if _, rest, ok := cutSQLAnnotation(comment); ok {
+		description, _ := cutTimeoutLine(rest)
+		return description
+	}
+
+	if index := strings.Index(comment, "\n\n"); index != -1 {
+		if strings.Contains(comment[index+2:], methodName) {
+			return comment[index+2:]
+		}
+		return comment[:index]
+	}
+	return ""
+}
+
+// formatDoc formats doc text as a // comment block, returns "" for empty docs
+func formatDoc(doc string) string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return ""
+	}
+
+	lines := strings.Split(doc, "\n")
+	for i, line := range lines {
+		if line = strings.TrimRight(line, " \t"); line == "" {
+			lines[i] = "//"
+		} else {
+			lines[i] = "// " + line
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 // ImplementsAllowedInterfaces reports whether typ or *typ implements any allowed interface.
@@ -64,7 +366,7 @@ func ImplementsAllowedInterfaces(typ types.Type) bool {
 	if ptr, ok := typ.(*types.Pointer); ok {
 		typ = ptr.Elem()
 	}
-	for _, t := range allowedInterfaces {
+	for _, t := range allowedInterfaces() {
 		iface, _ := t.Underlying().(*types.Interface)
 		if types.Implements(typ, iface) || types.Implements(types.NewPointer(typ), iface) {
 			return true
@@ -73,28 +375,75 @@ func ImplementsAllowedInterfaces(typ types.Type) bool {
 	return false
 }
 
-func findGoModDir(filename string) string {
-	cmd := exec.Command("go", "env", "GOMOD")
-	cmd.Dir = filepath.Dir(filename)
-	out, _ := cmd.Output()
-	return filepath.Dir(string(out))
+// ImplementsSerializer reports whether typ or *typ implements schema.SerializerInterface.
+func ImplementsSerializer(typ types.Type) bool {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	iface, _ := serializerInterface().Underlying().(*types.Interface)
+	return types.Implements(typ, iface) || types.Implements(types.NewPointer(typ), iface)
 }
 
-// getCurrentPackagePath gets the full import path of the current file's package
-func getCurrentPackagePath(filename string) string {
-	cfg := &packages.Config{
-		Mode: packages.NeedName,
-		Dir:  findGoModDir(filename),
+// underlyingHelperType returns the field helper of the underlying type of typ, a defined type or an alias
+// named name, or "" if it has none. Number helpers keep the named type, e.g. field.Number[models.Money].
+func underlyingHelperType(typ types.Type, name string) string {
+	if named, ok := types.Unalias(typ).(*types.Named); ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time" {
+		return "field.Time"
 	}
 
-	pkgs, err := packages.Load(cfg, filepath.Dir(filename))
-	if err == nil && len(pkgs) > 0 && pkgs[0].PkgPath != "" {
-		return pkgs[0].PkgPath
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		switch info := u.Info(); {
+		case info&types.IsString != 0:
+			return "field.String"
+		case info&types.IsBoolean != 0:
+			return "field.Bool"
+		case info&(types.IsInteger|types.IsFloat) != 0:
+			return "field.Number[" + name + "]"
+		}
+	case *types.Slice:
+		if elem, ok := u.Elem().Underlying().(*types.Basic); ok && elem.Kind() == types.Byte {
+			return "field.Bytes"
+		}
 	}
 	return ""
 }
 
-// loadNamedType returns a named type from a package with basic caching.
+// mapTypes returns the key and value types of a map type without import paths, e.g. string and
+// models.Label of map[string]example.com/models.Label
+func mapTypes(goType string) (key, value string, ok bool) {
+	rest, ok := strings.CutPrefix(goType, "map[")
+	if !ok {
+		return "", "", false
+	}
+	depth := 1
+	for i, r := range rest {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				short := func(t string) string {
+					elem := strings.TrimLeft(t, "[]*")
+					return t[:len(t)-len(elem)] + path.Base(elem)
+				}
+				return short(rest[:i]), short(rest[i+1:]), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// findGoModDir returns the module root directory of filename
+func findGoModDir(filename string) string {
+	cmd := exec.Command("go", "env", "GOMOD")
+	cmd.Dir = filepath.Dir(filename)
+	out, _ := cmd.Output()
+	return filepath.Dir(string(out))
+}
+
+// loadNamedType returns a named type from a package, only its types are loaded
 func loadNamedType(modRoot, pkgPath, name string) types.Type {
 	cfg := &packages.Config{
 		Mode: packages.NeedTypes | packages.NeedName,
@@ -102,8 +451,9 @@ func loadNamedType(modRoot, pkgPath, name string) types.Type {
 	}
 
 	pkgs, err := packages.Load(cfg, pkgPath)
-	if err != nil || len(pkgs) == 0 || pkgs[0].Types == nil {
-		return nil
+	if err != nil || len(pkgs) == 0 || pkgs[0].Types == nil || len(pkgs[0].Errors) > 0 {
+		// the export data of the toolchain may be unreadable by go/packages, type-check the package instead
+		return defaultLoader.namedType(modRoot, pkgPath, name)
 	}
 	if obj := pkgs[0].Types.Scope().Lookup(name); obj != nil {
 		return obj.Type()
@@ -111,42 +461,38 @@ func loadNamedType(modRoot, pkgPath, name string) types.Type {
 	return nil
 }
 
-// loadStructFromPackage loads a struct type definition from an external package by name
-func loadNamedStructType(modRoot, pkgPath, name string) (*ast.StructType, error) {
-	cfg := &packages.Config{
-		Mode: packages.NeedSyntax | packages.NeedTypes | packages.NeedImports,
-		Dir:  modRoot,
+// enumValues returns the names of the package-level constants declared with the named type,
+// in declaration order. It returns nil if the type is not a defined string or integer type,
+// types from the standard library (e.g. time.Duration) are never treated as enums.
+func (l *packageLoader) enumValues(modRoot, pkgPath, name string) []string {
+	pkg := l.pkg(modRoot, pkgPath)
+	if pkg == nil || pkg.Types == nil || pkg.Module == nil {
+		return nil
 	}
 
-	pkgs, err := packages.Load(cfg, pkgPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load package %q from %v: %w", pkgPath, modRoot, err)
+	scope := pkg.Types.Scope()
+	obj, ok := scope.Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil
 	}
 
-	if len(pkgs) == 0 {
-		return nil, fmt.Errorf("no packages found for path %q from %v", pkgPath, modRoot)
+	if basic, ok := obj.Type().Underlying().(*types.Basic); !ok || basic.Info()&(types.IsString|types.IsInteger) == 0 {
+		return nil
 	}
 
-	for _, pkg := range pkgs {
-		for _, syntax := range pkg.Syntax {
-			for _, decl := range syntax.Decls {
-				gen, ok := decl.(*ast.GenDecl)
-				if !ok {
-					continue
-				}
-				for _, spec := range gen.Specs {
-					ts, ok := spec.(*ast.TypeSpec)
-					if ok && ts.Name.Name == name {
-						if st, ok := ts.Type.(*ast.StructType); ok {
-							return st, nil
-						}
-					}
-				}
-			}
+	var consts []*types.Const
+	for _, n := range scope.Names() {
+		if c, ok := scope.Lookup(n).(*types.Const); ok && c.Exported() && types.Identical(c.Type(), obj.Type()) {
+			consts = append(consts, c)
 		}
 	}
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
 
-	return nil, fmt.Errorf("struct %s not found in package %s", name, pkgPath)
+	values := make([]string, len(consts))
+	for i, c := range consts {
+		values[i] = c.Name()
+	}
+	return values
 }
 
 // generateDBName generates database column name using GORM's NamingStrategy and COLUMN tag.
@@ -161,28 +507,168 @@ func generateDBName(fieldName, gormTag string) string {
 	return ns.ColumnName("", fieldName)
 }
 
-// mergeImports appends imports from src into dst if not already present (by Path)
+// parseTableNameMethod returns the receiver type name and the returned table name if decl is
+// a `TableName() string` method that returns a string literal, e.g.
+//
+//	func (User) TableName() string { return "members" }
+func parseTableNameMethod(decl *ast.FuncDecl) (recv string, name string) {
+	if decl.Name.Name != "TableName" || decl.Recv == nil || len(decl.Recv.List) != 1 || decl.Body == nil {
+		return "", ""
+	}
+
+	if decl.Type.Params.NumFields() != 0 || decl.Type.Results.NumFields() != 1 || len(decl.Body.List) != 1 {
+		return "", ""
+	}
+
+	recvType := decl.Recv.List[0].Type
+	if star, ok := recvType.(*ast.StarExpr); ok {
+		recvType = star.X
+	}
+
+	ident, ok := recvType.(*ast.Ident)
+	if !ok {
+		return "", ""
+	}
+
+	if ret, ok := decl.Body.List[0].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+		if name := strLit(ret.Results[0]); name != "" {
+			return ident.Name, name
+		}
+	}
+	return "", ""
+}
+
+// sqlNullElemType returns T of a generic sql.Null[T] type
+func sqlNullElemType(goType string) (string, bool) {
+	for _, prefix := range []string{"database/sql.Null[", "sql.Null["} {
+		if elemType, ok := strings.CutPrefix(goType, prefix); ok && strings.HasSuffix(elemType, "]") {
+			return strings.TrimSuffix(elemType, "]"), true
+		}
+	}
+	return "", false
+}
+
+// typeParams returns the type parameter list of a generic type declaration, e.g. [K comparable, V any],
+// and the parameters as type arguments, e.g. [K, V]
+func typeParams(fields *ast.FieldList) (params, args string) {
+	if fields == nil || len(fields.List) == 0 {
+		return "", ""
+	}
+
+	var paramList, argList []string
+	for _, f := range fields.List {
+		var names []string
+		for _, n := range f.Names {
+			names = append(names, n.Name)
+		}
+		paramList = append(paramList, strings.Join(names, ", ")+" "+types.ExprString(f.Type))
+		argList = append(argList, names...)
+	}
+	return "[" + strings.Join(paramList, ", ") + "]", "[" + strings.Join(argList, ", ") + "]"
+}
+
+// embeddedName returns the field name of a type when embedded, e.g. Number for field.Number[int]
+func embeddedName(typ string) string {
+	if idx := strings.Index(typ, "["); idx >= 0 {
+		typ = typ[:idx]
+	}
+	return typ[strings.LastIndex(typ, ".")+1:]
+}
+
+// mergeImports appends imports from src into dst if not already present (by Name and Path), a package
+// imported under different names is kept under each of them, as the code of the files refers to it by
+// their own name; see importConflict for the names imported from different paths
 func mergeImports(dst *[]Import, src []Import) {
-	existing := map[string]bool{}
+	existing := map[Import]bool{}
 	for _, i := range *dst {
-		existing[i.Path] = true
+		existing[i] = true
 	}
 	for _, i := range src {
-		if !existing[i.Path] {
+		if !existing[i] {
 			*dst = append(*dst, i)
-			existing[i.Path] = true
+			existing[i] = true
+		}
+	}
+}
+
+// importConflict returns an error if the generated code imports different packages under the same name,
+// e.g. when the merged files of a package import them with the same alias; imports of names the code
+// doesn't use are already removed
+func importConflict(outPath string, src []byte) error {
+	f, err := parser.ParseFile(token.NewFileSet(), outPath, src, parser.ImportsOnly)
+	if err != nil {
+		return err
+	}
+	paths := map[string]string{}
+	for _, spec := range f.Imports {
+		imp := parseImport(spec)
+		if imp.Name == "_" || imp.Name == "." {
+			continue
+		}
+		if other, ok := paths[imp.Name]; ok && other != imp.Path {
+			return fmt.Errorf("conflicting imports in %v: %q and %q are both imported as %s, import them under different names in the input files", outPath, other, imp.Path, imp.Name)
 		}
+		paths[imp.Name] = imp.Path
+	}
+	return nil
+}
+
+// generateMarker is the comment directive that opts a type in to generation, see genconfig.Config.RequireMarker
+const generateMarker = "//gorm:generate"
+
+// hasMarker reports whether the doc comment contains the generate marker directive
+func hasMarker(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
 	}
+	return slices.ContainsFunc(doc.List, func(c *ast.Comment) bool { return strings.TrimSpace(c.Text) == generateMarker })
 }
 
-// shouldSkipFile checks if a file contains the generated code header and should be skipped
-func shouldSkipFile(filePath string) bool {
+// viewMarker is the comment directive that declares a struct as a database view, optionally followed by
+// the SELECT statement of the view, see genconfig.Config.Views
+const viewMarker = "//gorm:view"
+
+// viewDirective reports whether the doc comment contains the view marker directive, with the SQL following it
+func viewDirective(doc *ast.CommentGroup) (ok bool, sql string) {
+	if doc == nil {
+		return false, ""
+	}
+	for _, c := range doc.List {
+		if rest, found := strings.CutPrefix(strings.TrimSpace(c.Text), viewMarker); found && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			return true, strings.TrimSpace(rest)
+		}
+	}
+	return false, ""
+}
+
+// shouldSkipFile checks if a file contains the generated code header, or one of the given hints, and should be skipped
+func shouldSkipFile(filePath string, hints ...string) bool {
 	if !strings.HasSuffix(filePath, ".go") {
 		return true
 	}
 
 	content, err := os.ReadFile(filePath)
-	return err == nil && bytes.Contains(content, []byte(codeGenHint))
+	if err != nil {
+		return false
+	}
+	for _, hint := range append([]string{codeGenHint}, hints...) {
+		if bytes.Contains(content, []byte(hint)) {
+			return true
+		}
+	}
+	return false
+}
+
+// generatedCodeRegexp matches the standard generated code comment, see https://go.dev/s/generatedcode
+var generatedCodeRegexp = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// headerHint returns the line of a generated file header used to detect generated files,
+// that is the standard generated code comment if present, or the whole header otherwise
+func headerHint(header string) string {
+	if hint := generatedCodeRegexp.FindString(header); hint != "" {
+		return hint
+	}
+	return strings.TrimSpace(header)
 }
 
 // strLit returns the unquoted string if expr is a string literal; otherwise "".
@@ -196,6 +682,29 @@ func strLit(expr ast.Expr) string {
 	return ""
 }
 
+// strLitValue returns the value of a string literal, or nil if expr isn't one
+func strLitValue(expr ast.Expr) *string {
+	if bl, ok := expr.(*ast.BasicLit); ok && bl.Kind == token.STRING {
+		if s, err := strconv.Unquote(bl.Value); err == nil {
+			return &s
+		}
+	}
+	return nil
+}
+
+// constLit converts a constant value to the literal expression it's written as in config literals
+func constLit(v constant.Value) ast.Expr {
+	switch v.Kind() {
+	case constant.String:
+		return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(constant.StringVal(v))}
+	case constant.Bool:
+		return &ast.Ident{Name: strconv.FormatBool(constant.BoolVal(v))}
+	case constant.Int:
+		return &ast.BasicLit{Kind: token.INT, Value: v.ExactString()}
+	}
+	return nil
+}
+
 func stripGeneric(s string) string {
 	if i := strings.Index(s, "["); i >= 0 {
 		return s[:i]
@@ -5,16 +5,19 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"gorm.io/cli/gorm/internal/drivers"
 	"gorm.io/cli/gorm/internal/gen"
 )
 
 func main() {
+	gen.SchemaDrivers = drivers.Dialectors
+
 	rootCmd := &cobra.Command{
 		Use:   "gorm",
 		Short: "GORM CLI Tool",
 	}
 
-	rootCmd.AddCommand(gen.New())
+	rootCmd.AddCommand(gen.New(), gen.NewClean(), gen.NewSchema(), gen.NewProto())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -8,6 +8,9 @@ export MYSQL_DSN='gorm:gorm@tcp(127.0.0.1:9910)/gorm?parseTime=true&charset=utf8
 echo "Running root module tests..." >&2
 go test -count=1 ./...
 
+echo "Running parallel generation tests with the race detector..." >&2
+go test -count=1 -race -run 'TestProcessConcurrent' ./internal/gen
+
 echo "Running examples module tests..." >&2
 (
   cd examples
@@ -274,6 +274,9 @@ func (q *preloadBuilder) LimitPerRecord(num int) PreloadBuilder {
 }
 
 func (c chainG[T]) Joins(jt clause.JoinTarget, on func(db JoinBuilder, joinTable clause.Table, curTable clause.Table) error) ChainInterface[T] {
+	if on == nil {
+		return c.with(c.g.Joins(jt, nil))
+	}
 	return c.with(c.g.Joins(jt, func(db gorm.JoinBuilder, joinTable clause.Table, curTable clause.Table) error {
 		return on(&joinBuilder{db}, joinTable, curTable)
 	}))
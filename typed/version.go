@@ -0,0 +1,40 @@
+package typed
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/exp/constraints"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVersionConflict is returned by UpdateVersioned when no record matches the expected version,
+// the record was updated or deleted concurrently
+var ErrVersionConflict = errors.New("optimistic lock conflict: version doesn't match")
+
+// VersionField is implemented by field.Version, the helper of optimistic lock version fields,
+// i.e. fields tagged `gen:"version"` or integer fields named after genconfig.Config.VersionField
+type VersionField[V constraints.Integer] interface {
+	Lock(version V) clause.Expression
+	Bump() clause.Assigner
+}
+
+// UpdateVersioned applies the assignments to the records matched by q whose version equals version,
+// and increments the version in the same statement. ErrVersionConflict is returned when no record
+// was updated.
+//
+// Example:
+//
+//	err := typed.UpdateVersioned(ctx, typed.G[User](db).Where(generated.User.ID.Eq(user.ID)),
+//		generated.User.Version, user.Version, generated.User.Name.Set("jinzhu"))
+func UpdateVersioned[T any, V constraints.Integer](ctx context.Context, q ChainInterface[T], version VersionField[V], current V, assignments ...clause.Assigner) error {
+	assignments = append(assignments[:len(assignments):len(assignments)], version.Bump())
+	rows, err := q.Where(version.Lock(current)).Set(assignments...).Update(ctx)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}